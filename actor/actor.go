@@ -37,6 +37,9 @@ var (
 // Actors are created using New and started with Run. Messages are processed sequentially through a mailbox.
 type Actor[Request, Response any] struct {
 	factory func(ref *Ref[Request, Response]) Processor[Request, Response]
+	// stopOnPanic makes a panic stop the actor instead of only failing the
+	// message, so that a supervisor can restart it with fresh state.
+	stopOnPanic bool
 }
 
 // New creates a new Actor with the given processor factory function.
@@ -66,6 +69,17 @@ func informCallerOfPanic[Request, Response any](
 	name string,
 	msg Message[Request, Response],
 	err any,
+) {
+	informCaller(ctx, msg, getPanicErr(name, err))
+}
+
+// informCaller attempts to send an error to a message's response channel if one exists,
+// and closes the channel. It uses a timeout to avoid blocking indefinitely if the caller
+// has stopped listening.
+func informCaller[Request, Response any](
+	ctx context.Context,
+	msg Message[Request, Response],
+	err error,
 ) {
 	if msg.ResponseChan == nil {
 		return
@@ -84,7 +98,7 @@ func informCallerOfPanic[Request, Response any](
 	}()
 
 	rsp := try.Try[Response]{
-		Error: getPanicErr(name, err),
+		Error: err,
 	}
 
 	// We wait for 1 of the following to happen:
@@ -101,15 +115,22 @@ func informCallerOfPanic[Request, Response any](
 }
 
 // runProcessor executes the processor's Process method with panic recovery.
-// If a panic occurs, it logs the error with stack trace, updates metrics, and notifies the caller.
+// If a panic occurs, it logs the error with stack trace, updates metrics, notifies the caller
+// and returns the panic as an error.
 func (a *Actor[Request, Response]) runProcessor(
 	ctx context.Context,
 	proc Processor[Request, Response],
 	msg Message[Request, Response],
 	name string,
-) {
+) (panicErr error) {
 	defer func() {
 		if err := recover(); err != nil {
+			if mp, ok := err.(*messagePanic[Request, Response]); ok {
+				msg, err = mp.msg, mp.value
+			}
+
+			panicErr = getPanicErr(name, err)
+
 			log := logger.Get(logger.WithSlackNotification(ctx))
 			subsystem := logger.GetSubsystem(ctx)
 
@@ -121,21 +142,31 @@ func (a *Actor[Request, Response]) runProcessor(
 				"error", err,
 				"stack", string(debug.Stack()))
 
+			publishDeadLetter(ctx, name, msg.Request, DeadLetterPanic, panicErr)
 			informCallerOfPanic(ctx, name, msg, err)
 		}
 	}()
 
 	proc.Process(msg)
+
+	return nil
 }
 
 // Run starts the actor and returns a reference that can be used to send messages to it.
 // The name parameter is used for logging and metrics. The depth parameter specifies the mailbox
 // buffer size (0 for unbuffered). Messages are delivered in FIFO order. The actor runs until the
 // context is canceled or Stop is called on the returned reference.
-func (a *Actor[Request, Response]) Run(ctx context.Context, name string, depth int) *Ref[Request, Response] {
+//
+// See WithOverflowPolicy for what happens when the mailbox is full.
+func (a *Actor[Request, Response]) Run(
+	ctx context.Context,
+	name string,
+	depth int,
+	opts ...RunOption,
+) *Ref[Request, Response] {
 	w, r, count := channels.Create[Message[Request, Response]](depth)
 
-	return a.run(ctx, name, w, r, count, depth > 0)
+	return a.run(ctx, name, w, r, count, depth > 0, max(depth, 0), opts)
 }
 
 // RunPriority starts the actor with a priority-ordered inbox instead of a FIFO one and returns a
@@ -150,18 +181,34 @@ func (a *Actor[Request, Response]) Run(ctx context.Context, name string, depth i
 // (SendCtx/RequestCtx honor their context while blocked). Use Run instead for bounded FIFO delivery.
 //
 // Stopping via Stop drains and processes any queued messages in priority order before the run loop
-// exits; canceling ctx stops immediately and discards queued messages.
-func (a *Actor[Request, Response]) RunPriority(ctx context.Context, name string, maxSize int) *Ref[Request, Response] {
+// exits; canceling ctx stops immediately and discards queued messages. With an overflow policy
+// other than OverflowBlock (see WithOverflowPolicy), a full inbox no longer blocks senders.
+// OverflowDropOldest would drop the highest-priority message, so RunPriority panics with
+// ErrUnsupportedOverflowPolicy if given it.
+func (a *Actor[Request, Response]) RunPriority(
+	ctx context.Context,
+	name string,
+	maxSize int,
+	opts ...RunOption,
+) *Ref[Request, Response] {
+	checkPriorityOverflow(opts)
+
 	w, r, count := channels.CreatePriority(ctx, maxSize, func(x, y Message[Request, Response]) bool {
 		return x.Weight > y.Weight
 	})
 
-	return a.run(ctx, name, w, r, count, true)
+	capacity := maxSize
+	if maxSize <= 0 {
+		capacity = -1 // unbounded
+	}
+
+	return a.run(ctx, name, w, r, count, true, capacity, opts)
 }
 
 // run wires up a Ref around the given inbox channels and starts the actor's processing goroutine.
 // It backs both Run (FIFO inbox) and RunPriority (heap inbox). trackQueue controls whether the
-// enqueued-messages gauge is reported (meaningful only when the inbox actually buffers).
+// enqueued-messages gauge is reported (meaningful only when the inbox actually buffers). capacity
+// is the inbox size used by overflow policies: negative if unbounded, 0 if unknown or unbuffered.
 func (a *Actor[Request, Response]) run(
 	ctx context.Context,
 	name string,
@@ -169,12 +216,22 @@ func (a *Actor[Request, Response]) run(
 	r <-chan Message[Request, Response],
 	count func() int,
 	trackQueue bool,
+	capacity int,
+	opts []RunOption,
 ) *Ref[Request, Response] {
+	var options runOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	ref := &Ref[Request, Response]{
 		inboxRead:  r,
 		inboxWrite: w,
 		getCount:   count,
 		name:       name,
+		overflow:   options.overflow,
+		capacity:   capacity,
 	}
 
 	ref.wg.Add(1)
@@ -199,6 +256,11 @@ func (a *Actor[Request, Response]) run(
 
 		defer ref.wg.Done()
 		defer ticker.Stop()
+		defer func() {
+			if s, ok := proc.(stopper); ok {
+				s.stop(context.WithoutCancel(ctx))
+			}
+		}()
 		defer aliveActors.WithLabelValues(subsystem, name).Dec()
 		defer actorStopped.Inc()
 		defer func() {
@@ -224,6 +286,7 @@ func (a *Actor[Request, Response]) run(
 				// Flip dead before closing the inbox so concurrent senders
 				// see Alive() == false instead of racing with the close.
 				// CloseChannelIgnorePanic recovers if Stop() got here first.
+				ref.setErr(ctx.Err())
 				ref.dead.Store(true)
 				channels.CloseChannelIgnorePanic(ref.inboxWrite)
 			case <-ticker.C:
@@ -247,12 +310,18 @@ func (a *Actor[Request, Response]) run(
 
 				start := time.Now()
 
-				a.runProcessor(ctx, proc, msg, name)
+				panicErr := a.runProcessor(ctx, proc, msg, name)
 
 				end := time.Now()
 
 				processedMessages.WithLabelValues(subsystem, name).Inc()
 				processingTime.WithLabelValues(subsystem, name).Observe(end.Sub(start).Seconds())
+
+				if panicErr != nil && a.stopOnPanic {
+					a.crash(ctx, ref, panicErr)
+
+					return
+				}
 			}
 		}
 	}()
@@ -260,6 +329,22 @@ func (a *Actor[Request, Response]) run(
 	return ref
 }
 
+// crash stops the actor after its processor panicked. Messages still in the inbox
+// are not processed; callers waiting for a response receive ErrDeadActor.
+func (a *Actor[Request, Response]) crash(ctx context.Context, ref *Ref[Request, Response], err error) {
+	ref.setErr(err)
+	ref.dead.Store(true)
+	channels.CloseChannelIgnorePanic(ref.inboxWrite)
+
+	// The actor's context may already be done, but callers are still waiting
+	replyCtx := context.WithoutCancel(ctx)
+
+	for msg := range ref.inboxRead {
+		publishDeadLetter(replyCtx, ref.name, msg.Request, DeadLetterDeadActor, ErrDeadActor)
+		informCaller(replyCtx, msg, ErrDeadActor)
+	}
+}
+
 // RunWithInbox starts the actor on a caller-supplied inbox instead of the
 // built-in FIFO (Run) or heap (RunPriority) ones, returning a reference for
 // sending messages. It is the same engine that backs Run and RunPriority,
@@ -285,8 +370,9 @@ func (a *Actor[Request, Response]) RunWithInbox(
 	r <-chan Message[Request, Response],
 	count func() int,
 	trackQueue bool,
+	opts ...RunOption,
 ) *Ref[Request, Response] {
-	return a.run(ctx, name, w, r, count, trackQueue)
+	return a.run(ctx, name, w, r, count, trackQueue, 0, opts)
 }
 
 // Ref is a reference to a running actor. It provides methods to send messages,
@@ -298,6 +384,11 @@ type Ref[Request, Response any] struct {
 	getCount   func() int
 	dead       atomic.Bool
 	name       string
+	errMu      sync.Mutex
+	err        error
+	overflow   OverflowPolicy
+	capacity   int
+	spilled    spillQueue[Request, Response]
 }
 
 // Name returns the actor's name.
@@ -310,6 +401,26 @@ func (r *Ref[Request, Response]) Alive() bool {
 	return !r.dead.Load()
 }
 
+// Err returns why the actor stopped: nil if it is still running or was stopped
+// with Stop, the context's error if its context was canceled, or an ErrActorPanic
+// error if it was supervised and its processor panicked.
+func (r *Ref[Request, Response]) Err() error {
+	r.errMu.Lock()
+	defer r.errMu.Unlock()
+
+	return r.err
+}
+
+// setErr records the first reason the actor stopped.
+func (r *Ref[Request, Response]) setErr(err error) {
+	r.errMu.Lock()
+	defer r.errMu.Unlock()
+
+	if r.err == nil {
+		r.err = err
+	}
+}
+
 // Stop signals the actor to shut down by closing its inbox channel.
 // It is safe to call multiple times.
 func (r *Ref[Request, Response]) Stop() {
@@ -326,7 +437,8 @@ func (r *Ref[Request, Response]) Wait() {
 }
 
 // submit is an internal method that sends a message to the actor's inbox,
-// tracking submission metrics and respecting context cancellation.
+// tracking submission metrics, respecting context cancellation and applying
+// the overflow policy. Messages that cannot be delivered become dead letters.
 //
 // The deferred recover catches "send on closed channel" when the run loop
 // (or Stop) closes inboxWrite concurrently with this send: a sender can
@@ -335,6 +447,8 @@ func (r *Ref[Request, Response]) Wait() {
 // crashing the goroutine.
 func (r *Ref[Request, Response]) submit(ctx context.Context, message Message[Request, Response]) (err error) {
 	if r.dead.Load() {
+		publishDeadLetter(ctx, r.name, message.Request, DeadLetterDeadActor, ErrDeadActor)
+
 		return ErrDeadActor
 	}
 
@@ -342,6 +456,10 @@ func (r *Ref[Request, Response]) submit(ctx context.Context, message Message[Req
 		if rec := recover(); rec != nil {
 			err = ErrDeadActor
 		}
+
+		if errors.Is(err, ErrDeadActor) {
+			publishDeadLetter(ctx, r.name, message.Request, DeadLetterDeadActor, err)
+		}
 	}()
 
 	subsystem := logger.GetSubsystem(ctx)
@@ -350,11 +468,9 @@ func (r *Ref[Request, Response]) submit(ctx context.Context, message Message[Req
 
 	begin := time.Now()
 
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case r.inboxWrite <- message:
-		break
+	err = r.deliver(ctx, message)
+	if err != nil {
+		return err
 	}
 
 	end := time.Now()
@@ -0,0 +1,221 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/amp-labs/amp-common/try"
+)
+
+// defaultStashCapacity is the number of messages a stateful actor can stash by default.
+const defaultStashCapacity = 1000
+
+// ErrStashFull is returned to the sender of a message that could not be stashed
+// because the stash already holds the maximum number of messages.
+var ErrStashFull = errors.New("actor stash is full")
+
+// Behavior handles one request of a stateful actor. It receives the actor's
+// current state and returns the next state along with the response. The
+// BehaviorContext lets it switch to another behavior for subsequent messages
+// (Become/Unbecome) and defer messages it cannot handle yet (Stash/UnstashAll).
+type Behavior[State, Request, Response any] func(
+	bc *BehaviorContext[State, Request, Response],
+	state State,
+	request Request,
+) (State, Response, error)
+
+// BehaviorContext is passed to a Behavior while it handles a message.
+type BehaviorContext[State, Request, Response any] struct {
+	self          *Ref[Request, Response]
+	behaviors     []Behavior[State, Request, Response] // stack, the current behavior is last
+	stash         []Message[Request, Response]
+	stashCapacity int
+	stashCurrent  bool
+	unstash       bool
+}
+
+// Self returns the reference to the actor, e.g. to send it messages.
+func (bc *BehaviorContext[State, Request, Response]) Self() *Ref[Request, Response] {
+	return bc.self
+}
+
+// Become makes behavior handle the following messages. The current behavior
+// is kept, so Unbecome can return to it.
+func (bc *BehaviorContext[State, Request, Response]) Become(behavior Behavior[State, Request, Response]) {
+	bc.behaviors = append(bc.behaviors, behavior)
+}
+
+// Unbecome returns to the behavior that was active before the last Become.
+// It does nothing if the initial behavior is active.
+func (bc *BehaviorContext[State, Request, Response]) Unbecome() {
+	if len(bc.behaviors) > 1 {
+		bc.behaviors = bc.behaviors[:len(bc.behaviors)-1]
+	}
+}
+
+// Stash sets the current message aside instead of answering it. It is handled
+// again, by whatever behavior is then active, after UnstashAll. The response
+// returned by the behavior for a stashed message is discarded.
+func (bc *BehaviorContext[State, Request, Response]) Stash() {
+	bc.stashCurrent = true
+}
+
+// UnstashAll re-delivers all stashed messages, in the order they were stashed,
+// once the current message has been handled and before any new messages.
+func (bc *BehaviorContext[State, Request, Response]) UnstashAll() {
+	bc.unstash = true
+}
+
+// Stashed returns the number of stashed messages.
+func (bc *BehaviorContext[State, Request, Response]) Stashed() int {
+	return len(bc.stash)
+}
+
+// StatefulOption configures an actor created with NewStateful.
+type StatefulOption func(*statefulOptions)
+
+// statefulOptions holds the configuration set by StatefulOption values.
+type statefulOptions struct {
+	stashCapacity int
+}
+
+// WithStashCapacity sets how many messages a stateful actor can stash (default 1000).
+// Messages stashed beyond that are answered with ErrStashFull.
+func WithStashCapacity(capacity int) StatefulOption {
+	return func(o *statefulOptions) {
+		o.stashCapacity = capacity
+	}
+}
+
+// NewStateful creates an actor whose messages are handled by a Behavior over
+// typed state. Every time the actor is started (including restarts by a
+// Supervisor) it begins with initial as its state and behavior as its
+// behavior. Like any actor, it handles one message at a time, so the state
+// needs no locking.
+//
+// Stashed messages that are still waiting when the actor stops are answered
+// with ErrDeadActor and reported as dead letters.
+func NewStateful[State, Request, Response any](
+	initial State,
+	behavior Behavior[State, Request, Response],
+	opts ...StatefulOption,
+) *Actor[Request, Response] {
+	options := statefulOptions{
+		stashCapacity: defaultStashCapacity,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return New[Request, Response](func(ref *Ref[Request, Response]) Processor[Request, Response] {
+		return &statefulProcessor[State, Request, Response]{
+			state: initial,
+			bc: &BehaviorContext[State, Request, Response]{
+				self:          ref,
+				behaviors:     []Behavior[State, Request, Response]{behavior},
+				stashCapacity: options.stashCapacity,
+			},
+		}
+	})
+}
+
+// messagePanic carries a panic raised while handling a message other than the
+// one passed to Process, so runProcessor can report it to the right caller.
+type messagePanic[Request, Response any] struct {
+	msg   Message[Request, Response]
+	value any
+}
+
+// statefulProcessor runs the behaviors of an actor created with NewStateful.
+type statefulProcessor[State, Request, Response any] struct {
+	state State
+	bc    *BehaviorContext[State, Request, Response]
+}
+
+func (p *statefulProcessor[State, Request, Response]) Process(msg Message[Request, Response]) {
+	queue := []Message[Request, Response]{msg}
+	replaying := false
+
+	var current Message[Request, Response]
+
+	defer func() {
+		if len(queue) > 0 {
+			// A behavior panicked; keep the unstashed messages that were not handled yet
+			p.bc.stash = append(queue, p.bc.stash...)
+		}
+
+		if !replaying {
+			return
+		}
+
+		if err := recover(); err != nil {
+			// msg has already been answered, so the panic must be reported
+			// against the unstashed message that caused it.
+			panic(&messagePanic[Request, Response]{msg: current, value: err})
+		}
+	}()
+
+	for len(queue) > 0 {
+		current, queue = queue[0], queue[1:]
+
+		p.handle(current)
+
+		replaying = true
+
+		if p.bc.unstash {
+			p.bc.unstash = false
+			queue = append(p.bc.stash, queue...)
+			p.bc.stash = nil
+		}
+	}
+}
+
+// handle runs the current behavior for one message and answers it, unless it was stashed.
+func (p *statefulProcessor[State, Request, Response]) handle(msg Message[Request, Response]) {
+	p.bc.stashCurrent = false
+
+	behavior := p.bc.behaviors[len(p.bc.behaviors)-1]
+
+	state, resp, err := behavior(p.bc, p.state, msg.Request)
+	p.state = state
+
+	if p.bc.stashCurrent {
+		if len(p.bc.stash) < p.bc.stashCapacity {
+			p.bc.stash = append(p.bc.stash, msg)
+
+			return
+		}
+
+		var zero Response
+
+		resp, err = zero, ErrStashFull
+	}
+
+	if msg.ResponseChan == nil {
+		if err != nil {
+			slog.Error("error processing message", "actor", p.bc.self.Name(), "error", err)
+		}
+
+		return
+	}
+
+	msg.ResponseChan <- try.Try[Response]{
+		Value: resp,
+		Error: err,
+	}
+
+	close(msg.ResponseChan)
+}
+
+// stop answers the messages that are still stashed when the actor stops.
+func (p *statefulProcessor[State, Request, Response]) stop(ctx context.Context) {
+	stashed := p.bc.stash
+	p.bc.stash = nil
+
+	for _, msg := range stashed {
+		publishDeadLetter(ctx, p.bc.self.Name(), msg.Request, DeadLetterDeadActor, ErrDeadActor)
+		informCaller(ctx, msg, ErrDeadActor)
+	}
+}
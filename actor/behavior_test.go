@@ -0,0 +1,229 @@
+package actor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/amp-labs/amp-common/try"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errAlreadyConnected = errors.New("already connected")
+
+// connCommand is a request to the connection actor used in the tests below.
+type connCommand struct {
+	Op    string
+	Value int
+}
+
+// connState is the state of the connection actor.
+type connState struct {
+	Connects int
+	Total    int
+}
+
+// connecting is the initial behavior of the connection actor: it stashes
+// queries until it is connected.
+func connecting(bc *BehaviorContext[connState, connCommand, int], state connState, cmd connCommand) (connState, int, error) {
+	switch cmd.Op {
+	case "connect":
+		state.Connects++
+
+		bc.Become(connected)
+		bc.UnstashAll()
+
+		return state, state.Connects, nil
+	case "add":
+		bc.Stash()
+
+		return state, 0, nil
+	default:
+		return state, 0, nil
+	}
+}
+
+// connected handles queries until the connection is closed.
+func connected(bc *BehaviorContext[connState, connCommand, int], state connState, cmd connCommand) (connState, int, error) {
+	switch cmd.Op {
+	case "connect":
+		return state, 0, errAlreadyConnected
+	case "add":
+		state.Total += cmd.Value
+
+		return state, state.Total, nil
+	case "disconnect":
+		bc.Unbecome()
+
+		return state, state.Total, nil
+	default:
+		return state, 0, nil
+	}
+}
+
+// publish sends a command without waiting, returning the channel its response arrives on.
+func publish(ref *Ref[connCommand, int], op string, value int) chan try.Try[int] {
+	responses := make(chan try.Try[int], 1)
+
+	ref.Publish(Message[connCommand, int]{
+		Request:      connCommand{Op: op, Value: value},
+		ResponseChan: responses,
+	})
+
+	return responses
+}
+
+func TestStatefulActorProtocol(t *testing.T) {
+	t.Parallel()
+
+	ref := NewStateful(connState{}, connecting).Run(t.Context(), "stateful_protocol", 10)
+
+	// Queries sent before connecting are stashed, then handled in order once connected
+	first := publish(ref, "add", 1)
+	second := publish(ref, "add", 2)
+
+	connects, err := ref.Request(connCommand{Op: "connect"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, connects)
+
+	assert.Equal(t, 1, (<-first).Value)
+	assert.Equal(t, 3, (<-second).Value)
+
+	total, err := ref.Request(connCommand{Op: "add", Value: 4})
+	require.NoError(t, err)
+	assert.Equal(t, 7, total)
+
+	_, err = ref.Request(connCommand{Op: "connect"})
+	require.ErrorIs(t, err, errAlreadyConnected)
+
+	// Disconnecting returns to the initial behavior, which stashes again; the state is kept
+	_, err = ref.Request(connCommand{Op: "disconnect"})
+	require.NoError(t, err)
+
+	third := publish(ref, "add", 10)
+
+	connects, err = ref.Request(connCommand{Op: "connect"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, connects)
+	assert.Equal(t, 17, (<-third).Value)
+
+	ref.Stop()
+	ref.Wait()
+}
+
+func TestStatefulActorStartsFromInitialState(t *testing.T) {
+	t.Parallel()
+
+	actor := NewStateful(connState{}, connected)
+
+	for range 2 {
+		ref := actor.Run(t.Context(), "stateful_initial", 1)
+
+		total, err := ref.Request(connCommand{Op: "add", Value: 5})
+		require.NoError(t, err)
+		assert.Equal(t, 5, total)
+
+		ref.Stop()
+		ref.Wait()
+	}
+}
+
+func TestStatefulActorStash(t *testing.T) {
+	t.Parallel()
+
+	t.Run("capacity", func(t *testing.T) {
+		t.Parallel()
+
+		ref := NewStateful(connState{}, connecting, WithStashCapacity(1)).Run(t.Context(), "stateful_capacity", 10)
+
+		stashed := publish(ref, "add", 1)
+
+		_, err := ref.Request(connCommand{Op: "add", Value: 2})
+		require.ErrorIs(t, err, ErrStashFull)
+
+		_, err = ref.Request(connCommand{Op: "connect"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, (<-stashed).Value)
+
+		ref.Stop()
+		ref.Wait()
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		t.Parallel()
+
+		ref := NewStateful(connState{}, connecting).Run(t.Context(), "stateful_stop", 10)
+
+		stashed := publish(ref, "add", 1)
+
+		// Make sure the message was stashed before stopping
+		_, err := ref.Request(connCommand{Op: "noop"})
+		require.NoError(t, err)
+
+		ref.Stop()
+		ref.Wait()
+
+		result := <-stashed
+		require.ErrorIs(t, result.Error, ErrDeadActor)
+	})
+}
+
+//nolint:paralleltest // Tests install the process-wide dead letter handler
+func TestStatefulActorPanicDuringUnstash(t *testing.T) {
+	queue := NewDeadLetterQueue(100)
+
+	SetDeadLetterHandler(queue.Handle)
+	t.Cleanup(func() { SetDeadLetterHandler(nil) })
+
+	// fragile panics on a particular value, but only once it is connected and
+	// replaying stashed messages.
+	fragile := func(bc *BehaviorContext[connState, connCommand, int], state connState, cmd connCommand) (
+		connState, int, error,
+	) {
+		if cmd.Op == "add" && cmd.Value == 13 {
+			panic("unlucky")
+		}
+
+		return connected(bc, state, cmd)
+	}
+
+	waiting := func(bc *BehaviorContext[connState, connCommand, int], state connState, cmd connCommand) (
+		connState, int, error,
+	) {
+		if cmd.Op != "connect" {
+			bc.Stash()
+
+			return state, 0, nil
+		}
+
+		state.Connects++
+
+		bc.Become(fragile)
+		bc.UnstashAll()
+
+		return state, state.Connects, nil
+	}
+
+	ref := NewStateful(connState{}, waiting).Run(t.Context(), "stateful_unstash_panic", 10)
+
+	first := publish(ref, "add", 1)
+	unlucky := publish(ref, "add", 13)
+
+	connects, err := ref.Request(connCommand{Op: "connect"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, connects)
+
+	assert.Equal(t, 1, (<-first).Value)
+
+	// The panic is reported to the sender of the message that caused it
+	result := <-unlucky
+	require.ErrorIs(t, result.Error, ErrActorPanic)
+
+	ref.Stop()
+	ref.Wait()
+
+	panicked := lettersFor(queue.Drain(), "stateful_unstash_panic")
+	require.Len(t, panicked, 1)
+	assert.Equal(t, DeadLetterPanic, panicked[0].Reason)
+	assert.Equal(t, connCommand{Op: "add", Value: 13}, panicked[0].Request)
+}
@@ -0,0 +1,120 @@
+package actor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/amp-labs/amp-common/logger"
+)
+
+// DeadLetterReason says why a message ended up in the dead letters.
+type DeadLetterReason string
+
+const (
+	// DeadLetterDropped means the message was dropped because the mailbox was full
+	// (OverflowDropNewest or OverflowDropOldest).
+	DeadLetterDropped DeadLetterReason = "dropped"
+	// DeadLetterRejected means the mailbox was full and the sender got ErrMailboxFull (OverflowReject).
+	DeadLetterRejected DeadLetterReason = "rejected"
+	// DeadLetterDeadActor means the actor had stopped before it could process the message.
+	DeadLetterDeadActor DeadLetterReason = "dead_actor"
+	// DeadLetterCanceled means the sender's context was canceled while waiting for room in the mailbox.
+	DeadLetterCanceled DeadLetterReason = "canceled"
+	// DeadLetterPanic means the actor's processor panicked while handling the message.
+	DeadLetterPanic DeadLetterReason = "panic"
+)
+
+// DeadLetter is a message that could not be delivered to an actor, or whose
+// processing panicked.
+type DeadLetter struct {
+	Actor   string
+	Request any
+	Reason  DeadLetterReason
+	Err     error
+	Time    time.Time
+}
+
+var (
+	deadLetterMu      sync.RWMutex                                 //nolint:gochecknoglobals
+	deadLetterHandler func(ctx context.Context, letter DeadLetter) //nolint:gochecknoglobals
+)
+
+// SetDeadLetterHandler installs the process-wide handler that receives every
+// dead letter, from all actors. Passing nil removes the handler; dead letters
+// are then only counted in metrics. The handler is called synchronously from
+// the sending or processing goroutine, so it must be fast and must not block.
+func SetDeadLetterHandler(handler func(ctx context.Context, letter DeadLetter)) {
+	deadLetterMu.Lock()
+	defer deadLetterMu.Unlock()
+
+	deadLetterHandler = handler
+}
+
+// publishDeadLetter records a dead letter in metrics and hands it to the handler, if any.
+func publishDeadLetter(ctx context.Context, actor string, request any, reason DeadLetterReason, err error) {
+	deadLetters.WithLabelValues(logger.GetSubsystem(ctx), actor, string(reason)).Inc()
+
+	deadLetterMu.RLock()
+	handler := deadLetterHandler
+	deadLetterMu.RUnlock()
+
+	if handler == nil {
+		return
+	}
+
+	handler(ctx, DeadLetter{
+		Actor:   actor,
+		Request: request,
+		Reason:  reason,
+		Err:     err,
+		Time:    time.Now(),
+	})
+}
+
+// DeadLetterQueue keeps the most recent dead letters in memory, for inspection
+// and tests. Install it with SetDeadLetterHandler(queue.Handle).
+type DeadLetterQueue struct {
+	mu       sync.Mutex
+	capacity int
+	letters  []DeadLetter
+}
+
+// NewDeadLetterQueue creates a queue that keeps up to capacity dead letters,
+// discarding the oldest ones when full.
+func NewDeadLetterQueue(capacity int) *DeadLetterQueue {
+	return &DeadLetterQueue{
+		capacity: max(capacity, 1),
+	}
+}
+
+// Handle adds a dead letter to the queue. It matches the signature expected by SetDeadLetterHandler.
+func (q *DeadLetterQueue) Handle(_ context.Context, letter DeadLetter) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.letters) == q.capacity {
+		q.letters = q.letters[1:]
+	}
+
+	q.letters = append(q.letters, letter)
+}
+
+// Len returns the number of dead letters in the queue.
+func (q *DeadLetterQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.letters)
+}
+
+// Drain removes and returns all dead letters in the queue, oldest first.
+func (q *DeadLetterQueue) Drain() []DeadLetter {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	letters := q.letters
+	q.letters = nil
+
+	return letters
+}
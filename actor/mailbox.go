@@ -0,0 +1,286 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/amp-labs/amp-common/logger"
+)
+
+var (
+	// ErrMailboxFull is returned when a message is rejected because the actor's mailbox is full.
+	ErrMailboxFull = errors.New("actor mailbox is full")
+	// ErrUnsupportedOverflowPolicy is the panic value of RunPriority when given
+	// an overflow policy that makes no sense for a priority inbox.
+	ErrUnsupportedOverflowPolicy = errors.New("overflow policy is not supported by this mailbox")
+)
+
+// pumpHandoffWait bounds how long a sender waits for the pump goroutine of a
+// RunPriority inbox to accept a message while the inbox has room.
+const pumpHandoffWait = 10 * time.Millisecond
+
+// OverflowPolicy decides what happens to a message sent to an actor whose mailbox is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes the sender wait until there is room (the default).
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest drops the message being sent. Fire-and-forget senders are
+	// not told; Request and RequestCtx fail with ErrMailboxFull because the
+	// message can never be answered.
+	OverflowDropNewest
+	// OverflowDropOldest drops the message the actor would process next to make
+	// room. A caller waiting for the dropped message's response receives
+	// ErrMailboxFull. RunPriority doesn't support it, since the next message is
+	// the highest-priority one; with RunWithInbox it drops whatever the inbox
+	// delivers next.
+	OverflowDropOldest
+	// OverflowReject fails the send with ErrMailboxFull.
+	OverflowReject
+	// OverflowSpill moves messages that don't fit into an unbounded overflow
+	// buffer that is fed into the mailbox, in order, as it drains. Senders never
+	// block, at the cost of unbounded memory use.
+	OverflowSpill
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowDropNewest:
+		return "drop_newest"
+	case OverflowDropOldest:
+		return "drop_oldest"
+	case OverflowReject:
+		return "reject"
+	case OverflowSpill:
+		return "spill"
+	default:
+		return "unknown"
+	}
+}
+
+// RunOption configures an actor started with Run, RunPriority or RunWithInbox.
+type RunOption func(*runOptions)
+
+// runOptions holds the configuration set by RunOptions.
+type runOptions struct {
+	overflow OverflowPolicy
+}
+
+// WithOverflowPolicy sets what happens to messages sent while the mailbox is
+// full. A mailbox is full when it holds as many messages as its depth (Run) or
+// maxSize (RunPriority); an unbuffered mailbox is full whenever the actor is
+// busy, and an unbounded one is never full. For RunWithInbox the mailbox is
+// considered full whenever the inbox does not accept a message immediately.
+func WithOverflowPolicy(policy OverflowPolicy) RunOption {
+	return func(o *runOptions) {
+		o.overflow = policy
+	}
+}
+
+// spillQueue is the overflow buffer of an actor using OverflowSpill.
+type spillQueue[Request, Response any] struct {
+	mu       sync.Mutex
+	messages []Message[Request, Response]
+	draining bool
+}
+
+// deliver sends a message to the inbox according to the overflow policy.
+// capacity is the mailbox size: negative for unbounded, 0 for unbuffered.
+func (r *Ref[Request, Response]) deliver(ctx context.Context, message Message[Request, Response]) error {
+	if r.overflow == OverflowBlock || r.capacity < 0 {
+		return r.send(ctx, message)
+	}
+
+	if r.overflow == OverflowSpill {
+		r.spill(ctx, message)
+
+		return nil
+	}
+
+	for {
+		if r.trySend(ctx, message) {
+			return nil
+		}
+
+		overflowedMessages.WithLabelValues(logger.GetSubsystem(ctx), r.name, r.overflow.String()).Inc()
+
+		switch r.overflow {
+		case OverflowDropNewest:
+			publishDeadLetter(ctx, r.name, message.Request, DeadLetterDropped, ErrMailboxFull)
+
+			if message.ResponseChan != nil {
+				return ErrMailboxFull
+			}
+
+			return nil
+		case OverflowDropOldest:
+			dropped, err := r.dropOldest(ctx)
+			if err != nil {
+				return err
+			}
+
+			if !dropped {
+				// Nothing queued to drop (the mailbox is unbuffered), so wait for the actor
+				return r.send(ctx, message)
+			}
+		default:
+			publishDeadLetter(ctx, r.name, message.Request, DeadLetterRejected, ErrMailboxFull)
+
+			return ErrMailboxFull
+		}
+	}
+}
+
+// send blocks until the message is in the inbox or ctx is done.
+func (r *Ref[Request, Response]) send(ctx context.Context, message Message[Request, Response]) error {
+	select {
+	case <-ctx.Done():
+		publishDeadLetter(ctx, r.name, message.Request, DeadLetterCanceled, ctx.Err())
+
+		return ctx.Err()
+	case r.inboxWrite <- message:
+		return nil
+	}
+}
+
+// trySend puts the message in the inbox if there is room. Inboxes fed by a
+// pump goroutine (RunPriority) may not accept a message immediately even when
+// they have room, so if the count says there is room it waits for the pump,
+// for at most pumpHandoffWait. The count may lag behind, so a pump that doesn't
+// take the message by then is treated as full.
+func (r *Ref[Request, Response]) trySend(ctx context.Context, message Message[Request, Response]) bool {
+	select {
+	case r.inboxWrite <- message:
+		return true
+	default:
+	}
+
+	if r.capacity == 0 || r.getCount() >= r.capacity || ctx.Err() != nil || r.dead.Load() {
+		return false
+	}
+
+	timer := time.NewTimer(pumpHandoffWait)
+	defer timer.Stop()
+
+	select {
+	case r.inboxWrite <- message:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return false
+	}
+}
+
+// checkPriorityOverflow panics if policy is not supported by priority inboxes.
+func checkPriorityOverflow(opts []RunOption) {
+	var options runOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.overflow == OverflowDropOldest {
+		panic(fmt.Errorf("%w: %s with RunPriority", ErrUnsupportedOverflowPolicy, options.overflow))
+	}
+}
+
+// dropOldest removes the next message from the inbox to make room. It reports
+// whether a message was dropped, or ErrDeadActor if the inbox is closed.
+func (r *Ref[Request, Response]) dropOldest(ctx context.Context) (bool, error) {
+	select {
+	case oldest, ok := <-r.inboxRead:
+		if !ok {
+			return false, ErrDeadActor
+		}
+
+		publishDeadLetter(ctx, r.name, oldest.Request, DeadLetterDropped, ErrMailboxFull)
+
+		// The caller is blocked waiting for a response; don't make the sender wait for it
+		go informCaller(context.WithoutCancel(ctx), oldest, ErrMailboxFull)
+
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// spill queues the message behind any spilled messages, or delivers it
+// directly if there are none and the mailbox has room.
+func (r *Ref[Request, Response]) spill(ctx context.Context, message Message[Request, Response]) {
+	r.spilled.mu.Lock()
+	defer r.spilled.mu.Unlock()
+
+	if !r.spilled.draining {
+		select {
+		case r.inboxWrite <- message:
+			return
+		default:
+		}
+	}
+
+	overflowedMessages.WithLabelValues(logger.GetSubsystem(ctx), r.name, r.overflow.String()).Inc()
+
+	r.spilled.messages = append(r.spilled.messages, message)
+
+	if !r.spilled.draining {
+		r.spilled.draining = true
+
+		go r.drainSpill(context.WithoutCancel(ctx))
+	}
+}
+
+// drainSpill feeds spilled messages into the inbox in order until none are left.
+func (r *Ref[Request, Response]) drainSpill(ctx context.Context) {
+	for {
+		r.spilled.mu.Lock()
+
+		if len(r.spilled.messages) == 0 {
+			r.spilled.draining = false
+			r.spilled.mu.Unlock()
+
+			return
+		}
+
+		message := r.spilled.messages[0]
+		r.spilled.mu.Unlock()
+
+		if !r.sendSpilled(message) {
+			// The actor stopped; everything still spilled is undeliverable
+			r.spilled.mu.Lock()
+			undeliverable := r.spilled.messages
+			r.spilled.messages = nil
+			r.spilled.draining = false
+			r.spilled.mu.Unlock()
+
+			for _, msg := range undeliverable {
+				publishDeadLetter(ctx, r.name, msg.Request, DeadLetterDeadActor, ErrDeadActor)
+				informCaller(ctx, msg, ErrDeadActor)
+			}
+
+			return
+		}
+
+		r.spilled.mu.Lock()
+		r.spilled.messages = r.spilled.messages[1:]
+		r.spilled.mu.Unlock()
+	}
+}
+
+// sendSpilled blocks until the message is in the inbox. It returns false if the inbox was closed.
+func (r *Ref[Request, Response]) sendSpilled(message Message[Request, Response]) (ok bool) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			ok = false
+		}
+	}()
+
+	r.inboxWrite <- message
+
+	return true
+}
@@ -0,0 +1,246 @@
+package actor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/amp-common/try"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedActor records the requests it processes. It blocks on the gate before
+// processing each request, so tests can fill its mailbox.
+type gatedActor struct {
+	gate      chan struct{}
+	started   chan int
+	mu        sync.Mutex
+	processed []int
+}
+
+func newGatedActor() *gatedActor {
+	return &gatedActor{
+		gate:    make(chan struct{}),
+		started: make(chan int, 100),
+	}
+}
+
+func (g *gatedActor) actor() *Actor[int, int] {
+	return New[int, int](func(ref *Ref[int, int]) Processor[int, int] {
+		return SimpleProcessor(func(req int) (int, error) {
+			g.started <- req
+			<-g.gate
+
+			g.mu.Lock()
+			g.processed = append(g.processed, req)
+			g.mu.Unlock()
+
+			return req, nil
+		})
+	})
+}
+
+// fill starts the actor on the first request and queues the rest.
+func (g *gatedActor) fill(t *testing.T, ref *Ref[int, int], requests ...int) {
+	t.Helper()
+
+	require.NoError(t, ref.submit(t.Context(), Message[int, int]{Request: requests[0]}))
+	assert.Equal(t, requests[0], <-g.started)
+
+	for _, req := range requests[1:] {
+		require.NoError(t, ref.submit(t.Context(), Message[int, int]{Request: req}))
+	}
+}
+
+// finish lets the actor process everything that is queued and returns the processed requests.
+func (g *gatedActor) finish(ref *Ref[int, int]) []int {
+	close(g.gate)
+	ref.Stop()
+	ref.Wait()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.processed
+}
+
+func lettersFor(letters []DeadLetter, actor string) []DeadLetter {
+	var matching []DeadLetter
+
+	for _, letter := range letters {
+		if letter.Actor == actor {
+			matching = append(matching, letter)
+		}
+	}
+
+	return matching
+}
+
+//nolint:paralleltest // Tests install the process-wide dead letter handler
+func TestMailboxOverflowAndDeadLetters(t *testing.T) {
+	queue := NewDeadLetterQueue(100)
+
+	SetDeadLetterHandler(queue.Handle)
+	t.Cleanup(func() { SetDeadLetterHandler(nil) })
+
+	t.Run("reject", func(t *testing.T) {
+		gated := newGatedActor()
+		ref := gated.actor().Run(t.Context(), "reject", 1, WithOverflowPolicy(OverflowReject))
+
+		gated.fill(t, ref, 1, 2)
+
+		err := ref.submit(t.Context(), Message[int, int]{Request: 3})
+		require.ErrorIs(t, err, ErrMailboxFull)
+
+		_, err = ref.RequestCtx(t.Context(), 4)
+		require.ErrorIs(t, err, ErrMailboxFull)
+
+		assert.Equal(t, []int{1, 2}, gated.finish(ref))
+
+		letters := lettersFor(queue.Drain(), "reject")
+		require.Len(t, letters, 2)
+		assert.Equal(t, DeadLetterRejected, letters[0].Reason)
+		assert.Equal(t, 3, letters[0].Request)
+		require.ErrorIs(t, letters[0].Err, ErrMailboxFull)
+	})
+
+	t.Run("drop newest", func(t *testing.T) {
+		gated := newGatedActor()
+		ref := gated.actor().Run(t.Context(), "drop_newest", 1, WithOverflowPolicy(OverflowDropNewest))
+
+		gated.fill(t, ref, 1, 2, 3)
+
+		// A request can't be answered once dropped, so the caller is told
+		_, err := ref.RequestCtx(t.Context(), 4)
+		require.ErrorIs(t, err, ErrMailboxFull)
+
+		assert.Equal(t, []int{1, 2}, gated.finish(ref))
+
+		letters := lettersFor(queue.Drain(), "drop_newest")
+		require.Len(t, letters, 2)
+		assert.Equal(t, DeadLetterDropped, letters[0].Reason)
+		assert.Equal(t, 3, letters[0].Request)
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		gated := newGatedActor()
+		ref := gated.actor().Run(t.Context(), "drop_oldest", 2, WithOverflowPolicy(OverflowDropOldest))
+
+		responses := make(chan try.Try[int])
+
+		gated.fill(t, ref, 1)
+		require.NoError(t, ref.submit(t.Context(), Message[int, int]{Request: 2, ResponseChan: responses}))
+		require.NoError(t, ref.submit(t.Context(), Message[int, int]{Request: 3}))
+		require.NoError(t, ref.submit(t.Context(), Message[int, int]{Request: 4}))
+
+		// The waiting caller of the dropped request learns about it
+		response := <-responses
+		require.ErrorIs(t, response.Error, ErrMailboxFull)
+
+		assert.Equal(t, []int{1, 3, 4}, gated.finish(ref))
+
+		letters := lettersFor(queue.Drain(), "drop_oldest")
+		require.Len(t, letters, 1)
+		assert.Equal(t, 2, letters[0].Request)
+	})
+
+	t.Run("spill", func(t *testing.T) {
+		gated := newGatedActor()
+		ref := gated.actor().Run(t.Context(), "spill", 1, WithOverflowPolicy(OverflowSpill))
+
+		gated.fill(t, ref, 1, 2, 3, 4, 5)
+
+		close(gated.gate)
+
+		require.Eventually(t, func() bool {
+			gated.mu.Lock()
+			defer gated.mu.Unlock()
+
+			return len(gated.processed) == 5
+		}, time.Second, time.Millisecond)
+
+		ref.Stop()
+		ref.Wait()
+
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, gated.processed)
+		assert.Empty(t, lettersFor(queue.Drain(), "spill"))
+	})
+
+	t.Run("priority reject", func(t *testing.T) {
+		gated := newGatedActor()
+		ref := gated.actor().RunPriority(t.Context(), "priority_reject", 2, WithOverflowPolicy(OverflowReject))
+
+		gated.fill(t, ref, 1, 2, 3)
+
+		err := ref.submit(t.Context(), Message[int, int]{Request: 4, Weight: 10})
+		require.ErrorIs(t, err, ErrMailboxFull)
+
+		assert.Equal(t, []int{1, 2, 3}, gated.finish(ref))
+		assert.Len(t, lettersFor(queue.Drain(), "priority_reject"), 1)
+	})
+
+	t.Run("priority drop oldest is unsupported", func(t *testing.T) {
+		gated := newGatedActor()
+
+		assert.PanicsWithError(t, "overflow policy is not supported by this mailbox: drop_oldest with RunPriority", func() {
+			gated.actor().RunPriority(t.Context(), "priority_drop_oldest", 2, WithOverflowPolicy(OverflowDropOldest))
+		})
+	})
+
+	t.Run("dead actor, canceled and panic", func(t *testing.T) {
+		gated := newGatedActor()
+		ref := gated.actor().Run(t.Context(), "blocked", 0)
+
+		gated.fill(t, ref, 1)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+
+		require.ErrorIs(t, ref.submit(ctx, Message[int, int]{Request: 2}), context.DeadlineExceeded)
+
+		gated.finish(ref)
+		require.ErrorIs(t, ref.submit(t.Context(), Message[int, int]{Request: 3}), ErrDeadActor)
+
+		panicking := New[int, int](func(ref *Ref[int, int]) Processor[int, int] {
+			return NewProcessor(func(msg Message[int, int]) {
+				panic("boom")
+			})
+		}).Run(t.Context(), "panicking", 1)
+		defer panicking.Stop()
+
+		_, err := panicking.Request(4)
+		require.ErrorIs(t, err, ErrActorPanic)
+
+		letters := queue.Drain()
+
+		blocked := lettersFor(letters, "blocked")
+		require.Len(t, blocked, 2)
+		assert.Equal(t, DeadLetterCanceled, blocked[0].Reason)
+		assert.Equal(t, DeadLetterDeadActor, blocked[1].Reason)
+
+		panicked := lettersFor(letters, "panicking")
+		require.Len(t, panicked, 1)
+		assert.Equal(t, DeadLetterPanic, panicked[0].Reason)
+		assert.Equal(t, 4, panicked[0].Request)
+		require.ErrorIs(t, panicked[0].Err, ErrActorPanic)
+	})
+}
+
+func TestDeadLetterQueueCapacity(t *testing.T) {
+	t.Parallel()
+
+	queue := NewDeadLetterQueue(2)
+
+	for i := range 3 {
+		queue.Handle(t.Context(), DeadLetter{Request: i})
+	}
+
+	assert.Equal(t, 2, queue.Len())
+
+	letters := queue.Drain()
+	assert.Equal(t, 1, letters[0].Request)
+	assert.Equal(t, 2, letters[1].Request)
+	assert.Equal(t, 0, queue.Len())
+}
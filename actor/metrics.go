@@ -110,4 +110,22 @@ var (
 			600,  // 10m
 		},
 	}, []string{"subsystem", "actor"})
+
+	// supervisorRestarts counts the number of times a supervisor restarted a child.
+	supervisorRestarts = promauto.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "actor_supervisor_restarts",
+		Help: "The total number of children restarted by a supervisor",
+	}, []string{"subsystem", "supervisor", "actor"})
+
+	// deadLetters counts messages that could not be delivered or whose processing panicked.
+	deadLetters = promauto.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "actor_dead_letters",
+		Help: "The total number of messages that could not be delivered or whose processing panicked",
+	}, []string{"subsystem", "actor", "reason"})
+
+	// overflowedMessages counts messages sent while the actor's mailbox was full.
+	overflowedMessages = promauto.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "actor_overflowed_messages",
+		Help: "The total number of messages sent to a full mailbox, by overflow policy",
+	}, []string{"subsystem", "actor", "policy"})
 )
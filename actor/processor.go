@@ -1,6 +1,7 @@
 package actor
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/amp-labs/amp-common/try"
@@ -12,6 +13,12 @@ type Processor[Request, Response any] interface {
 	Process(msg Message[Request, Response])
 }
 
+// stopper is implemented by processors that hold on to messages across calls
+// to Process. The actor calls stop once it has stopped processing messages.
+type stopper interface {
+	stop(ctx context.Context)
+}
+
 // processor is a simple implementation of Processor that wraps a function.
 type processor[Request, Response any] struct {
 	process func(Message[Request, Response])
@@ -0,0 +1,565 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/amp-labs/amp-common/logger"
+	"github.com/amp-labs/amp-common/retry"
+)
+
+const (
+	// defaultMaxRestarts is the restart intensity used when SupervisorSpec.MaxRestarts is zero.
+	defaultMaxRestarts = 3
+	// defaultRestartWindow is the restart period used when SupervisorSpec.Within is zero.
+	defaultRestartWindow = 5 * time.Second
+)
+
+var (
+	// ErrRestartIntensity is returned when a supervisor gives up because its children
+	// restarted more often than its restart intensity allows.
+	ErrRestartIntensity = errors.New("restart intensity exceeded")
+	// ErrSupervisorStopped is returned when adding a child to a stopped supervisor.
+	ErrSupervisorStopped = errors.New("supervisor is stopped")
+	// ErrDuplicateChild is returned when adding a child whose name is already in use.
+	ErrDuplicateChild = errors.New("duplicate child name")
+)
+
+// Strategy determines which children a supervisor restarts when one of them fails.
+type Strategy int
+
+const (
+	// OneForOne restarts only the child that stopped.
+	OneForOne Strategy = iota
+	// OneForAll stops all other children and restarts every child.
+	OneForAll
+	// RestForOne restarts the child that stopped and every child added after it.
+	RestForOne
+)
+
+func (s Strategy) String() string {
+	switch s {
+	case OneForOne:
+		return "one_for_one"
+	case OneForAll:
+		return "one_for_all"
+	case RestForOne:
+		return "rest_for_one"
+	default:
+		return fmt.Sprintf("Strategy(%d)", int(s))
+	}
+}
+
+// RestartPolicy determines whether a child is restarted when it stops.
+type RestartPolicy int
+
+const (
+	// Permanent children are always restarted.
+	Permanent RestartPolicy = iota
+	// Transient children are restarted only when they crash (their processor panicked).
+	Transient
+	// Temporary children are never restarted.
+	Temporary
+)
+
+// SupervisorSpec configures a Supervisor.
+type SupervisorSpec struct {
+	// Strategy selects which children are restarted when one fails.
+	Strategy Strategy
+	// MaxRestarts is the number of restarts allowed within Within before the
+	// supervisor gives up, stops all children and fails with ErrRestartIntensity.
+	// Defaults to 3.
+	MaxRestarts int
+	// Within is the period over which restarts are counted. Defaults to 5s.
+	Within time.Duration
+	// Backoff, if set, delays each restart. It is called with the number of
+	// restarts already counted within the period, so delays grow while a child
+	// keeps failing.
+	Backoff retry.Backoff
+}
+
+// ChildKind tells workers and supervisors apart in a supervision tree.
+type ChildKind string
+
+const (
+	// WorkerKind is a supervised actor.
+	WorkerKind ChildKind = "worker"
+	// SupervisorKind is a nested supervisor.
+	SupervisorKind ChildKind = "supervisor"
+)
+
+// TreeNode describes a supervisor or one of its children, for introspection.
+type TreeNode struct {
+	Name     string
+	Kind     ChildKind
+	Alive    bool
+	Restarts int        // number of times the node was restarted by its supervisor
+	Children []TreeNode // children of a supervisor, in start order
+}
+
+// supervisable is a child that a supervisor can start and stop.
+type supervisable interface {
+	childName() string
+	restartPolicy() RestartPolicy
+	// start launches a new incarnation of the child. The returned function
+	// blocks until that incarnation stops and returns why it stopped.
+	start(ctx context.Context) func() error
+	// stop stops the current incarnation and waits for it.
+	stop()
+	node() TreeNode
+}
+
+// childEntry is the supervisor's bookkeeping for one child.
+type childEntry struct {
+	child       supervisable
+	incarnation uint64
+	restarts    int
+}
+
+// childExit reports that an incarnation of a child stopped.
+type childExit struct {
+	entry       *childEntry
+	incarnation uint64
+	err         error
+}
+
+// supervisorRun is one incarnation of a supervisor.
+type supervisorRun struct {
+	ctx      context.Context //nolint:containedctx
+	cancel   context.CancelFunc
+	exits    chan childExit
+	done     chan struct{}
+	err      error
+	restarts []time.Time
+}
+
+// Supervisor owns a group of actors and restarts them when they stop,
+// following Erlang/OTP's supervision principles. Supervisors can supervise
+// other supervisors, forming a tree.
+//
+// A supervised actor crashes when its processor panics: unlike an unsupervised
+// actor, it stops instead of carrying on with the next message, and its
+// supervisor restarts it with a fresh processor.
+type Supervisor struct {
+	name     string
+	spec     SupervisorSpec
+	policy   RestartPolicy
+	mu       sync.Mutex
+	children []*childEntry
+	run      *supervisorRun
+}
+
+// NewSupervisor creates and starts a supervisor. It runs until ctx is canceled,
+// Stop is called or its restart intensity is exceeded. Add children with
+// Supervise and NewChildSupervisor.
+func NewSupervisor(ctx context.Context, name string, spec SupervisorSpec) *Supervisor {
+	sup := newSupervisor(name, spec, Permanent)
+	sup.start(ctx)
+
+	return sup
+}
+
+func newSupervisor(name string, spec SupervisorSpec, policy RestartPolicy) *Supervisor {
+	if spec.MaxRestarts <= 0 {
+		spec.MaxRestarts = defaultMaxRestarts
+	}
+
+	if spec.Within <= 0 {
+		spec.Within = defaultRestartWindow
+	}
+
+	return &Supervisor{
+		name:   name,
+		spec:   spec,
+		policy: policy,
+	}
+}
+
+// Name returns the supervisor's name.
+func (s *Supervisor) Name() string {
+	return s.name
+}
+
+// NewChildSupervisor adds a supervisor as a child of s and starts it. When the
+// child supervisor gives up, s handles that like any other crashed child.
+func (s *Supervisor) NewChildSupervisor(name string, spec SupervisorSpec, policy RestartPolicy) (*Supervisor, error) {
+	child := newSupervisor(name, spec, policy)
+
+	err := s.add(child)
+	if err != nil {
+		return nil, err
+	}
+
+	return child, nil
+}
+
+// Supervise adds an actor to the supervisor and starts it with Run, using
+// depth as the mailbox size. The policy decides whether it is restarted.
+func Supervise[Request, Response any](
+	sup *Supervisor,
+	name string,
+	actor *Actor[Request, Response],
+	depth int,
+	policy RestartPolicy,
+) (*Child[Request, Response], error) {
+	child := &Child[Request, Response]{
+		name:   name,
+		depth:  depth,
+		policy: policy,
+		actor: &Actor[Request, Response]{
+			factory:     actor.factory,
+			stopOnPanic: true,
+		},
+	}
+
+	err := sup.add(child)
+	if err != nil {
+		return nil, err
+	}
+
+	return child, nil
+}
+
+// Stop stops all children, in reverse start order, and then the supervisor.
+// It is safe to call multiple times.
+func (s *Supervisor) Stop() {
+	s.stop()
+}
+
+// Wait blocks until the supervisor has stopped and returns an error wrapping
+// ErrRestartIntensity if it gave up, or nil otherwise.
+func (s *Supervisor) Wait() error {
+	s.mu.Lock()
+	run := s.run
+	s.mu.Unlock()
+
+	if run == nil {
+		return nil
+	}
+
+	<-run.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return run.err
+}
+
+// Tree returns a snapshot of the supervision tree rooted at s.
+func (s *Supervisor) Tree() TreeNode {
+	return s.node()
+}
+
+func (s *Supervisor) add(child supervisable) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.run == nil || s.run.ctx.Err() != nil {
+		return fmt.Errorf("%w: %s", ErrSupervisorStopped, s.name)
+	}
+
+	for _, entry := range s.children {
+		if entry.child.childName() == child.childName() {
+			return fmt.Errorf("%w: %s", ErrDuplicateChild, child.childName())
+		}
+	}
+
+	entry := &childEntry{child: child}
+	s.children = append(s.children, entry)
+	s.startChild(s.run, entry)
+
+	return nil
+}
+
+// startChild starts a new incarnation of a child and watches for it to stop. Callers hold s.mu.
+func (s *Supervisor) startChild(run *supervisorRun, entry *childEntry) {
+	entry.incarnation++
+	incarnation := entry.incarnation
+	wait := entry.child.start(run.ctx)
+
+	go func() {
+		err := wait()
+
+		select {
+		case run.exits <- childExit{entry: entry, incarnation: incarnation, err: err}:
+		case <-run.done:
+		}
+	}()
+}
+
+func (s *Supervisor) childName() string {
+	return s.name
+}
+
+func (s *Supervisor) restartPolicy() RestartPolicy {
+	return s.policy
+}
+
+func (s *Supervisor) start(ctx context.Context) func() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	run := &supervisorRun{
+		ctx:    runCtx,
+		cancel: cancel,
+		exits:  make(chan childExit),
+		done:   make(chan struct{}),
+	}
+
+	s.run = run
+
+	// A restarted supervisor restarts all of its children
+	for _, entry := range s.children {
+		s.startChild(run, entry)
+	}
+
+	go s.loop(run)
+
+	return func() error {
+		<-run.done
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		return run.err
+	}
+}
+
+func (s *Supervisor) stop() {
+	s.mu.Lock()
+	run := s.run
+	s.mu.Unlock()
+
+	if run == nil {
+		return
+	}
+
+	run.cancel()
+	<-run.done
+}
+
+func (s *Supervisor) node() TreeNode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	node := TreeNode{
+		Name:     s.name,
+		Kind:     SupervisorKind,
+		Alive:    s.run != nil && s.run.ctx.Err() == nil,
+		Children: make([]TreeNode, 0, len(s.children)),
+	}
+
+	for _, entry := range s.children {
+		child := entry.child.node()
+		child.Restarts = entry.restarts
+		node.Children = append(node.Children, child)
+	}
+
+	return node
+}
+
+// loop handles child exits until the supervisor stops.
+func (s *Supervisor) loop(run *supervisorRun) {
+	defer close(run.done)
+
+	for {
+		select {
+		case <-run.ctx.Done():
+			s.mu.Lock()
+			s.stopChildren(s.children)
+			s.mu.Unlock()
+
+			return
+		case exit := <-run.exits:
+			err := s.handleExit(run, exit)
+			if err != nil {
+				s.mu.Lock()
+				run.err = err
+				s.stopChildren(s.children)
+				s.mu.Unlock()
+
+				run.cancel()
+
+				return
+			}
+		}
+	}
+}
+
+// handleExit applies the restart policy and strategy to a stopped child. It
+// returns an error if the restart intensity was exceeded.
+func (s *Supervisor) handleExit(run *supervisorRun, exit childExit) error {
+	s.mu.Lock()
+
+	index := slices.Index(s.children, exit.entry)
+	if index < 0 || exit.entry.incarnation != exit.incarnation || run.ctx.Err() != nil {
+		// The child was removed or restarted already, or we are shutting down
+		s.mu.Unlock()
+
+		return nil
+	}
+
+	if !shouldRestart(exit.entry.child.restartPolicy(), exit.err) {
+		s.children = slices.Delete(s.children, index, index+1)
+		s.mu.Unlock()
+
+		return nil
+	}
+
+	now := time.Now()
+	run.restarts = slices.DeleteFunc(run.restarts, func(t time.Time) bool {
+		return now.Sub(t) >= s.spec.Within
+	})
+
+	if len(run.restarts) >= s.spec.MaxRestarts {
+		s.mu.Unlock()
+
+		return fmt.Errorf("%w: supervisor %s: %d restarts within %s, last exit of %s: %w",
+			ErrRestartIntensity, s.name, len(run.restarts), s.spec.Within, exit.entry.child.childName(), exit.err)
+	}
+
+	var delay time.Duration
+	if s.spec.Backoff != nil {
+		delay = s.spec.Backoff.Delay(uint(len(run.restarts))) //nolint:gosec
+	}
+
+	run.restarts = append(run.restarts, now)
+	s.mu.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-run.ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Children may have been added or removed while we waited
+	index = slices.Index(s.children, exit.entry)
+	if index < 0 || run.ctx.Err() != nil {
+		return nil
+	}
+
+	var affected []*childEntry
+
+	switch s.spec.Strategy {
+	case OneForAll:
+		affected = slices.Clone(s.children)
+	case RestForOne:
+		affected = slices.Clone(s.children[index:])
+	default:
+		affected = []*childEntry{exit.entry}
+	}
+
+	// The failed child has already stopped; stopping it again is a no-op
+	s.stopChildren(affected)
+
+	subsystem := logger.GetSubsystem(run.ctx)
+
+	for _, entry := range affected {
+		entry.restarts++
+		supervisorRestarts.WithLabelValues(subsystem, s.name, entry.child.childName()).Inc()
+		s.startChild(run, entry)
+	}
+
+	return nil
+}
+
+// stopChildren stops children in reverse order. Their exits become stale and are
+// ignored because the next incarnation (if any) gets a new incarnation number.
+// Callers hold s.mu.
+func (s *Supervisor) stopChildren(children []*childEntry) {
+	for _, entry := range slices.Backward(children) {
+		entry.incarnation++
+		entry.child.stop()
+	}
+}
+
+// shouldRestart reports whether a child with the given policy is restarted after stopping with err.
+func shouldRestart(policy RestartPolicy, err error) bool {
+	switch policy {
+	case Permanent:
+		return true
+	case Transient:
+		return err != nil
+	default:
+		return false
+	}
+}
+
+// Child is an actor owned by a Supervisor. Every restart starts a new actor,
+// so look up the current Ref with Ref instead of holding on to it.
+type Child[Request, Response any] struct {
+	name   string
+	actor  *Actor[Request, Response]
+	depth  int
+	policy RestartPolicy
+	mu     sync.Mutex
+	ref    *Ref[Request, Response]
+}
+
+// Name returns the child's name.
+func (c *Child[Request, Response]) Name() string {
+	return c.name
+}
+
+// Ref returns the reference to the child's current incarnation.
+func (c *Child[Request, Response]) Ref() *Ref[Request, Response] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ref
+}
+
+func (c *Child[Request, Response]) childName() string {
+	return c.name
+}
+
+func (c *Child[Request, Response]) restartPolicy() RestartPolicy {
+	return c.policy
+}
+
+func (c *Child[Request, Response]) start(ctx context.Context) func() error {
+	ref := c.actor.Run(ctx, c.name, c.depth)
+
+	c.mu.Lock()
+	c.ref = ref
+	c.mu.Unlock()
+
+	return func() error {
+		ref.Wait()
+
+		return ref.Err()
+	}
+}
+
+func (c *Child[Request, Response]) stop() {
+	ref := c.Ref()
+	if ref == nil {
+		return
+	}
+
+	ref.Stop()
+	ref.Wait()
+}
+
+func (c *Child[Request, Response]) node() TreeNode {
+	ref := c.Ref()
+
+	return TreeNode{
+		Name:  c.name,
+		Kind:  WorkerKind,
+		Alive: ref != nil && ref.Alive(),
+	}
+}
@@ -0,0 +1,276 @@
+package actor
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/amp-common/retry"
+	"github.com/amp-labs/amp-common/try"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const crashRequest = -1
+
+// newCrashingActor returns an actor that echoes requests and panics on crashRequest.
+// starts counts how many processors were created, i.e. how often it was (re)started.
+func newCrashingActor(starts *atomic.Int32) *Actor[int, int] {
+	return New[int, int](func(ref *Ref[int, int]) Processor[int, int] {
+		starts.Add(1)
+
+		return SimpleProcessor(func(req int) (int, error) {
+			if req == crashRequest {
+				panic("crash requested")
+			}
+
+			return req, nil
+		})
+	})
+}
+
+// crash makes a supervised child panic and waits for the crashed incarnation to stop.
+func crash(t *testing.T, child *Child[int, int]) *Ref[int, int] {
+	t.Helper()
+
+	ref := child.Ref()
+
+	_, err := ref.Request(crashRequest)
+	require.ErrorIs(t, err, ErrActorPanic)
+
+	ref.Wait()
+	require.ErrorIs(t, ref.Err(), ErrActorPanic)
+
+	return ref
+}
+
+// waitRestarted waits until the child runs a new incarnation.
+func waitRestarted(t *testing.T, child *Child[int, int], old *Ref[int, int]) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		ref := child.Ref()
+
+		return ref != old && ref.Alive()
+	}, time.Second, time.Millisecond)
+
+	result, err := child.Ref().Request(7)
+	require.NoError(t, err)
+	assert.Equal(t, 7, result)
+}
+
+func TestSupervisorStrategies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		strategy  Strategy
+		restarted []bool // for children a, b and c after b crashes
+	}{
+		{OneForOne, []bool{false, true, false}},
+		{OneForAll, []bool{true, true, true}},
+		{RestForOne, []bool{false, true, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy.String(), func(t *testing.T) {
+			t.Parallel()
+
+			sup := NewSupervisor(t.Context(), "root", SupervisorSpec{Strategy: tt.strategy})
+			defer sup.Stop()
+
+			starts := make([]*atomic.Int32, 3)
+			children := make([]*Child[int, int], 3)
+
+			for i, name := range []string{"a", "b", "c"} {
+				starts[i] = &atomic.Int32{}
+
+				child, err := Supervise(sup, name, newCrashingActor(starts[i]), 1, Permanent)
+				require.NoError(t, err)
+
+				children[i] = child
+			}
+
+			old := crash(t, children[1])
+			waitRestarted(t, children[1], old)
+
+			tree := sup.Tree()
+			require.Len(t, tree.Children, 3)
+
+			for i, restarted := range tt.restarted {
+				expected := int32(1)
+				if restarted {
+					expected = 2
+				}
+
+				require.Eventually(t, func() bool { return starts[i].Load() == expected }, time.Second, time.Millisecond)
+				assert.True(t, children[i].Ref().Alive())
+				assert.Equal(t, int(expected-1), sup.Tree().Children[i].Restarts)
+			}
+		})
+	}
+}
+
+func TestSupervisorRestartPolicies(t *testing.T) {
+	t.Parallel()
+
+	sup := NewSupervisor(t.Context(), "root", SupervisorSpec{})
+	defer sup.Stop()
+
+	var starts atomic.Int32
+
+	// A transient child that stops normally is not restarted
+	transient, err := Supervise(sup, "transient", newCrashingActor(&starts), 1, Transient)
+	require.NoError(t, err)
+
+	transient.Ref().Stop()
+	transient.Ref().Wait()
+
+	// A temporary child is not restarted even if it crashes
+	temporary, err := Supervise(sup, "temporary", newCrashingActor(&starts), 1, Temporary)
+	require.NoError(t, err)
+
+	crash(t, temporary)
+
+	// A permanent child is restarted even if it stops normally
+	permanent, err := Supervise(sup, "permanent", newCrashingActor(&starts), 1, Permanent)
+	require.NoError(t, err)
+
+	old := permanent.Ref()
+	old.Stop()
+	waitRestarted(t, permanent, old)
+
+	require.Eventually(t, func() bool { return len(sup.Tree().Children) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, "permanent", sup.Tree().Children[0].Name)
+
+	_, err = Supervise(sup, "permanent", newCrashingActor(&starts), 1, Permanent)
+	require.ErrorIs(t, err, ErrDuplicateChild)
+}
+
+func TestSupervisorRestartIntensity(t *testing.T) {
+	t.Parallel()
+
+	sup := NewSupervisor(t.Context(), "root", SupervisorSpec{MaxRestarts: 2, Within: time.Minute})
+
+	var starts atomic.Int32
+
+	child, err := Supervise(sup, "flaky", newCrashingActor(&starts), 1, Permanent)
+	require.NoError(t, err)
+
+	for range 2 {
+		old := crash(t, child)
+		waitRestarted(t, child, old)
+	}
+
+	crash(t, child)
+
+	require.ErrorIs(t, sup.Wait(), ErrRestartIntensity)
+	assert.Equal(t, int32(3), starts.Load())
+	assert.False(t, sup.Tree().Alive)
+
+	_, err = Supervise(sup, "late", newCrashingActor(&starts), 1, Permanent)
+	require.ErrorIs(t, err, ErrSupervisorStopped)
+}
+
+func TestSupervisorBackoff(t *testing.T) {
+	t.Parallel()
+
+	const delay = 50 * time.Millisecond
+
+	sup := NewSupervisor(t.Context(), "root", SupervisorSpec{
+		Backoff: retry.ExpBackoff{Base: delay, Max: time.Second, Factor: 2},
+	})
+	defer sup.Stop()
+
+	var starts atomic.Int32
+
+	child, err := Supervise(sup, "slow", newCrashingActor(&starts), 1, Permanent)
+	require.NoError(t, err)
+
+	begin := time.Now()
+	old := crash(t, child)
+	waitRestarted(t, child, old)
+
+	assert.GreaterOrEqual(t, time.Since(begin), delay)
+}
+
+func TestSupervisorTree(t *testing.T) {
+	t.Parallel()
+
+	root := NewSupervisor(t.Context(), "root", SupervisorSpec{Strategy: OneForOne, MaxRestarts: 5, Within: time.Minute})
+	defer root.Stop()
+
+	var starts atomic.Int32
+
+	_, err := Supervise(root, "worker", newCrashingActor(&starts), 1, Permanent)
+	require.NoError(t, err)
+
+	nested, err := root.NewChildSupervisor("nested", SupervisorSpec{MaxRestarts: 1, Within: time.Minute}, Permanent)
+	require.NoError(t, err)
+
+	leaf, err := Supervise(nested, "leaf", newCrashingActor(&starts), 1, Permanent)
+	require.NoError(t, err)
+
+	assert.Equal(t, TreeNode{
+		Name:  "root",
+		Kind:  SupervisorKind,
+		Alive: true,
+		Children: []TreeNode{
+			{Name: "worker", Kind: WorkerKind, Alive: true},
+			{Name: "nested", Kind: SupervisorKind, Alive: true, Children: []TreeNode{
+				{Name: "leaf", Kind: WorkerKind, Alive: true},
+			}},
+		},
+	}, root.Tree())
+
+	// The nested supervisor gives up on the second crash and is restarted by the root
+	old := crash(t, leaf)
+	waitRestarted(t, leaf, old)
+
+	old = crash(t, leaf)
+	waitRestarted(t, leaf, old)
+
+	require.Eventually(t, func() bool {
+		tree := root.Tree()
+
+		return tree.Children[1].Restarts == 1 && tree.Children[1].Alive
+	}, time.Second, time.Millisecond)
+
+	root.Stop()
+	require.NoError(t, root.Wait())
+	assert.False(t, leaf.Ref().Alive())
+}
+
+func TestSupervisedActorRejectsQueuedMessagesAfterCrash(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	act := New[int, int](func(ref *Ref[int, int]) Processor[int, int] {
+		return SimpleProcessor(func(req int) (int, error) {
+			<-release
+
+			panic("crash")
+		})
+	})
+
+	sup := NewSupervisor(t.Context(), "root", SupervisorSpec{Strategy: OneForOne})
+	defer sup.Stop()
+
+	child, err := Supervise(sup, "crasher", act, 10, Temporary)
+	require.NoError(t, err)
+
+	ref := child.Ref()
+	ref.Send(1)
+
+	// Queued behind the message that crashes the actor
+	responses := make(chan try.Try[int])
+	ref.Publish(Message[int, int]{Request: 2, ResponseChan: responses})
+
+	close(release)
+
+	response := <-responses
+	require.ErrorIs(t, response.Error, ErrDeadActor)
+
+	ref.Wait()
+	require.ErrorIs(t, ref.Err(), ErrActorPanic)
+}
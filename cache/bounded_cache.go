@@ -0,0 +1,421 @@
+package cache
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/amp-labs/amp-common/collectable"
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/maps"
+	"github.com/amp-labs/amp-common/utils"
+)
+
+// eviction is an entry that left the cache, waiting to be reported once the lock is released.
+type eviction[K any, V any] struct {
+	key    K
+	value  V
+	reason EvictionReason
+}
+
+// load is an in-flight Loader call shared by every GetOrLoad caller for a key.
+type load[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// boundedCache is the Cache implementation. A single mutex guards the entries
+// and the policy, since even a hit has to update the eviction order.
+type boundedCache[K collectable.Collectable[K], V any] struct {
+	mutex   sync.Mutex
+	options *cacheOptions[K, V]
+	entries maps.Map[K, *entry[K, V]]
+	loads   maps.Map[K, *load[V]]
+	policy  policy[K, V]
+	weight  int64
+	stats   Stats
+}
+
+func newBoundedCache[K collectable.Collectable[K], V any](
+	hash hashing.HashFunc,
+	options *cacheOptions[K, V],
+) *boundedCache[K, V] {
+	return &boundedCache[K, V]{
+		options: options,
+		entries: maps.NewHashMap[K, *entry[K, V]](hash),
+		loads:   maps.NewHashMap[K, *load[V]](hash),
+		policy:  newPolicy[K, V](options.policy),
+	}
+}
+
+func (c *boundedCache[K, V]) Get(key K) (V, bool, error) {
+	c.mutex.Lock()
+	value, found, evicted, err := c.getLocked(key)
+	c.mutex.Unlock()
+
+	c.notify(evicted)
+
+	return value, found, err
+}
+
+func (c *boundedCache[K, V]) Contains(key K) (bool, error) {
+	c.mutex.Lock()
+	e, evicted, err := c.liveLocked(key, time.Now())
+	c.mutex.Unlock()
+
+	c.notify(evicted)
+
+	return e != nil, err
+}
+
+func (c *boundedCache[K, V]) Add(key K, value V) error {
+	return c.AddWithTTL(key, value, c.options.ttl)
+}
+
+func (c *boundedCache[K, V]) AddWithTTL(key K, value V, ttl time.Duration) error {
+	c.mutex.Lock()
+	evicted, err := c.addLocked(key, value, ttl)
+	c.mutex.Unlock()
+
+	c.notify(evicted)
+
+	return err
+}
+
+func (c *boundedCache[K, V]) Remove(key K) error {
+	c.mutex.Lock()
+
+	e, found, err := c.entries.Get(key)
+	if err != nil || !found {
+		c.mutex.Unlock()
+
+		return err
+	}
+
+	evicted, err := c.removeLocked(e, EvictedRemoved)
+	c.mutex.Unlock()
+
+	c.notify(evicted)
+
+	return err
+}
+
+func (c *boundedCache[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[K, V]) (V, error) {
+	var zero V
+
+	c.mutex.Lock()
+
+	value, found, evicted, err := c.getLocked(key)
+	if err != nil || found {
+		c.mutex.Unlock()
+		c.notify(evicted)
+
+		return value, err
+	}
+
+	pending, loading, err := c.loads.Get(key)
+	if err != nil {
+		c.mutex.Unlock()
+		c.notify(evicted)
+
+		return zero, err
+	}
+
+	if loading {
+		c.mutex.Unlock()
+		c.notify(evicted)
+
+		select {
+		case <-pending.done:
+			return pending.value, pending.err
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+
+	pending = &load[V]{done: make(chan struct{})}
+	if err := c.loads.Add(key, pending); err != nil {
+		c.mutex.Unlock()
+		c.notify(evicted)
+
+		return zero, err
+	}
+
+	c.stats.Loads++
+	cacheLoads.WithLabelValues(c.options.name).Inc()
+	c.mutex.Unlock()
+	c.notify(evicted)
+
+	pending.value, pending.err = c.callLoader(ctx, key, loader)
+
+	c.mutex.Lock()
+
+	// The key hashed successfully above, so removing it can't fail.
+	_ = c.loads.Remove(key)
+
+	var overflow []eviction[K, V]
+
+	if pending.err != nil {
+		c.stats.LoadErrors++
+		cacheLoadErrors.WithLabelValues(c.options.name).Inc()
+	} else {
+		overflow, pending.err = c.addLocked(key, pending.value, c.options.ttl)
+	}
+
+	c.mutex.Unlock()
+	close(pending.done)
+
+	c.notify(overflow)
+
+	return pending.value, pending.err
+}
+
+func (c *boundedCache[K, V]) Load(ctx context.Context, key K) (V, error) {
+	if c.options.loader == nil {
+		var zero V
+
+		return zero, ErrNoLoader
+	}
+
+	return c.GetOrLoad(ctx, key, c.options.loader)
+}
+
+func (c *boundedCache[K, V]) Purge() int {
+	now := time.Now()
+
+	c.mutex.Lock()
+
+	var expired []*entry[K, V]
+
+	for _, e := range c.entries.Seq() {
+		if e.isExpired(now) {
+			expired = append(expired, e)
+		}
+	}
+
+	var evicted []eviction[K, V]
+
+	for _, e := range expired {
+		// The keys were hashed when they were added, so removing them can't fail.
+		more, _ := c.removeLocked(e, EvictedExpired)
+		evicted = append(evicted, more...)
+	}
+
+	c.mutex.Unlock()
+
+	c.notify(evicted)
+
+	return len(evicted)
+}
+
+func (c *boundedCache[K, V]) Clear() {
+	c.mutex.Lock()
+
+	evicted := make([]eviction[K, V], 0, c.entries.Size())
+	for key, e := range c.entries.Seq() {
+		evicted = append(evicted, eviction[K, V]{key: key, value: e.value, reason: EvictedRemoved})
+	}
+
+	c.entries.Clear()
+	c.policy.clear()
+	c.weight = 0
+	cacheEntries.WithLabelValues(c.options.name).Sub(float64(len(evicted)))
+	cacheEvictions.WithLabelValues(c.options.name, EvictedRemoved.String()).Add(float64(len(evicted)))
+	c.mutex.Unlock()
+
+	c.notify(evicted)
+}
+
+func (c *boundedCache[K, V]) Size() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.entries.Size()
+}
+
+func (c *boundedCache[K, V]) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stats := c.stats
+	stats.Size = c.entries.Size()
+	stats.Weight = c.weight
+
+	return stats
+}
+
+// getLocked looks up a live entry for key, recording the hit or miss.
+func (c *boundedCache[K, V]) getLocked(key K) (V, bool, []eviction[K, V], error) {
+	var zero V
+
+	e, evicted, err := c.liveLocked(key, time.Now())
+	if err != nil {
+		return zero, false, evicted, err
+	}
+
+	if e == nil {
+		c.stats.Misses++
+		cacheMisses.WithLabelValues(c.options.name).Inc()
+
+		return zero, false, evicted, nil
+	}
+
+	c.stats.Hits++
+	cacheHits.WithLabelValues(c.options.name).Inc()
+	c.policy.used(e)
+
+	return e.value, true, evicted, nil
+}
+
+// liveLocked returns the entry for key, or nil if there is none. An expired
+// entry is removed and reported rather than returned.
+func (c *boundedCache[K, V]) liveLocked(key K, now time.Time) (*entry[K, V], []eviction[K, V], error) {
+	e, found, err := c.entries.Get(key)
+	if err != nil || !found {
+		return nil, nil, err
+	}
+
+	if e.isExpired(now) {
+		evicted, err := c.removeLocked(e, EvictedExpired)
+
+		return nil, evicted, err
+	}
+
+	return e, nil, nil
+}
+
+// addLocked evicts entries until a new entry for key fits within the bounds,
+// then stores it. A replaced entry is not reported as evicted.
+func (c *boundedCache[K, V]) addLocked(key K, value V, ttl time.Duration) ([]eviction[K, V], error) {
+	added := &entry[K, V]{
+		key:    key,
+		value:  value,
+		weight: 1,
+	}
+
+	if c.options.weigher != nil {
+		added.weight = max(c.options.weigher(key, value), 0)
+	}
+
+	if ttl > 0 {
+		added.expiresAt = time.Now().Add(ttl)
+	}
+
+	previous, found, err := c.entries.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.options.weigher != nil && added.weight > c.options.maxWeight {
+		// The entry can never fit; drop it (and the value it replaces) rather
+		// than evicting everything else first.
+		var evicted []eviction[K, V]
+
+		if found {
+			if evicted, err = c.removeLocked(previous, EvictedCapacity); err != nil {
+				return evicted, err
+			}
+		}
+
+		c.stats.Evictions++
+		cacheEvictions.WithLabelValues(c.options.name, EvictedCapacity.String()).Inc()
+
+		return append(evicted, eviction[K, V]{key: key, value: value, reason: EvictedCapacity}), nil
+	}
+
+	if found {
+		// Take the previous entry out of the bounds, without reporting it, so
+		// it can't be picked to make room for its own replacement.
+		if err := c.entries.Remove(key); err != nil {
+			return nil, err
+		}
+
+		added.frequency = previous.frequency
+		c.policy.removed(previous)
+		c.weight -= previous.weight
+	} else {
+		cacheEntries.WithLabelValues(c.options.name).Inc()
+	}
+
+	// Make room before adding, so that the new entry (which LFU would rank
+	// lowest) is never the one evicted.
+	var evicted []eviction[K, V]
+
+	for c.overLimit(added.weight) {
+		victim := c.policy.victim()
+		if victim == nil {
+			break
+		}
+
+		more, err := c.removeLocked(victim, EvictedCapacity)
+		evicted = append(evicted, more...)
+
+		if err != nil {
+			return evicted, err
+		}
+	}
+
+	if err := c.entries.Add(key, added); err != nil {
+		return evicted, err
+	}
+
+	c.policy.added(added)
+	c.weight += added.weight
+
+	return evicted, nil
+}
+
+// overLimit reports whether adding one more entry of the given weight would
+// exceed the bounds.
+func (c *boundedCache[K, V]) overLimit(weight int64) bool {
+	if c.options.maxEntries > 0 && c.entries.Size() >= c.options.maxEntries {
+		return true
+	}
+
+	return c.options.weigher != nil && c.weight+weight > c.options.maxWeight
+}
+
+// removeLocked drops e from the cache and returns it as an eviction for reason.
+func (c *boundedCache[K, V]) removeLocked(e *entry[K, V], reason EvictionReason) ([]eviction[K, V], error) {
+	if err := c.entries.Remove(e.key); err != nil {
+		return nil, err
+	}
+
+	c.policy.removed(e)
+	c.weight -= e.weight
+
+	if reason != EvictedRemoved {
+		c.stats.Evictions++
+	}
+
+	cacheEntries.WithLabelValues(c.options.name).Dec()
+	cacheEvictions.WithLabelValues(c.options.name, reason.String()).Inc()
+
+	return []eviction[K, V]{{key: e.key, value: e.value, reason: reason}}, nil
+}
+
+// notify reports evicted entries to the WithOnEvict callback. It must be
+// called without holding the lock.
+func (c *boundedCache[K, V]) notify(evicted []eviction[K, V]) {
+	if c.options.onEvict == nil {
+		return
+	}
+
+	for _, e := range evicted {
+		c.options.onEvict(e.key, e.value, e.reason)
+	}
+}
+
+// callLoader runs loader, turning a panic into an error so that callers
+// waiting on the same load aren't left hanging.
+func (c *boundedCache[K, V]) callLoader(ctx context.Context, key K, loader Loader[K, V]) (value V, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = utils.GetPanicRecoveryError(r, debug.Stack())
+		}
+	}()
+
+	return loader(ctx, key)
+}
@@ -0,0 +1,148 @@
+// Package cache provides a generic, bounded, thread-safe cache with LRU or LFU
+// eviction, per-entry TTLs, weight bounds, eviction callbacks, single-flight
+// loading and Prometheus metrics.
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/amp-labs/amp-common/collectable"
+	"github.com/amp-labs/amp-common/hashing"
+)
+
+// ErrNoLoader is returned by Cache.Load when the cache was created without WithLoader.
+var ErrNoLoader = errors.New("cache has no loader")
+
+// Loader computes the value for a key that isn't cached. It is used by
+// Cache.GetOrLoad and Cache.Load; errors are returned to the caller and
+// nothing is cached.
+type Loader[K any, V any] func(ctx context.Context, key K) (V, error)
+
+// EvictionReason explains why an entry left the cache, as reported to the
+// WithOnEvict callback and the cache_evictions_total metric.
+type EvictionReason int
+
+const (
+	// EvictedCapacity means the entry was dropped to keep the cache within its
+	// WithMaxEntries or WithMaxWeight bound.
+	EvictedCapacity EvictionReason = iota
+	// EvictedExpired means the entry's TTL elapsed.
+	EvictedExpired
+	// EvictedRemoved means the entry was removed with Cache.Remove or Cache.Clear.
+	EvictedRemoved
+)
+
+// String returns the metric label for the reason.
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictedCapacity:
+		return "capacity"
+	case EvictedExpired:
+		return "expired"
+	case EvictedRemoved:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// Policy selects which entry is evicted when the cache is over its bounds.
+type Policy int
+
+const (
+	// LRU evicts the least recently used entry. This is the default.
+	LRU Policy = iota
+	// LFU evicts the least frequently used entry, breaking ties by recency.
+	LFU
+)
+
+// Stats contains cache statistics, as returned by Cache.Stats.
+type Stats struct {
+	// Size is the number of entries, including expired entries not yet removed.
+	Size int
+	// Weight is the total weight of the entries (see WithMaxWeight).
+	Weight int64
+	// Hits is the number of lookups that found a live entry.
+	Hits int64
+	// Misses is the number of lookups that found no live entry.
+	Misses int64
+	// Evictions is the number of entries dropped for capacity or expiry.
+	Evictions int64
+	// Loads is the number of times a Loader was called.
+	Loads int64
+	// LoadErrors is the number of Loader calls that failed.
+	LoadErrors int64
+}
+
+// Cache is a bounded key-value cache. All methods are safe for concurrent use.
+//
+// Expired entries are removed lazily, when they are looked up or evicted, or
+// eagerly with Purge. Until then they count towards Size and the bounds.
+type Cache[K any, V any] interface {
+	// Get returns the value for key. A hit counts as a use of the entry for
+	// the eviction policy. An error is returned only if hashing the key fails.
+	Get(key K) (value V, found bool, err error)
+
+	// Contains reports whether key has a live entry, without counting as a use.
+	Contains(key K) (bool, error)
+
+	// Add stores value under key with the default TTL (see WithTTL), replacing
+	// any existing entry. It may evict other entries to stay within bounds.
+	Add(key K, value V) error
+
+	// AddWithTTL is like Add with an explicit TTL. A non-positive TTL means the
+	// entry never expires.
+	AddWithTTL(key K, value V, ttl time.Duration) error
+
+	// Remove drops the entry for key, if any, reporting it as EvictedRemoved.
+	Remove(key K) error
+
+	// GetOrLoad returns the cached value for key, or calls loader to compute and
+	// cache it. Concurrent calls for the same key share a single loader call;
+	// waiters give up when their own ctx is done, but the loader runs with the
+	// context of the caller that started it.
+	GetOrLoad(ctx context.Context, key K, loader Loader[K, V]) (V, error)
+
+	// Load is GetOrLoad with the loader configured by WithLoader. It returns
+	// ErrNoLoader if there is none.
+	Load(ctx context.Context, key K) (V, error)
+
+	// Purge removes all expired entries and returns how many were removed.
+	Purge() int
+
+	// Clear removes every entry, reporting each as EvictedRemoved.
+	Clear()
+
+	// Size returns the number of entries, including expired entries not yet removed.
+	Size() int
+
+	// Stats returns the current cache statistics.
+	Stats() Stats
+}
+
+// New creates a Cache. Keys are hashed with hash and disambiguated with their
+// Equals method, like the maps package. With no options the cache is an
+// unbounded LRU whose entries never expire.
+//
+// Example:
+//
+//	c := cache.New[hashing.HashableString, *User](hashing.Xxh3,
+//	    cache.WithName[hashing.HashableString, *User]("users"),
+//	    cache.WithMaxEntries[hashing.HashableString, *User](10_000),
+//	    cache.WithTTL[hashing.HashableString, *User](5*time.Minute),
+//	)
+//
+//	user, err := c.GetOrLoad(ctx, "user-id", fetchUser)
+func New[K collectable.Collectable[K], V any](hash hashing.HashFunc, opts ...Option[K, V]) Cache[K, V] {
+	options := &cacheOptions[K, V]{
+		name: "cache",
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return newBoundedCache(hash, options)
+}
@@ -0,0 +1,327 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type key = hashing.HashableString
+
+var errLoadFailed = errors.New("load failed")
+
+// recorder collects the entries reported to a WithOnEvict callback.
+type recorder struct {
+	mutex   sync.Mutex
+	evicted map[key]EvictionReason
+}
+
+func (r *recorder) option() Option[key, int] {
+	r.evicted = make(map[key]EvictionReason)
+
+	return WithOnEvict(func(k key, _ int, reason EvictionReason) {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+
+		r.evicted[k] = reason
+	})
+}
+
+func (r *recorder) reasons() map[key]EvictionReason {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.evicted
+}
+
+func contains(t *testing.T, c Cache[key, int], k key) bool {
+	t.Helper()
+
+	found, err := c.Contains(k)
+	require.NoError(t, err)
+
+	return found
+}
+
+func TestCacheLRU(t *testing.T) {
+	t.Parallel()
+
+	var evictions recorder
+
+	c := New[key, int](hashing.Sha256, WithMaxEntries[key, int](2), evictions.option())
+
+	require.NoError(t, c.Add("a", 1))
+	require.NoError(t, c.Add("b", 2))
+
+	// Using "a" makes "b" the least recently used entry
+	value, found, err := c.Get("a")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 1, value)
+
+	require.NoError(t, c.Add("c", 3))
+
+	assert.True(t, contains(t, c, "a"))
+	assert.False(t, contains(t, c, "b"))
+	assert.True(t, contains(t, c, "c"))
+	assert.Equal(t, map[key]EvictionReason{"b": EvictedCapacity}, evictions.reasons())
+
+	// Replacing a value isn't an eviction
+	require.NoError(t, c.Add("a", 10))
+	assert.Equal(t, 2, c.Size())
+	assert.Len(t, evictions.reasons(), 1)
+}
+
+func TestCacheLFU(t *testing.T) {
+	t.Parallel()
+
+	c := New[key, int](hashing.Sha256, WithPolicy[key, int](LFU), WithMaxEntries[key, int](3))
+
+	for _, k := range []key{"a", "b", "c"} {
+		require.NoError(t, c.Add(k, 0))
+	}
+
+	for range 3 {
+		_, _, _ = c.Get("a")
+	}
+
+	_, _, _ = c.Get("b")
+	_, _, _ = c.Get("c")
+
+	// "b" and "c" are tied, so the least recently used of them goes first
+	require.NoError(t, c.Add("d", 0))
+	assert.False(t, contains(t, c, "b"))
+
+	// "d" is now the least frequently used entry, even though it's the newest
+	require.NoError(t, c.Add("e", 0))
+	assert.False(t, contains(t, c, "d"))
+	assert.True(t, contains(t, c, "a"))
+	assert.True(t, contains(t, c, "c"))
+	assert.True(t, contains(t, c, "e"))
+
+	// A removed entry is no longer a candidate, new entries are evicted first
+	require.NoError(t, c.Remove("e"))
+	require.NoError(t, c.Add("f", 0))
+	require.NoError(t, c.Add("g", 0))
+	assert.False(t, contains(t, c, "f"))
+	assert.True(t, contains(t, c, "a"))
+}
+
+func TestCacheWeight(t *testing.T) {
+	t.Parallel()
+
+	var evictions recorder
+
+	c := New[key, int](hashing.Sha256,
+		WithMaxWeight(10, func(_ key, value int) int64 { return int64(value) }),
+		evictions.option(),
+	)
+
+	require.NoError(t, c.Add("a", 4))
+	require.NoError(t, c.Add("b", 4))
+	require.NoError(t, c.Add("c", 4))
+
+	assert.False(t, contains(t, c, "a"))
+	assert.Equal(t, int64(8), c.Stats().Weight)
+
+	// An entry that can never fit is dropped on its own
+	require.NoError(t, c.Add("huge", 11))
+	assert.False(t, contains(t, c, "huge"))
+	assert.Equal(t, 2, c.Size())
+	assert.Equal(t, map[key]EvictionReason{"a": EvictedCapacity, "huge": EvictedCapacity}, evictions.reasons())
+
+	require.NoError(t, c.Add("b", 1))
+	assert.Equal(t, int64(5), c.Stats().Weight)
+}
+
+func TestCacheTTL(t *testing.T) {
+	t.Parallel()
+
+	var evictions recorder
+
+	c := New[key, int](hashing.Sha256, WithTTL[key, int](20*time.Millisecond), evictions.option())
+
+	require.NoError(t, c.Add("default", 1))
+	require.NoError(t, c.AddWithTTL("short", 2, 10*time.Millisecond))
+	require.NoError(t, c.AddWithTTL("forever", 3, 0))
+
+	assert.True(t, contains(t, c, "short"))
+
+	time.Sleep(15 * time.Millisecond)
+
+	_, found, err := c.Get("short")
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.True(t, contains(t, c, "default"))
+
+	time.Sleep(15 * time.Millisecond)
+
+	assert.Equal(t, 2, c.Size(), "expired entries are removed lazily")
+	assert.Equal(t, 1, c.Purge())
+	assert.Equal(t, 1, c.Size())
+	assert.True(t, contains(t, c, "forever"))
+	assert.Equal(t, map[key]EvictionReason{"short": EvictedExpired, "default": EvictedExpired}, evictions.reasons())
+}
+
+func TestCacheRemoveAndClear(t *testing.T) {
+	t.Parallel()
+
+	var evictions recorder
+
+	c := New[key, int](hashing.Sha256, evictions.option())
+
+	require.NoError(t, c.Add("a", 1))
+	require.NoError(t, c.Add("b", 2))
+	require.NoError(t, c.Remove("a"))
+	require.NoError(t, c.Remove("missing"))
+	require.NoError(t, c.Add("c", 3))
+
+	c.Clear()
+
+	assert.Equal(t, 0, c.Size())
+	assert.Equal(t, map[key]EvictionReason{
+		"a": EvictedRemoved,
+		"b": EvictedRemoved,
+		"c": EvictedRemoved,
+	}, evictions.reasons())
+	assert.Equal(t, int64(0), c.Stats().Evictions)
+}
+
+func TestCacheOnEvictCanUseCache(t *testing.T) {
+	t.Parallel()
+
+	var c Cache[key, int]
+
+	c = New[key, int](hashing.Sha256,
+		WithMaxEntries[key, int](1),
+		WithOnEvict(func(_ key, value int, _ EvictionReason) {
+			// The callback runs without the lock held, so this mustn't deadlock
+			assert.Equal(t, 1, c.Size())
+			assert.Equal(t, 1, value)
+		}),
+	)
+
+	require.NoError(t, c.Add("a", 1))
+	require.NoError(t, c.Add("b", 2))
+}
+
+func TestCacheGetOrLoad(t *testing.T) {
+	t.Parallel()
+
+	t.Run("loads once and caches the value", func(t *testing.T) {
+		t.Parallel()
+
+		c := New[key, int](hashing.Sha256)
+
+		var (
+			wg      sync.WaitGroup
+			release = make(chan struct{})
+		)
+
+		loader := func(context.Context, key) (int, error) {
+			<-release
+
+			return 42, nil
+		}
+
+		for range 10 {
+			wg.Go(func() {
+				value, err := c.GetOrLoad(t.Context(), "a", loader)
+				assert.NoError(t, err)
+				assert.Equal(t, 42, value)
+			})
+		}
+
+		// Give the callers time to pile up on the same load
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		value, err := c.GetOrLoad(t.Context(), "a", loader)
+		require.NoError(t, err)
+		assert.Equal(t, 42, value)
+
+		stats := c.Stats()
+		assert.Equal(t, int64(1), stats.Loads)
+		assert.Equal(t, int64(1), stats.Hits)
+		assert.Equal(t, int64(10), stats.Misses)
+	})
+
+	t.Run("errors aren't cached", func(t *testing.T) {
+		t.Parallel()
+
+		c := New[key, int](hashing.Sha256)
+
+		_, err := c.GetOrLoad(t.Context(), "a", func(context.Context, key) (int, error) {
+			return 0, errLoadFailed
+		})
+		require.ErrorIs(t, err, errLoadFailed)
+		assert.False(t, contains(t, c, "a"))
+
+		_, err = c.GetOrLoad(t.Context(), "a", func(context.Context, key) (int, error) {
+			panic("boom")
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "boom")
+
+		value, err := c.GetOrLoad(t.Context(), "a", func(context.Context, key) (int, error) {
+			return 1, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, value)
+		assert.Equal(t, int64(2), c.Stats().LoadErrors)
+	})
+
+	t.Run("waiters stop when their context is done", func(t *testing.T) {
+		t.Parallel()
+
+		c := New[key, int](hashing.Sha256)
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		go func() {
+			_, _ = c.GetOrLoad(context.Background(), "a", func(context.Context, key) (int, error) {
+				close(started)
+				<-release
+
+				return 1, nil
+			})
+		}()
+
+		<-started
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		_, err := c.GetOrLoad(ctx, "a", func(context.Context, key) (int, error) {
+			t.Error("the loader must not run twice")
+
+			return 0, nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+
+		close(release)
+	})
+
+	t.Run("Load uses the configured loader", func(t *testing.T) {
+		t.Parallel()
+
+		c := New[key, int](hashing.Sha256, WithLoader(func(_ context.Context, k key) (int, error) {
+			return len(k), nil
+		}))
+
+		value, err := c.Load(t.Context(), "abc")
+		require.NoError(t, err)
+		assert.Equal(t, 3, value)
+
+		_, err = New[key, int](hashing.Sha256).Load(t.Context(), "abc")
+		require.ErrorIs(t, err, ErrNoLoader)
+	})
+}
@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Prometheus metrics for monitoring cache effectiveness. Every metric is
+// labeled with the cache's name (see WithName).
+
+var (
+	// cacheHits counts lookups that found a live entry.
+	cacheHits = promauto.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "cache_hits_total",
+		Help: "The total number of cache lookups that found a value",
+	}, []string{"cache"})
+
+	// cacheMisses counts lookups that found no live entry.
+	cacheMisses = promauto.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "cache_misses_total",
+		Help: "The total number of cache lookups that found no value",
+	}, []string{"cache"})
+
+	// cacheEvictions counts entries leaving the cache, labeled with the EvictionReason.
+	cacheEvictions = promauto.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "cache_evictions_total",
+		Help: "The total number of entries removed from the cache, per reason",
+	}, []string{"cache", "reason"})
+
+	// cacheLoads counts Loader calls.
+	cacheLoads = promauto.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "cache_loads_total",
+		Help: "The total number of values loaded into the cache",
+	}, []string{"cache"})
+
+	// cacheLoadErrors counts Loader calls that returned an error or panicked.
+	cacheLoadErrors = promauto.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "cache_load_errors_total",
+		Help: "The total number of errors loading values into the cache",
+	}, []string{"cache"})
+
+	// cacheEntries tracks the number of entries currently cached.
+	cacheEntries = promauto.NewGaugeVec(prometheus.GaugeOpts{ //nolint:gochecknoglobals
+		Name: "cache_entries",
+		Help: "The number of entries in the cache",
+	}, []string{"cache"})
+)
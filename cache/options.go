@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"time"
+)
+
+// cacheOptions holds the configuration accumulated from Option values before a cache is built.
+type cacheOptions[K any, V any] struct {
+	name       string
+	policy     Policy
+	maxEntries int
+	maxWeight  int64
+	weigher    func(key K, value V) int64
+	ttl        time.Duration
+	onEvict    func(key K, value V, reason EvictionReason)
+	loader     Loader[K, V]
+}
+
+// Option is a functional option for configuring a Cache during creation.
+// Options are passed to New and applied in order.
+type Option[K any, V any] func(*cacheOptions[K, V])
+
+// WithName sets the name used in Prometheus metrics labels to distinguish
+// between caches. If not specified, the default name "cache" is used.
+func WithName[K any, V any](name string) Option[K, V] {
+	return func(o *cacheOptions[K, V]) {
+		o.name = name
+	}
+}
+
+// WithPolicy selects the eviction policy. The default is LRU.
+func WithPolicy[K any, V any](policy Policy) Option[K, V] {
+	return func(o *cacheOptions[K, V]) {
+		o.policy = policy
+	}
+}
+
+// WithMaxEntries bounds the number of entries. Non-positive values mean no bound (the default).
+func WithMaxEntries[K any, V any](maxEntries int) Option[K, V] {
+	return func(o *cacheOptions[K, V]) {
+		o.maxEntries = maxEntries
+	}
+}
+
+// WithMaxWeight bounds the total weight of the entries, as computed by weigher
+// when each entry is added. Negative weights count as zero. An entry heavier
+// than maxWeight on its own is evicted as soon as it is added. Without this
+// option every entry weighs 1 and the total weight is unbounded.
+func WithMaxWeight[K any, V any](maxWeight int64, weigher func(key K, value V) int64) Option[K, V] {
+	return func(o *cacheOptions[K, V]) {
+		o.maxWeight = maxWeight
+		o.weigher = weigher
+	}
+}
+
+// WithTTL sets the default time to live used by Add and by loaded values.
+// Non-positive values mean entries never expire (the default).
+func WithTTL[K any, V any](ttl time.Duration) Option[K, V] {
+	return func(o *cacheOptions[K, V]) {
+		o.ttl = ttl
+	}
+}
+
+// WithOnEvict registers a callback invoked for every entry that leaves the
+// cache, except for entries replaced by a new value for the same key. It is
+// called after the cache's lock is released, so it may use the cache.
+func WithOnEvict[K any, V any](onEvict func(key K, value V, reason EvictionReason)) Option[K, V] {
+	return func(o *cacheOptions[K, V]) {
+		o.onEvict = onEvict
+	}
+}
+
+// WithLoader sets the loader used by Cache.Load.
+func WithLoader[K any, V any](loader Loader[K, V]) Option[K, V] {
+	return func(o *cacheOptions[K, V]) {
+		o.loader = loader
+	}
+}
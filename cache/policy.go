@@ -0,0 +1,166 @@
+package cache
+
+import (
+	"container/list"
+	"time"
+)
+
+// entry is a cached value along with the bookkeeping used by the eviction policies.
+type entry[K any, V any] struct {
+	key       K
+	value     V
+	weight    int64
+	expiresAt time.Time // zero if the entry never expires
+	frequency int       // number of uses, only maintained by lfuPolicy
+	element   *list.Element
+}
+
+// isExpired reports whether the entry's TTL has elapsed at now.
+func (e *entry[K, V]) isExpired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// policy decides the eviction order of the entries in a cache. It is only
+// called with the cache's lock held.
+type policy[K any, V any] interface {
+	// added starts tracking a new entry.
+	added(e *entry[K, V])
+	// used records a cache hit on a tracked entry.
+	used(e *entry[K, V])
+	// removed stops tracking an entry.
+	removed(e *entry[K, V])
+	// victim returns the entry to evict next, or nil if nothing is tracked.
+	victim() *entry[K, V]
+	// clear stops tracking every entry.
+	clear()
+}
+
+// newPolicy returns the policy implementation for p.
+func newPolicy[K any, V any](p Policy) policy[K, V] {
+	if p == LFU {
+		return newLFUPolicy[K, V]()
+	}
+
+	return newLRUPolicy[K, V]()
+}
+
+// lruPolicy keeps entries in a list ordered from most to least recently used.
+type lruPolicy[K any, V any] struct {
+	order *list.List
+}
+
+func newLRUPolicy[K any, V any]() *lruPolicy[K, V] {
+	return &lruPolicy[K, V]{order: list.New()}
+}
+
+func (p *lruPolicy[K, V]) added(e *entry[K, V]) {
+	e.element = p.order.PushFront(e)
+}
+
+func (p *lruPolicy[K, V]) used(e *entry[K, V]) {
+	p.order.MoveToFront(e.element)
+}
+
+func (p *lruPolicy[K, V]) removed(e *entry[K, V]) {
+	p.order.Remove(e.element)
+}
+
+func (p *lruPolicy[K, V]) victim() *entry[K, V] {
+	back := p.order.Back()
+	if back == nil {
+		return nil
+	}
+
+	return back.Value.(*entry[K, V]) //nolint:forcetypeassert // the list only holds entries
+}
+
+func (p *lruPolicy[K, V]) clear() {
+	p.order.Init()
+}
+
+// lfuPolicy keeps one recency-ordered list per use count, so that every
+// operation is O(1) apart from recomputing the minimum count after the last
+// entry with that count is removed.
+type lfuPolicy[K any, V any] struct {
+	buckets      map[int]*list.List
+	minFrequency int
+}
+
+func newLFUPolicy[K any, V any]() *lfuPolicy[K, V] {
+	return &lfuPolicy[K, V]{buckets: make(map[int]*list.List)}
+}
+
+// added tracks e with its existing frequency, so that replacing a value keeps
+// the key's history; new entries start at 1.
+func (p *lfuPolicy[K, V]) added(e *entry[K, V]) {
+	if e.frequency < 1 {
+		e.frequency = 1
+	}
+
+	if len(p.buckets) == 0 || e.frequency < p.minFrequency {
+		p.minFrequency = e.frequency
+	}
+
+	p.push(e)
+}
+
+func (p *lfuPolicy[K, V]) used(e *entry[K, V]) {
+	p.unlink(e)
+
+	if _, ok := p.buckets[p.minFrequency]; !ok && p.minFrequency == e.frequency {
+		p.minFrequency++
+	}
+
+	e.frequency++
+	p.push(e)
+}
+
+func (p *lfuPolicy[K, V]) removed(e *entry[K, V]) {
+	p.unlink(e)
+}
+
+func (p *lfuPolicy[K, V]) victim() *entry[K, V] {
+	if len(p.buckets) == 0 {
+		return nil
+	}
+
+	bucket, ok := p.buckets[p.minFrequency]
+	if !ok {
+		// The least used entries were removed, find the next lowest count.
+		p.minFrequency = 0
+
+		for frequency, candidate := range p.buckets {
+			if p.minFrequency == 0 || frequency < p.minFrequency {
+				p.minFrequency, bucket = frequency, candidate
+			}
+		}
+	}
+
+	return bucket.Back().Value.(*entry[K, V]) //nolint:forcetypeassert // the lists only hold entries
+}
+
+func (p *lfuPolicy[K, V]) clear() {
+	clear(p.buckets)
+	p.minFrequency = 0
+}
+
+// push adds e to the front of the list for its frequency.
+func (p *lfuPolicy[K, V]) push(e *entry[K, V]) {
+	bucket, ok := p.buckets[e.frequency]
+	if !ok {
+		bucket = list.New()
+		p.buckets[e.frequency] = bucket
+	}
+
+	e.element = bucket.PushFront(e)
+}
+
+// unlink removes e from the list for its frequency, dropping the list once empty.
+func (p *lfuPolicy[K, V]) unlink(e *entry[K, V]) {
+	bucket := p.buckets[e.frequency]
+	bucket.Remove(e.element)
+
+	if bucket.Len() == 0 {
+		delete(p.buckets, e.frequency)
+	}
+}
@@ -0,0 +1,439 @@
+// Package circuitbreaker stops calls to a dependency that keeps failing, so
+// callers fail fast instead of paying for timeouts and retries against a
+// dependency that is hard-down.
+//
+// A Breaker starts closed and lets every call through. It trips open after too
+// many consecutive failures or too high a failure rate within a sliding window,
+// and then rejects calls with ErrOpen. After the open timeout it turns
+// half-open and admits a few probe calls: if they succeed it closes again,
+// otherwise it reopens.
+//
+// Basic usage:
+//
+//	breaker := circuitbreaker.New(circuitbreaker.WithName("billing"))
+//
+//	err := breaker.Execute(ctx, func(ctx context.Context) error {
+//	    return callBilling(ctx)
+//	})
+//	if errors.Is(err, circuitbreaker.ErrOpen) {
+//	    // billing is down; fail fast
+//	}
+//
+// A Group keeps one breaker per key, such as per host. Breakers plug into
+// retry.WithCircuitBreaker and transport.NewCircuitBreakerTransport. State
+// changes are logged and exported as Prometheus metrics.
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/amp-labs/amp-common/logger"
+)
+
+var (
+	// ErrOpen is returned when the breaker is open and rejects calls.
+	ErrOpen = errors.New("circuit breaker is open")
+
+	// ErrTooManyRequests is returned when the breaker is half-open and all of
+	// its probe calls are already in flight.
+	ErrTooManyRequests = errors.New("circuit breaker is half-open and at its probe limit")
+)
+
+// State is the state of a Breaker.
+type State int
+
+const (
+	// Closed lets every call through while counting failures. This is the initial state.
+	Closed State = iota
+	// Open rejects every call until the open timeout elapses.
+	Open
+	// HalfOpen admits a limited number of probe calls to test whether the dependency recovered.
+	HalfOpen
+)
+
+// String returns the log and metric label for the state.
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Outcome is how a call admitted by a Breaker ended, as reported to it.
+type Outcome int
+
+const (
+	// Success means the dependency worked. It resets the consecutive failures
+	// and, in the half-open state, counts toward closing the breaker.
+	Success Outcome = iota
+	// Failure means the dependency misbehaved. It counts toward tripping the
+	// breaker and, in the half-open state, reopens it.
+	Failure
+	// Ignored means the call says nothing about the dependency, for instance
+	// because the caller gave up. It is not recorded; in the half-open state it
+	// only frees the probe slot.
+	Ignored
+)
+
+// String returns the log label for the outcome.
+func (o Outcome) String() string {
+	switch o {
+	case Success:
+		return "success"
+	case Failure:
+		return "failure"
+	case Ignored:
+		return "ignored"
+	default:
+		return "unknown"
+	}
+}
+
+// Counts contains a snapshot of a breaker's counters, as returned by Breaker.Counts.
+type Counts struct {
+	// Requests is the number of calls recorded within the sliding window.
+	Requests int
+	// Failures is the number of failed calls recorded within the sliding window.
+	Failures int
+	// ConsecutiveFailures is the number of failures since the last success.
+	ConsecutiveFailures int
+}
+
+// Breaker is a circuit breaker. All methods are safe for concurrent use, and a
+// nil *Breaker lets every call through.
+type Breaker struct {
+	opts *options
+	key  string
+
+	mutex               sync.Mutex
+	state               State
+	generation          uint64 // incremented on every state change, to ignore stale outcomes
+	window              *window
+	consecutiveFailures int
+	openedAt            time.Time
+	probesInFlight      int
+	probeSuccesses      int
+}
+
+// transition is a state change waiting to be reported once the lock is released.
+type transition struct {
+	from, to State
+}
+
+// New creates a closed Breaker. Without options it trips after 5 consecutive
+// failures or a failure rate of 50% over at least 20 calls in the last minute,
+// stays open for 30 seconds and then admits a single probe call.
+//
+// Example:
+//
+//	breaker := circuitbreaker.New(
+//	    circuitbreaker.WithName("billing"),
+//	    circuitbreaker.WithConsecutiveFailures(10),
+//	    circuitbreaker.WithOpenTimeout(time.Minute),
+//	)
+func New(opts ...Option) *Breaker {
+	return newBreaker(newOptions(opts), "")
+}
+
+func newBreaker(opts *options, key string) *Breaker {
+	breakerState.WithLabelValues(opts.name, key).Set(float64(Closed))
+	breakerRejections.WithLabelValues(opts.name, key).Add(0)
+
+	return &Breaker{
+		opts:   opts,
+		key:    key,
+		window: newWindow(opts.window, opts.windowBuckets),
+	}
+}
+
+// Name returns the breaker's name (see WithName).
+func (b *Breaker) Name() string {
+	return b.opts.name
+}
+
+// Key returns the breaker's key within its Group, or an empty string for a standalone breaker.
+func (b *Breaker) Key() string {
+	return b.key
+}
+
+// State returns the breaker's current state. An open breaker whose timeout has
+// elapsed reports HalfOpen.
+func (b *Breaker) State() State {
+	b.mutex.Lock()
+
+	changes := b.refreshLocked(b.opts.now())
+	state := b.state
+
+	b.mutex.Unlock()
+
+	b.report(context.Background(), changes)
+
+	return state
+}
+
+// Counts returns a snapshot of the breaker's counters.
+func (b *Breaker) Counts() Counts {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	requests, failures := b.window.totals(b.opts.now())
+
+	return Counts{
+		Requests:            requests,
+		Failures:            failures,
+		ConsecutiveFailures: b.consecutiveFailures,
+	}
+}
+
+// Classify returns the Outcome of a call that ended with err, for this breaker (see WithClassifier).
+func (b *Breaker) Classify(err error) Outcome {
+	if b == nil {
+		return DefaultClassifier(err)
+	}
+
+	return b.opts.classify(err)
+}
+
+// Allow asks the breaker to let a call through. If it refuses, it returns
+// ErrOpen or ErrTooManyRequests. Otherwise the caller must make the call and
+// then invoke done exactly once, reporting its Outcome; later invocations are
+// ignored. ctx is used for log events.
+//
+// Execute wraps this for the common case; Allow suits callers that classify
+// outcomes themselves, such as HTTP clients judging status codes.
+func (b *Breaker) Allow(ctx context.Context) (done func(outcome Outcome), err error) {
+	if b == nil {
+		return func(Outcome) {}, nil
+	}
+
+	b.mutex.Lock()
+
+	changes := b.refreshLocked(b.opts.now())
+
+	switch b.state {
+	case Open:
+		err = ErrOpen
+	case HalfOpen:
+		if b.probesInFlight >= b.opts.halfOpenRequests {
+			err = ErrTooManyRequests
+		} else {
+			b.probesInFlight++
+		}
+	case Closed:
+	}
+
+	generation := b.generation
+
+	b.mutex.Unlock()
+
+	b.report(ctx, changes)
+
+	if err != nil {
+		breakerRejections.WithLabelValues(b.opts.name, b.key).Inc()
+
+		return nil, err
+	}
+
+	var called atomic.Bool
+
+	return func(outcome Outcome) {
+		if called.CompareAndSwap(false, true) {
+			b.record(ctx, generation, outcome)
+		}
+	}, nil
+}
+
+// Execute runs f if the breaker allows it and records its outcome, classified
+// with Classify. It returns ErrOpen or ErrTooManyRequests without running f if
+// the breaker refuses the call, and otherwise returns f's error.
+//
+// Example:
+//
+//	err := breaker.Execute(ctx, func(ctx context.Context) error {
+//	    return callBilling(ctx)
+//	})
+func (b *Breaker) Execute(ctx context.Context, f func(ctx context.Context) error) error {
+	done, err := b.Allow(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = f(ctx)
+	done(b.Classify(err))
+
+	return err
+}
+
+// Reset forces the breaker closed and forgets every recorded outcome.
+func (b *Breaker) Reset() {
+	b.mutex.Lock()
+
+	var changes []transition
+	if b.state != Closed {
+		changes = append(changes, b.setStateLocked(Closed, b.opts.now()))
+	}
+
+	b.window.reset()
+	b.consecutiveFailures = 0
+
+	b.mutex.Unlock()
+
+	b.report(context.Background(), changes)
+}
+
+// busy reports whether the breaker holds state worth keeping even if unused:
+// it is open and its timeout hasn't elapsed, or it has probe calls in flight.
+func (b *Breaker) busy(now time.Time) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case Open:
+		return now.Sub(b.openedAt) < b.opts.openTimeout
+	case HalfOpen:
+		return b.probesInFlight > 0
+	case Closed:
+	}
+
+	return false
+}
+
+// record applies the outcome of a call admitted during the given generation.
+func (b *Breaker) record(ctx context.Context, generation uint64, outcome Outcome) {
+	b.mutex.Lock()
+
+	now := b.opts.now()
+	changes := b.refreshLocked(now)
+
+	// The state changed while the call was running, so its outcome describes a
+	// period the breaker has already moved past.
+	if generation != b.generation {
+		b.mutex.Unlock()
+		b.report(ctx, changes)
+
+		return
+	}
+
+	failed := outcome == Failure
+
+	switch b.state {
+	case Closed:
+		if outcome == Ignored {
+			break
+		}
+
+		b.window.record(now, failed)
+
+		if failed {
+			b.consecutiveFailures++
+		} else {
+			b.consecutiveFailures = 0
+		}
+
+		if b.shouldTripLocked(now) {
+			changes = append(changes, b.setStateLocked(Open, now))
+		}
+	case HalfOpen:
+		b.probesInFlight--
+
+		switch outcome {
+		case Failure:
+			changes = append(changes, b.setStateLocked(Open, now))
+		case Success:
+			b.probeSuccesses++
+			if b.probeSuccesses >= b.opts.halfOpenRequests {
+				changes = append(changes, b.setStateLocked(Closed, now))
+			}
+		case Ignored:
+		}
+	case Open:
+	}
+
+	b.mutex.Unlock()
+
+	b.report(ctx, changes)
+}
+
+// shouldTripLocked reports whether a closed breaker has crossed either threshold.
+func (b *Breaker) shouldTripLocked(now time.Time) bool {
+	if b.opts.consecutiveFailures > 0 && b.consecutiveFailures >= b.opts.consecutiveFailures {
+		return true
+	}
+
+	if b.opts.failureRate <= 0 {
+		return false
+	}
+
+	requests, failures := b.window.totals(now)
+	if requests == 0 || requests < b.opts.minRequests {
+		return false
+	}
+
+	return float64(failures)/float64(requests) >= b.opts.failureRate
+}
+
+// refreshLocked moves an open breaker to half-open once its timeout has elapsed.
+func (b *Breaker) refreshLocked(now time.Time) []transition {
+	if b.state == Open && now.Sub(b.openedAt) >= b.opts.openTimeout {
+		return []transition{b.setStateLocked(HalfOpen, now)}
+	}
+
+	return nil
+}
+
+// setStateLocked switches to state and resets the counters tied to the old one.
+func (b *Breaker) setStateLocked(state State, now time.Time) transition {
+	change := transition{from: b.state, to: state}
+
+	b.state = state
+	b.generation++
+	b.probesInFlight = 0
+	b.probeSuccesses = 0
+
+	switch state {
+	case Open:
+		b.openedAt = now
+	case Closed:
+		b.window.reset()
+		b.consecutiveFailures = 0
+	case HalfOpen:
+	}
+
+	return change
+}
+
+// report publishes state changes to the metrics, the log and the
+// WithOnStateChange callback. It must be called without holding the lock.
+func (b *Breaker) report(ctx context.Context, changes []transition) {
+	for _, change := range changes {
+		breakerState.WithLabelValues(b.opts.name, b.key).Set(float64(change.to))
+		breakerStateChanges.WithLabelValues(b.opts.name, b.key, change.from.String(), change.to.String()).Inc()
+
+		args := []any{
+			"breaker", b.opts.name,
+			"key", b.key,
+			"from", change.from.String(),
+			"to", change.to.String(),
+		}
+
+		if change.to == Open {
+			logger.Warn(ctx, "circuit breaker opened", args...)
+		} else {
+			logger.Info(ctx, "circuit breaker state changed", args...)
+		}
+
+		if b.opts.onStateChange != nil {
+			b.opts.onStateChange(b.opts.name, b.key, change.from, change.to)
+		}
+	}
+}
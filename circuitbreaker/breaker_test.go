@@ -0,0 +1,315 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errBoom = errors.New("boom")
+
+// clock is a manually advanced time source.
+type clock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func newClock() *clock {
+	return &clock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *clock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func fail(context.Context) error    { return errBoom }
+func succeed(context.Context) error { return nil }
+
+func TestBreakerTripsOnConsecutiveFailures(t *testing.T) {
+	t.Parallel()
+
+	b := New(WithName("consecutive"), WithConsecutiveFailures(3), WithFailureRate(0, 0), withNow(newClock().Now))
+
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	require.NoError(t, b.Execute(t.Context(), succeed), "a success resets the streak")
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	assert.Equal(t, Closed, b.State())
+	assert.Equal(t, 2, b.Counts().ConsecutiveFailures)
+
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	assert.Equal(t, Open, b.State())
+
+	called := false
+	err := b.Execute(t.Context(), func(context.Context) error {
+		called = true
+
+		return nil
+	})
+	require.ErrorIs(t, err, ErrOpen)
+	assert.False(t, called)
+}
+
+func TestBreakerTripsOnFailureRate(t *testing.T) {
+	t.Parallel()
+
+	clk := newClock()
+	b := New(WithName("rate"), WithConsecutiveFailures(0), WithFailureRate(0.5, 4),
+		WithWindow(10*time.Second, 10), withNow(clk.Now))
+
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	require.NoError(t, b.Execute(t.Context(), succeed))
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	assert.Equal(t, Closed, b.State(), "below the minimum number of requests")
+
+	// The first outcomes slide out of the window, so the rate stays low.
+	clk.Advance(11 * time.Second)
+	assert.Zero(t, b.Counts().Requests)
+
+	require.NoError(t, b.Execute(t.Context(), succeed))
+	require.NoError(t, b.Execute(t.Context(), succeed))
+	require.NoError(t, b.Execute(t.Context(), succeed))
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	assert.Equal(t, Closed, b.State())
+
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	assert.Equal(t, Counts{Requests: 6, Failures: 3, ConsecutiveFailures: 3}, b.Counts())
+	assert.Equal(t, Open, b.State())
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	t.Parallel()
+
+	clk := newClock()
+	b := New(WithName("half-open"), WithConsecutiveFailures(1), WithOpenTimeout(time.Second),
+		WithHalfOpenRequests(2), withNow(clk.Now))
+
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	require.Equal(t, Open, b.State())
+
+	clk.Advance(time.Second)
+	assert.Equal(t, HalfOpen, b.State())
+
+	first, err := b.Allow(t.Context())
+	require.NoError(t, err)
+
+	second, err := b.Allow(t.Context())
+	require.NoError(t, err)
+
+	_, err = b.Allow(t.Context())
+	require.ErrorIs(t, err, ErrTooManyRequests)
+
+	first(Success)
+	first(Failure) // ignored: done was already called
+	assert.Equal(t, HalfOpen, b.State())
+
+	second(Success)
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestBreakerIgnoredOutcomes(t *testing.T) {
+	t.Parallel()
+
+	clk := newClock()
+	b := New(WithName("ignored"), WithConsecutiveFailures(2), WithOpenTimeout(time.Second), withNow(clk.Now))
+
+	canceled := func(context.Context) error { return context.Canceled }
+
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	require.ErrorIs(t, b.Execute(t.Context(), canceled), context.Canceled)
+	assert.Equal(t, Counts{Requests: 1, Failures: 1, ConsecutiveFailures: 1}, b.Counts(), "a cancellation isn't recorded")
+
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	require.Equal(t, Open, b.State())
+
+	clk.Advance(time.Second)
+
+	require.ErrorIs(t, b.Execute(t.Context(), canceled), context.Canceled)
+	assert.Equal(t, HalfOpen, b.State(), "a cancelled probe doesn't close the breaker")
+
+	require.NoError(t, b.Execute(t.Context(), succeed), "the probe slot was freed")
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestBreakerHalfOpenFailureReopens(t *testing.T) {
+	t.Parallel()
+
+	clk := newClock()
+	b := New(WithName("reopen"), WithConsecutiveFailures(1), WithOpenTimeout(time.Second), withNow(clk.Now))
+
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	clk.Advance(time.Second)
+
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	assert.Equal(t, Open, b.State())
+
+	clk.Advance(500 * time.Millisecond)
+	require.ErrorIs(t, b.Execute(t.Context(), succeed), ErrOpen, "the open timeout restarts")
+}
+
+func TestBreakerIgnoresStaleOutcomes(t *testing.T) {
+	t.Parallel()
+
+	b := New(WithName("stale"), WithConsecutiveFailures(1), withNow(newClock().Now))
+
+	slow, err := b.Allow(t.Context())
+	require.NoError(t, err)
+
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	require.Equal(t, Open, b.State())
+
+	b.Reset()
+	slow(Failure) // admitted before the breaker opened, so it doesn't count
+	assert.Equal(t, Closed, b.State())
+	assert.Equal(t, Counts{}, b.Counts())
+}
+
+func TestBreakerClassifier(t *testing.T) {
+	t.Parallel()
+
+	errInvalid := errors.New("invalid")
+
+	b := New(WithName("classifier"), WithConsecutiveFailures(1), withNow(newClock().Now),
+		WithClassifier(func(err error) Outcome {
+			if errors.Is(err, errInvalid) {
+				return Success
+			}
+
+			return DefaultClassifier(err)
+		}))
+
+	require.ErrorIs(t, b.Execute(t.Context(), func(context.Context) error { return errInvalid }), errInvalid)
+	assert.Equal(t, Closed, b.State())
+	assert.Equal(t, Counts{Requests: 1}, b.Counts(), "recorded as a success")
+
+	assert.Equal(t, Success, DefaultClassifier(nil))
+	assert.Equal(t, Ignored, DefaultClassifier(context.Canceled))
+	assert.Equal(t, Failure, DefaultClassifier(context.DeadlineExceeded))
+	assert.Equal(t, Failure, DefaultClassifier(errBoom))
+}
+
+func TestBreakerOnStateChange(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mutex   sync.Mutex
+		changes []string
+	)
+
+	clk := newClock()
+	b := New(WithName("callback"), WithConsecutiveFailures(1), WithOpenTimeout(time.Second), withNow(clk.Now),
+		WithOnStateChange(func(name, key string, from, to State) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			assert.Equal(t, "callback", name)
+			assert.Empty(t, key)
+
+			changes = append(changes, from.String()+"->"+to.String())
+		}))
+
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	clk.Advance(time.Second)
+	require.NoError(t, b.Execute(t.Context(), succeed))
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed"}, changes)
+}
+
+func TestNilBreaker(t *testing.T) {
+	t.Parallel()
+
+	var b *Breaker
+
+	done, err := b.Allow(t.Context())
+	require.NoError(t, err)
+	done(Failure)
+
+	require.ErrorIs(t, b.Execute(t.Context(), fail), errBoom)
+	assert.Equal(t, Failure, b.Classify(errBoom))
+}
+
+func TestBreakerConcurrentUse(t *testing.T) {
+	t.Parallel()
+
+	b := New(WithName("concurrent"), WithConsecutiveFailures(0), WithFailureRate(0.9, 1000))
+
+	var wg sync.WaitGroup
+
+	for i := range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range 100 {
+				if (i+j)%2 == 0 {
+					_ = b.Execute(t.Context(), fail)
+				} else {
+					_ = b.Execute(t.Context(), succeed)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, Closed, b.State())
+	assert.Equal(t, 800, b.Counts().Requests)
+}
+
+func TestGroup(t *testing.T) {
+	t.Parallel()
+
+	g := NewGroup(WithName("group"), WithConsecutiveFailures(1), withNow(newClock().Now))
+
+	a := g.Get("a.example.com")
+	assert.Same(t, a, g.Get("a.example.com"))
+	assert.Equal(t, "a.example.com", a.Key())
+	assert.Equal(t, "group", a.Name())
+
+	require.ErrorIs(t, a.Execute(t.Context(), fail), errBoom)
+	require.NoError(t, g.Get("b.example.com").Execute(t.Context(), succeed))
+
+	assert.Equal(t, []string{"a.example.com", "b.example.com"}, g.Keys())
+	assert.Equal(t, map[string]State{"a.example.com": Open, "b.example.com": Closed}, g.States())
+}
+
+func TestGroupEvictsIdleBreakers(t *testing.T) {
+	t.Parallel()
+
+	clk := newClock()
+	g := NewGroup(WithName("group-evict"), WithConsecutiveFailures(1), WithOpenTimeout(time.Hour),
+		WithIdleTimeout(time.Minute), withNow(clk.Now))
+
+	idle := g.Get("idle.example.com")
+	require.NoError(t, idle.Execute(t.Context(), succeed))
+	require.ErrorIs(t, g.Get("open.example.com").Execute(t.Context(), fail), errBoom)
+
+	clk.Advance(30 * time.Second)
+	g.Get("busy.example.com")
+	clk.Advance(30 * time.Second)
+	g.Get("busy.example.com")
+
+	assert.Equal(t, []string{"busy.example.com", "open.example.com"}, g.Keys(), "an open breaker is kept")
+	assert.NotSame(t, idle, g.Get("idle.example.com"), "an evicted key gets a fresh breaker")
+}
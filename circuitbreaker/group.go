@@ -0,0 +1,132 @@
+package circuitbreaker
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Group keeps an independent Breaker per key, such as per host or per
+// endpoint, so that one failing dependency doesn't block calls to healthy ones.
+// Breakers are created on first use and share the Group's options. All methods
+// are safe for concurrent use.
+//
+// Breakers of keys left unused for the idle timeout (see WithIdleTimeout) are
+// evicted along with their metric series, so keys may come from an unbounded
+// set such as per-tenant hosts. A breaker that is open, or has probe calls in
+// flight, is never evicted.
+type Group struct {
+	opts *options
+
+	mutex     sync.RWMutex
+	breakers  map[string]*groupEntry
+	lastSweep time.Time
+}
+
+// groupEntry is a Group's breaker for one key.
+type groupEntry struct {
+	breaker  *Breaker
+	lastUsed atomic.Int64 // Unix nanoseconds of the last Get
+}
+
+// NewGroup creates an empty Group whose breakers are configured with opts.
+//
+// Example:
+//
+//	hosts := circuitbreaker.NewGroup(circuitbreaker.WithName("upstream"))
+//	err := hosts.Get(req.URL.Host).Execute(ctx, call)
+func NewGroup(opts ...Option) *Group {
+	o := newOptions(opts)
+
+	return &Group{
+		opts:      o,
+		breakers:  make(map[string]*groupEntry),
+		lastSweep: o.now(),
+	}
+}
+
+// Get returns the breaker for key, creating it if needed, and marks the key as used.
+func (g *Group) Get(key string) *Breaker {
+	now := g.opts.now()
+
+	g.mutex.RLock()
+	entry, ok := g.breakers[key]
+	sweep := g.shouldSweepLocked(now)
+	g.mutex.RUnlock()
+
+	if ok && !sweep {
+		entry.lastUsed.Store(now.UnixNano())
+
+		return entry.breaker
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.evictIdleLocked(now)
+
+	if entry, ok = g.breakers[key]; !ok {
+		entry = &groupEntry{breaker: newBreaker(g.opts, key)}
+		g.breakers[key] = entry
+	}
+
+	entry.lastUsed.Store(now.UnixNano())
+
+	return entry.breaker
+}
+
+// Keys returns the keys that have a breaker, in sorted order.
+func (g *Group) Keys() []string {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	return slices.Sorted(maps.Keys(g.breakers))
+}
+
+// States returns the current state of every breaker, by key.
+func (g *Group) States() map[string]State {
+	g.mutex.RLock()
+	entries := maps.Clone(g.breakers)
+	g.mutex.RUnlock()
+
+	states := make(map[string]State, len(entries))
+	for key, entry := range entries {
+		states[key] = entry.breaker.State()
+	}
+
+	return states
+}
+
+// shouldSweepLocked reports whether an idle timeout has passed since the last
+// eviction sweep. The map is scanned at most once per idle timeout, so a key is
+// evicted between one and two idle timeouts after its last use.
+func (g *Group) shouldSweepLocked(now time.Time) bool {
+	return g.opts.idleTimeout > 0 && now.Sub(g.lastSweep) >= g.opts.idleTimeout
+}
+
+// evictIdleLocked drops the breakers of keys unused for the idle timeout, and
+// deletes their metric series.
+func (g *Group) evictIdleLocked(now time.Time) {
+	if !g.shouldSweepLocked(now) {
+		return
+	}
+
+	g.lastSweep = now
+
+	for key, entry := range g.breakers {
+		if now.Sub(time.Unix(0, entry.lastUsed.Load())) < g.opts.idleTimeout || entry.breaker.busy(now) {
+			continue
+		}
+
+		delete(g.breakers, key)
+
+		labels := prometheus.Labels{"breaker": g.opts.name, "key": key}
+		breakerState.Delete(labels)
+		breakerRejections.Delete(labels)
+		breakerStateChanges.DeletePartialMatch(labels)
+	}
+}
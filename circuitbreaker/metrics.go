@@ -0,0 +1,31 @@
+package circuitbreaker
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Prometheus metrics for monitoring circuit breakers. Every metric is labeled
+// with the breaker's name (see WithName) and its key within a Group, which is
+// empty for standalone breakers.
+
+var (
+	// breakerState tracks the current State of each breaker (0 closed, 1 open, 2 half-open).
+	breakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{ //nolint:gochecknoglobals
+		Name: "circuit_breaker_state",
+		Help: "The current state of the circuit breaker: 0 closed, 1 open, 2 half-open",
+	}, []string{"breaker", "key"})
+
+	// breakerStateChanges counts state transitions, labeled with both states.
+	breakerStateChanges = promauto.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "circuit_breaker_state_changes_total",
+		Help: "The total number of circuit breaker state changes",
+	}, []string{"breaker", "key", "from", "to"})
+
+	// breakerRejections counts calls refused because the breaker was open or
+	// its half-open probes were all in flight.
+	breakerRejections = promauto.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "circuit_breaker_rejections_total",
+		Help: "The total number of calls rejected by the circuit breaker",
+	}, []string{"breaker", "key"})
+)
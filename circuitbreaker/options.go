@@ -0,0 +1,177 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	defaultName                = "circuit_breaker"
+	defaultConsecutiveFailures = 5
+	defaultFailureRate         = 0.5
+	defaultMinRequests         = 20
+	defaultWindow              = time.Minute
+	defaultWindowBuckets       = 10
+	defaultOpenTimeout         = 30 * time.Second
+	defaultHalfOpenRequests    = 1
+	defaultIdleTimeout         = 10 * time.Minute
+)
+
+// options holds the configuration accumulated from Option values before a breaker is built.
+type options struct {
+	name                string
+	consecutiveFailures int
+	failureRate         float64
+	minRequests         int
+	window              time.Duration
+	windowBuckets       int
+	openTimeout         time.Duration
+	halfOpenRequests    int
+	idleTimeout         time.Duration
+	classify            func(err error) Outcome
+	onStateChange       func(name, key string, from, to State)
+	now                 func() time.Time
+}
+
+// Option is a functional option for configuring a Breaker or Group during creation.
+type Option func(*options)
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		name:                defaultName,
+		consecutiveFailures: defaultConsecutiveFailures,
+		failureRate:         defaultFailureRate,
+		minRequests:         defaultMinRequests,
+		window:              defaultWindow,
+		windowBuckets:       defaultWindowBuckets,
+		openTimeout:         defaultOpenTimeout,
+		halfOpenRequests:    defaultHalfOpenRequests,
+		idleTimeout:         defaultIdleTimeout,
+		classify:            DefaultClassifier,
+		now:                 time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.windowBuckets < 1 {
+		o.windowBuckets = 1
+	}
+
+	if o.window <= 0 {
+		o.window = defaultWindow
+	}
+
+	if o.halfOpenRequests < 1 {
+		o.halfOpenRequests = 1
+	}
+
+	return o
+}
+
+// DefaultClassifier is the classifier used unless WithClassifier is given. A
+// nil error is a Success and every other error a Failure, except
+// context.Canceled, which is Ignored: it means the caller gave up, which says
+// nothing about the dependency.
+func DefaultClassifier(err error) Outcome {
+	switch {
+	case err == nil:
+		return Success
+	case errors.Is(err, context.Canceled):
+		return Ignored
+	default:
+		return Failure
+	}
+}
+
+// WithName sets the name used in log events and in Prometheus metrics labels.
+// If not specified, the default name "circuit_breaker" is used.
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// WithConsecutiveFailures trips the breaker after n failures in a row.
+// Non-positive values disable this threshold. The default is 5.
+func WithConsecutiveFailures(n int) Option {
+	return func(o *options) {
+		o.consecutiveFailures = n
+	}
+}
+
+// WithFailureRate trips the breaker once the fraction of failed calls within
+// the sliding window reaches rate, provided the window holds at least
+// minRequests calls. A non-positive rate disables this threshold. The default
+// is a rate of 0.5 over at least 20 calls.
+func WithFailureRate(rate float64, minRequests int) Option {
+	return func(o *options) {
+		o.failureRate = rate
+		o.minRequests = minRequests
+	}
+}
+
+// WithWindow sets the length of the sliding window used by WithFailureRate and
+// the number of buckets it is split into; outcomes expire one bucket at a time.
+// The default is one minute split into 10 buckets.
+func WithWindow(size time.Duration, buckets int) Option {
+	return func(o *options) {
+		o.window = size
+		o.windowBuckets = buckets
+	}
+}
+
+// WithOpenTimeout sets how long an open breaker rejects calls before letting
+// probe calls through in the half-open state. The default is 30 seconds.
+func WithOpenTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.openTimeout = timeout
+	}
+}
+
+// WithHalfOpenRequests sets how many probe calls a half-open breaker admits at
+// once. The breaker closes after that many probes succeed and reopens on the
+// first probe that fails. The default is 1.
+func WithHalfOpenRequests(n int) Option {
+	return func(o *options) {
+		o.halfOpenRequests = n
+	}
+}
+
+// WithIdleTimeout sets how long a Group keeps the breaker of a key that isn't
+// used. Breakers that are open or have probe calls in flight are kept
+// regardless. Non-positive values mean breakers are never evicted. The default
+// is 10 minutes. It has no effect on a standalone Breaker.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.idleTimeout = timeout
+	}
+}
+
+// WithClassifier sets the function deciding the Outcome of a call from its
+// error. Errors caused by the caller, such as validation errors, are usually
+// best classified as Success (the dependency answered) or Ignored. The default
+// is DefaultClassifier.
+func WithClassifier(classify func(err error) Outcome) Option {
+	return func(o *options) {
+		o.classify = classify
+	}
+}
+
+// WithOnStateChange registers a callback invoked after every state change. key
+// is the breaker's key within its Group, or empty for a standalone breaker. It
+// is called after the breaker's lock is released, so it may use the breaker.
+func WithOnStateChange(onStateChange func(name, key string, from, to State)) Option {
+	return func(o *options) {
+		o.onStateChange = onStateChange
+	}
+}
+
+// withNow replaces the clock, for tests.
+func withNow(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
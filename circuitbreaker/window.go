@@ -0,0 +1,90 @@
+package circuitbreaker
+
+import "time"
+
+// bucket holds the outcomes recorded during one slice of a window.
+type bucket struct {
+	successes int
+	failures  int
+}
+
+// window is a time-based sliding window of call outcomes. It is split into
+// fixed-length buckets kept in a ring, so old outcomes expire a bucket at a
+// time rather than one by one.
+type window struct {
+	buckets    []bucket
+	bucketSize time.Duration
+	head       int       // index of the bucket covering headStart
+	headStart  time.Time // start of the newest bucket
+}
+
+func newWindow(size time.Duration, buckets int) *window {
+	bucketSize := size / time.Duration(buckets)
+	if bucketSize <= 0 {
+		bucketSize = 1
+	}
+
+	return &window{
+		buckets:    make([]bucket, buckets),
+		bucketSize: bucketSize,
+	}
+}
+
+// advance moves the window forward to now, clearing buckets that fell out of it.
+func (w *window) advance(now time.Time) {
+	if w.headStart.IsZero() {
+		w.headStart = now
+
+		return
+	}
+
+	elapsed := int(now.Sub(w.headStart) / w.bucketSize)
+	if elapsed <= 0 {
+		return
+	}
+
+	if elapsed >= len(w.buckets) {
+		clear(w.buckets)
+		w.head = 0
+		w.headStart = now
+
+		return
+	}
+
+	for range elapsed {
+		w.head = (w.head + 1) % len(w.buckets)
+		w.buckets[w.head] = bucket{}
+	}
+
+	w.headStart = w.headStart.Add(time.Duration(elapsed) * w.bucketSize)
+}
+
+// record adds one outcome to the newest bucket.
+func (w *window) record(now time.Time, failed bool) {
+	w.advance(now)
+
+	if failed {
+		w.buckets[w.head].failures++
+	} else {
+		w.buckets[w.head].successes++
+	}
+}
+
+// totals returns the number of calls and failures within the window at now.
+func (w *window) totals(now time.Time) (requests, failures int) {
+	w.advance(now)
+
+	for _, b := range w.buckets {
+		requests += b.successes + b.failures
+		failures += b.failures
+	}
+
+	return requests, failures
+}
+
+// reset forgets every recorded outcome.
+func (w *window) reset() {
+	clear(w.buckets)
+	w.head = 0
+	w.headStart = time.Time{}
+}
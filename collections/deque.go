@@ -0,0 +1,115 @@
+package collections
+
+import (
+	"iter"
+
+	"github.com/amp-labs/amp-common/optional"
+)
+
+// Deque is a double-ended queue: values can be pushed and popped at both ends
+// in amortized O(1), and read by position in O(1). It grows as needed.
+type Deque[T any] interface {
+	// PushFront adds value at the front.
+	PushFront(value T)
+
+	// PushBack adds value at the back.
+	PushBack(value T)
+
+	// PopFront removes and returns the value at the front, or None if the deque is empty.
+	PopFront() optional.Value[T]
+
+	// PopBack removes and returns the value at the back, or None if the deque is empty.
+	PopBack() optional.Value[T]
+
+	// Front returns the value at the front without removing it, or None if the deque is empty.
+	Front() optional.Value[T]
+
+	// Back returns the value at the back without removing it, or None if the deque is empty.
+	Back() optional.Value[T]
+
+	// Get returns the value at the given zero-based index from the front, or
+	// None if the index is out of range.
+	Get(index int) optional.Value[T]
+
+	// Size returns the number of values in the deque.
+	Size() int
+
+	// Clear removes all values.
+	Clear()
+
+	// Entries returns the values from front to back as a new slice.
+	Entries() []T
+
+	// Seq returns an iterator over the values from front to back.
+	Seq() iter.Seq[T]
+}
+
+// NewDeque creates an empty Deque.
+func NewDeque[T any]() Deque[T] {
+	return &deque[T]{}
+}
+
+type deque[T any] struct {
+	ring ring[T]
+}
+
+func (d *deque[T]) PushFront(value T) {
+	if d.ring.full() {
+		d.ring.grow()
+	}
+
+	d.ring.pushFront(value)
+}
+
+func (d *deque[T]) PushBack(value T) {
+	if d.ring.full() {
+		d.ring.grow()
+	}
+
+	d.ring.pushBack(value)
+}
+
+func (d *deque[T]) PopFront() optional.Value[T] {
+	return toOptional(d.ring.popFront())
+}
+
+func (d *deque[T]) PopBack() optional.Value[T] {
+	return toOptional(d.ring.popBack())
+}
+
+func (d *deque[T]) Front() optional.Value[T] {
+	return toOptional(d.ring.get(0))
+}
+
+func (d *deque[T]) Back() optional.Value[T] {
+	return toOptional(d.ring.get(d.ring.size - 1))
+}
+
+func (d *deque[T]) Get(index int) optional.Value[T] {
+	return toOptional(d.ring.get(index))
+}
+
+func (d *deque[T]) Size() int {
+	return d.ring.size
+}
+
+func (d *deque[T]) Clear() {
+	d.ring.clear()
+}
+
+func (d *deque[T]) Entries() []T {
+	return d.ring.entries()
+}
+
+func (d *deque[T]) Seq() iter.Seq[T] {
+	return d.ring.seq()
+}
+
+// toOptional converts a (value, ok) pair to an optional.Value.
+func toOptional[T any](value T, ok bool) optional.Value[T] {
+	if !ok {
+		return optional.None[T]()
+	}
+
+	return optional.Some(value)
+}
@@ -0,0 +1,117 @@
+package collections
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/amp-labs/amp-common/optional"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDequeBothEnds(t *testing.T) {
+	t.Parallel()
+
+	d := NewDeque[int]()
+	assert.True(t, d.PopFront().Empty())
+	assert.True(t, d.PopBack().Empty())
+	assert.True(t, d.Front().Empty())
+	assert.True(t, d.Back().Empty())
+
+	d.PushBack(2)
+	d.PushBack(3)
+	d.PushFront(1)
+	d.PushFront(0)
+
+	assert.Equal(t, 4, d.Size())
+	assert.Equal(t, optional.Some(0), d.Front())
+	assert.Equal(t, optional.Some(3), d.Back())
+	assert.Equal(t, []int{0, 1, 2, 3}, d.Entries())
+	assert.Equal(t, []int{0, 1, 2, 3}, slices.Collect(d.Seq()))
+
+	assert.Equal(t, optional.Some(0), d.PopFront())
+	assert.Equal(t, optional.Some(3), d.PopBack())
+	assert.Equal(t, []int{1, 2}, d.Entries())
+}
+
+func TestDequeGet(t *testing.T) {
+	t.Parallel()
+
+	d := NewDeque[string]()
+	d.PushBack("b")
+	d.PushFront("a")
+	d.PushBack("c")
+
+	assert.Equal(t, optional.Some("a"), d.Get(0))
+	assert.Equal(t, optional.Some("c"), d.Get(2))
+	assert.True(t, d.Get(-1).Empty())
+	assert.True(t, d.Get(3).Empty())
+}
+
+func TestDequeWrapsAndGrows(t *testing.T) {
+	t.Parallel()
+
+	d := NewDeque[int]()
+	want := make([]int, 0, 100)
+
+	// Alternate ends so the contents wrap around the buffer while it grows.
+	for i := range 50 {
+		d.PushBack(i)
+		d.PushFront(-i - 1)
+
+		want = append([]int{-i - 1}, want...)
+		want = append(want, i)
+	}
+
+	assert.Equal(t, want, d.Entries())
+
+	for i := range 30 {
+		assert.Equal(t, optional.Some(want[i]), d.PopFront())
+	}
+
+	d.PushBack(100)
+	want = append(want[30:], 100)
+	assert.Equal(t, want, d.Entries())
+
+	d.Clear()
+	assert.Equal(t, 0, d.Size())
+	assert.Empty(t, d.Entries())
+}
+
+func TestThreadSafeDeque(t *testing.T) {
+	t.Parallel()
+
+	d := NewThreadSafeDeque(NewDeque[int]())
+
+	assert.Nil(t, NewThreadSafeDeque[int](nil))
+	assert.Same(t, d, NewThreadSafeDeque(d))
+
+	const workers, perWorker = 8, 100
+
+	var wg sync.WaitGroup
+
+	for w := range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range perWorker {
+				if w%2 == 0 {
+					d.PushFront(i)
+				} else {
+					d.PushBack(i)
+				}
+
+				for range d.Seq() {
+					break
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, workers*perWorker, d.Size())
+	assert.Len(t, d.Entries(), workers*perWorker)
+}
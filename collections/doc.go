@@ -0,0 +1,9 @@
+// Package collections provides generic in-memory queues: a PriorityQueue with
+// handles for updating or removing queued values, a double-ended Deque, and a
+// fixed-capacity RingBuffer that either overwrites its oldest value or rejects
+// new ones when full.
+//
+// Like the maps and set packages, the implementations are not safe for
+// concurrent use; wrap them with NewThreadSafePriorityQueue, NewThreadSafeDeque
+// or NewThreadSafeRingBuffer when they are shared between goroutines.
+package collections
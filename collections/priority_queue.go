@@ -0,0 +1,223 @@
+package collections
+
+import (
+	"container/heap"
+	"iter"
+	"slices"
+
+	"github.com/amp-labs/amp-common/optional"
+)
+
+// PriorityQueue holds values ordered by a less function given at creation:
+// less(a, b) reports whether a should be popped before b. Values of equal
+// priority, those for which neither less(a, b) nor less(b, a) holds, are
+// popped in the order they were pushed.
+//
+// Push returns a Handle that identifies the queued value, so that it can later
+// be re-prioritized with Update or taken out of the queue with Remove. A
+// handle stops being valid once its value leaves the queue.
+type PriorityQueue[T any] interface {
+	// Push adds value to the queue in O(log n) and returns its handle.
+	Push(value T) Handle[T]
+
+	// Pop removes and returns the highest-priority value in O(log n), or None if the queue is empty.
+	Pop() optional.Value[T]
+
+	// Peek returns the highest-priority value without removing it, or None if the queue is empty.
+	Peek() optional.Value[T]
+
+	// Update replaces the value behind handle and moves it to its new position
+	// in O(log n). It keeps its place among values of equal priority. Returns
+	// false if the handle's value is no longer queued.
+	Update(handle Handle[T], value T) bool
+
+	// Remove takes the value behind handle out of the queue in O(log n) and
+	// returns it, or returns None if the value is no longer queued.
+	Remove(handle Handle[T]) optional.Value[T]
+
+	// Size returns the number of queued values.
+	Size() int
+
+	// Clear removes all values. Handles to them are no longer valid.
+	Clear()
+
+	// Entries returns the queued values in priority order, without removing them.
+	// This sorts a copy of the queue, so it costs O(n log n).
+	Entries() []T
+
+	// Seq returns an iterator over the queued values in priority order, like Entries.
+	Seq() iter.Seq[T]
+}
+
+// Handle identifies a value pushed onto a PriorityQueue. The zero Handle does
+// not refer to any value.
+type Handle[T any] struct {
+	item *priorityItem[T]
+}
+
+// NewPriorityQueue creates an empty PriorityQueue ordered by less, which must be non-nil.
+//
+// Example:
+//
+//	// Earliest deadline first
+//	q := collections.NewPriorityQueue(func(a, b Job) bool { return a.Deadline.Before(b.Deadline) })
+func NewPriorityQueue[T any](less func(a, b T) bool) PriorityQueue[T] {
+	return &priorityQueue[T]{heap: priorityHeap[T]{less: less}}
+}
+
+// priorityItem is a queued value. seq breaks ties between values of equal
+// priority, and index is the item's position in the heap, or -1 once it has
+// left the queue.
+type priorityItem[T any] struct {
+	value T
+	seq   uint64
+	index int
+}
+
+type priorityQueue[T any] struct {
+	heap priorityHeap[T]
+	seq  uint64
+}
+
+func (q *priorityQueue[T]) Push(value T) Handle[T] {
+	q.seq++
+
+	item := &priorityItem[T]{value: value, seq: q.seq}
+	heap.Push(&q.heap, item)
+
+	return Handle[T]{item: item}
+}
+
+func (q *priorityQueue[T]) Pop() optional.Value[T] {
+	if q.heap.Len() == 0 {
+		return optional.None[T]()
+	}
+
+	item, _ := heap.Pop(&q.heap).(*priorityItem[T])
+
+	return optional.Some(item.value)
+}
+
+func (q *priorityQueue[T]) Peek() optional.Value[T] {
+	if q.heap.Len() == 0 {
+		return optional.None[T]()
+	}
+
+	return optional.Some(q.heap.items[0].value)
+}
+
+func (q *priorityQueue[T]) Update(handle Handle[T], value T) bool {
+	if !q.owns(handle) {
+		return false
+	}
+
+	handle.item.value = value
+	heap.Fix(&q.heap, handle.item.index)
+
+	return true
+}
+
+func (q *priorityQueue[T]) Remove(handle Handle[T]) optional.Value[T] {
+	if !q.owns(handle) {
+		return optional.None[T]()
+	}
+
+	item, _ := heap.Remove(&q.heap, handle.item.index).(*priorityItem[T])
+
+	return optional.Some(item.value)
+}
+
+// owns reports whether handle refers to a value that is queued in q.
+func (q *priorityQueue[T]) owns(handle Handle[T]) bool {
+	item := handle.item
+
+	return item != nil && item.index >= 0 && item.index < len(q.heap.items) && q.heap.items[item.index] == item
+}
+
+func (q *priorityQueue[T]) Size() int {
+	return q.heap.Len()
+}
+
+func (q *priorityQueue[T]) Clear() {
+	for _, item := range q.heap.items {
+		item.index = -1
+	}
+
+	q.heap.items = nil
+}
+
+func (q *priorityQueue[T]) Entries() []T {
+	items := slices.Clone(q.heap.items)
+
+	slices.SortFunc(items, func(a, b *priorityItem[T]) int {
+		switch {
+		case q.heap.before(a, b):
+			return -1
+		case q.heap.before(b, a):
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	values := make([]T, len(items))
+	for i, item := range items {
+		values[i] = item.value
+	}
+
+	return values
+}
+
+func (q *priorityQueue[T]) Seq() iter.Seq[T] {
+	return slices.Values(q.Entries())
+}
+
+// priorityHeap implements heap.Interface over queued items.
+type priorityHeap[T any] struct {
+	items []*priorityItem[T]
+	less  func(a, b T) bool
+}
+
+// before reports whether a should be popped before b.
+func (h *priorityHeap[T]) before(a, b *priorityItem[T]) bool {
+	switch {
+	case h.less(a.value, b.value):
+		return true
+	case h.less(b.value, a.value):
+		return false
+	default:
+		// Equal priority: preserve submission order.
+		return a.seq < b.seq
+	}
+}
+
+func (h *priorityHeap[T]) Len() int {
+	return len(h.items)
+}
+
+func (h *priorityHeap[T]) Less(i, j int) bool {
+	return h.before(h.items[i], h.items[j])
+}
+
+func (h *priorityHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+func (h *priorityHeap[T]) Push(x any) {
+	item, _ := x.(*priorityItem[T])
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+
+func (h *priorityHeap[T]) Pop() any {
+	old := h.items
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil // release the item for GC
+	item.index = -1
+	h.items = old[:n-1]
+
+	return item
+}
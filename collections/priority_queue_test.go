@@ -0,0 +1,170 @@
+package collections
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/amp-labs/amp-common/optional"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type job struct {
+	name     string
+	priority int
+}
+
+// byPriority orders jobs with the highest priority first.
+func byPriority(a, b job) bool {
+	return a.priority > b.priority
+}
+
+func drain[T any](q PriorityQueue[T]) []T {
+	var out []T
+
+	for value, ok := q.Pop().Get(); ok; value, ok = q.Pop().Get() {
+		out = append(out, value)
+	}
+
+	return out
+}
+
+func names(jobs []job) []string {
+	out := make([]string, len(jobs))
+	for i, j := range jobs {
+		out[i] = j.name
+	}
+
+	return out
+}
+
+func TestPriorityQueueOrdersByPriority(t *testing.T) {
+	t.Parallel()
+
+	q := NewPriorityQueue(func(a, b int) bool { return a < b })
+
+	for _, v := range []int{5, 1, 4, 2, 3} {
+		q.Push(v)
+	}
+
+	assert.Equal(t, 5, q.Size())
+	assert.Equal(t, optional.Some(1), q.Peek())
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, q.Entries())
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, slices.Collect(q.Seq()))
+	assert.Equal(t, 5, q.Size(), "Entries and Seq must not consume the queue")
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, drain(q))
+	assert.True(t, q.Pop().Empty())
+	assert.True(t, q.Peek().Empty())
+}
+
+func TestPriorityQueueEqualPrioritiesAreFIFO(t *testing.T) {
+	t.Parallel()
+
+	q := NewPriorityQueue(byPriority)
+	q.Push(job{"a", 1})
+	q.Push(job{"b", 2})
+	q.Push(job{"c", 1})
+	q.Push(job{"d", 2})
+	q.Push(job{"e", 1})
+
+	assert.Equal(t, []string{"b", "d", "a", "c", "e"}, names(q.Entries()))
+	assert.Equal(t, []string{"b", "d", "a", "c", "e"}, names(drain(q)))
+}
+
+func TestPriorityQueueUpdate(t *testing.T) {
+	t.Parallel()
+
+	q := NewPriorityQueue(byPriority)
+	q.Push(job{"a", 3})
+	low := q.Push(job{"b", 1})
+	q.Push(job{"c", 2})
+
+	require.True(t, q.Update(low, job{"b", 5}))
+	assert.Equal(t, "b", q.Peek().GetOrPanic().name)
+
+	require.True(t, q.Update(low, job{"b", 0}))
+	assert.Equal(t, []string{"a", "c", "b"}, names(drain(q)))
+
+	assert.False(t, q.Update(low, job{"b", 9}), "handle is stale once its value is popped")
+	assert.Equal(t, 0, q.Size())
+}
+
+func TestPriorityQueueUpdateKeepsPlaceAmongEquals(t *testing.T) {
+	t.Parallel()
+
+	q := NewPriorityQueue(byPriority)
+	first := q.Push(job{"a", 1})
+	q.Push(job{"b", 1})
+
+	require.True(t, q.Update(first, job{"a2", 1}))
+	assert.Equal(t, []string{"a2", "b"}, names(drain(q)))
+}
+
+func TestPriorityQueueRemove(t *testing.T) {
+	t.Parallel()
+
+	q := NewPriorityQueue(byPriority)
+	q.Push(job{"a", 3})
+	middle := q.Push(job{"b", 2})
+	q.Push(job{"c", 1})
+
+	assert.Equal(t, optional.Some(job{"b", 2}), q.Remove(middle))
+	assert.True(t, q.Remove(middle).Empty(), "removing twice is a no-op")
+	assert.Equal(t, []string{"a", "c"}, names(drain(q)))
+}
+
+func TestPriorityQueueInvalidHandles(t *testing.T) {
+	t.Parallel()
+
+	q := NewPriorityQueue(byPriority)
+	other := NewPriorityQueue(byPriority)
+	foreign := other.Push(job{"x", 1})
+	cleared := q.Push(job{"a", 1})
+
+	assert.False(t, q.Update(Handle[job]{}, job{"z", 1}))
+	assert.True(t, q.Remove(Handle[job]{}).Empty())
+	assert.False(t, q.Update(foreign, job{"x", 2}), "handles from another queue are rejected")
+	assert.True(t, q.Remove(foreign).Empty())
+	assert.Equal(t, 1, other.Size())
+
+	q.Clear()
+	assert.Equal(t, 0, q.Size())
+	assert.False(t, q.Update(cleared, job{"a", 2}))
+	assert.True(t, q.Remove(cleared).Empty())
+}
+
+func TestThreadSafePriorityQueue(t *testing.T) {
+	t.Parallel()
+
+	inner := NewPriorityQueue(func(a, b int) bool { return a < b })
+	q := NewThreadSafePriorityQueue(inner)
+
+	assert.Nil(t, NewThreadSafePriorityQueue[int](nil))
+	assert.Same(t, q, NewThreadSafePriorityQueue(q))
+
+	const workers, perWorker = 8, 100
+
+	var wg sync.WaitGroup
+
+	for w := range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range perWorker {
+				handle := q.Push(w*perWorker + i)
+				if i%2 == 0 {
+					q.Remove(handle)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	values := drain(q)
+	assert.Len(t, values, workers*perWorker/2)
+	assert.True(t, slices.IsSorted(values))
+}
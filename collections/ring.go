@@ -0,0 +1,116 @@
+package collections
+
+import "iter"
+
+// minRingCapacity is the capacity a growing ring starts with.
+const minRingCapacity = 8
+
+// ring is a circular buffer shared by Deque and RingBuffer. It holds size
+// values starting at head, wrapping around the end of buf. Callers make sure
+// there is room before pushing.
+type ring[T any] struct {
+	buf  []T
+	head int
+	size int
+}
+
+// slot returns the position in buf of the value at index i from the front.
+func (r *ring[T]) slot(i int) int {
+	return (r.head + i) % len(r.buf)
+}
+
+func (r *ring[T]) full() bool {
+	return r.size == len(r.buf)
+}
+
+// grow doubles the capacity, moving the values to the start of a new buffer.
+func (r *ring[T]) grow() {
+	buf := make([]T, max(2*len(r.buf), minRingCapacity))
+	r.copyTo(buf)
+	r.buf = buf
+	r.head = 0
+}
+
+// copyTo copies the values, front to back, to the start of dst.
+func (r *ring[T]) copyTo(dst []T) {
+	if r.size == 0 {
+		return
+	}
+
+	n := copy(dst, r.buf[r.head:min(r.head+r.size, len(r.buf))])
+	copy(dst[n:], r.buf[:r.size-n])
+}
+
+func (r *ring[T]) pushBack(value T) {
+	r.buf[r.slot(r.size)] = value
+	r.size++
+}
+
+func (r *ring[T]) pushFront(value T) {
+	r.head = (r.head - 1 + len(r.buf)) % len(r.buf)
+	r.buf[r.head] = value
+	r.size++
+}
+
+func (r *ring[T]) popFront() (T, bool) {
+	var zero T
+
+	if r.size == 0 {
+		return zero, false
+	}
+
+	value := r.buf[r.head]
+	r.buf[r.head] = zero // release the value for GC
+	r.head = r.slot(1)
+	r.size--
+
+	return value, true
+}
+
+func (r *ring[T]) popBack() (T, bool) {
+	var zero T
+
+	if r.size == 0 {
+		return zero, false
+	}
+
+	last := r.slot(r.size - 1)
+	value := r.buf[last]
+	r.buf[last] = zero // release the value for GC
+	r.size--
+
+	return value, true
+}
+
+func (r *ring[T]) get(index int) (T, bool) {
+	if index < 0 || index >= r.size {
+		var zero T
+
+		return zero, false
+	}
+
+	return r.buf[r.slot(index)], true
+}
+
+func (r *ring[T]) clear() {
+	clear(r.buf)
+	r.head = 0
+	r.size = 0
+}
+
+func (r *ring[T]) entries() []T {
+	values := make([]T, r.size)
+	r.copyTo(values)
+
+	return values
+}
+
+func (r *ring[T]) seq() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := range r.size {
+			if !yield(r.buf[r.slot(i)]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package collections
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/amp-labs/amp-common/optional"
+)
+
+var (
+	// ErrFull is returned by RingBuffer.Push when the buffer is full and was
+	// created with RejectWhenFull.
+	ErrFull = errors.New("ring buffer is full")
+
+	// ErrInvalidCapacity is returned when a RingBuffer is created with a capacity below one.
+	ErrInvalidCapacity = errors.New("capacity must be positive")
+)
+
+// FullPolicy decides what RingBuffer.Push does when the buffer is full.
+type FullPolicy int
+
+const (
+	// OverwriteOldest drops the oldest value to make room for the new one,
+	// which suits keeping the most recent values, such as a history of events.
+	OverwriteOldest FullPolicy = iota
+
+	// RejectWhenFull leaves the buffer unchanged and makes Push return ErrFull,
+	// which suits bounded work queues that should apply backpressure.
+	RejectWhenFull
+)
+
+// RingBuffer is a first-in, first-out queue with a fixed capacity that never
+// allocates after creation. Pushing onto a full buffer either overwrites the
+// oldest value or fails, depending on its FullPolicy.
+type RingBuffer[T any] interface {
+	// Push adds value at the back. If the buffer is full, it either drops the
+	// oldest value first or returns ErrFull, depending on the FullPolicy.
+	Push(value T) error
+
+	// Pop removes and returns the oldest value, or None if the buffer is empty.
+	Pop() optional.Value[T]
+
+	// Peek returns the oldest value without removing it, or None if the buffer is empty.
+	Peek() optional.Value[T]
+
+	// Get returns the value at the given zero-based index from the oldest, or
+	// None if the index is out of range.
+	Get(index int) optional.Value[T]
+
+	// Size returns the number of values in the buffer.
+	Size() int
+
+	// Capacity returns the maximum number of values the buffer holds.
+	Capacity() int
+
+	// IsFull reports whether the buffer holds Capacity values.
+	IsFull() bool
+
+	// Clear removes all values.
+	Clear()
+
+	// Entries returns the values from oldest to newest as a new slice.
+	Entries() []T
+
+	// Seq returns an iterator over the values from oldest to newest.
+	Seq() iter.Seq[T]
+}
+
+// NewRingBuffer creates an empty RingBuffer holding up to capacity values.
+// Returns ErrInvalidCapacity if capacity is less than one.
+func NewRingBuffer[T any](capacity int, policy FullPolicy) (RingBuffer[T], error) {
+	if capacity < 1 {
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidCapacity, capacity)
+	}
+
+	return &ringBuffer[T]{
+		ring:   ring[T]{buf: make([]T, capacity)},
+		policy: policy,
+	}, nil
+}
+
+type ringBuffer[T any] struct {
+	ring   ring[T]
+	policy FullPolicy
+}
+
+func (b *ringBuffer[T]) Push(value T) error {
+	if b.ring.full() {
+		if b.policy == RejectWhenFull {
+			return ErrFull
+		}
+
+		b.ring.popFront()
+	}
+
+	b.ring.pushBack(value)
+
+	return nil
+}
+
+func (b *ringBuffer[T]) Pop() optional.Value[T] {
+	return toOptional(b.ring.popFront())
+}
+
+func (b *ringBuffer[T]) Peek() optional.Value[T] {
+	return toOptional(b.ring.get(0))
+}
+
+func (b *ringBuffer[T]) Get(index int) optional.Value[T] {
+	return toOptional(b.ring.get(index))
+}
+
+func (b *ringBuffer[T]) Size() int {
+	return b.ring.size
+}
+
+func (b *ringBuffer[T]) Capacity() int {
+	return len(b.ring.buf)
+}
+
+func (b *ringBuffer[T]) IsFull() bool {
+	return b.ring.full()
+}
+
+func (b *ringBuffer[T]) Clear() {
+	b.ring.clear()
+}
+
+func (b *ringBuffer[T]) Entries() []T {
+	return b.ring.entries()
+}
+
+func (b *ringBuffer[T]) Seq() iter.Seq[T] {
+	return b.ring.seq()
+}
@@ -0,0 +1,109 @@
+package collections
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/amp-labs/amp-common/optional"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRingBufferInvalidCapacity(t *testing.T) {
+	t.Parallel()
+
+	for _, capacity := range []int{0, -1} {
+		_, err := NewRingBuffer[int](capacity, OverwriteOldest)
+		require.ErrorIs(t, err, ErrInvalidCapacity)
+	}
+}
+
+func TestRingBufferOverwriteOldest(t *testing.T) {
+	t.Parallel()
+
+	b, err := NewRingBuffer[int](3, OverwriteOldest)
+	require.NoError(t, err)
+
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, b.Push(i))
+	}
+
+	assert.True(t, b.IsFull())
+	assert.Equal(t, 3, b.Size())
+	assert.Equal(t, 3, b.Capacity())
+	assert.Equal(t, []int{3, 4, 5}, b.Entries())
+	assert.Equal(t, []int{3, 4, 5}, slices.Collect(b.Seq()))
+	assert.Equal(t, optional.Some(3), b.Peek())
+	assert.Equal(t, optional.Some(5), b.Get(2))
+	assert.True(t, b.Get(3).Empty())
+
+	assert.Equal(t, optional.Some(3), b.Pop())
+	assert.False(t, b.IsFull())
+	require.NoError(t, b.Push(6))
+	assert.Equal(t, []int{4, 5, 6}, b.Entries())
+}
+
+func TestRingBufferRejectWhenFull(t *testing.T) {
+	t.Parallel()
+
+	b, err := NewRingBuffer[string](2, RejectWhenFull)
+	require.NoError(t, err)
+
+	require.NoError(t, b.Push("a"))
+	require.NoError(t, b.Push("b"))
+	require.ErrorIs(t, b.Push("c"), ErrFull)
+	assert.Equal(t, []string{"a", "b"}, b.Entries())
+
+	assert.Equal(t, optional.Some("a"), b.Pop())
+	require.NoError(t, b.Push("c"))
+	assert.Equal(t, []string{"b", "c"}, b.Entries())
+
+	b.Clear()
+	assert.Equal(t, 0, b.Size())
+	assert.True(t, b.Pop().Empty())
+	assert.True(t, b.Peek().Empty())
+	assert.Equal(t, 2, b.Capacity())
+}
+
+func TestThreadSafeRingBuffer(t *testing.T) {
+	t.Parallel()
+
+	inner, err := NewRingBuffer[int](64, RejectWhenFull)
+	require.NoError(t, err)
+
+	b := NewThreadSafeRingBuffer(inner)
+
+	assert.Nil(t, NewThreadSafeRingBuffer[int](nil))
+	assert.Same(t, b, NewThreadSafeRingBuffer(b))
+
+	const workers, perWorker = 8, 50
+
+	var (
+		wg       sync.WaitGroup
+		mutex    sync.Mutex
+		accepted int
+	)
+
+	for range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range perWorker {
+				if b.Push(i) == nil {
+					mutex.Lock()
+					accepted++
+					mutex.Unlock()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 64, accepted)
+	assert.True(t, b.IsFull())
+	assert.Len(t, b.Entries(), 64)
+}
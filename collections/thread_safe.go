@@ -0,0 +1,267 @@
+package collections
+
+import (
+	"iter"
+	"slices"
+	"sync"
+
+	"github.com/amp-labs/amp-common/optional"
+)
+
+// NewThreadSafePriorityQueue wraps a PriorityQueue with a sync.RWMutex. Seq
+// iterates over a snapshot, so the loop body may use the queue.
+func NewThreadSafePriorityQueue[T any](q PriorityQueue[T]) PriorityQueue[T] {
+	if q == nil {
+		return nil
+	}
+
+	if tsq, ok := q.(*threadSafePriorityQueue[T]); ok {
+		// Already thread-safe, return as-is
+		return tsq
+	}
+
+	return &threadSafePriorityQueue[T]{internal: q}
+}
+
+type threadSafePriorityQueue[T any] struct {
+	mutex    sync.RWMutex
+	internal PriorityQueue[T]
+}
+
+func (t *threadSafePriorityQueue[T]) Push(value T) Handle[T] {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.Push(value)
+}
+
+func (t *threadSafePriorityQueue[T]) Pop() optional.Value[T] {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.Pop()
+}
+
+func (t *threadSafePriorityQueue[T]) Peek() optional.Value[T] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Peek()
+}
+
+func (t *threadSafePriorityQueue[T]) Update(handle Handle[T], value T) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.Update(handle, value)
+}
+
+func (t *threadSafePriorityQueue[T]) Remove(handle Handle[T]) optional.Value[T] {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.Remove(handle)
+}
+
+func (t *threadSafePriorityQueue[T]) Size() int {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Size()
+}
+
+func (t *threadSafePriorityQueue[T]) Clear() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.internal.Clear()
+}
+
+func (t *threadSafePriorityQueue[T]) Entries() []T {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Entries()
+}
+
+func (t *threadSafePriorityQueue[T]) Seq() iter.Seq[T] {
+	return slices.Values(t.Entries())
+}
+
+// NewThreadSafeDeque wraps a Deque with a sync.RWMutex. Seq iterates over a
+// snapshot, so the loop body may use the deque.
+func NewThreadSafeDeque[T any](d Deque[T]) Deque[T] {
+	if d == nil {
+		return nil
+	}
+
+	if tsd, ok := d.(*threadSafeDeque[T]); ok {
+		// Already thread-safe, return as-is
+		return tsd
+	}
+
+	return &threadSafeDeque[T]{internal: d}
+}
+
+type threadSafeDeque[T any] struct {
+	mutex    sync.RWMutex
+	internal Deque[T]
+}
+
+func (t *threadSafeDeque[T]) PushFront(value T) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.internal.PushFront(value)
+}
+
+func (t *threadSafeDeque[T]) PushBack(value T) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.internal.PushBack(value)
+}
+
+func (t *threadSafeDeque[T]) PopFront() optional.Value[T] {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.PopFront()
+}
+
+func (t *threadSafeDeque[T]) PopBack() optional.Value[T] {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.PopBack()
+}
+
+func (t *threadSafeDeque[T]) Front() optional.Value[T] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Front()
+}
+
+func (t *threadSafeDeque[T]) Back() optional.Value[T] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Back()
+}
+
+func (t *threadSafeDeque[T]) Get(index int) optional.Value[T] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Get(index)
+}
+
+func (t *threadSafeDeque[T]) Size() int {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Size()
+}
+
+func (t *threadSafeDeque[T]) Clear() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.internal.Clear()
+}
+
+func (t *threadSafeDeque[T]) Entries() []T {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Entries()
+}
+
+func (t *threadSafeDeque[T]) Seq() iter.Seq[T] {
+	return slices.Values(t.Entries())
+}
+
+// NewThreadSafeRingBuffer wraps a RingBuffer with a sync.RWMutex. Seq
+// iterates over a snapshot, so the loop body may use the buffer.
+func NewThreadSafeRingBuffer[T any](b RingBuffer[T]) RingBuffer[T] {
+	if b == nil {
+		return nil
+	}
+
+	if tsb, ok := b.(*threadSafeRingBuffer[T]); ok {
+		// Already thread-safe, return as-is
+		return tsb
+	}
+
+	return &threadSafeRingBuffer[T]{internal: b}
+}
+
+type threadSafeRingBuffer[T any] struct {
+	mutex    sync.RWMutex
+	internal RingBuffer[T]
+}
+
+func (t *threadSafeRingBuffer[T]) Push(value T) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.Push(value)
+}
+
+func (t *threadSafeRingBuffer[T]) Pop() optional.Value[T] {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.Pop()
+}
+
+func (t *threadSafeRingBuffer[T]) Peek() optional.Value[T] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Peek()
+}
+
+func (t *threadSafeRingBuffer[T]) Get(index int) optional.Value[T] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Get(index)
+}
+
+func (t *threadSafeRingBuffer[T]) Size() int {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Size()
+}
+
+func (t *threadSafeRingBuffer[T]) Capacity() int {
+	return t.internal.Capacity()
+}
+
+func (t *threadSafeRingBuffer[T]) IsFull() bool {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.IsFull()
+}
+
+func (t *threadSafeRingBuffer[T]) Clear() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.internal.Clear()
+}
+
+func (t *threadSafeRingBuffer[T]) Entries() []T {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Entries()
+}
+
+func (t *threadSafeRingBuffer[T]) Seq() iter.Seq[T] {
+	return slices.Values(t.Entries())
+}
@@ -2,10 +2,10 @@ package dns
 
 import (
 	"net"
-	"sync"
 	"time"
 
-	lru "github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/amp-labs/amp-common/cache"
+	"github.com/amp-labs/amp-common/hashing"
 )
 
 // ipCacheEntry is a cached set of IPs for one host along with the wall-clock
@@ -21,13 +21,12 @@ func (e *ipCacheEntry) isExpired() bool {
 }
 
 // dnsCache is a TTL-aware, size-bounded cache of resolved IP addresses keyed by
-// host. The backing LRU enforces the size bound and a hard maxTTL eviction; the
+// host. The backing LRU cache enforces the size bound and a hard maxTTL eviction; the
 // per-entry expiry (clamped between minTTL and maxTTL) governs freshness so the
 // record's own TTL is honored. A zero-size cache is disabled and every method
 // becomes a no-op, letting callers use it unconditionally.
 type dnsCache struct {
-	ipCache *lru.LRU[string, *ipCacheEntry]
-	mu      sync.RWMutex
+	ipCache cache.Cache[hashing.HashableString, *ipCacheEntry]
 	enabled bool
 	minTTL  time.Duration
 	maxTTL  time.Duration
@@ -41,7 +40,11 @@ func newDNSCache(size int, minTTL, maxTTL time.Duration) *dnsCache {
 		return &dnsCache{enabled: false}
 	}
 
-	ipCache := lru.NewLRU[string, *ipCacheEntry](size, nil, maxTTL)
+	ipCache := cache.New[hashing.HashableString, *ipCacheEntry](hashing.Xxh3,
+		cache.WithName[hashing.HashableString, *ipCacheEntry]("dns"),
+		cache.WithMaxEntries[hashing.HashableString, *ipCacheEntry](size),
+		cache.WithTTL[hashing.HashableString, *ipCacheEntry](maxTTL),
+	)
 
 	return &dnsCache{
 		ipCache: ipCache,
@@ -59,11 +62,8 @@ func (c *dnsCache) getIPs(host string) []net.IP {
 		return nil
 	}
 
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	entry, ok := c.ipCache.Get(host)
-	if !ok {
+	entry, ok, err := c.ipCache.Get(hashing.HashableString(host))
+	if err != nil || !ok {
 		return nil
 	}
 
@@ -97,8 +97,6 @@ func (c *dnsCache) setIPs(host string, ips []net.IP, ttl time.Duration) {
 		expiresAt: time.Now().Add(ttl),
 	}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.ipCache.Add(host, entry)
+	// Hashing a string can't fail, so there's no error worth surfacing.
+	_ = c.ipCache.Add(hashing.HashableString(host), entry)
 }
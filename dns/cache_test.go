@@ -84,7 +84,8 @@ func TestDNSCache_TTLClampedToMin(t *testing.T) {
 
 	c.setIPs("a.com", []net.IP{net.ParseIP("1.2.3.4")}, time.Second)
 
-	entry, ok := c.ipCache.Get("a.com")
+	entry, ok, err := c.ipCache.Get("a.com")
+	require.NoError(t, err)
 	require.True(t, ok)
 	// A 1s TTL is raised to the 1h floor.
 	assert.True(t, entry.expiresAt.After(before.Add(30*time.Minute)),
@@ -99,7 +100,8 @@ func TestDNSCache_TTLClampedToMax(t *testing.T) {
 
 	c.setIPs("a.com", []net.IP{net.ParseIP("1.2.3.4")}, 48*time.Hour)
 
-	entry, ok := c.ipCache.Get("a.com")
+	entry, ok, err := c.ipCache.Get("a.com")
+	require.NoError(t, err)
 	require.True(t, ok)
 	// A 48h TTL is capped at the 1h ceiling.
 	assert.True(t, entry.expiresAt.Before(before.Add(2*time.Hour)),
@@ -0,0 +1,102 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/amp-labs/amp-common/circuitbreaker"
+)
+
+// NewCircuitBreakerTransport creates an http.RoundTripper that guards every
+// request with a circuit breaker keyed by the request's host (including any
+// port), so a host that is down fails fast without affecting other hosts.
+//
+// While a host's breaker is open, requests to it fail with an error wrapping
+// circuitbreaker.ErrOpen (or circuitbreaker.ErrTooManyRequests while half-open)
+// without reaching the wrapped transport.
+//
+// Parameters:
+//   - transport: The underlying http.RoundTripper to wrap (uses http.DefaultTransport if nil)
+//   - breakers: The per-host breakers (a Group named "http_transport" with default settings if nil)
+//   - classify: Decides the outcome of each request for the breaker (uses DefaultClassifier if nil)
+//
+// Example:
+//
+//	breakers := circuitbreaker.NewGroup(
+//	    circuitbreaker.WithName("upstream"),
+//	    circuitbreaker.WithConsecutiveFailures(10),
+//	)
+//
+//	client := &http.Client{
+//	    Transport: transport.NewCircuitBreakerTransport(transport.Get(ctx), breakers, nil),
+//	}
+func NewCircuitBreakerTransport(
+	transport http.RoundTripper,
+	breakers *circuitbreaker.Group,
+	classify func(response *http.Response, err error) circuitbreaker.Outcome,
+) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	if breakers == nil {
+		breakers = circuitbreaker.NewGroup(circuitbreaker.WithName("http_transport"))
+	}
+
+	if classify == nil {
+		classify = DefaultClassifier
+	}
+
+	return &circuitBreakerTransport{
+		transport: transport,
+		breakers:  breakers,
+		classify:  classify,
+	}
+}
+
+// DefaultClassifier classifies transport errors with
+// circuitbreaker.DefaultClassifier, so context.Canceled is ignored and other
+// errors are failures. 5xx responses are failures too. Other responses,
+// including 4xx, mean the host is up and answering, so they are successes.
+func DefaultClassifier(response *http.Response, err error) circuitbreaker.Outcome {
+	if err != nil {
+		return circuitbreaker.DefaultClassifier(err)
+	}
+
+	if response != nil && response.StatusCode >= http.StatusInternalServerError {
+		return circuitbreaker.Failure
+	}
+
+	return circuitbreaker.Success
+}
+
+// circuitBreakerTransport is an http.RoundTripper that consults a per-host
+// circuit breaker before delegating to the underlying transport.
+type circuitBreakerTransport struct {
+	// transport is the underlying RoundTripper that performs the request
+	transport http.RoundTripper
+
+	// breakers holds one breaker per host
+	breakers *circuitbreaker.Group
+
+	// classify decides the outcome of each request for the breaker
+	classify func(response *http.Response, err error) circuitbreaker.Outcome
+}
+
+// Compile-time check to ensure circuitBreakerTransport implements http.RoundTripper.
+var _ http.RoundTripper = (*circuitBreakerTransport)(nil)
+
+// RoundTrip sends the request if the host's breaker allows it and records the outcome.
+func (c *circuitBreakerTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	host := request.URL.Host
+
+	done, err := c.breakers.Get(host).Allow(request.Context())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, host)
+	}
+
+	response, err := c.transport.RoundTrip(request)
+	done(c.classify(response, err))
+
+	return response, err
+}
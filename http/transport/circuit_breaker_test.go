@@ -0,0 +1,88 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/amp-common/circuitbreaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCircuitBreakerTransport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("opens the breaker per host", func(t *testing.T) {
+		t.Parallel()
+
+		calls := map[string]int{}
+		inner := NewCustom(func(req *http.Request) (*http.Response, error) {
+			calls[req.URL.Host]++
+
+			status := http.StatusOK
+			if req.URL.Host == "down.example.com" {
+				status = http.StatusServiceUnavailable
+			}
+
+			return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(""))}, nil
+		})
+
+		breakers := circuitbreaker.NewGroup(
+			circuitbreaker.WithName("transport-test"),
+			circuitbreaker.WithConsecutiveFailures(2),
+			circuitbreaker.WithOpenTimeout(time.Hour),
+		)
+		trans := NewCircuitBreakerTransport(inner, breakers, nil)
+
+		send := func(url string) (*http.Response, error) {
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			return trans.RoundTrip(req)
+		}
+
+		for range 2 {
+			resp, err := send("http://down.example.com/health")
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+			require.NoError(t, resp.Body.Close())
+		}
+
+		resp, err := send("http://down.example.com/health")
+		require.ErrorIs(t, err, circuitbreaker.ErrOpen)
+		assert.Nil(t, resp)
+		assert.Contains(t, err.Error(), "down.example.com")
+		assert.Equal(t, 2, calls["down.example.com"])
+
+		resp, err = send("http://up.example.com/health")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+
+		assert.Equal(t, circuitbreaker.Open, breakers.Get("down.example.com").State())
+		assert.Equal(t, circuitbreaker.Closed, breakers.Get("up.example.com").State())
+	})
+
+	t.Run("uses defaults for nil arguments", func(t *testing.T) {
+		t.Parallel()
+
+		trans := NewCircuitBreakerTransport(nil, nil, nil)
+
+		require.NotNil(t, trans)
+		assert.Implements(t, (*http.RoundTripper)(nil), trans)
+	})
+}
+
+func TestDefaultClassifier(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, circuitbreaker.Success, DefaultClassifier(&http.Response{StatusCode: http.StatusOK}, nil))
+	assert.Equal(t, circuitbreaker.Success, DefaultClassifier(&http.Response{StatusCode: http.StatusNotFound}, nil))
+	assert.Equal(t, circuitbreaker.Failure, DefaultClassifier(&http.Response{StatusCode: http.StatusBadGateway}, nil))
+	assert.Equal(t, circuitbreaker.Failure, DefaultClassifier(nil, errNetworkFailure))
+	assert.Equal(t, circuitbreaker.Ignored, DefaultClassifier(nil, context.Canceled))
+}
@@ -0,0 +1,69 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/amp-labs/amp-common/ratelimit"
+)
+
+// NewRateLimitTransport creates an http.RoundTripper that throttles requests
+// with a rate limiter keyed by the request's host (including any port), so
+// each host gets its own quota.
+//
+// Each request waits for a slot from its host's limiter before reaching the
+// wrapped transport. If the request's context ends first, or its deadline
+// would expire before a slot frees up, the request fails without being sent,
+// with an error wrapping the context's error or ratelimit.ErrDeadlineTooSoon.
+//
+// Parameters:
+//   - transport: The underlying http.RoundTripper to wrap (uses http.DefaultTransport if nil)
+//   - limiters: The per-host limiters (requests are not limited if nil)
+//
+// Example:
+//
+//	limiters := ratelimit.NewGroup(func(string) ratelimit.Limiter {
+//	    return ratelimit.NewTokenBucket(10, 20, ratelimit.WithName("upstream"))
+//	})
+//
+//	client := &http.Client{
+//	    Transport: transport.NewRateLimitTransport(transport.Get(ctx), limiters),
+//	}
+func NewRateLimitTransport(transport http.RoundTripper, limiters *ratelimit.Group) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	if limiters == nil {
+		return transport
+	}
+
+	return &rateLimitTransport{
+		transport: transport,
+		limiters:  limiters,
+	}
+}
+
+// rateLimitTransport is an http.RoundTripper that waits for a per-host rate
+// limiter before delegating to the underlying transport.
+type rateLimitTransport struct {
+	// transport is the underlying RoundTripper that performs the request
+	transport http.RoundTripper
+
+	// limiters holds one limiter per host
+	limiters *ratelimit.Group
+}
+
+// Compile-time check to ensure rateLimitTransport implements http.RoundTripper.
+var _ http.RoundTripper = (*rateLimitTransport)(nil)
+
+// RoundTrip waits for the host's limiter and then sends the request.
+func (r *rateLimitTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	host := request.URL.Host
+
+	if err := r.limiters.Wait(request.Context(), host); err != nil {
+		return nil, fmt.Errorf("rate limit for %s: %w", host, err)
+	}
+
+	return r.transport.RoundTrip(request)
+}
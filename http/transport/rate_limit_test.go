@@ -0,0 +1,76 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/amp-common/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRateLimitTransport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("limits each host separately", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			mutex sync.Mutex
+			calls = map[string]int{}
+		)
+
+		inner := NewCustom(func(req *http.Request) (*http.Response, error) {
+			mutex.Lock()
+			calls[req.URL.Host]++
+			mutex.Unlock()
+
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+		})
+
+		limiters := ratelimit.NewGroup(func(string) ratelimit.Limiter {
+			return ratelimit.NewSlidingWindow(1, time.Hour, ratelimit.WithName("transport-test"))
+		})
+		trans := NewRateLimitTransport(inner, limiters)
+
+		send := func(url string) (*http.Response, error) {
+			ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+			defer cancel()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			return trans.RoundTrip(req)
+		}
+
+		resp, err := send("http://a.example.com/")
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		resp, err = send("http://b.example.com/")
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		resp, err = send("http://a.example.com/")
+		require.ErrorIs(t, err, ratelimit.ErrDeadlineTooSoon)
+		assert.Nil(t, resp)
+		assert.Contains(t, err.Error(), "a.example.com")
+
+		assert.Equal(t, map[string]int{"a.example.com": 1, "b.example.com": 1}, calls)
+	})
+
+	t.Run("nil limiters pass requests through", func(t *testing.T) {
+		t.Parallel()
+
+		inner := NewCustom(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+		})
+
+		assert.Same(t, inner, NewRateLimitTransport(inner, nil))
+	})
+}
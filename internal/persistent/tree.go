@@ -0,0 +1,406 @@
+package persistent
+
+import (
+	"iter"
+
+	"github.com/amp-labs/amp-common/sortable"
+)
+
+// Tree is a path-copying AVL tree ordered by a comparison function. Every node
+// records the size of its subtree, so rank and select queries run in O(log n).
+//
+// Trees must be created with NewTree.
+type Tree[K any, V any] struct {
+	root *treeNode[K, V]
+	cmp  func(a, b K) int
+}
+
+type treeNode[K any, V any] struct {
+	key    K
+	value  V
+	left   *treeNode[K, V]
+	right  *treeNode[K, V]
+	height int
+	size   int
+}
+
+// NewTree creates an empty tree. cmp returns a negative number if a sorts
+// before b, a positive number if it sorts after b, and zero if they are equal.
+func NewTree[K any, V any](cmp func(a, b K) int) Tree[K, V] {
+	return Tree[K, V]{cmp: cmp}
+}
+
+// CompareSortable orders sortable values for use with NewTree.
+func CompareSortable[K sortable.Sortable[K]](a, b K) int {
+	switch {
+	case a.LessThan(b):
+		return -1
+	case b.LessThan(a):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Len returns the number of entries in the tree.
+func (t Tree[K, V]) Len() int {
+	return t.root.count()
+}
+
+// Get returns the value stored for key.
+func (t Tree[K, V]) Get(key K) (V, bool) {
+	for n := t.root; n != nil; {
+		c := t.cmp(key, n.key)
+
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+
+	var zero V
+
+	return zero, false
+}
+
+// With returns a tree in which key maps to value. The receiver is left unchanged.
+func (t Tree[K, V]) With(key K, value V) Tree[K, V] {
+	return Tree[K, V]{root: t.with(t.root, key, value), cmp: t.cmp}
+}
+
+// Without returns a tree without key, and whether key was present. If it was
+// not, the receiver itself is returned.
+func (t Tree[K, V]) Without(key K) (Tree[K, V], bool) {
+	root, removed := t.without(t.root, key)
+	if !removed {
+		return t, false
+	}
+
+	return Tree[K, V]{root: root, cmp: t.cmp}, true
+}
+
+// Clear returns an empty tree with the same ordering.
+func (t Tree[K, V]) Clear() Tree[K, V] {
+	return Tree[K, V]{cmp: t.cmp}
+}
+
+// Min returns the entry with the smallest key.
+func (t Tree[K, V]) Min() (Entry[K, V], bool) {
+	if t.root == nil {
+		return Entry[K, V]{}, false
+	}
+
+	return t.root.leftmost().entry(), true
+}
+
+// Max returns the entry with the largest key.
+func (t Tree[K, V]) Max() (Entry[K, V], bool) {
+	n := t.root
+	if n == nil {
+		return Entry[K, V]{}, false
+	}
+
+	for n.right != nil {
+		n = n.right
+	}
+
+	return n.entry(), true
+}
+
+// Floor returns the entry with the greatest key less than key, or equal to it if inclusive is set.
+func (t Tree[K, V]) Floor(key K, inclusive bool) (Entry[K, V], bool) {
+	var best *treeNode[K, V]
+
+	for n := t.root; n != nil; {
+		c := t.cmp(key, n.key)
+
+		if c > 0 || (c == 0 && inclusive) {
+			best = n
+
+			if c == 0 {
+				break
+			}
+
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+
+	if best == nil {
+		return Entry[K, V]{}, false
+	}
+
+	return best.entry(), true
+}
+
+// Ceiling returns the entry with the smallest key greater than key, or equal to it if inclusive is set.
+func (t Tree[K, V]) Ceiling(key K, inclusive bool) (Entry[K, V], bool) {
+	var best *treeNode[K, V]
+
+	for n := t.root; n != nil; {
+		c := t.cmp(key, n.key)
+
+		if c < 0 || (c == 0 && inclusive) {
+			best = n
+
+			if c == 0 {
+				break
+			}
+
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+
+	if best == nil {
+		return Entry[K, V]{}, false
+	}
+
+	return best.entry(), true
+}
+
+// Rank returns the number of keys strictly less than key.
+func (t Tree[K, V]) Rank(key K) int {
+	rank := 0
+
+	for n := t.root; n != nil; {
+		c := t.cmp(key, n.key)
+
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			rank += n.left.count() + 1
+			n = n.right
+		default:
+			return rank + n.left.count()
+		}
+	}
+
+	return rank
+}
+
+// Select returns the entry at the given zero-based position in key order.
+func (t Tree[K, V]) Select(index int) (Entry[K, V], bool) {
+	if index < 0 || index >= t.Len() {
+		return Entry[K, V]{}, false
+	}
+
+	n := t.root
+
+	for {
+		left := n.left.count()
+
+		switch {
+		case index < left:
+			n = n.left
+		case index > left:
+			index -= left + 1
+			n = n.right
+		default:
+			return n.entry(), true
+		}
+	}
+}
+
+// All returns an iterator over all entries in ascending key order.
+func (t Tree[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.root.ascend(yield)
+	}
+}
+
+// Descending returns an iterator over all entries in descending key order.
+func (t Tree[K, V]) Descending() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.root.descend(yield)
+	}
+}
+
+// Range returns an iterator over the entries with from <= key < to, in ascending key order.
+func (t Tree[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.ascendRange(t.root, from, to, yield)
+	}
+}
+
+func (t Tree[K, V]) ascendRange(n *treeNode[K, V], from, to K, yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	aboveFrom := t.cmp(n.key, from) >= 0
+	belowTo := t.cmp(n.key, to) < 0
+
+	if aboveFrom && !t.ascendRange(n.left, from, to, yield) {
+		return false
+	}
+
+	if aboveFrom && belowTo && !yield(n.key, n.value) {
+		return false
+	}
+
+	if belowTo {
+		return t.ascendRange(n.right, from, to, yield)
+	}
+
+	return true
+}
+
+func (t Tree[K, V]) with(n *treeNode[K, V], key K, value V) *treeNode[K, V] {
+	if n == nil {
+		return &treeNode[K, V]{key: key, value: value, height: 1, size: 1}
+	}
+
+	c := t.cmp(key, n.key)
+
+	switch {
+	case c < 0:
+		return balance(n.key, n.value, t.with(n.left, key, value), n.right)
+	case c > 0:
+		return balance(n.key, n.value, n.left, t.with(n.right, key, value))
+	default:
+		return &treeNode[K, V]{key: key, value: value, left: n.left, right: n.right, height: n.height, size: n.size}
+	}
+}
+
+func (t Tree[K, V]) without(n *treeNode[K, V], key K) (*treeNode[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	c := t.cmp(key, n.key)
+
+	switch {
+	case c < 0:
+		left, removed := t.without(n.left, key)
+		if !removed {
+			return n, false
+		}
+
+		return balance(n.key, n.value, left, n.right), true
+	case c > 0:
+		right, removed := t.without(n.right, key)
+		if !removed {
+			return n, false
+		}
+
+		return balance(n.key, n.value, n.left, right), true
+	}
+
+	switch {
+	case n.left == nil:
+		return n.right, true
+	case n.right == nil:
+		return n.left, true
+	}
+
+	// Replace the node with its in-order successor
+	successor := n.right.leftmost()
+	right := withoutMin(n.right)
+
+	return balance(successor.key, successor.value, n.left, right), true
+}
+
+// withoutMin returns a copy of the subtree without its leftmost node.
+func withoutMin[K any, V any](n *treeNode[K, V]) *treeNode[K, V] {
+	if n.left == nil {
+		return n.right
+	}
+
+	return balance(n.key, n.value, withoutMin(n.left), n.right)
+}
+
+// balance builds a node from its parts, rotating once or twice if the heights
+// of left and right differ by more than one.
+func balance[K any, V any](key K, value V, left, right *treeNode[K, V]) *treeNode[K, V] {
+	switch diff := left.depth() - right.depth(); {
+	case diff > 1:
+		if left.left.depth() < left.right.depth() {
+			// Left-right case: rotate the left child first
+			left = rotateLeft(left.key, left.value, left.left, left.right)
+		}
+
+		return rotateRight(key, value, left, right)
+	case diff < -1:
+		if right.right.depth() < right.left.depth() {
+			// Right-left case: rotate the right child first
+			right = rotateRight(right.key, right.value, right.left, right.right)
+		}
+
+		return rotateLeft(key, value, left, right)
+	default:
+		return newNode(key, value, left, right)
+	}
+}
+
+// rotateRight builds the node (key, left, right) with left lifted to the top.
+func rotateRight[K any, V any](key K, value V, left, right *treeNode[K, V]) *treeNode[K, V] {
+	return newNode(left.key, left.value, left.left, newNode(key, value, left.right, right))
+}
+
+// rotateLeft builds the node (key, left, right) with right lifted to the top.
+func rotateLeft[K any, V any](key K, value V, left, right *treeNode[K, V]) *treeNode[K, V] {
+	return newNode(right.key, right.value, newNode(key, value, left, right.left), right.right)
+}
+
+func newNode[K any, V any](key K, value V, left, right *treeNode[K, V]) *treeNode[K, V] {
+	return &treeNode[K, V]{
+		key:    key,
+		value:  value,
+		left:   left,
+		right:  right,
+		height: max(left.depth(), right.depth()) + 1,
+		size:   left.count() + right.count() + 1,
+	}
+}
+
+func (n *treeNode[K, V]) depth() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.height
+}
+
+func (n *treeNode[K, V]) count() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.size
+}
+
+func (n *treeNode[K, V]) leftmost() *treeNode[K, V] {
+	for n.left != nil {
+		n = n.left
+	}
+
+	return n
+}
+
+func (n *treeNode[K, V]) entry() Entry[K, V] {
+	return Entry[K, V]{Key: n.key, Value: n.value}
+}
+
+func (n *treeNode[K, V]) ascend(yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	return n.left.ascend(yield) && yield(n.key, n.value) && n.right.ascend(yield)
+}
+
+func (n *treeNode[K, V]) descend(yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	return n.right.descend(yield) && yield(n.key, n.value) && n.left.descend(yield)
+}
@@ -0,0 +1,170 @@
+package persistent
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/amp-labs/amp-common/sortable"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// requireAVLInvariants checks ordering, balance, heights and subtree sizes.
+func requireAVLInvariants(t *testing.T, n *treeNode[int, int]) {
+	t.Helper()
+
+	require.NoError(t, checkAVL(n))
+}
+
+func checkAVL(n *treeNode[int, int]) error {
+	if n == nil {
+		return nil
+	}
+
+	switch {
+	case n.left != nil && n.left.key >= n.key, n.right != nil && n.right.key <= n.key:
+		return fmt.Errorf("keys out of order at %d", n.key)
+	case abs(n.left.depth()-n.right.depth()) > 1:
+		return fmt.Errorf("unbalanced at %d", n.key)
+	case n.height != max(n.left.depth(), n.right.depth())+1:
+		return fmt.Errorf("wrong height at %d", n.key)
+	case n.size != n.left.count()+n.right.count()+1:
+		return fmt.Errorf("wrong size at %d", n.key)
+	}
+
+	if err := checkAVL(n.left); err != nil { //nolint:noinlineerr // Recursion reads better inline
+		return err
+	}
+
+	return checkAVL(n.right)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
+
+func TestTreeMatchesSortedSlice(t *testing.T) {
+	t.Parallel()
+
+	for seed := range uint64(10) {
+		rng := rand.New(rand.NewPCG(seed, seed)) //nolint:gosec // Deterministic test data
+
+		tree := NewTree[int, int](cmp.Compare[int])
+		model := make(map[int]int)
+
+		var (
+			versions []Tree[int, int]
+			lengths  []int
+		)
+
+		for i := range 1000 {
+			key := rng.IntN(300)
+
+			if rng.IntN(3) == 0 {
+				tree, _ = tree.Without(key)
+				delete(model, key)
+			} else {
+				tree = tree.With(key, i)
+				model[key] = i
+			}
+
+			requireAVLInvariants(t, tree.root)
+			require.Equal(t, len(model), tree.Len())
+
+			if i%100 == 0 {
+				versions = append(versions, tree)
+				lengths = append(lengths, len(model))
+			}
+		}
+
+		keys := make([]int, 0, len(model))
+		for key := range model {
+			keys = append(keys, key)
+		}
+
+		slices.Sort(keys)
+
+		var got []int
+		for key, value := range tree.All() {
+			got = append(got, key)
+
+			assert.Equal(t, model[key], value)
+		}
+
+		assert.Equal(t, keys, got)
+
+		for i, key := range keys {
+			assert.Equal(t, i, tree.Rank(key))
+
+			entry, found := tree.Select(i)
+			assert.True(t, found)
+			assert.Equal(t, key, entry.Key)
+		}
+
+		for i, version := range versions {
+			assert.Equal(t, lengths[i], version.Len())
+			requireAVLInvariants(t, version.root)
+		}
+	}
+}
+
+func TestTreeOrderedQueries(t *testing.T) {
+	t.Parallel()
+
+	tree := NewTree[sortable.Int, string](CompareSortable[sortable.Int])
+	for _, key := range []sortable.Int{10, 20, 30, 40} {
+		tree = tree.With(key, "")
+	}
+
+	floor, found := tree.Floor(25, true)
+	assert.True(t, found)
+	assert.Equal(t, sortable.Int(20), floor.Key)
+
+	floor, _ = tree.Floor(20, true)
+	assert.Equal(t, sortable.Int(20), floor.Key)
+
+	lower, _ := tree.Floor(20, false)
+	assert.Equal(t, sortable.Int(10), lower.Key)
+
+	_, found = tree.Floor(10, false)
+	assert.False(t, found)
+
+	ceiling, _ := tree.Ceiling(25, true)
+	assert.Equal(t, sortable.Int(30), ceiling.Key)
+
+	higher, _ := tree.Ceiling(30, false)
+	assert.Equal(t, sortable.Int(40), higher.Key)
+
+	_, found = tree.Ceiling(40, false)
+	assert.False(t, found)
+
+	var inRange []sortable.Int
+	for key := range tree.Range(15, 40) {
+		inRange = append(inRange, key)
+	}
+
+	assert.Equal(t, []sortable.Int{20, 30}, inRange)
+
+	var descending []sortable.Int
+	for key := range tree.Descending() {
+		descending = append(descending, key)
+	}
+
+	assert.Equal(t, []sortable.Int{40, 30, 20, 10}, descending)
+
+	minEntry, _ := tree.Min()
+	maxEntry, _ := tree.Max()
+	assert.Equal(t, sortable.Int(10), minEntry.Key)
+	assert.Equal(t, sortable.Int(40), maxEntry.Key)
+
+	_, found = tree.Select(4)
+	assert.False(t, found)
+	assert.Equal(t, 2, tree.Rank(25))
+}
@@ -0,0 +1,316 @@
+// Package persistent provides immutable, structurally shared data structures
+// used to build the persistent maps and sets in the maps and set packages.
+//
+// Every modification returns a new version that shares all untouched nodes with
+// the version it was derived from. Versions never change once built, so any
+// number of goroutines can read them without synchronization.
+package persistent
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+
+	"github.com/amp-labs/amp-common/collectable"
+)
+
+const (
+	trieBits  = 5
+	trieWidth = 1 << trieBits
+	trieMask  = trieWidth - 1
+	// trieDepth is the number of levels after which all 64 hash bits are used up.
+	trieDepth = (64 + trieBits - 1) / trieBits
+)
+
+// seed is shared by all tries so that hashes are comparable across versions.
+var seed = maphash.MakeSeed() //nolint:gochecknoglobals // Must be stable for the life of the process
+
+// Hash reduces a string hash, as produced by a hashing.HashFunc, to the 64-bit
+// value used to place a key in a Trie.
+func Hash(s string) uint64 {
+	return maphash.String(seed, s)
+}
+
+// Entry is a key-value pair stored in a Trie.
+type Entry[K any, V any] struct {
+	Key   K
+	Value V
+}
+
+// Trie is a hash array mapped trie. Each level consumes five bits of a key's
+// hash; interior nodes store only the children that exist, indexed through a
+// bitmap. Keys with identical 64-bit hashes share a collision leaf and are told
+// apart with Equals.
+//
+// The zero value is an empty trie.
+type Trie[K collectable.Collectable[K], V any] struct {
+	root *trieNode[K, V]
+	size int
+}
+
+// trieNode is an interior node. Bit i of bitmap is set if the node has a child
+// for the hash fragment i; the child is at the position given by the number of
+// lower bits set.
+type trieNode[K collectable.Collectable[K], V any] struct {
+	bitmap uint32
+	slots  []trieSlot[K, V]
+}
+
+// trieSlot is either a sub-node or a leaf holding the entries whose hashes are equal.
+type trieSlot[K collectable.Collectable[K], V any] struct {
+	node    *trieNode[K, V]
+	hash    uint64
+	entries []Entry[K, V]
+}
+
+// Len returns the number of entries in the trie.
+func (t Trie[K, V]) Len() int {
+	return t.size
+}
+
+// Get returns the value stored for key, whose hash is h.
+func (t Trie[K, V]) Get(h uint64, key K) (V, bool) {
+	node := t.root
+
+	for shift := 0; node != nil; shift += trieBits {
+		bit := uint32(1) << ((h >> shift) & trieMask)
+		if node.bitmap&bit == 0 {
+			break
+		}
+
+		slot := &node.slots[node.index(bit)]
+		if slot.node != nil {
+			node = slot.node
+
+			continue
+		}
+
+		if slot.hash == h {
+			if i := find(slot.entries, key); i >= 0 {
+				return slot.entries[i].Value, true
+			}
+		}
+
+		break
+	}
+
+	var zero V
+
+	return zero, false
+}
+
+// With returns a trie in which key maps to value. The receiver is left unchanged.
+func (t Trie[K, V]) With(h uint64, key K, value V) Trie[K, V] {
+	root := t.root
+	if root == nil {
+		root = &trieNode[K, V]{}
+	}
+
+	newRoot, added := root.with(0, h, Entry[K, V]{Key: key, Value: value})
+
+	size := t.size
+	if added {
+		size++
+	}
+
+	return Trie[K, V]{root: newRoot, size: size}
+}
+
+// Without returns a trie without key, and whether key was present. If it was not,
+// the receiver itself is returned.
+func (t Trie[K, V]) Without(h uint64, key K) (Trie[K, V], bool) {
+	if t.root == nil {
+		return t, false
+	}
+
+	newRoot, removed := t.root.without(0, h, key)
+	if !removed {
+		return t, false
+	}
+
+	if newRoot != nil && len(newRoot.slots) == 0 {
+		newRoot = nil
+	}
+
+	return Trie[K, V]{root: newRoot, size: t.size - 1}, true
+}
+
+// All returns an iterator over the entries of the trie in hash order.
+func (t Trie[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if t.root != nil {
+			t.root.all(yield)
+		}
+	}
+}
+
+func (n *trieNode[K, V]) index(bit uint32) int {
+	return bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+func (n *trieNode[K, V]) all(yield func(K, V) bool) bool {
+	for i := range n.slots {
+		slot := &n.slots[i]
+
+		if slot.node != nil {
+			if !slot.node.all(yield) {
+				return false
+			}
+
+			continue
+		}
+
+		for _, entry := range slot.entries {
+			if !yield(entry.Key, entry.Value) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// with returns a copy of the node with entry inserted, and whether the entry's
+// key is new. Only the nodes on the path to the entry are copied.
+func (n *trieNode[K, V]) with(shift int, h uint64, entry Entry[K, V]) (*trieNode[K, V], bool) {
+	bit := uint32(1) << ((h >> shift) & trieMask)
+	idx := n.index(bit)
+
+	if n.bitmap&bit == 0 {
+		slots := make([]trieSlot[K, V], len(n.slots)+1)
+		copy(slots, n.slots[:idx])
+		slots[idx] = trieSlot[K, V]{hash: h, entries: []Entry[K, V]{entry}}
+		copy(slots[idx+1:], n.slots[idx:])
+
+		return &trieNode[K, V]{bitmap: n.bitmap | bit, slots: slots}, true
+	}
+
+	slot := n.slots[idx]
+	added := true
+
+	switch {
+	case slot.node != nil:
+		slot.node, added = slot.node.with(shift+trieBits, h, entry)
+	case slot.hash == h:
+		entries := make([]Entry[K, V], len(slot.entries), len(slot.entries)+1)
+		copy(entries, slot.entries)
+
+		if i := find(entries, entry.Key); i >= 0 {
+			entries[i] = entry
+			added = false
+		} else {
+			entries = append(entries, entry)
+		}
+
+		slot.entries = entries
+	default:
+		// Two different hashes share this fragment: push both down a level
+		slot = trieSlot[K, V]{node: split(shift+trieBits, slot, trieSlot[K, V]{hash: h, entries: []Entry[K, V]{entry}})}
+	}
+
+	return n.replaced(idx, slot), added
+}
+
+// split builds the node that holds two leaves whose hashes differ but agree on
+// all fragments above shift.
+func split[K collectable.Collectable[K], V any](shift int, a, b trieSlot[K, V]) *trieNode[K, V] {
+	if shift >= trieDepth*trieBits {
+		// Unreachable, since leaves with equal hashes are merged before splitting
+		panic("persistent: hash bits exhausted")
+	}
+
+	bitA := uint32(1) << ((a.hash >> shift) & trieMask)
+	bitB := uint32(1) << ((b.hash >> shift) & trieMask)
+
+	switch {
+	case bitA == bitB:
+		return &trieNode[K, V]{bitmap: bitA, slots: []trieSlot[K, V]{{node: split(shift+trieBits, a, b)}}}
+	case bitA < bitB:
+		return &trieNode[K, V]{bitmap: bitA | bitB, slots: []trieSlot[K, V]{a, b}}
+	default:
+		return &trieNode[K, V]{bitmap: bitA | bitB, slots: []trieSlot[K, V]{b, a}}
+	}
+}
+
+// without returns a copy of the node with key removed, and whether key was
+// present. A node left with a single leaf is collapsed into that leaf by the
+// caller, and an empty node is returned as nil.
+func (n *trieNode[K, V]) without(shift int, h uint64, key K) (*trieNode[K, V], bool) {
+	bit := uint32(1) << ((h >> shift) & trieMask)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+
+	idx := n.index(bit)
+	slot := n.slots[idx]
+
+	if slot.node != nil {
+		child, removed := slot.node.without(shift+trieBits, h, key)
+		if !removed {
+			return n, false
+		}
+
+		switch {
+		case child == nil:
+			return n.removed(idx, bit), true
+		case len(child.slots) == 1 && child.slots[0].node == nil:
+			// Pull a lone leaf up so that lookups stay short
+			return n.replaced(idx, child.slots[0]), true
+		default:
+			slot.node = child
+
+			return n.replaced(idx, slot), true
+		}
+	}
+
+	if slot.hash != h {
+		return n, false
+	}
+
+	i := find(slot.entries, key)
+	if i < 0 {
+		return n, false
+	}
+
+	if len(slot.entries) == 1 {
+		return n.removed(idx, bit), true
+	}
+
+	entries := make([]Entry[K, V], 0, len(slot.entries)-1)
+	entries = append(entries, slot.entries[:i]...)
+	slot.entries = append(entries, slot.entries[i+1:]...)
+
+	return n.replaced(idx, slot), true
+}
+
+// replaced returns a copy of the node with the slot at idx replaced.
+func (n *trieNode[K, V]) replaced(idx int, slot trieSlot[K, V]) *trieNode[K, V] {
+	slots := make([]trieSlot[K, V], len(n.slots))
+	copy(slots, n.slots)
+	slots[idx] = slot
+
+	return &trieNode[K, V]{bitmap: n.bitmap, slots: slots}
+}
+
+// removed returns a copy of the node without the slot at idx, or nil if it was the last one.
+func (n *trieNode[K, V]) removed(idx int, bit uint32) *trieNode[K, V] {
+	if len(n.slots) == 1 {
+		return nil
+	}
+
+	slots := make([]trieSlot[K, V], 0, len(n.slots)-1)
+	slots = append(slots, n.slots[:idx]...)
+	slots = append(slots, n.slots[idx+1:]...)
+
+	return &trieNode[K, V]{bitmap: n.bitmap &^ bit, slots: slots}
+}
+
+func find[K collectable.Collectable[K], V any](entries []Entry[K, V], key K) int {
+	for i, entry := range entries {
+		if entry.Key.Equals(key) {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,134 @@
+package persistent
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"testing"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func trieContents(t Trie[hashing.HashableString, int]) map[hashing.HashableString]int {
+	out := make(map[hashing.HashableString]int, t.Len())
+	for key, value := range t.All() {
+		out[key] = value
+	}
+
+	return out
+}
+
+func TestTrieMatchesGoMap(t *testing.T) {
+	t.Parallel()
+
+	for seed := range uint64(10) {
+		rng := rand.New(rand.NewPCG(seed, seed)) //nolint:gosec // Deterministic test data
+
+		var trie Trie[hashing.HashableString, int]
+
+		model := make(map[hashing.HashableString]int)
+		versions := []Trie[hashing.HashableString, int]{trie}
+		snapshots := []map[hashing.HashableString]int{{}}
+
+		for i := range 2000 {
+			key := hashing.HashableString(strconv.Itoa(rng.IntN(500)))
+			h := Hash(string(key))
+
+			if rng.IntN(3) == 0 {
+				var removed bool
+
+				trie, removed = trie.Without(h, key)
+				_, existed := model[key]
+				assert.Equal(t, existed, removed)
+
+				delete(model, key)
+			} else {
+				trie = trie.With(h, key, i)
+				model[key] = i
+			}
+
+			require.Equal(t, len(model), trie.Len())
+
+			if i%200 == 0 {
+				versions = append(versions, trie)
+
+				snapshot := make(map[hashing.HashableString]int, len(model))
+				for k, v := range model {
+					snapshot[k] = v
+				}
+
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+
+		assert.Equal(t, model, trieContents(trie))
+
+		for key, value := range model {
+			got, found := trie.Get(Hash(string(key)), key)
+			assert.True(t, found)
+			assert.Equal(t, value, got)
+		}
+
+		// Earlier versions are unaffected by later changes
+		for i, version := range versions {
+			assert.Equal(t, snapshots[i], trieContents(version))
+		}
+	}
+}
+
+func TestTrieCollisions(t *testing.T) {
+	t.Parallel()
+
+	var trie Trie[hashing.HashableString, string]
+
+	// Full collisions share a leaf; partial ones share a prefix of hash fragments
+	trie = trie.With(1, "a", "a")
+	trie = trie.With(1, "b", "b")
+	trie = trie.With(1|1<<60, "c", "c")
+	trie = trie.With(1|1<<35, "d", "d")
+	require.Equal(t, 4, trie.Len())
+
+	for _, tc := range []struct {
+		hash uint64
+		key  hashing.HashableString
+	}{{1, "a"}, {1, "b"}, {1 | 1<<60, "c"}, {1 | 1<<35, "d"}} {
+		value, found := trie.Get(tc.hash, tc.key)
+		assert.True(t, found)
+		assert.Equal(t, string(tc.key), value)
+	}
+
+	_, found := trie.Get(1, "c")
+	assert.False(t, found)
+
+	updated := trie.With(1, "b", "B")
+	value, _ := updated.Get(1, "b")
+	assert.Equal(t, "B", value)
+	assert.Equal(t, 4, updated.Len())
+
+	value, _ = trie.Get(1, "b")
+	assert.Equal(t, "b", value)
+
+	for _, tc := range []struct {
+		hash uint64
+		key  hashing.HashableString
+	}{{1, "a"}, {1 | 1<<60, "c"}, {1, "b"}, {1 | 1<<35, "d"}} {
+		var removed bool
+
+		trie, removed = trie.Without(tc.hash, tc.key)
+		assert.True(t, removed)
+	}
+
+	assert.Equal(t, 0, trie.Len())
+	assert.Nil(t, trie.root)
+}
+
+func TestTrieWithoutMissingKeyReturnsReceiver(t *testing.T) {
+	t.Parallel()
+
+	trie := Trie[hashing.HashableString, int]{}.With(Hash("a"), "a", 1)
+
+	same, removed := trie.Without(Hash("b"), "b")
+	assert.False(t, removed)
+	assert.Same(t, trie.root, same.root)
+}
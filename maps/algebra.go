@@ -0,0 +1,193 @@
+package maps //nolint:revive // Established package name; renaming would break all consumers.
+
+import "iter"
+
+// lookup is the part of Map and OrderedMap that the key-based set algebra and
+// Diff need from the other operand.
+type lookup[K any, V any] interface {
+	Get(key K) (value V, found bool, err error)
+	Contains(key K) (bool, error)
+	Size() int
+}
+
+// Delta describes how a map changed from one version to another. See Diff.
+type Delta[K any, V any] struct {
+	// Added holds the entries whose keys are only in the newer map.
+	Added []KeyValuePair[K, V]
+
+	// Removed holds the entries whose keys are only in the older map.
+	Removed []KeyValuePair[K, V]
+
+	// Changed holds the keys that are in both maps with different values.
+	Changed []ValueChange[K, V]
+}
+
+// ValueChange is a key whose value differs between two versions of a map.
+type ValueChange[K any, V any] struct {
+	Key K
+	Old V
+	New V
+}
+
+// IsEmpty reports whether the two maps had the same entries.
+func (d Delta[K, V]) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff reports the keys that were added, removed or changed going from one map
+// to the other. Values are compared with equal, since V need not be comparable.
+// The order of the entries in the result is non-deterministic. Returns an error
+// if hashing any key fails.
+//
+// Example:
+//
+//	delta, err := maps.Diff(before, after, func(a, b int) bool { return a == b })
+func Diff[K any, V any](from, to Map[K, V], equal func(a, b V) bool) (Delta[K, V], error) {
+	return diff(from.Seq(), from, to.Seq(), to, equal)
+}
+
+// DiffOrdered is Diff for ordered maps. Removed and changed keys are reported in
+// their order in from, and added keys in their order in to.
+func DiffOrdered[K any, V any](from, to OrderedMap[K, V], equal func(a, b V) bool) (Delta[K, V], error) {
+	return diff(orderedPairs(from.Seq()), from, orderedPairs(to.Seq()), to, equal)
+}
+
+func diff[K any, V any](
+	fromEntries iter.Seq2[K, V],
+	from lookup[K, V],
+	toEntries iter.Seq2[K, V],
+	to lookup[K, V],
+	equal func(a, b V) bool,
+) (Delta[K, V], error) {
+	var delta Delta[K, V]
+
+	from, to = stored(from), stored(to)
+
+	for key, old := range fromEntries {
+		value, found, err := to.Get(key)
+		if err != nil {
+			return Delta[K, V]{}, err
+		}
+
+		switch {
+		case !found:
+			delta.Removed = append(delta.Removed, KeyValuePair[K, V]{Key: key, Value: old})
+		case !equal(old, value):
+			delta.Changed = append(delta.Changed, ValueChange[K, V]{Key: key, Old: old, New: value})
+		}
+	}
+
+	err := addMissingKeys(toEntries, from, func(key K, value V) error {
+		delta.Added = append(delta.Added, KeyValuePair[K, V]{Key: key, Value: value})
+
+		return nil
+	})
+	if err != nil {
+		return Delta[K, V]{}, err
+	}
+
+	return delta, nil
+}
+
+// stored returns the map holding m's entries. Default maps fill in values for
+// the keys they are asked about, so the set algebra and Diff look at the map
+// underneath to leave their operands unchanged.
+func stored[K any, V any](m lookup[K, V]) lookup[K, V] {
+	switch d := m.(type) {
+	case *defaultMap[K, V]:
+		return d.m
+	case *defaultOrderedMap[K, V]:
+		return d.m
+	default:
+		return m
+	}
+}
+
+// addMissingKeys calls add for each entry whose key other does not contain.
+func addMissingKeys[K any, V any](entries iter.Seq2[K, V], other lookup[K, V], add func(K, V) error) error {
+	other = stored(other)
+
+	for key, value := range entries {
+		contains, err := other.Contains(key)
+		if err != nil {
+			return err
+		}
+
+		if !contains {
+			err = add(key, value)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// isSubsetKeys reports whether other contains the keys of each of the size entries.
+func isSubsetKeys[K any, V any](size int, entries iter.Seq2[K, V], other lookup[K, V]) (bool, error) {
+	other = stored(other)
+
+	if size > other.Size() {
+		return false, nil
+	}
+
+	for key := range entries {
+		contains, err := other.Contains(key)
+		if err != nil {
+			return false, err
+		}
+
+		if !contains {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// isDisjointKeys reports whether other contains none of the entries' keys.
+func isDisjointKeys[K any, V any](entries iter.Seq2[K, V], other lookup[K, V]) (bool, error) {
+	other = stored(other)
+
+	for key := range entries {
+		contains, err := other.Contains(key)
+		if err != nil {
+			return false, err
+		}
+
+		if contains {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// isEqualMap reports whether the size entries and other hold the same keys with
+// values that are equal according to equal.
+func isEqualMap[K any, V any](
+	size int,
+	entries iter.Seq2[K, V],
+	other lookup[K, V],
+	equal func(a, b V) bool,
+) (bool, error) {
+	other = stored(other)
+
+	if size != other.Size() {
+		return false, nil
+	}
+
+	for key, value := range entries {
+		otherValue, found, err := other.Get(key)
+		if err != nil {
+			return false, err
+		}
+
+		if !found || !equal(value, otherValue) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
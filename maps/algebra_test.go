@@ -0,0 +1,195 @@
+package maps_test
+
+import (
+	"testing"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/maps"
+	"github.com/amp-labs/amp-common/sortable"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intEquals(a, b int) bool {
+	return a == b
+}
+
+func TestMapAlgebra(t *testing.T) {
+	t.Parallel()
+
+	constructors := map[string]func() maps.Map[tag, int]{
+		"hash":        func() maps.Map[tag, int] { return maps.NewHashMap[tag, int](hashing.Sha256) },
+		"persistent":  func() maps.Map[tag, int] { return maps.NewPersistentHashMap[tag, int](hashing.Sha256) },
+		"concurrent":  func() maps.Map[tag, int] { return maps.NewConcurrentHashMap[tag, int](hashing.Sha256, 4) },
+		"thread-safe": func() maps.Map[tag, int] { return maps.NewThreadSafeMap(maps.NewHashMap[tag, int](hashing.Sha256)) },
+		"default": func() maps.Map[tag, int] {
+			return maps.NewDefaultZeroMap(maps.NewHashMap[tag, int](hashing.Sha256))
+		},
+	}
+
+	for name, newMap := range constructors {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			build := func(entries map[tag]int) maps.Map[tag, int] {
+				m := newMap()
+				for key, value := range entries {
+					require.NoError(t, m.Add(key, value))
+				}
+
+				return m
+			}
+
+			ab := build(map[tag]int{"a": 1, "b": 2})
+			bc := build(map[tag]int{"b": 20, "c": 3})
+
+			difference, err := ab.Difference(bc)
+			require.NoError(t, err)
+			assert.Equal(t, map[tag]int{"a": 1}, toGoMap(difference))
+
+			symmetric, err := ab.SymmetricDifference(bc)
+			require.NoError(t, err)
+			assert.Equal(t, map[tag]int{"a": 1, "c": 3}, toGoMap(symmetric))
+
+			subset, err := build(map[tag]int{"b": 0}).IsSubsetOf(ab)
+			require.NoError(t, err)
+			assert.True(t, subset)
+
+			superset, err := ab.IsSupersetOf(bc)
+			require.NoError(t, err)
+			assert.False(t, superset)
+
+			disjoint, err := ab.IsDisjoint(build(map[tag]int{"z": 0}))
+			require.NoError(t, err)
+			assert.True(t, disjoint)
+
+			// Equals compares values, the other predicates only keys
+			equal, err := ab.Equals(build(map[tag]int{"b": 2, "a": 1}), intEquals)
+			require.NoError(t, err)
+			assert.True(t, equal)
+
+			equal, err = ab.Equals(build(map[tag]int{"a": 1, "b": 3}), intEquals)
+			require.NoError(t, err)
+			assert.False(t, equal)
+
+			equal, err = ab.Equals(bc, intEquals)
+			require.NoError(t, err)
+			assert.False(t, equal)
+
+			// Lookups must not fill in default values
+			assert.Equal(t, 2, ab.Size())
+			assert.Equal(t, 2, bc.Size())
+		})
+	}
+}
+
+func TestOrderedMapAlgebra(t *testing.T) {
+	t.Parallel()
+
+	build := func(t *testing.T, m maps.OrderedMap[tag, int], keys ...tag) maps.OrderedMap[tag, int] {
+		t.Helper()
+
+		for i, key := range keys {
+			require.NoError(t, m.Add(key, i))
+		}
+
+		return m
+	}
+
+	constructors := map[string]func() maps.OrderedMap[tag, int]{
+		"ordered": func() maps.OrderedMap[tag, int] { return maps.NewOrderedHashMap[tag, int](hashing.Sha256) },
+		"persistent": func() maps.OrderedMap[tag, int] {
+			return maps.NewPersistentOrderedHashMap[tag, int](hashing.Sha256)
+		},
+		"thread-safe": func() maps.OrderedMap[tag, int] {
+			return maps.NewThreadSafeOrderedMap(maps.NewOrderedHashMap[tag, int](hashing.Sha256))
+		},
+	}
+
+	for name, newMap := range constructors {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m := build(t, newMap(), "c", "a", "b")
+			other := build(t, newMap(), "e", "b", "d")
+
+			difference, err := m.Difference(other)
+			require.NoError(t, err)
+			assert.Equal(t, []tag{"c", "a"}, tagKeys(difference))
+
+			symmetric, err := m.SymmetricDifference(other)
+			require.NoError(t, err)
+			assert.Equal(t, []tag{"c", "a", "e", "d"}, tagKeys(symmetric))
+
+			equal, err := m.Equals(build(t, newMap(), "c", "a", "b"), intEquals)
+			require.NoError(t, err)
+			assert.True(t, equal)
+		})
+	}
+}
+
+func TestSortedMapAlgebra(t *testing.T) {
+	t.Parallel()
+
+	tree := maps.NewRedBlackTreeMap[sortable.Int, string]()
+	require.NoError(t, tree.Add(1, "one"))
+	require.NoError(t, tree.Add(2, "two"))
+
+	persistentTree := maps.NewPersistentSortedMap[sortable.Int, string]()
+	require.NoError(t, persistentTree.Add(2, "two"))
+	require.NoError(t, persistentTree.Add(3, "three"))
+
+	symmetric, err := tree.SymmetricDifference(persistentTree)
+	require.NoError(t, err)
+	assert.Equal(t, []sortable.Int{1, 3}, symmetric.Keys().Entries())
+
+	difference, err := persistentTree.Difference(tree)
+	require.NoError(t, err)
+	assert.Equal(t, []sortable.Int{3}, difference.Keys().Entries())
+	assert.Equal(t, 2, persistentTree.Size())
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	before := maps.NewOrderedHashMap[tag, int](hashing.Sha256)
+	require.NoError(t, before.Add("kept", 1))
+	require.NoError(t, before.Add("changed", 2))
+	require.NoError(t, before.Add("removed", 3))
+
+	after := maps.NewOrderedHashMap[tag, int](hashing.Sha256)
+	require.NoError(t, after.Add("added", 4))
+	require.NoError(t, after.Add("changed", 20))
+	require.NoError(t, after.Add("kept", 1))
+
+	delta, err := maps.DiffOrdered(before, after, intEquals)
+	require.NoError(t, err)
+	assert.False(t, delta.IsEmpty())
+	assert.Equal(t, []maps.KeyValuePair[tag, int]{{Key: "added", Value: 4}}, delta.Added)
+	assert.Equal(t, []maps.KeyValuePair[tag, int]{{Key: "removed", Value: 3}}, delta.Removed)
+	assert.Equal(t, []maps.ValueChange[tag, int]{{Key: "changed", Old: 2, New: 20}}, delta.Changed)
+
+	// Diffing a default map doesn't fill in the missing keys
+	defaults := maps.NewDefaultZeroMap(maps.NewHashMap[tag, int](hashing.Sha256))
+	require.NoError(t, defaults.Add("kept", 1))
+
+	unordered := maps.NewHashMap[tag, int](hashing.Sha256)
+	require.NoError(t, unordered.Add("kept", 1))
+
+	delta, err = maps.Diff(defaults, unordered, intEquals)
+	require.NoError(t, err)
+	assert.True(t, delta.IsEmpty())
+
+	delta, err = maps.Diff(unordered, maps.NewDefaultZeroMap(maps.NewHashMap[tag, int](hashing.Sha256)), intEquals)
+	require.NoError(t, err)
+	assert.Len(t, delta.Removed, 1)
+}
+
+func toGoMap(m maps.Map[tag, int]) map[tag]int {
+	result := make(map[tag]int, m.Size())
+	for key, value := range m.Seq() {
+		result[key] = value
+	}
+
+	return result
+}
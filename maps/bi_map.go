@@ -0,0 +1,198 @@
+package maps //nolint:revive // Established package name; renaming would break all consumers.
+
+import (
+	"errors"
+	"iter"
+
+	"github.com/amp-labs/amp-common/collectable"
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/optional"
+	"github.com/amp-labs/amp-common/set"
+)
+
+// ErrValueAlreadyBound is returned by BiMap.Add when the value already belongs to a different key.
+var ErrValueAlreadyBound = errors.New("value is already bound to a different key")
+
+// NewBiMap creates an empty BiMap. It keeps a hash map in each direction, both
+// using hash, so lookups by key and by value are equally cheap.
+//
+// The returned map is not thread-safe; wrap it with NewThreadSafeBiMap for
+// concurrent use.
+//
+// Example:
+//
+//	names := maps.NewBiMap[UserID, hashing.HashableString](hashing.Sha256)
+//	_ = names.Add(42, "alice")
+//	id, _, _ := names.GetKey("alice") // 42
+//	err := names.Add(7, "alice")      // ErrValueAlreadyBound
+func NewBiMap[K collectable.Collectable[K], V collectable.Collectable[V]](hash hashing.HashFunc) BiMap[K, V] {
+	return &biMap[K, V]{
+		forward:  NewHashMap[K, V](hash),
+		backward: NewHashMap[V, K](hash),
+	}
+}
+
+// biMap keeps forward and backward in sync: key maps to value in forward
+// exactly when value maps to key in backward. Inverse swaps the two maps.
+type biMap[K collectable.Collectable[K], V collectable.Collectable[V]] struct {
+	forward  Map[K, V]
+	backward Map[V, K]
+}
+
+func (b *biMap[K, V]) Get(key K) (V, bool, error) {
+	return b.forward.Get(key)
+}
+
+func (b *biMap[K, V]) GetKey(value V) (K, bool, error) {
+	return b.backward.Get(value)
+}
+
+func (b *biMap[K, V]) Add(key K, value V) error {
+	owner, found, err := b.backward.Get(value)
+	if err != nil {
+		return err
+	}
+
+	if found && !owner.Equals(key) {
+		return ErrValueAlreadyBound
+	}
+
+	return b.put(key, value)
+}
+
+func (b *biMap[K, V]) ForceAdd(key K, value V) error {
+	if err := b.RemoveValue(value); err != nil {
+		return err
+	}
+
+	return b.put(key, value)
+}
+
+// put associates key with value, dropping the key's previous value from the
+// backward map. The caller has made sure value isn't bound to another key.
+func (b *biMap[K, V]) put(key K, value V) error {
+	previous, found, err := b.forward.Get(key)
+	if err != nil {
+		return err
+	}
+
+	if found {
+		if err := b.backward.Remove(previous); err != nil {
+			return err
+		}
+	}
+
+	if err := b.forward.Add(key, value); err != nil {
+		return err
+	}
+
+	return b.backward.Add(value, key)
+}
+
+func (b *biMap[K, V]) Remove(key K) error {
+	value, found, err := b.forward.Get(key)
+	if err != nil || !found {
+		return err
+	}
+
+	if err := b.backward.Remove(value); err != nil {
+		return err
+	}
+
+	return b.forward.Remove(key)
+}
+
+func (b *biMap[K, V]) RemoveValue(value V) error {
+	return b.Inverse().Remove(value)
+}
+
+func (b *biMap[K, V]) Clear() {
+	b.forward.Clear()
+	b.backward.Clear()
+}
+
+func (b *biMap[K, V]) Contains(key K) (bool, error) {
+	return b.forward.Contains(key)
+}
+
+func (b *biMap[K, V]) ContainsValue(value V) (bool, error) {
+	return b.backward.Contains(value)
+}
+
+func (b *biMap[K, V]) Size() int {
+	return b.forward.Size()
+}
+
+func (b *biMap[K, V]) Seq() iter.Seq2[K, V] {
+	return b.forward.Seq()
+}
+
+func (b *biMap[K, V]) Inverse() BiMap[V, K] {
+	return &biMap[V, K]{
+		forward:  b.backward,
+		backward: b.forward,
+	}
+}
+
+func (b *biMap[K, V]) Clone() BiMap[K, V] {
+	return &biMap[K, V]{
+		forward:  b.forward.Clone(),
+		backward: b.backward.Clone(),
+	}
+}
+
+func (b *biMap[K, V]) HashFunction() hashing.HashFunc {
+	return b.forward.HashFunction()
+}
+
+func (b *biMap[K, V]) Keys() set.Set[K] {
+	return b.forward.Keys()
+}
+
+func (b *biMap[K, V]) Values() set.Set[V] {
+	return b.backward.Keys()
+}
+
+func (b *biMap[K, V]) ForEach(f func(key K, value V)) {
+	b.forward.ForEach(f)
+}
+
+func (b *biMap[K, V]) ForAll(predicate func(key K, value V) bool) bool {
+	return b.forward.ForAll(predicate)
+}
+
+func (b *biMap[K, V]) Exists(predicate func(key K, value V) bool) bool {
+	return b.forward.Exists(predicate)
+}
+
+func (b *biMap[K, V]) Filter(predicate func(key K, value V) bool) BiMap[K, V] {
+	result := NewBiMap[K, V](b.HashFunction())
+
+	for key, value := range b.Seq() {
+		if predicate(key, value) {
+			_ = result.Add(key, value) // The entry was hashed, and is unique, in this map
+		}
+	}
+
+	return result
+}
+
+func (b *biMap[K, V]) FilterNot(predicate func(key K, value V) bool) BiMap[K, V] {
+	return b.Filter(func(key K, value V) bool {
+		return !predicate(key, value)
+	})
+}
+
+func (b *biMap[K, V]) Map(f func(key K, value V) (K, V)) BiMap[K, V] {
+	result := NewBiMap[K, V](b.HashFunction())
+
+	for key, value := range b.Seq() {
+		_ = result.ForceAdd(f(key, value)) // Entries that can't be hashed are dropped, as in Map.Map
+	}
+
+	return result
+}
+
+func (b *biMap[K, V]) FindFirst(predicate func(key K, value V) bool) optional.Value[KeyValuePair[K, V]] {
+	return b.forward.FindFirst(predicate)
+}
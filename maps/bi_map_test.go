@@ -0,0 +1,140 @@
+package maps_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/maps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBiMap(t *testing.T) {
+	t.Parallel()
+
+	newBiMap := func(t *testing.T) maps.BiMap[testKey, tag] {
+		t.Helper()
+
+		m := maps.NewBiMap[testKey, tag](hashing.Sha256)
+		require.NoError(t, m.Add(testKey{"1"}, "alice"))
+		require.NoError(t, m.Add(testKey{"2"}, "bob"))
+
+		return m
+	}
+
+	t.Run("looks up both ways", func(t *testing.T) {
+		t.Parallel()
+
+		m := newBiMap(t)
+
+		value, found, err := m.Get(testKey{"1"})
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, tag("alice"), value)
+
+		key, found, err := m.GetKey("bob")
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, testKey{"2"}, key)
+
+		assert.ElementsMatch(t, []tag{"alice", "bob"}, m.Values().Entries())
+	})
+
+	t.Run("enforces unique values", func(t *testing.T) {
+		t.Parallel()
+
+		m := newBiMap(t)
+
+		require.ErrorIs(t, m.Add(testKey{"3"}, "alice"), maps.ErrValueAlreadyBound)
+		assert.Equal(t, 2, m.Size())
+
+		// Re-adding the same pair is fine, and changing a key's value frees the old one
+		require.NoError(t, m.Add(testKey{"1"}, "alice"))
+		require.NoError(t, m.Add(testKey{"1"}, "carol"))
+
+		contains, err := m.ContainsValue("alice")
+		require.NoError(t, err)
+		assert.False(t, contains)
+
+		// ForceAdd takes the value away from its current key
+		require.NoError(t, m.ForceAdd(testKey{"3"}, "bob"))
+
+		contains, err = m.Contains(testKey{"2"})
+		require.NoError(t, err)
+		assert.False(t, contains)
+		assert.Equal(t, 2, m.Size())
+	})
+
+	t.Run("inverse shares storage", func(t *testing.T) {
+		t.Parallel()
+
+		m := newBiMap(t)
+		inverse := m.Inverse()
+
+		require.NoError(t, inverse.Add("carol", testKey{"3"}))
+		require.NoError(t, m.RemoveValue("alice"))
+
+		value, found, err := m.Get(testKey{"3"})
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, tag("carol"), value)
+
+		contains, err := inverse.Contains("alice")
+		require.NoError(t, err)
+		assert.False(t, contains)
+		assert.Equal(t, 2, inverse.Size())
+	})
+
+	t.Run("functional API", func(t *testing.T) {
+		t.Parallel()
+
+		m := newBiMap(t)
+
+		filtered := m.Filter(func(_ testKey, value tag) bool { return value == "bob" })
+		assert.Equal(t, 1, filtered.Size())
+		assert.Equal(t, 1, m.FilterNot(func(_ testKey, value tag) bool { return value == "bob" }).Size())
+
+		// Entries mapped to the same value collapse into one
+		mapped := m.Map(func(key testKey, _ tag) (testKey, tag) { return key, "same" })
+		assert.Equal(t, 1, mapped.Size())
+
+		assert.True(t, m.ForAll(func(key testKey, _ tag) bool { return key.value != "" }))
+		assert.True(t, m.Exists(func(key testKey, _ tag) bool { return key.value == "2" }))
+
+		clone := m.Clone()
+		clone.Clear()
+		assert.Equal(t, 2, m.Size())
+	})
+}
+
+func TestThreadSafeBiMap(t *testing.T) {
+	t.Parallel()
+
+	m := maps.NewThreadSafeBiMap(maps.NewBiMap[testKey, tag](hashing.Sha256))
+	assert.Same(t, m, maps.NewThreadSafeBiMap(m))
+
+	inverse := m.Inverse()
+
+	var wg sync.WaitGroup
+
+	for i := range 10 {
+		wg.Go(func() {
+			for j := range 10 {
+				id := fmt.Sprint(i*10 + j)
+
+				if j%2 == 0 {
+					assert.NoError(t, m.Add(testKey{id}, tag(id)))
+				} else {
+					assert.NoError(t, inverse.Add(tag(id), testKey{id}))
+				}
+			}
+		})
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 100, m.Size())
+	assert.Equal(t, 100, inverse.Size())
+}
@@ -0,0 +1,580 @@
+package maps //nolint:revive // Established package name; renaming would break all consumers.
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"runtime"
+	"sync"
+
+	"github.com/amp-labs/amp-common/collectable"
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/optional"
+	"github.com/amp-labs/amp-common/set"
+	"github.com/amp-labs/amp-common/zero"
+)
+
+// shardsPerProc is the number of shards per available CPU used when no shard count is given.
+const shardsPerProc = 4
+
+// NewConcurrentHashMap creates a ConcurrentMap that spreads its keys over
+// independently locked shards. Each key is hashed once with the provided hash
+// function, which also selects its shard, so goroutines working on keys in
+// different shards never contend. Compared to NewThreadSafeMap, which guards the
+// whole map with one lock, this scales much better under many writers.
+//
+// shards is rounded up to a power of two. If it is zero or negative, a default
+// based on GOMAXPROCS is used.
+//
+// Operations that span the whole map (Size, Seq, ForEach, Union, Clone, ...)
+// visit the shards one at a time, so they don't see a consistent snapshot of
+// the map if it is modified concurrently.
+//
+// Example:
+//
+//	counts := maps.NewConcurrentHashMap[hashing.HashableString, int](hashing.XxHash32, 0)
+//	_, _, err := counts.Compute("requests", func(_ hashing.HashableString, n int, _ bool) (int, bool, error) {
+//	    return n + 1, true, nil
+//	})
+func NewConcurrentHashMap[K collectable.Collectable[K], V any](hash hashing.HashFunc, shards int) ConcurrentMap[K, V] {
+	if shards <= 0 {
+		shards = shardsPerProc * runtime.GOMAXPROCS(0)
+	}
+
+	// Round up to a power of two so a shard can be picked with a mask
+	count := 1 << bits.Len(uint(shards-1)) //nolint:gosec // shards is positive
+
+	m := &concurrentHashMap[K, V]{
+		hash:   hash,
+		seed:   maphash.MakeSeed(),
+		shards: make([]concurrentShard[K, V], count),
+		mask:   uint64(count - 1), //nolint:gosec // count is positive
+	}
+
+	for i := range m.shards {
+		m.shards[i].data = make(map[string]bucket[K, V])
+	}
+
+	return m
+}
+
+var _ ConcurrentMap[hashing.HashableString, string] = (*concurrentHashMap[hashing.HashableString, string])(nil)
+
+// concurrentHashMap is the ConcurrentMap returned by NewConcurrentHashMap.
+type concurrentHashMap[K collectable.Collectable[K], V any] struct {
+	hash   hashing.HashFunc
+	seed   maphash.Seed
+	shards []concurrentShard[K, V]
+	mask   uint64
+}
+
+// concurrentShard holds the keys whose hashes map to it, guarded by its own lock.
+// Like hashMap, it stores the entries in buckets keyed by hash value.
+type concurrentShard[K collectable.Collectable[K], V any] struct {
+	mutex sync.RWMutex
+	data  map[string]bucket[K, V]
+	size  int
+}
+
+// locate hashes key and returns its shard together with the hash value.
+func (m *concurrentHashMap[K, V]) locate(key K) (*concurrentShard[K, V], string, error) {
+	hashVal, err := m.hash(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &m.shards[maphash.String(m.seed, hashVal)&m.mask], hashVal, nil
+}
+
+// empty returns a new, empty map with the same hash function and shard count.
+func (m *concurrentHashMap[K, V]) empty() *concurrentHashMap[K, V] {
+	return NewConcurrentHashMap[K, V](m.hash, len(m.shards)).(*concurrentHashMap[K, V]) //nolint:forcetypeassert
+}
+
+// get returns the value stored for key. The caller must hold the shard's lock.
+func (s *concurrentShard[K, V]) get(hashVal string, key K) (V, bool) {
+	entries := s.data[hashVal]
+
+	i := entries.find(key)
+	if i < 0 {
+		return zero.Value[V](), false
+	}
+
+	return entries[i].Value, true
+}
+
+// put stores value for key. The caller must hold the shard's write lock.
+func (s *concurrentShard[K, V]) put(hashVal string, key K, value V) {
+	entries := s.data[hashVal]
+
+	if i := entries.find(key); i >= 0 {
+		entries[i].Value = value
+
+		return
+	}
+
+	s.data[hashVal] = append(entries, KeyValuePair[K, V]{Key: key, Value: value})
+	s.size++
+}
+
+// remove deletes key. The caller must hold the shard's write lock.
+func (s *concurrentShard[K, V]) remove(hashVal string, key K) {
+	entries := s.data[hashVal]
+
+	i := entries.find(key)
+	if i < 0 {
+		return
+	}
+
+	if rest := entries.without(i); rest != nil {
+		s.data[hashVal] = rest
+	} else {
+		delete(s.data, hashVal)
+	}
+
+	s.size--
+}
+
+// Get retrieves the value for the given key, locking only the key's shard for reading.
+// Returns an error only if hashing the key fails.
+func (m *concurrentHashMap[K, V]) Get(key K) (value V, found bool, err error) {
+	shard, hashVal, err := m.locate(key)
+	if err != nil {
+		return zero.Value[V](), false, err
+	}
+
+	shard.mutex.RLock()
+	defer shard.mutex.RUnlock()
+
+	value, found = shard.get(hashVal, key)
+
+	return value, found, nil
+}
+
+// GetOrElse retrieves the value for the given key, or returns defaultValue if the key doesn't exist.
+// Returns an error only if hashing the key fails.
+func (m *concurrentHashMap[K, V]) GetOrElse(key K, defaultValue V) (value V, err error) {
+	value, found, err := m.Get(key)
+	if err != nil {
+		return zero.Value[V](), err
+	}
+
+	if !found {
+		return defaultValue, nil
+	}
+
+	return value, nil
+}
+
+// Add inserts or updates a key-value pair, locking only the key's shard.
+// Returns an error only if hashing the key fails.
+func (m *concurrentHashMap[K, V]) Add(key K, value V) error {
+	shard, hashVal, err := m.locate(key)
+	if err != nil {
+		return err
+	}
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	shard.put(hashVal, key, value)
+
+	return nil
+}
+
+// Remove deletes a key-value pair, locking only the key's shard.
+// Returns an error only if hashing the key fails.
+func (m *concurrentHashMap[K, V]) Remove(key K) error {
+	shard, hashVal, err := m.locate(key)
+	if err != nil {
+		return err
+	}
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	shard.remove(hashVal, key)
+
+	return nil
+}
+
+// Clear removes all entries, one shard at a time.
+func (m *concurrentHashMap[K, V]) Clear() {
+	for i := range m.shards {
+		shard := &m.shards[i]
+
+		shard.mutex.Lock()
+		shard.data = make(map[string]bucket[K, V])
+		shard.size = 0
+		shard.mutex.Unlock()
+	}
+}
+
+// Contains checks whether a key exists in the map.
+// Returns an error only if hashing the key fails.
+func (m *concurrentHashMap[K, V]) Contains(key K) (bool, error) {
+	_, found, err := m.Get(key)
+
+	return found, err
+}
+
+// Size returns the number of entries, summed over the shards.
+func (m *concurrentHashMap[K, V]) Size() int {
+	size := 0
+
+	for i := range m.shards {
+		shard := &m.shards[i]
+
+		shard.mutex.RLock()
+		size += shard.size
+		shard.mutex.RUnlock()
+	}
+
+	return size
+}
+
+// ComputeIfAbsent returns the value for key, computing and storing it with f
+// while the key's shard is locked if the key is absent.
+func (m *concurrentHashMap[K, V]) ComputeIfAbsent(key K, f func(key K) (V, error)) (value V, computed bool, err error) {
+	shard, hashVal, err := m.locate(key)
+	if err != nil {
+		return zero.Value[V](), false, err
+	}
+
+	// Most calls find the key, so try with the shared lock first
+	shard.mutex.RLock()
+	value, found := shard.get(hashVal, key)
+	shard.mutex.RUnlock()
+
+	if found {
+		return value, false, nil
+	}
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	// Another goroutine may have stored the key in the meantime
+	if value, found = shard.get(hashVal, key); found {
+		return value, false, nil
+	}
+
+	value, err = f(key)
+	if err != nil {
+		return zero.Value[V](), false, err
+	}
+
+	shard.put(hashVal, key, value)
+
+	return value, true, nil
+}
+
+// ComputeIfPresent recomputes the value for key with f while the key's shard is locked.
+func (m *concurrentHashMap[K, V]) ComputeIfPresent(
+	key K,
+	f func(key K, value V) (newValue V, keep bool, err error),
+) (value V, present bool, err error) {
+	return m.Compute(key, func(key K, value V, found bool) (V, bool, error) {
+		if !found {
+			return value, false, nil
+		}
+
+		return f(key, value)
+	})
+}
+
+// Compute recomputes the value for key with f while the key's shard is locked.
+// If f fails, the entry is left unchanged and its error is returned.
+func (m *concurrentHashMap[K, V]) Compute(
+	key K,
+	f func(key K, value V, found bool) (newValue V, keep bool, err error),
+) (value V, present bool, err error) {
+	shard, hashVal, err := m.locate(key)
+	if err != nil {
+		return zero.Value[V](), false, err
+	}
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	current, found := shard.get(hashVal, key)
+
+	newValue, keep, err := f(key, current, found)
+	if err != nil {
+		return current, found, err
+	}
+
+	if !keep {
+		shard.remove(hashVal, key)
+
+		return zero.Value[V](), false, nil
+	}
+
+	shard.put(hashVal, key, newValue)
+
+	return newValue, true, nil
+}
+
+// CompareAndSwap stores newValue for key if its current value equals old.
+// It panics if V's dynamic type is not comparable.
+func (m *concurrentHashMap[K, V]) CompareAndSwap(key K, old, newValue V) (bool, error) {
+	shard, hashVal, err := m.locate(key)
+	if err != nil {
+		return false, err
+	}
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	current, found := shard.get(hashVal, key)
+	if !found || any(current) != any(old) {
+		return false, nil
+	}
+
+	shard.put(hashVal, key, newValue)
+
+	return true, nil
+}
+
+// snapshot copies the entries of all shards, locking one shard at a time.
+func (m *concurrentHashMap[K, V]) snapshot() []KeyValuePair[K, V] {
+	var entries []KeyValuePair[K, V]
+
+	for i := range m.shards {
+		shard := &m.shards[i]
+
+		shard.mutex.RLock()
+
+		for _, bucket := range shard.data {
+			entries = append(entries, bucket...)
+		}
+
+		shard.mutex.RUnlock()
+	}
+
+	return entries
+}
+
+// Seq returns an iterator over a snapshot of the map's entries. No lock is held
+// while iterating, and changes made after Seq is called are not visible.
+func (m *concurrentHashMap[K, V]) Seq() iter.Seq2[K, V] {
+	entries := m.snapshot()
+
+	return func(yield func(K, V) bool) {
+		for _, entry := range entries {
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Union creates a new concurrent map containing all entries from both maps.
+// If a key exists in both maps, the value from other takes precedence.
+func (m *concurrentHashMap[K, V]) Union(other Map[K, V]) (Map[K, V], error) {
+	result := m.empty()
+
+	for _, entry := range m.snapshot() {
+		err := result.Add(entry.Key, entry.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for key, value := range other.Seq() {
+		err := result.Add(key, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Intersection creates a new concurrent map with the entries whose keys also exist in other.
+// The values are taken from this map.
+func (m *concurrentHashMap[K, V]) Intersection(other Map[K, V]) (Map[K, V], error) {
+	result := m.empty()
+
+	for _, entry := range m.snapshot() {
+		contains, err := other.Contains(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		if contains {
+			err = result.Add(entry.Key, entry.Value)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Difference creates a new concurrent map with the entries whose keys are not in other.
+func (m *concurrentHashMap[K, V]) Difference(other Map[K, V]) (Map[K, V], error) {
+	result := m.empty()
+
+	err := addMissingKeys(m.Seq(), other, result.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SymmetricDifference creates a new concurrent map with the entries whose keys are in exactly one
+// of the two maps.
+func (m *concurrentHashMap[K, V]) SymmetricDifference(other Map[K, V]) (Map[K, V], error) {
+	result := m.empty()
+
+	err := addMissingKeys(m.Seq(), other, result.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	err = addMissingKeys(other.Seq(), m, result.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// IsSubsetOf reports whether every key of this map is also in other.
+func (m *concurrentHashMap[K, V]) IsSubsetOf(other Map[K, V]) (bool, error) {
+	return isSubsetKeys(m.Size(), m.Seq(), other)
+}
+
+// IsSupersetOf reports whether every key of other is also in this map.
+func (m *concurrentHashMap[K, V]) IsSupersetOf(other Map[K, V]) (bool, error) {
+	return isSubsetKeys(other.Size(), other.Seq(), m)
+}
+
+// IsDisjoint reports whether the two maps have no keys in common.
+func (m *concurrentHashMap[K, V]) IsDisjoint(other Map[K, V]) (bool, error) {
+	return isDisjointKeys(m.Seq(), other)
+}
+
+// Equals reports whether both maps have the same keys with equal values.
+func (m *concurrentHashMap[K, V]) Equals(other Map[K, V], valueEquals func(a, b V) bool) (bool, error) {
+	return isEqualMap(m.Size(), m.Seq(), other, valueEquals)
+}
+
+// Clone creates a new concurrent map with the same entries and shard count.
+func (m *concurrentHashMap[K, V]) Clone() Map[K, V] {
+	if m == nil {
+		return nil
+	}
+
+	result := m.empty()
+
+	for _, entry := range m.snapshot() {
+		_ = result.Add(entry.Key, entry.Value) // Add should not fail here
+	}
+
+	return result
+}
+
+// HashFunction returns the hash function used by this map.
+func (m *concurrentHashMap[K, V]) HashFunction() hashing.HashFunc {
+	return m.hash
+}
+
+// Keys returns a set containing all keys from the map.
+func (m *concurrentHashMap[K, V]) Keys() set.Set[K] {
+	keys := set.NewSet[K](m.hash)
+
+	for _, entry := range m.snapshot() {
+		_ = keys.Add(entry.Key) // Add should not fail for existing keys
+	}
+
+	return keys
+}
+
+// ForEach applies the given function to each entry of a snapshot of the map.
+// No lock is held while f runs, so f may access the map.
+func (m *concurrentHashMap[K, V]) ForEach(f func(key K, value V)) {
+	for _, entry := range m.snapshot() {
+		f(entry.Key, entry.Value)
+	}
+}
+
+// ForAll tests whether a predicate holds for all entries of a snapshot of the map.
+func (m *concurrentHashMap[K, V]) ForAll(predicate func(key K, value V) bool) bool {
+	for _, entry := range m.snapshot() {
+		if !predicate(entry.Key, entry.Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Filter creates a new concurrent map with the entries for which the predicate returns true.
+func (m *concurrentHashMap[K, V]) Filter(predicate func(key K, value V) bool) Map[K, V] {
+	result := m.empty()
+
+	for _, entry := range m.snapshot() {
+		if predicate(entry.Key, entry.Value) {
+			_ = result.Add(entry.Key, entry.Value) // Add should not fail for valid keys
+		}
+	}
+
+	return result
+}
+
+// FilterNot creates a new concurrent map with the entries for which the predicate returns false.
+func (m *concurrentHashMap[K, V]) FilterNot(predicate func(key K, value V) bool) Map[K, V] {
+	return m.Filter(func(key K, value V) bool { return !predicate(key, value) })
+}
+
+// Map creates a new concurrent map with the entries transformed by f.
+// If the transformation produces duplicate keys, later entries overwrite earlier ones.
+func (m *concurrentHashMap[K, V]) Map(f func(key K, value V) (K, V)) Map[K, V] {
+	result := m.empty()
+
+	for _, entry := range m.snapshot() {
+		_ = result.Add(f(entry.Key, entry.Value)) // Duplicate keys will be overwritten
+	}
+
+	return result
+}
+
+// FlatMap creates a new concurrent map by merging the maps returned by f for each entry.
+// If duplicate keys exist across multiple results, later values take precedence.
+func (m *concurrentHashMap[K, V]) FlatMap(f func(key K, value V) Map[K, V]) Map[K, V] {
+	result := m.empty()
+
+	for _, entry := range m.snapshot() {
+		for newKey, newValue := range f(entry.Key, entry.Value).Seq() {
+			_ = result.Add(newKey, newValue) // Duplicate keys will be overwritten
+		}
+	}
+
+	return result
+}
+
+// Exists tests whether at least one entry of a snapshot of the map satisfies the predicate.
+func (m *concurrentHashMap[K, V]) Exists(predicate func(key K, value V) bool) bool {
+	for _, entry := range m.snapshot() {
+		if predicate(entry.Key, entry.Value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FindFirst returns an entry of a snapshot of the map that satisfies the predicate.
+// The iteration order is non-deterministic, so "first" is not guaranteed to be consistent.
+func (m *concurrentHashMap[K, V]) FindFirst(predicate func(key K, value V) bool) optional.Value[KeyValuePair[K, V]] {
+	for _, entry := range m.snapshot() {
+		if predicate(entry.Key, entry.Value) {
+			return optional.Some(entry)
+		}
+	}
+
+	return optional.None[KeyValuePair[K, V]]()
+}
@@ -0,0 +1,229 @@
+package maps_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/maps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errComputeFailed = errors.New("compute failed")
+
+func TestConcurrentHashMap(t *testing.T) {
+	t.Parallel()
+
+	t.Run("behaves like a map", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewConcurrentHashMap[testKey, int](hashing.Sha256, 3)
+
+		for i := range 20 {
+			require.NoError(t, m.Add(testKey{fmt.Sprint(i)}, i))
+		}
+
+		require.NoError(t, m.Add(testKey{"0"}, 100))
+		require.NoError(t, m.Remove(testKey{"1"}))
+		assert.Equal(t, 19, m.Size())
+
+		value, found, err := m.Get(testKey{"0"})
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, 100, value)
+
+		value, err = m.GetOrElse(testKey{"1"}, -1)
+		require.NoError(t, err)
+		assert.Equal(t, -1, value)
+
+		assert.Equal(t, 19, m.Keys().Size())
+		assert.Equal(t, 19, m.Clone().Size())
+		assert.Equal(t, 11, m.Filter(func(_ testKey, v int) bool { return v >= 10 }).Size())
+
+		m.Clear()
+		assert.Equal(t, 0, m.Size())
+	})
+
+	t.Run("keeps colliding keys apart", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewConcurrentHashMap[collidingKey, string](hashing.Sha256, 0)
+
+		require.NoError(t, m.Add(collidingKey{id: 1, hash: "same"}, "one"))
+		require.NoError(t, m.Add(collidingKey{id: 2, hash: "same"}, "two"))
+		require.NoError(t, m.Remove(collidingKey{id: 1, hash: "same"}))
+
+		value, found, err := m.Get(collidingKey{id: 2, hash: "same"})
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "two", value)
+		assert.Equal(t, 1, m.Size())
+	})
+
+	t.Run("ComputeIfAbsent", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewConcurrentHashMap[testKey, int](hashing.Sha256, 0)
+
+		value, computed, err := m.ComputeIfAbsent(testKey{"a"}, func(testKey) (int, error) { return 1, nil })
+		require.NoError(t, err)
+		assert.True(t, computed)
+		assert.Equal(t, 1, value)
+
+		value, computed, err = m.ComputeIfAbsent(testKey{"a"}, func(testKey) (int, error) { return 2, nil })
+		require.NoError(t, err)
+		assert.False(t, computed)
+		assert.Equal(t, 1, value)
+
+		value, computed, err = m.ComputeIfAbsent(testKey{"b"}, func(testKey) (int, error) { return 3, errComputeFailed })
+		require.ErrorIs(t, err, errComputeFailed)
+		assert.False(t, computed, "nothing was stored")
+		assert.Zero(t, value)
+
+		contains, err := m.Contains(testKey{"b"})
+		require.NoError(t, err)
+		assert.False(t, contains)
+	})
+
+	t.Run("ComputeIfPresent", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewConcurrentHashMap[testKey, int](hashing.Sha256, 0)
+		require.NoError(t, m.Add(testKey{"a"}, 1))
+
+		double := func(_ testKey, v int) (int, bool, error) { return v * 2, true, nil }
+
+		value, present, err := m.ComputeIfPresent(testKey{"a"}, double)
+		require.NoError(t, err)
+		assert.True(t, present)
+		assert.Equal(t, 2, value)
+
+		_, present, err = m.ComputeIfPresent(testKey{"missing"}, double)
+		require.NoError(t, err)
+		assert.False(t, present)
+		assert.Equal(t, 1, m.Size())
+
+		_, present, err = m.ComputeIfPresent(testKey{"a"}, func(testKey, int) (int, bool, error) { return 0, false, nil })
+		require.NoError(t, err)
+		assert.False(t, present)
+		assert.Equal(t, 0, m.Size())
+	})
+
+	t.Run("Compute", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewConcurrentHashMap[testKey, int](hashing.Sha256, 0)
+
+		increment := func(_ testKey, v int, found bool) (int, bool, error) {
+			if !found {
+				return 1, true, nil
+			}
+
+			return v + 1, true, nil
+		}
+
+		_, _, err := m.Compute(testKey{"a"}, increment)
+		require.NoError(t, err)
+
+		value, present, err := m.Compute(testKey{"a"}, increment)
+		require.NoError(t, err)
+		assert.True(t, present)
+		assert.Equal(t, 2, value)
+
+		value, present, err = m.Compute(testKey{"a"}, func(testKey, int, bool) (int, bool, error) {
+			return 0, false, errComputeFailed
+		})
+		require.ErrorIs(t, err, errComputeFailed)
+		assert.True(t, present)
+		assert.Equal(t, 2, value)
+	})
+
+	t.Run("CompareAndSwap", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewConcurrentHashMap[testKey, string](hashing.Sha256, 0)
+		require.NoError(t, m.Add(testKey{"a"}, "old"))
+
+		swapped, err := m.CompareAndSwap(testKey{"a"}, "other", "new")
+		require.NoError(t, err)
+		assert.False(t, swapped)
+
+		swapped, err = m.CompareAndSwap(testKey{"a"}, "old", "new")
+		require.NoError(t, err)
+		assert.True(t, swapped)
+
+		swapped, err = m.CompareAndSwap(testKey{"missing"}, "", "new")
+		require.NoError(t, err)
+		assert.False(t, swapped)
+
+		value, _, err := m.Get(testKey{"a"})
+		require.NoError(t, err)
+		assert.Equal(t, "new", value)
+	})
+
+	t.Run("atomic under contention", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewConcurrentHashMap[testKey, int](hashing.Sha256, 4)
+
+		var (
+			wg    sync.WaitGroup
+			calls atomic.Int32
+		)
+
+		for range 8 {
+			wg.Go(func() {
+				for i := range 100 {
+					key := testKey{fmt.Sprint(i % 10)}
+
+					_, _, err := m.Compute(key, func(_ testKey, v int, _ bool) (int, bool, error) {
+						return v + 1, true, nil
+					})
+					assert.NoError(t, err)
+
+					_, _, err = m.ComputeIfAbsent(testKey{"once"}, func(testKey) (int, error) {
+						calls.Add(1)
+
+						return 0, nil
+					})
+					assert.NoError(t, err)
+
+					for {
+						current, _, err := m.Get(testKey{"cas"})
+						assert.NoError(t, err)
+
+						if current == 0 {
+							_, _, _ = m.ComputeIfAbsent(testKey{"cas"}, func(testKey) (int, error) { return 1, nil })
+
+							continue
+						}
+
+						swapped, err := m.CompareAndSwap(testKey{"cas"}, current, current+1)
+						assert.NoError(t, err)
+
+						if swapped {
+							break
+						}
+					}
+				}
+			})
+		}
+
+		wg.Wait()
+
+		for i := range 10 {
+			value, _, err := m.Get(testKey{fmt.Sprint(i)})
+			require.NoError(t, err)
+			assert.Equal(t, 80, value)
+		}
+
+		value, _, err := m.Get(testKey{"cas"})
+		require.NoError(t, err)
+		assert.Equal(t, 801, value)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
@@ -132,7 +132,7 @@ var _ Map[string, string] = (*defaultMap[string, string])(nil)
 //   - If the function returns ErrNoDefaultValue, returns zero value with found=false
 //   - If the function returns another error, returns that error
 //
-// Returns ErrHashCollision if a hash collision occurs during lookup or insertion.
+// Returns an error if hashing the key fails.
 func (d *defaultMap[K, V]) Get(key K) (value V, found bool, err error) {
 	value, found, err = d.m.Get(key)
 	if found || err != nil {
@@ -154,7 +154,7 @@ func (d *defaultMap[K, V]) Get(key K) (value V, found bool, err error) {
 // GetOrElse retrieves the value for the given key, or returns defaultValue if the key doesn't exist.
 // If the key doesn't exist, the default value function is NOT invoked - the provided defaultValue
 // parameter is returned directly instead.
-// Returns ErrHashCollision if a different key with the same hash exists in the map.
+// Returns an error only if hashing the key fails.
 func (d *defaultMap[K, V]) GetOrElse(key K, defaultValue V) (value V, err error) {
 	var found bool
 
@@ -177,14 +177,14 @@ func (d *defaultMap[K, V]) GetOrElse(key K, defaultValue V) (value V, err error)
 
 // Add inserts or updates a key-value pair in the map.
 // This operation bypasses the default value function and directly adds the provided value.
-// Returns ErrHashCollision if a hash collision occurs.
+// Returns an error only if hashing the key fails.
 func (d *defaultMap[K, V]) Add(key K, value V) error {
 	return d.m.Add(key, value)
 }
 
 // Remove deletes the key-value pair from the map.
 // If the key doesn't exist, this is a no-op and returns nil.
-// Returns ErrHashCollision if a hash collision occurs.
+// Returns an error only if hashing the key fails.
 func (d *defaultMap[K, V]) Remove(key K) error {
 	return d.m.Remove(key)
 }
@@ -200,7 +200,7 @@ func (d *defaultMap[K, V]) Clear() {
 //   - If the function returns ErrNoDefaultValue, returns false
 //   - If the function returns another error, returns that error
 //
-// Returns ErrHashCollision if a hash collision occurs during lookup or insertion.
+// Returns an error if hashing the key fails.
 func (d *defaultMap[K, V]) Contains(key K) (bool, error) {
 	contains, err := d.m.Contains(key)
 	if err != nil {
@@ -267,7 +267,7 @@ func (d *defaultMap[K, V]) Seq() iter.Seq2[K, V] {
 // Union creates a new defaultMap containing all key-value pairs from both this map and other.
 // If a key exists in both maps, the value from other takes precedence.
 // The returned map uses the same default value function as this map.
-// Returns ErrHashCollision if any hash collision occurs during the operation.
+// Returns an error if hashing any key fails.
 func (d *defaultMap[K, V]) Union(other Map[K, V]) (Map[K, V], error) {
 	tmp, err := d.m.Union(other)
 	if err != nil {
@@ -283,7 +283,7 @@ func (d *defaultMap[K, V]) Union(other Map[K, V]) (Map[K, V], error) {
 // Intersection creates a new defaultMap containing only key-value pairs whose keys exist in both maps.
 // The values are taken from this map, not from other.
 // The returned map uses the same default value function as this map.
-// Returns ErrHashCollision if any hash collision occurs during the operation.
+// Returns an error if hashing any key fails.
 func (d *defaultMap[K, V]) Intersection(other Map[K, V]) (Map[K, V], error) {
 	tmp, err := d.m.Intersection(other)
 	if err != nil {
@@ -296,6 +296,57 @@ func (d *defaultMap[K, V]) Intersection(other Map[K, V]) (Map[K, V], error) {
 	}, nil
 }
 
+// Difference creates a new defaultMap containing the key-value pairs of this map whose keys are not in other.
+// The returned map uses the same default value function as this map.
+// Returns an error if hashing any key fails.
+func (d *defaultMap[K, V]) Difference(other Map[K, V]) (Map[K, V], error) {
+	tmp, err := d.m.Difference(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return &defaultMap[K, V]{
+		m: tmp,
+		f: d.f,
+	}, nil
+}
+
+// SymmetricDifference creates a new defaultMap containing the key-value pairs whose keys are in exactly one
+// of the two maps. The returned map uses the same default value function as this map.
+// Returns an error if hashing any key fails.
+func (d *defaultMap[K, V]) SymmetricDifference(other Map[K, V]) (Map[K, V], error) {
+	tmp, err := d.m.SymmetricDifference(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return &defaultMap[K, V]{
+		m: tmp,
+		f: d.f,
+	}, nil
+}
+
+// IsSubsetOf reports whether every key of this map is also in other.
+func (d *defaultMap[K, V]) IsSubsetOf(other Map[K, V]) (bool, error) {
+	return d.m.IsSubsetOf(other)
+}
+
+// IsSupersetOf reports whether every key of other is also in this map.
+func (d *defaultMap[K, V]) IsSupersetOf(other Map[K, V]) (bool, error) {
+	return d.m.IsSupersetOf(other)
+}
+
+// IsDisjoint reports whether the two maps have no keys in common.
+func (d *defaultMap[K, V]) IsDisjoint(other Map[K, V]) (bool, error) {
+	return d.m.IsDisjoint(other)
+}
+
+// Equals reports whether both maps have the same keys with equal values.
+// Default values are not generated for keys missing from either map.
+func (d *defaultMap[K, V]) Equals(other Map[K, V], valueEquals func(a, b V) bool) (bool, error) {
+	return d.m.Equals(other, valueEquals)
+}
+
 // Clone creates a shallow copy of the map, duplicating its structure and entries.
 // The keys and values themselves are not deep-copied; they are referenced as-is.
 // Note: The cloned map does NOT preserve the default value function - it will not
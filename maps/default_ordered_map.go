@@ -137,7 +137,7 @@ var _ OrderedMap[string, string] = (*defaultOrderedMap[string, string])(nil)
 //   - If the function returns ErrNoDefaultValue, returns zero value with found=false
 //   - If the function returns another error, returns that error
 //
-// Returns ErrHashCollision if a hash collision occurs during lookup or insertion.
+// Returns an error if hashing the key fails.
 func (d *defaultOrderedMap[K, V]) Get(key K) (value V, found bool, err error) {
 	value, found, err = d.m.Get(key)
 	if found || err != nil {
@@ -159,7 +159,7 @@ func (d *defaultOrderedMap[K, V]) Get(key K) (value V, found bool, err error) {
 // GetOrElse retrieves the value for the given key, or returns defaultValue if the key doesn't exist.
 // If the key doesn't exist, the default value function is NOT invoked - the provided defaultValue
 // parameter is returned directly instead.
-// Returns ErrHashCollision if a different key with the same hash exists in the map.
+// Returns an error only if hashing the key fails.
 func (d *defaultOrderedMap[K, V]) GetOrElse(key K, defaultValue V) (value V, err error) {
 	var found bool
 
@@ -184,14 +184,14 @@ func (d *defaultOrderedMap[K, V]) GetOrElse(key K, defaultValue V) (value V, err
 // This operation bypasses the default value function and directly adds the provided value.
 // If the key already exists, its value is replaced without changing the insertion order.
 // If the key is new, it's appended to the end of the insertion order.
-// Returns ErrHashCollision if a hash collision occurs.
+// Returns an error only if hashing the key fails.
 func (d *defaultOrderedMap[K, V]) Add(key K, value V) error {
 	return d.m.Add(key, value)
 }
 
 // Remove deletes the key-value pair from the map.
 // If the key doesn't exist, this is a no-op and returns nil.
-// Returns ErrHashCollision if a hash collision occurs.
+// Returns an error only if hashing the key fails.
 func (d *defaultOrderedMap[K, V]) Remove(key K) error {
 	return d.m.Remove(key)
 }
@@ -207,7 +207,7 @@ func (d *defaultOrderedMap[K, V]) Clear() {
 //   - If the function returns ErrNoDefaultValue, returns false
 //   - If the function returns another error, returns that error
 //
-// Returns ErrHashCollision if a hash collision occurs during lookup or insertion.
+// Returns an error if hashing the key fails.
 func (d *defaultOrderedMap[K, V]) Contains(key K) (bool, error) {
 	contains, err := d.m.Contains(key)
 	if err != nil {
@@ -278,7 +278,7 @@ func (d *defaultOrderedMap[K, V]) Seq() iter.Seq2[int, KeyValuePair[K, V]] {
 // If a key exists in both maps, the value from other takes precedence, but the key maintains
 // its original position from this map.
 // The returned map uses the same default value function as this map.
-// Returns ErrHashCollision if any hash collision occurs during the operation.
+// Returns an error if hashing any key fails.
 func (d *defaultOrderedMap[K, V]) Union(other OrderedMap[K, V]) (OrderedMap[K, V], error) {
 	tmp, err := d.m.Union(other)
 	if err != nil {
@@ -294,7 +294,7 @@ func (d *defaultOrderedMap[K, V]) Union(other OrderedMap[K, V]) (OrderedMap[K, V
 // Intersection creates a new defaultOrderedMap containing only key-value pairs whose keys exist in both maps.
 // The values are taken from this map, not from other, and the insertion order is preserved from this map.
 // The returned map uses the same default value function as this map.
-// Returns ErrHashCollision if any hash collision occurs during the operation.
+// Returns an error if hashing any key fails.
 func (d *defaultOrderedMap[K, V]) Intersection(other OrderedMap[K, V]) (OrderedMap[K, V], error) {
 	tmp, err := d.m.Intersection(other)
 	if err != nil {
@@ -307,6 +307,58 @@ func (d *defaultOrderedMap[K, V]) Intersection(other OrderedMap[K, V]) (OrderedM
 	}, nil
 }
 
+// Difference creates a new defaultOrderedMap containing the key-value pairs of this map whose keys are not in other.
+// The insertion order is preserved from this map.
+// The returned map uses the same default value function as this map.
+// Returns an error if hashing any key fails.
+func (d *defaultOrderedMap[K, V]) Difference(other OrderedMap[K, V]) (OrderedMap[K, V], error) {
+	tmp, err := d.m.Difference(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return &defaultOrderedMap[K, V]{
+		m: tmp,
+		f: d.f,
+	}, nil
+}
+
+// SymmetricDifference creates a new defaultOrderedMap containing the key-value pairs whose keys are in exactly one
+// of the two maps. The returned map uses the same default value function as this map.
+// Returns an error if hashing any key fails.
+func (d *defaultOrderedMap[K, V]) SymmetricDifference(other OrderedMap[K, V]) (OrderedMap[K, V], error) {
+	tmp, err := d.m.SymmetricDifference(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return &defaultOrderedMap[K, V]{
+		m: tmp,
+		f: d.f,
+	}, nil
+}
+
+// IsSubsetOf reports whether every key of this map is also in other.
+func (d *defaultOrderedMap[K, V]) IsSubsetOf(other OrderedMap[K, V]) (bool, error) {
+	return d.m.IsSubsetOf(other)
+}
+
+// IsSupersetOf reports whether every key of other is also in this map.
+func (d *defaultOrderedMap[K, V]) IsSupersetOf(other OrderedMap[K, V]) (bool, error) {
+	return d.m.IsSupersetOf(other)
+}
+
+// IsDisjoint reports whether the two maps have no keys in common.
+func (d *defaultOrderedMap[K, V]) IsDisjoint(other OrderedMap[K, V]) (bool, error) {
+	return d.m.IsDisjoint(other)
+}
+
+// Equals reports whether both maps have the same keys with equal values.
+// Default values are not generated for keys missing from either map.
+func (d *defaultOrderedMap[K, V]) Equals(other OrderedMap[K, V], valueEquals func(a, b V) bool) (bool, error) {
+	return d.m.Equals(other, valueEquals)
+}
+
 // Clone creates a shallow copy of the map, duplicating its structure, entries, and insertion order.
 // The keys and values themselves are not deep-copied; they are referenced as-is.
 // Note: The cloned map does NOT preserve the default value function - it will not
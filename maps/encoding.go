@@ -0,0 +1,784 @@
+package maps //nolint:revive // Established package name; renaming would break all consumers.
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/amp-labs/amp-common/collectable"
+	"github.com/amp-labs/amp-common/hashing"
+	"gopkg.in/yaml.v3"
+)
+
+// Maps are encoded as a JSON object (or YAML mapping) when every key encodes
+// as a string (or YAML scalar), and otherwise as a list of {"key": ..., "value": ...}
+// entries. Ordered maps keep their insertion order and sorted maps their key order
+// in both forms. Decoding accepts either form, and replaces the map's contents.
+// Since the map implementations are unexported, use UnmarshalJSON, UnmarshalYAML
+// and their ordered variants to decode into a new map, or decode into an existing
+// one created with the right hash function:
+//
+//	m := maps.NewHashMap[hashing.HashableString, int](hashing.Sha256)
+//	err := gob.NewDecoder(r).Decode(m)
+
+// errUnexpectedEncoding is returned when encoded data is neither an object/mapping nor a list.
+var errUnexpectedEncoding = errors.New("expected an object or a list of entries")
+
+// encodedEntry is the list form of a single map entry.
+type encodedEntry[K any, V any] struct {
+	Key   K `json:"key"   yaml:"key"`
+	Value V `json:"value" yaml:"value"`
+}
+
+// UnmarshalJSON decodes a JSON-encoded map into a new hash map that uses hash.
+func UnmarshalJSON[K collectable.Collectable[K], V any](data []byte, hash hashing.HashFunc) (Map[K, V], error) {
+	m := NewHashMap[K, V](hash)
+
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// UnmarshalOrderedJSON decodes a JSON-encoded map into a new ordered hash map that
+// uses hash, keeping the order of the encoded entries.
+func UnmarshalOrderedJSON[K collectable.Collectable[K], V any](
+	data []byte,
+	hash hashing.HashFunc,
+) (OrderedMap[K, V], error) {
+	m := NewOrderedHashMap[K, V](hash)
+
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// UnmarshalYAML decodes a YAML-encoded map into a new hash map that uses hash.
+func UnmarshalYAML[K collectable.Collectable[K], V any](data []byte, hash hashing.HashFunc) (Map[K, V], error) {
+	m := NewHashMap[K, V](hash)
+
+	if err := yaml.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// UnmarshalOrderedYAML decodes a YAML-encoded map into a new ordered hash map that
+// uses hash, keeping the order of the encoded entries.
+func UnmarshalOrderedYAML[K collectable.Collectable[K], V any](
+	data []byte,
+	hash hashing.HashFunc,
+) (OrderedMap[K, V], error) {
+	m := NewOrderedHashMap[K, V](hash)
+
+	if err := yaml.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// MarshalJSON encodes the wrapped value on its own, so that maps keyed by Key
+// encode as plain JSON objects.
+func (m Key[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Key)
+}
+
+// UnmarshalJSON decodes the wrapped value.
+func (m *Key[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &m.Key)
+}
+
+// MarshalYAML encodes the wrapped value on its own.
+func (m Key[T]) MarshalYAML() (any, error) {
+	return m.Key, nil
+}
+
+// UnmarshalYAML decodes the wrapped value.
+func (m *Key[T]) UnmarshalYAML(node *yaml.Node) error {
+	return node.Decode(&m.Key)
+}
+
+// marshalPairsJSON encodes pairs, in order, as a JSON object or a list of entries.
+func marshalPairsJSON[K any, V any](pairs iter.Seq2[K, V]) ([]byte, error) {
+	var (
+		keys, values [][]byte
+		objectKeys   = true
+	)
+
+	for key, value := range pairs {
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		valueJSON, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		objectKeys = objectKeys && len(keyJSON) > 0 && keyJSON[0] == '"'
+		keys = append(keys, keyJSON)
+		values = append(values, valueJSON)
+	}
+
+	var buf bytes.Buffer
+
+	if objectKeys {
+		buf.WriteByte('{')
+	} else {
+		buf.WriteByte('[')
+	}
+
+	for i := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		if objectKeys {
+			buf.Write(keys[i])
+			buf.WriteByte(':')
+			buf.Write(values[i])
+		} else {
+			buf.WriteString(`{"key":`)
+			buf.Write(keys[i])
+			buf.WriteString(`,"value":`)
+			buf.Write(values[i])
+			buf.WriteByte('}')
+		}
+	}
+
+	if objectKeys {
+		buf.WriteByte('}')
+	} else {
+		buf.WriteByte(']')
+	}
+
+	return buf.Bytes(), nil
+}
+
+// unmarshalPairsJSON decodes a JSON object or list of entries, keeping the encoded order.
+func unmarshalPairsJSON[K any, V any](data []byte) ([]KeyValuePair[K, V], error) {
+	data = bytes.TrimSpace(data)
+
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil, nil
+	case len(data) > 0 && data[0] == '[':
+		var entries []encodedEntry[K, V]
+
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, err
+		}
+
+		pairs := make([]KeyValuePair[K, V], len(entries))
+		for i, entry := range entries {
+			pairs[i] = KeyValuePair[K, V]{Key: entry.Key, Value: entry.Value}
+		}
+
+		return pairs, nil
+	case len(data) > 0 && data[0] == '{':
+		return unmarshalObjectJSON[K, V](data)
+	default:
+		return nil, fmt.Errorf("%w, got %.20q", errUnexpectedEncoding, data)
+	}
+}
+
+// unmarshalObjectJSON decodes a JSON object token by token, since decoding into
+// a Go map would lose the order of its keys.
+func unmarshalObjectJSON[K any, V any](data []byte) ([]KeyValuePair[K, V], error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	// Skip the opening brace, which the caller has already checked.
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	var pairs []KeyValuePair[K, V]
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		// Object keys are always strings; decode the key from its quoted form
+		// so that K's own JSON decoding applies.
+		keyJSON, err := json.Marshal(token)
+		if err != nil {
+			return nil, err
+		}
+
+		var pair KeyValuePair[K, V]
+
+		if err := json.Unmarshal(keyJSON, &pair.Key); err != nil {
+			return nil, err
+		}
+
+		if err := decoder.Decode(&pair.Value); err != nil {
+			return nil, err
+		}
+
+		pairs = append(pairs, pair)
+	}
+
+	return pairs, nil
+}
+
+// marshalPairsYAML encodes pairs, in order, as a YAML mapping or a sequence of entries.
+func marshalPairsYAML[K any, V any](pairs iter.Seq2[K, V]) (any, error) {
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	sequence := &yaml.Node{Kind: yaml.SequenceNode}
+
+	for key, value := range pairs {
+		keyNode := &yaml.Node{}
+		if err := keyNode.Encode(key); err != nil {
+			return nil, err
+		}
+
+		valueNode := &yaml.Node{}
+		if err := valueNode.Encode(value); err != nil {
+			return nil, err
+		}
+
+		mapping.Content = append(mapping.Content, keyNode, valueNode)
+		sequence.Content = append(sequence.Content, &yaml.Node{
+			Kind: yaml.MappingNode,
+			Content: []*yaml.Node{
+				{Kind: yaml.ScalarNode, Value: "key"}, keyNode,
+				{Kind: yaml.ScalarNode, Value: "value"}, valueNode,
+			},
+		})
+	}
+
+	for i := 0; i < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Kind != yaml.ScalarNode {
+			return sequence, nil
+		}
+	}
+
+	return mapping, nil
+}
+
+// unmarshalPairsYAML decodes a YAML mapping or sequence of entries, keeping the encoded order.
+func unmarshalPairsYAML[K any, V any](node *yaml.Node) ([]KeyValuePair[K, V], error) {
+	switch node.Kind { //nolint:exhaustive // Other kinds are rejected below
+	case yaml.MappingNode:
+		pairs := make([]KeyValuePair[K, V], 0, len(node.Content)/2)
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			var pair KeyValuePair[K, V]
+
+			if err := node.Content[i].Decode(&pair.Key); err != nil {
+				return nil, err
+			}
+
+			if err := node.Content[i+1].Decode(&pair.Value); err != nil {
+				return nil, err
+			}
+
+			pairs = append(pairs, pair)
+		}
+
+		return pairs, nil
+	case yaml.SequenceNode:
+		var entries []encodedEntry[K, V]
+
+		if err := node.Decode(&entries); err != nil {
+			return nil, err
+		}
+
+		pairs := make([]KeyValuePair[K, V], len(entries))
+		for i, entry := range entries {
+			pairs[i] = KeyValuePair[K, V]{Key: entry.Key, Value: entry.Value}
+		}
+
+		return pairs, nil
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return nil, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w, got YAML node at line %d", errUnexpectedEncoding, node.Line)
+}
+
+// gobEncodePairs encodes pairs, in order, as a gob-encoded slice of KeyValuePair.
+func gobEncodePairs[K any, V any](size int, pairs iter.Seq2[K, V]) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := gob.NewEncoder(&buf).Encode(collectPairs(size, pairs)); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// gobDecodePairs decodes the output of gobEncodePairs.
+func gobDecodePairs[K any, V any](data []byte) ([]KeyValuePair[K, V], error) {
+	var pairs []KeyValuePair[K, V]
+
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&pairs); err != nil {
+		return nil, err
+	}
+
+	return pairs, nil
+}
+
+// replacePairs replaces a map's contents with pairs, once decoding has succeeded.
+func replacePairs[K any, V any](
+	clearAll func(),
+	add func(key K, value V) error,
+	pairs []KeyValuePair[K, V],
+	err error,
+) error {
+	if err != nil {
+		return err
+	}
+
+	clearAll()
+
+	for _, pair := range pairs {
+		if err := add(pair.Key, pair.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// orderedPairs adapts an OrderedMap's indexed iterator to key-value pairs.
+func orderedPairs[K any, V any](seq iter.Seq2[int, KeyValuePair[K, V]]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, pair := range seq {
+			if !yield(pair.Key, pair.Value) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (h *hashMap[K, V]) MarshalJSON() ([]byte, error) {
+	return marshalPairsJSON(h.Seq())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the map's contents.
+func (h *hashMap[K, V]) UnmarshalJSON(data []byte) error {
+	pairs, err := unmarshalPairsJSON[K, V](data)
+
+	return replacePairs(h.Clear, h.Add, pairs, err)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (h *hashMap[K, V]) MarshalYAML() (any, error) {
+	return marshalPairsYAML(h.Seq())
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the map's contents.
+func (h *hashMap[K, V]) UnmarshalYAML(node *yaml.Node) error {
+	pairs, err := unmarshalPairsYAML[K, V](node)
+
+	return replacePairs(h.Clear, h.Add, pairs, err)
+}
+
+// GobEncode implements gob.GobEncoder.
+func (h *hashMap[K, V]) GobEncode() ([]byte, error) {
+	return gobEncodePairs(h.Size(), h.Seq())
+}
+
+// GobDecode implements gob.GobDecoder, replacing the map's contents.
+func (h *hashMap[K, V]) GobDecode(data []byte) error {
+	pairs, err := gobDecodePairs[K, V](data)
+
+	return replacePairs(h.Clear, h.Add, pairs, err)
+}
+
+// MarshalJSON implements json.Marshaler, keeping insertion order.
+func (o *orderedHashMap[K, V]) MarshalJSON() ([]byte, error) {
+	return marshalPairsJSON(orderedPairs(o.Seq()))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the map's contents in the encoded order.
+func (o *orderedHashMap[K, V]) UnmarshalJSON(data []byte) error {
+	pairs, err := unmarshalPairsJSON[K, V](data)
+
+	return replacePairs(o.Clear, o.Add, pairs, err)
+}
+
+// MarshalYAML implements yaml.Marshaler, keeping insertion order.
+func (o *orderedHashMap[K, V]) MarshalYAML() (any, error) {
+	return marshalPairsYAML(orderedPairs(o.Seq()))
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the map's contents in the encoded order.
+func (o *orderedHashMap[K, V]) UnmarshalYAML(node *yaml.Node) error {
+	pairs, err := unmarshalPairsYAML[K, V](node)
+
+	return replacePairs(o.Clear, o.Add, pairs, err)
+}
+
+// GobEncode implements gob.GobEncoder, keeping insertion order.
+func (o *orderedHashMap[K, V]) GobEncode() ([]byte, error) {
+	return gobEncodePairs(o.Size(), orderedPairs(o.Seq()))
+}
+
+// GobDecode implements gob.GobDecoder, replacing the map's contents in the encoded order.
+func (o *orderedHashMap[K, V]) GobDecode(data []byte) error {
+	pairs, err := gobDecodePairs[K, V](data)
+
+	return replacePairs(o.Clear, o.Add, pairs, err)
+}
+
+// MarshalJSON implements json.Marshaler, in key order.
+func (t *redBlackTreeMap[K, V]) MarshalJSON() ([]byte, error) {
+	return marshalPairsJSON(t.Seq())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the map's contents.
+func (t *redBlackTreeMap[K, V]) UnmarshalJSON(data []byte) error {
+	pairs, err := unmarshalPairsJSON[K, V](data)
+
+	return replacePairs(t.Clear, t.Add, pairs, err)
+}
+
+// MarshalYAML implements yaml.Marshaler, in key order.
+func (t *redBlackTreeMap[K, V]) MarshalYAML() (any, error) {
+	return marshalPairsYAML(t.Seq())
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the map's contents.
+func (t *redBlackTreeMap[K, V]) UnmarshalYAML(node *yaml.Node) error {
+	pairs, err := unmarshalPairsYAML[K, V](node)
+
+	return replacePairs(t.Clear, t.Add, pairs, err)
+}
+
+// GobEncode implements gob.GobEncoder, in key order.
+func (t *redBlackTreeMap[K, V]) GobEncode() ([]byte, error) {
+	return gobEncodePairs(t.Size(), t.Seq())
+}
+
+// GobDecode implements gob.GobDecoder, replacing the map's contents.
+func (t *redBlackTreeMap[K, V]) GobDecode(data []byte) error {
+	pairs, err := gobDecodePairs[K, V](data)
+
+	return replacePairs(t.Clear, t.Add, pairs, err)
+}
+
+// MarshalJSON implements json.Marshaler, encoding a snapshot of the map.
+func (t *threadSafeMap[K, V]) MarshalJSON() ([]byte, error) {
+	return marshalPairsJSON(t.Seq())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the map's contents under the write lock.
+func (t *threadSafeMap[K, V]) UnmarshalJSON(data []byte) error {
+	pairs, err := unmarshalPairsJSON[K, V](data)
+
+	return t.replace(pairs, err)
+}
+
+// MarshalYAML implements yaml.Marshaler, encoding a snapshot of the map.
+func (t *threadSafeMap[K, V]) MarshalYAML() (any, error) {
+	return marshalPairsYAML(t.Seq())
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the map's contents under the write lock.
+func (t *threadSafeMap[K, V]) UnmarshalYAML(node *yaml.Node) error {
+	pairs, err := unmarshalPairsYAML[K, V](node)
+
+	return t.replace(pairs, err)
+}
+
+// GobEncode implements gob.GobEncoder, encoding a snapshot of the map.
+func (t *threadSafeMap[K, V]) GobEncode() ([]byte, error) {
+	return gobEncodePairs(t.Size(), t.Seq())
+}
+
+// GobDecode implements gob.GobDecoder, replacing the map's contents under the write lock.
+func (t *threadSafeMap[K, V]) GobDecode(data []byte) error {
+	pairs, err := gobDecodePairs[K, V](data)
+
+	return t.replace(pairs, err)
+}
+
+func (t *threadSafeMap[K, V]) replace(pairs []KeyValuePair[K, V], err error) error {
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return replacePairs(t.internal.Clear, t.internal.Add, pairs, nil)
+}
+
+// MarshalJSON implements json.Marshaler, encoding a snapshot of the map in insertion order.
+func (t *threadSafeOrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	return marshalPairsJSON(orderedPairs(t.Seq()))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the map's contents under the write lock.
+func (t *threadSafeOrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	pairs, err := unmarshalPairsJSON[K, V](data)
+
+	return t.replace(pairs, err)
+}
+
+// MarshalYAML implements yaml.Marshaler, encoding a snapshot of the map in insertion order.
+func (t *threadSafeOrderedMap[K, V]) MarshalYAML() (any, error) {
+	return marshalPairsYAML(orderedPairs(t.Seq()))
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the map's contents under the write lock.
+func (t *threadSafeOrderedMap[K, V]) UnmarshalYAML(node *yaml.Node) error {
+	pairs, err := unmarshalPairsYAML[K, V](node)
+
+	return t.replace(pairs, err)
+}
+
+// GobEncode implements gob.GobEncoder, encoding a snapshot of the map in insertion order.
+func (t *threadSafeOrderedMap[K, V]) GobEncode() ([]byte, error) {
+	return gobEncodePairs(t.Size(), orderedPairs(t.Seq()))
+}
+
+// GobDecode implements gob.GobDecoder, replacing the map's contents under the write lock.
+func (t *threadSafeOrderedMap[K, V]) GobDecode(data []byte) error {
+	pairs, err := gobDecodePairs[K, V](data)
+
+	return t.replace(pairs, err)
+}
+
+func (t *threadSafeOrderedMap[K, V]) replace(pairs []KeyValuePair[K, V], err error) error {
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return replacePairs(t.internal.Clear, t.internal.Add, pairs, nil)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d *defaultMap[K, V]) MarshalJSON() ([]byte, error) {
+	return marshalPairsJSON(d.Seq())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the map's contents.
+func (d *defaultMap[K, V]) UnmarshalJSON(data []byte) error {
+	pairs, err := unmarshalPairsJSON[K, V](data)
+
+	return replacePairs(d.Clear, d.Add, pairs, err)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (d *defaultMap[K, V]) MarshalYAML() (any, error) {
+	return marshalPairsYAML(d.Seq())
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the map's contents.
+func (d *defaultMap[K, V]) UnmarshalYAML(node *yaml.Node) error {
+	pairs, err := unmarshalPairsYAML[K, V](node)
+
+	return replacePairs(d.Clear, d.Add, pairs, err)
+}
+
+// GobEncode implements gob.GobEncoder.
+func (d *defaultMap[K, V]) GobEncode() ([]byte, error) {
+	return gobEncodePairs(d.Size(), d.Seq())
+}
+
+// GobDecode implements gob.GobDecoder, replacing the map's contents.
+func (d *defaultMap[K, V]) GobDecode(data []byte) error {
+	pairs, err := gobDecodePairs[K, V](data)
+
+	return replacePairs(d.Clear, d.Add, pairs, err)
+}
+
+// MarshalJSON implements json.Marshaler, keeping insertion order.
+func (d *defaultOrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	return marshalPairsJSON(orderedPairs(d.Seq()))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the map's contents in the encoded order.
+func (d *defaultOrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	pairs, err := unmarshalPairsJSON[K, V](data)
+
+	return replacePairs(d.Clear, d.Add, pairs, err)
+}
+
+// MarshalYAML implements yaml.Marshaler, keeping insertion order.
+func (d *defaultOrderedMap[K, V]) MarshalYAML() (any, error) {
+	return marshalPairsYAML(orderedPairs(d.Seq()))
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the map's contents in the encoded order.
+func (d *defaultOrderedMap[K, V]) UnmarshalYAML(node *yaml.Node) error {
+	pairs, err := unmarshalPairsYAML[K, V](node)
+
+	return replacePairs(d.Clear, d.Add, pairs, err)
+}
+
+// GobEncode implements gob.GobEncoder, keeping insertion order.
+func (d *defaultOrderedMap[K, V]) GobEncode() ([]byte, error) {
+	return gobEncodePairs(d.Size(), orderedPairs(d.Seq()))
+}
+
+// GobDecode implements gob.GobDecoder, replacing the map's contents in the encoded order.
+func (d *defaultOrderedMap[K, V]) GobDecode(data []byte) error {
+	pairs, err := gobDecodePairs[K, V](data)
+
+	return replacePairs(d.Clear, d.Add, pairs, err)
+}
+
+// MarshalJSON implements json.Marshaler. The encoding is a snapshot that is consistent per shard.
+func (m *concurrentHashMap[K, V]) MarshalJSON() ([]byte, error) {
+	return marshalPairsJSON(m.Seq())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the map's contents.
+func (m *concurrentHashMap[K, V]) UnmarshalJSON(data []byte) error {
+	pairs, err := unmarshalPairsJSON[K, V](data)
+
+	return replacePairs(m.Clear, m.Add, pairs, err)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (m *concurrentHashMap[K, V]) MarshalYAML() (any, error) {
+	return marshalPairsYAML(m.Seq())
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the map's contents.
+func (m *concurrentHashMap[K, V]) UnmarshalYAML(node *yaml.Node) error {
+	pairs, err := unmarshalPairsYAML[K, V](node)
+
+	return replacePairs(m.Clear, m.Add, pairs, err)
+}
+
+// GobEncode implements gob.GobEncoder.
+func (m *concurrentHashMap[K, V]) GobEncode() ([]byte, error) {
+	return gobEncodePairs(m.Size(), m.Seq())
+}
+
+// GobDecode implements gob.GobDecoder, replacing the map's contents.
+func (m *concurrentHashMap[K, V]) GobDecode(data []byte) error {
+	pairs, err := gobDecodePairs[K, V](data)
+
+	return replacePairs(m.Clear, m.Add, pairs, err)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m *persistentHashMap[K, V]) MarshalJSON() ([]byte, error) {
+	return marshalPairsJSON(m.Seq())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the map's contents.
+func (m *persistentHashMap[K, V]) UnmarshalJSON(data []byte) error {
+	pairs, err := unmarshalPairsJSON[K, V](data)
+
+	return replacePairs(m.Clear, m.Add, pairs, err)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (m *persistentHashMap[K, V]) MarshalYAML() (any, error) {
+	return marshalPairsYAML(m.Seq())
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the map's contents.
+func (m *persistentHashMap[K, V]) UnmarshalYAML(node *yaml.Node) error {
+	pairs, err := unmarshalPairsYAML[K, V](node)
+
+	return replacePairs(m.Clear, m.Add, pairs, err)
+}
+
+// GobEncode implements gob.GobEncoder.
+func (m *persistentHashMap[K, V]) GobEncode() ([]byte, error) {
+	return gobEncodePairs(m.Size(), m.Seq())
+}
+
+// GobDecode implements gob.GobDecoder, replacing the map's contents.
+func (m *persistentHashMap[K, V]) GobDecode(data []byte) error {
+	pairs, err := gobDecodePairs[K, V](data)
+
+	return replacePairs(m.Clear, m.Add, pairs, err)
+}
+
+// MarshalJSON implements json.Marshaler, in key order.
+func (m *persistentSortedMap[K, V]) MarshalJSON() ([]byte, error) {
+	return marshalPairsJSON(m.Seq())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the map's contents.
+func (m *persistentSortedMap[K, V]) UnmarshalJSON(data []byte) error {
+	pairs, err := unmarshalPairsJSON[K, V](data)
+
+	return replacePairs(m.Clear, m.Add, pairs, err)
+}
+
+// MarshalYAML implements yaml.Marshaler, in key order.
+func (m *persistentSortedMap[K, V]) MarshalYAML() (any, error) {
+	return marshalPairsYAML(m.Seq())
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the map's contents.
+func (m *persistentSortedMap[K, V]) UnmarshalYAML(node *yaml.Node) error {
+	pairs, err := unmarshalPairsYAML[K, V](node)
+
+	return replacePairs(m.Clear, m.Add, pairs, err)
+}
+
+// GobEncode implements gob.GobEncoder, in key order.
+func (m *persistentSortedMap[K, V]) GobEncode() ([]byte, error) {
+	return gobEncodePairs(m.Size(), m.Seq())
+}
+
+// GobDecode implements gob.GobDecoder, replacing the map's contents.
+func (m *persistentSortedMap[K, V]) GobDecode(data []byte) error {
+	pairs, err := gobDecodePairs[K, V](data)
+
+	return replacePairs(m.Clear, m.Add, pairs, err)
+}
+
+// MarshalJSON implements json.Marshaler, keeping insertion order.
+func (m *persistentOrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	return marshalPairsJSON(orderedPairs(m.Seq()))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the map's contents in the encoded order.
+func (m *persistentOrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	pairs, err := unmarshalPairsJSON[K, V](data)
+
+	return replacePairs(m.Clear, m.Add, pairs, err)
+}
+
+// MarshalYAML implements yaml.Marshaler, keeping insertion order.
+func (m *persistentOrderedMap[K, V]) MarshalYAML() (any, error) {
+	return marshalPairsYAML(orderedPairs(m.Seq()))
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the map's contents in the encoded order.
+func (m *persistentOrderedMap[K, V]) UnmarshalYAML(node *yaml.Node) error {
+	pairs, err := unmarshalPairsYAML[K, V](node)
+
+	return replacePairs(m.Clear, m.Add, pairs, err)
+}
+
+// GobEncode implements gob.GobEncoder, keeping insertion order.
+func (m *persistentOrderedMap[K, V]) GobEncode() ([]byte, error) {
+	return gobEncodePairs(m.Size(), orderedPairs(m.Seq()))
+}
+
+// GobDecode implements gob.GobDecoder, replacing the map's contents in the encoded order.
+func (m *persistentOrderedMap[K, V]) GobDecode(data []byte) error {
+	pairs, err := gobDecodePairs[K, V](data)
+
+	return replacePairs(m.Clear, m.Add, pairs, err)
+}
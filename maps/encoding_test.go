@@ -0,0 +1,179 @@
+package maps_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"testing"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/maps"
+	"github.com/amp-labs/amp-common/sortable"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// point is a key type that encodes as a JSON object or YAML mapping, so maps
+// keyed by it use the list form.
+type point struct {
+	X int `json:"row" yaml:"row"`
+	Y int `json:"col" yaml:"col"`
+}
+
+func (p point) UpdateHash(h hash.Hash) error {
+	_, err := fmt.Fprintf(h, "%d,%d", p.X, p.Y)
+
+	return err
+}
+
+func (p point) Equals(other point) bool {
+	return p == other
+}
+
+func TestMapJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("string keys encode as an object", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewHashMap[tag, int](hashing.Sha256)
+		require.NoError(t, m.Add("a", 1))
+		require.NoError(t, m.Add("b", 2))
+
+		data, err := json.Marshal(m)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"a": 1, "b": 2}`, string(data))
+
+		decoded, err := maps.UnmarshalJSON[tag, int](data, hashing.Sha256)
+		require.NoError(t, err)
+		assert.Equal(t, 2, decoded.Size())
+
+		value, found, err := decoded.Get("b")
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, 2, value)
+	})
+
+	t.Run("ordered maps keep their order", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewOrderedHashMap[tag, int](hashing.Sha256)
+		require.NoError(t, m.Add("z", 1))
+		require.NoError(t, m.Add("a", 2))
+		require.NoError(t, m.Add("m", 3))
+
+		data, err := json.Marshal(m)
+		require.NoError(t, err)
+		assert.Equal(t, `{"z":1,"a":2,"m":3}`, string(data))
+
+		decoded, err := maps.UnmarshalOrderedJSON[tag, int](data, hashing.Sha256)
+		require.NoError(t, err)
+		assert.Equal(t, []tag{"z", "a", "m"}, tagKeys(decoded))
+	})
+
+	t.Run("other keys encode as a list of entries", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewRedBlackTreeMap[sortable.Int, string]()
+		require.NoError(t, m.Add(2, "two"))
+		require.NoError(t, m.Add(1, "one"))
+
+		data, err := json.Marshal(m)
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"key": 1, "value": "one"}, {"key": 2, "value": "two"}]`, string(data))
+
+		decoded := maps.NewRedBlackTreeMap[sortable.Int, string]()
+		require.NoError(t, decoded.Add(3, "replaced"))
+		require.NoError(t, json.Unmarshal(data, decoded))
+		assert.Equal(t, 2, decoded.Size())
+
+		contains, err := decoded.Contains(3)
+		require.NoError(t, err)
+		assert.False(t, contains)
+	})
+
+	t.Run("thread-safe maps encode their contents", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewThreadSafeMap(maps.NewHashMap[maps.Key[int], string](hashing.Sha256))
+		require.NoError(t, m.Add(maps.Key[int]{Key: 7}, "seven"))
+
+		data, err := json.Marshal(m)
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"key": 7, "value": "seven"}]`, string(data))
+
+		decoded := maps.NewThreadSafeMap(maps.NewHashMap[maps.Key[int], string](hashing.Sha256))
+		require.NoError(t, json.Unmarshal(data, decoded))
+
+		value, found, err := decoded.Get(maps.Key[int]{Key: 7})
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "seven", value)
+	})
+
+	t.Run("rejects other values", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := maps.UnmarshalJSON[tag, int]([]byte(`"nope"`), hashing.Sha256)
+		require.Error(t, err)
+	})
+}
+
+func TestMapYAML(t *testing.T) {
+	t.Parallel()
+
+	m := maps.NewOrderedHashMap[tag, int](hashing.Sha256)
+	require.NoError(t, m.Add("z", 1))
+	require.NoError(t, m.Add("a", 2))
+
+	data, err := yaml.Marshal(m)
+	require.NoError(t, err)
+	assert.Equal(t, "z: 1\na: 2\n", string(data))
+
+	decoded, err := maps.UnmarshalOrderedYAML[tag, int](data, hashing.Sha256)
+	require.NoError(t, err)
+	assert.Equal(t, []tag{"z", "a"}, tagKeys(decoded))
+
+	// Non-scalar keys fall back to a sequence of entries
+	entries := maps.NewOrderedHashMap[point, string](hashing.Sha256)
+	require.NoError(t, entries.Add(point{X: 1, Y: 2}, "pair"))
+
+	data, err = yaml.Marshal(entries)
+	require.NoError(t, err)
+	assert.Equal(t, "- key:\n    row: 1\n    col: 2\n  value: pair\n", string(data))
+
+	decodedEntries := maps.NewOrderedHashMap[point, string](hashing.Sha256)
+	require.NoError(t, yaml.Unmarshal(data, decodedEntries))
+
+	value, found, err := decodedEntries.Get(point{X: 1, Y: 2})
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "pair", value)
+}
+
+func TestMapGob(t *testing.T) {
+	t.Parallel()
+
+	m := maps.NewPersistentOrderedHashMap[tag, int](hashing.Sha256)
+	require.NoError(t, m.Add("b", 2))
+	require.NoError(t, m.Add("a", 1))
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(m))
+
+	decoded := maps.NewThreadSafeOrderedMap(maps.NewOrderedHashMap[tag, int](hashing.Sha256))
+	require.NoError(t, gob.NewDecoder(&buf).Decode(decoded))
+	assert.Equal(t, []tag{"b", "a"}, tagKeys(decoded))
+}
+
+func tagKeys[V any](m maps.OrderedMap[tag, V]) []tag {
+	var keys []tag
+	for _, pair := range m.Seq() {
+		keys = append(keys, pair.Key)
+	}
+
+	return keys
+}
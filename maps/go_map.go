@@ -46,8 +46,7 @@ func (m Key[T]) Equals(other Key[T]) bool {
 // The hash parameter specifies the hash function to use for the map (e.g., hashing.Sha256).
 // Returns nil if the input map is nil.
 //
-// Panics if adding a key-value pair fails due to a hash collision. This should be rare
-// with a good hash function like SHA-256.
+// Panics if adding a key-value pair fails, which only happens if hashing a key fails.
 //
 // Example:
 //
@@ -4,7 +4,6 @@ import (
 	"iter"
 
 	"github.com/amp-labs/amp-common/collectable"
-	errors2 "github.com/amp-labs/amp-common/errors"
 	"github.com/amp-labs/amp-common/hashing"
 	"github.com/amp-labs/amp-common/optional"
 	"github.com/amp-labs/amp-common/set"
@@ -12,8 +11,10 @@ import (
 )
 
 // NewHashMap creates a new hash-based Map implementation using the provided hash function.
-// The hash function must produce consistent hash values for equal keys and should
-// minimize collisions to avoid ErrHashCollision errors during operations.
+// The hash function must produce consistent hash values for equal keys. Distinct keys
+// that share a hash value are kept apart using their Equals method, so fast
+// non-cryptographic hashes such as hashing.XxHash32 are safe to use; collisions only
+// cost an extra comparison per colliding key.
 //
 // The returned map is not thread-safe. Concurrent access must be synchronized by the caller.
 //
@@ -27,7 +28,7 @@ import (
 func NewHashMap[K collectable.Collectable[K], V any](hash hashing.HashFunc) Map[K, V] {
 	return &hashMap[K, V]{
 		hash: hash,
-		data: make(map[string]KeyValuePair[K, V]),
+		data: make(map[string]bucket[K, V]),
 	}
 }
 
@@ -39,8 +40,8 @@ func NewHashMap[K collectable.Collectable[K], V any](hash hashing.HashFunc) Map[
 // for memory reallocation during initial insertions. This can improve performance when building
 // large maps. The map will still grow dynamically if more entries are added beyond the initial size.
 //
-// The hash function must produce consistent hash values for equal keys and should minimize
-// collisions to avoid ErrHashCollision errors during operations.
+// The hash function must produce consistent hash values for equal keys. Keys that share
+// a hash value are disambiguated using their Equals method.
 //
 // The returned map is not thread-safe. Concurrent access must be synchronized by the caller.
 //
@@ -59,45 +60,70 @@ func NewHashMap[K collectable.Collectable[K], V any](hash hashing.HashFunc) Map[
 func NewHashMapWithSize[K collectable.Collectable[K], V any](hash hashing.HashFunc, size int) Map[K, V] {
 	return &hashMap[K, V]{
 		hash: hash,
-		data: make(map[string]KeyValuePair[K, V], size),
+		data: make(map[string]bucket[K, V], size),
 	}
 }
 
+// bucket holds the entries whose keys share a hash value. Almost all buckets
+// hold a single entry; keys in the same bucket are told apart with Equals.
+type bucket[K collectable.Collectable[K], V any] []KeyValuePair[K, V]
+
+// find returns the index of the entry for key, or -1 if the bucket doesn't contain key.
+func (b bucket[K, V]) find(key K) int {
+	for i, entry := range b {
+		if entry.Key.Equals(key) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// without returns the bucket with the entry at index i removed, or nil if it was the only entry.
+func (b bucket[K, V]) without(i int) bucket[K, V] {
+	if len(b) == 1 {
+		return nil
+	}
+
+	rest := make(bucket[K, V], 0, len(b)-1)
+	rest = append(rest, b[:i]...)
+
+	return append(rest, b[i+1:]...)
+}
+
 // hashMap is the concrete implementation of the Map interface using a hash table.
-// It stores entries in a Go map indexed by string hash values. Collision detection
-// is performed by comparing the full key using the Comparable interface when hash
-// values match. This ensures correctness even with imperfect hash functions.
+// It stores entries in a Go map of buckets indexed by string hash values. Keys whose
+// hash values match are told apart by comparing the full key using the Comparable
+// interface. This ensures correctness even with imperfect hash functions.
 //
 // The implementation is not thread-safe and uses O(1) average-case lookup time.
 type hashMap[K collectable.Collectable[K], V any] struct {
-	hash hashing.HashFunc              // Hash function for converting keys to string hashes
-	data map[string]KeyValuePair[K, V] // Internal storage indexed by hash values
+	hash hashing.HashFunc        // Hash function for converting keys to string hashes
+	data map[string]bucket[K, V] // Internal storage indexed by hash values
+	size int                     // Number of entries across all buckets
 }
 
 // Get retrieves the value for the given key from the hash map.
 // If the key exists, returns the value with found=true. If the key doesn't exist, returns
-// a zero value with found=false.
-// Returns ErrHashCollision if a different key with the same hash exists in the map.
+// a zero value with found=false. An error is only returned if hashing the key fails.
 func (h *hashMap[K, V]) Get(key K) (value V, found bool, errOut error) {
 	hashVal, err := h.hash(key)
 	if err != nil {
 		return zero.Value[V](), false, err
 	}
 
-	kv, ok := h.data[hashVal]
-	if !ok {
-		return zero.Value[V](), false, nil
-	}
+	entries := h.data[hashVal]
 
-	if !kv.Key.Equals(key) {
-		return zero.Value[V](), false, errors2.ErrHashCollision
+	i := entries.find(key)
+	if i < 0 {
+		return zero.Value[V](), false, nil
 	}
 
-	return kv.Value, true, nil
+	return entries[i].Value, true, nil
 }
 
 // GetOrElse retrieves the value for the given key, or returns defaultValue if the key doesn't exist.
-// Returns ErrHashCollision if a different key with the same hash exists in the map.
+// An error is only returned if hashing the key fails.
 func (h *hashMap[K, V]) GetOrElse(key K, defaultValue V) (value V, err error) {
 	value, found, err := h.Get(key)
 	if err != nil {
@@ -113,42 +139,51 @@ func (h *hashMap[K, V]) GetOrElse(key K, defaultValue V) (value V, err error) {
 
 // Add inserts or updates a key-value pair in the hash map.
 // If the key already exists (determined by both hash and equality), its value is replaced.
-// Returns ErrHashCollision if a different key produces the same hash value.
+// A different key that produces the same hash value is stored alongside the existing one.
+// An error is only returned if hashing the key fails.
 func (h *hashMap[K, V]) Add(key K, value V) error {
 	hashVal, err := h.hash(key)
 	if err != nil {
 		return err
 	}
 
-	prev, ok := h.data[hashVal]
+	entries := h.data[hashVal]
+
+	if i := entries.find(key); i >= 0 {
+		entries[i] = KeyValuePair[K, V]{Key: key, Value: value}
 
-	if ok && !key.Equals(prev.Key) {
-		// Hash collision detected
-		return errors2.ErrHashCollision
+		return nil
 	}
 
-	h.data[hashVal] = KeyValuePair[K, V]{Key: key, Value: value}
+	h.data[hashVal] = append(entries, KeyValuePair[K, V]{Key: key, Value: value})
+	h.size++
 
 	return nil
 }
 
 // Remove deletes a key-value pair from the hash map.
-// If the key doesn't exist, this is a no-op and returns nil.
-// Returns ErrHashCollision if a different key with the same hash exists in the map.
+// If the key doesn't exist, this is a no-op and returns nil. Other keys with the
+// same hash value are left untouched. An error is only returned if hashing the key fails.
 func (h *hashMap[K, V]) Remove(key K) error {
 	hashVal, err := h.hash(key)
 	if err != nil {
 		return err
 	}
 
-	prev, ok := h.data[hashVal]
+	entries := h.data[hashVal]
 
-	if ok && !key.Equals(prev.Key) {
-		// Hash collision detected - the stored key is different
-		return errors2.ErrHashCollision
+	i := entries.find(key)
+	if i < 0 {
+		return nil
 	}
 
-	delete(h.data, hashVal)
+	if rest := entries.without(i); rest != nil {
+		h.data[hashVal] = rest
+	} else {
+		delete(h.data, hashVal)
+	}
+
+	h.size--
 
 	return nil
 }
@@ -157,36 +192,26 @@ func (h *hashMap[K, V]) Remove(key K) error {
 // The map remains usable after calling Clear. This operation is O(1) as it simply
 // reallocates the internal storage, allowing the old data to be garbage collected.
 func (h *hashMap[K, V]) Clear() {
-	h.data = make(map[string]KeyValuePair[K, V])
+	h.data = make(map[string]bucket[K, V])
+	h.size = 0
 }
 
 // Contains checks whether a key exists in the hash map.
 // Returns true if the key exists, false otherwise.
-// Returns ErrHashCollision if a different key with the same hash exists in the map.
+// An error is only returned if hashing the key fails.
 func (h *hashMap[K, V]) Contains(key K) (bool, error) {
 	hashVal, err := h.hash(key)
 	if err != nil {
 		return false, err
 	}
 
-	prev, ok := h.data[hashVal]
-
-	if !ok {
-		return false, nil
-	}
-
-	if !key.Equals(prev.Key) {
-		// Hash collision detected - the stored key is different
-		return false, errors2.ErrHashCollision
-	}
-
-	return true, nil
+	return h.data[hashVal].find(key) >= 0, nil
 }
 
 // Size returns the number of key-value pairs currently stored in the hash map.
-// This operation is O(1) as it simply returns the length of the internal storage.
+// This operation is O(1) as the map keeps count of its entries.
 func (h *hashMap[K, V]) Size() int {
-	return len(h.data)
+	return h.size
 }
 
 // Seq returns an iterator for ranging over all key-value pairs in the hash map.
@@ -200,9 +225,11 @@ func (h *hashMap[K, V]) Size() int {
 // The iterator stops early if the yield function returns false.
 func (h *hashMap[K, V]) Seq() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		for _, entry := range h.data {
-			if !yield(entry.Key, entry.Value) {
-				return
+		for _, entries := range h.data {
+			for _, entry := range entries {
+				if !yield(entry.Key, entry.Value) {
+					return
+				}
 			}
 		}
 	}
@@ -211,7 +238,6 @@ func (h *hashMap[K, V]) Seq() iter.Seq2[K, V] {
 // Union creates a new hash map containing all key-value pairs from both this map and other.
 // If a key exists in both maps, the value from other takes precedence in the result.
 // Returns a new Map instance with entries from both maps merged together.
-// Returns ErrHashCollision if any hash collision occurs during the operation.
 //
 // The time complexity is O(n + m) where n is the size of this map and m is the size of other.
 func (h *hashMap[K, V]) Union(other Map[K, V]) (Map[K, V], error) {
@@ -239,7 +265,6 @@ func (h *hashMap[K, V]) Union(other Map[K, V]) (Map[K, V], error) {
 // Intersection creates a new hash map containing only key-value pairs whose keys exist in both maps.
 // The values are taken from this map, not from other. Keys are compared using both hash and equality.
 // Returns a new Map instance with only the common entries.
-// Returns ErrHashCollision if any hash collision occurs during the operation.
 //
 // The time complexity is O(n) where n is the size of the smaller map.
 func (h *hashMap[K, V]) Intersection(other Map[K, V]) (Map[K, V], error) {
@@ -263,6 +288,60 @@ func (h *hashMap[K, V]) Intersection(other Map[K, V]) (Map[K, V], error) {
 	return result, nil
 }
 
+// Difference creates a new hash map containing the key-value pairs of this map whose keys are not in other.
+//
+// The time complexity is O(n) where n is the size of this map.
+func (h *hashMap[K, V]) Difference(other Map[K, V]) (Map[K, V], error) {
+	result := NewHashMap[K, V](h.hash)
+
+	err := addMissingKeys(h.Seq(), other, result.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SymmetricDifference creates a new hash map containing the key-value pairs whose keys are in exactly
+// one of the two maps, with values taken from the map that holds them.
+//
+// The time complexity is O(n + m) where n is the size of this map and m is the size of other.
+func (h *hashMap[K, V]) SymmetricDifference(other Map[K, V]) (Map[K, V], error) {
+	result := NewHashMap[K, V](h.hash)
+
+	err := addMissingKeys(h.Seq(), other, result.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	err = addMissingKeys(other.Seq(), h, result.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// IsSubsetOf reports whether every key of this map is also in other.
+func (h *hashMap[K, V]) IsSubsetOf(other Map[K, V]) (bool, error) {
+	return isSubsetKeys(h.Size(), h.Seq(), other)
+}
+
+// IsSupersetOf reports whether every key of other is also in this map.
+func (h *hashMap[K, V]) IsSupersetOf(other Map[K, V]) (bool, error) {
+	return isSubsetKeys(other.Size(), other.Seq(), h)
+}
+
+// IsDisjoint reports whether the two maps have no keys in common.
+func (h *hashMap[K, V]) IsDisjoint(other Map[K, V]) (bool, error) {
+	return isDisjointKeys(h.Seq(), other)
+}
+
+// Equals reports whether both maps have the same keys with equal values.
+func (h *hashMap[K, V]) Equals(other Map[K, V], valueEquals func(a, b V) bool) (bool, error) {
+	return isEqualMap(h.Size(), h.Seq(), other, valueEquals)
+}
+
 // Clone creates a shallow copy of the hash map, duplicating its structure and entries.
 // The keys and values themselves are not deep-copied; they are referenced as-is in the new map.
 // Returns a new Map instance with the same entries as this map.
@@ -273,8 +352,8 @@ func (h *hashMap[K, V]) Intersection(other Map[K, V]) (Map[K, V], error) {
 // This operation is O(n) where n is the number of entries in the map, as it iterates through
 // all entries to populate the new map.
 //
-// Note: Since the map was already validated during construction, Add operations during cloning
-// should not fail with hash collisions. Any errors are silently ignored.
+// Note: Since the keys were already hashed successfully when they were added, Add operations
+// during cloning should not fail. Any errors are silently ignored.
 //
 // Example:
 //
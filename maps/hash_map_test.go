@@ -146,7 +146,7 @@ func TestHashMap_Add(t *testing.T) {
 		assert.Equal(t, 100, m.Size())
 	})
 
-	t.Run("returns error on hash collision", func(t *testing.T) {
+	t.Run("keeps colliding keys apart", func(t *testing.T) {
 		t.Parallel()
 
 		m := maps.NewHashMap[collidingKey, string](hashing.Sha256)
@@ -157,7 +157,16 @@ func TestHashMap_Add(t *testing.T) {
 		require.NoError(t, err)
 
 		err = m.Add(key2, "value2")
-		assert.Error(t, err)
+		require.NoError(t, err)
+		assert.Equal(t, 2, m.Size())
+
+		value, err := m.GetOrElse(key1, "")
+		require.NoError(t, err)
+		assert.Equal(t, "value1", value)
+
+		value, err = m.GetOrElse(key2, "")
+		require.NoError(t, err)
+		assert.Equal(t, "value2", value)
 	})
 }
 
@@ -211,7 +220,7 @@ func TestHashMap_Remove(t *testing.T) {
 		assert.True(t, contains)
 	})
 
-	t.Run("returns error on hash collision", func(t *testing.T) {
+	t.Run("leaves colliding keys untouched", func(t *testing.T) {
 		t.Parallel()
 
 		m := maps.NewHashMap[collidingKey, string](hashing.Sha256)
@@ -222,7 +231,22 @@ func TestHashMap_Remove(t *testing.T) {
 		require.NoError(t, err)
 
 		err = m.Remove(key2)
-		assert.Error(t, err)
+		require.NoError(t, err)
+		assert.Equal(t, 1, m.Size())
+
+		err = m.Add(key2, "value2")
+		require.NoError(t, err)
+
+		err = m.Remove(key1)
+		require.NoError(t, err)
+
+		contains, err := m.Contains(key1)
+		require.NoError(t, err)
+		assert.False(t, contains)
+
+		contains, err = m.Contains(key2)
+		require.NoError(t, err)
+		assert.True(t, contains)
 	})
 }
 
@@ -313,7 +337,7 @@ func TestHashMap_Contains(t *testing.T) {
 		assert.False(t, contains)
 	})
 
-	t.Run("returns error on hash collision", func(t *testing.T) {
+	t.Run("returns false for colliding key", func(t *testing.T) {
 		t.Parallel()
 
 		m := maps.NewHashMap[collidingKey, string](hashing.Sha256)
@@ -324,7 +348,7 @@ func TestHashMap_Contains(t *testing.T) {
 		require.NoError(t, err)
 
 		contains, err := m.Contains(key2)
-		require.Error(t, err)
+		require.NoError(t, err)
 		assert.False(t, contains)
 	})
 }
@@ -726,7 +750,7 @@ func TestHashMap_Get(t *testing.T) {
 		}
 	})
 
-	t.Run("returns error on hash collision", func(t *testing.T) {
+	t.Run("returns not found for colliding key", func(t *testing.T) {
 		t.Parallel()
 
 		m := maps.NewHashMap[collidingKey, string](hashing.Sha256)
@@ -736,10 +760,10 @@ func TestHashMap_Get(t *testing.T) {
 		err := m.Add(key1, "value1")
 		require.NoError(t, err)
 
-		// Try to get with a different key but same hash
+		// Get with a different key but same hash
 		key2 := collidingKey{id: 2, hash: "samehash"}
 		value, found, err := m.Get(key2)
-		require.Error(t, err)
+		require.NoError(t, err)
 		assert.False(t, found)
 		assert.Empty(t, value)
 	})
@@ -854,7 +878,7 @@ func TestHashMap_GetOrElse(t *testing.T) {
 		assert.Equal(t, 42, value)
 	})
 
-	t.Run("returns error on hash collision", func(t *testing.T) {
+	t.Run("returns default for colliding key", func(t *testing.T) {
 		t.Parallel()
 
 		m := maps.NewHashMap[collidingKey, string](hashing.Sha256)
@@ -864,8 +888,8 @@ func TestHashMap_GetOrElse(t *testing.T) {
 
 		key2 := collidingKey{id: 2, hash: "same"}
 		value, err := m.GetOrElse(key2, "default")
-		require.Error(t, err)
-		assert.Empty(t, value)
+		require.NoError(t, err)
+		assert.Equal(t, "default", value)
 	})
 }
 
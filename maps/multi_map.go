@@ -0,0 +1,249 @@
+package maps //nolint:revive // Established package name; renaming would break all consumers.
+
+import (
+	"iter"
+
+	"github.com/amp-labs/amp-common/collectable"
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/optional"
+	"github.com/amp-labs/amp-common/set"
+)
+
+// NewMultiMap creates an empty MultiMap. Keys are stored in a hash map and each
+// key's values in a set.Set, both using hash. Iteration order is non-deterministic;
+// use NewOrderedMultiMap to keep insertion order.
+//
+// The returned multimap is not thread-safe; wrap it with NewThreadSafeMultiMap
+// for concurrent use.
+//
+// Example:
+//
+//	headers := maps.NewMultiMap[hashing.HashableString, hashing.HashableString](hashing.Sha256)
+//	_ = headers.AddAll("Accept", "text/html", "application/json")
+//	accept, _ := headers.Get("Accept") // {"text/html", "application/json"}
+func NewMultiMap[K collectable.Collectable[K], V collectable.Collectable[V]](hash hashing.HashFunc) MultiMap[K, V] {
+	return &multiMap[K, V]{
+		hash: hash,
+		data: NewHashMap[K, set.Set[V]](hash),
+	}
+}
+
+// multiMap stores a non-empty value set per key, and keeps a running count of pairs.
+type multiMap[K collectable.Collectable[K], V collectable.Collectable[V]] struct {
+	hash hashing.HashFunc
+	data Map[K, set.Set[V]]
+	size int
+}
+
+func (m *multiMap[K, V]) Get(key K) (set.Set[V], error) {
+	values, found, err := m.data.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return set.NewSet[V](m.hash), nil
+	}
+
+	return values.Clone(), nil
+}
+
+func (m *multiMap[K, V]) Add(key K, value V) error {
+	values, found, err := m.data.Get(key)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		values = set.NewSet[V](m.hash)
+	}
+
+	// Check the value before touching the map, so a hashing error leaves it unchanged.
+	contains, err := values.Contains(value)
+	if err != nil || contains {
+		return err
+	}
+
+	if err := values.Add(value); err != nil {
+		return err
+	}
+
+	if !found {
+		if err := m.data.Add(key, values); err != nil {
+			return err
+		}
+	}
+
+	m.size++
+
+	return nil
+}
+
+func (m *multiMap[K, V]) AddAll(key K, values ...V) error {
+	for _, value := range values {
+		if err := m.Add(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *multiMap[K, V]) Remove(key K, value V) error {
+	values, found, err := m.data.Get(key)
+	if err != nil || !found {
+		return err
+	}
+
+	contains, err := values.Contains(value)
+	if err != nil || !contains {
+		return err
+	}
+
+	if err := values.Remove(value); err != nil {
+		return err
+	}
+
+	m.size--
+
+	if values.Size() == 0 {
+		return m.data.Remove(key)
+	}
+
+	return nil
+}
+
+func (m *multiMap[K, V]) RemoveKey(key K) error {
+	values, found, err := m.data.Get(key)
+	if err != nil || !found {
+		return err
+	}
+
+	m.size -= values.Size()
+
+	return m.data.Remove(key)
+}
+
+func (m *multiMap[K, V]) Clear() {
+	m.data.Clear()
+	m.size = 0
+}
+
+func (m *multiMap[K, V]) Contains(key K) (bool, error) {
+	return m.data.Contains(key)
+}
+
+func (m *multiMap[K, V]) ContainsEntry(key K, value V) (bool, error) {
+	values, found, err := m.data.Get(key)
+	if err != nil || !found {
+		return false, err
+	}
+
+	return values.Contains(value)
+}
+
+func (m *multiMap[K, V]) Size() int {
+	return m.size
+}
+
+func (m *multiMap[K, V]) KeyCount() int {
+	return m.data.Size()
+}
+
+func (m *multiMap[K, V]) Seq() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for key, values := range m.data.Seq() {
+			for value := range values.Seq() {
+				if !yield(key, value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (m *multiMap[K, V]) Clone() MultiMap[K, V] {
+	result := &multiMap[K, V]{
+		hash: m.hash,
+		data: NewHashMapWithSize[K, set.Set[V]](m.hash, m.data.Size()),
+		size: m.size,
+	}
+
+	for key, values := range m.data.Seq() {
+		_ = result.data.Add(key, values.Clone()) // The key was hashed when it was added
+	}
+
+	return result
+}
+
+func (m *multiMap[K, V]) HashFunction() hashing.HashFunc {
+	return m.hash
+}
+
+func (m *multiMap[K, V]) Keys() set.Set[K] {
+	return m.data.Keys()
+}
+
+func (m *multiMap[K, V]) ForEach(f func(key K, value V)) {
+	for key, value := range m.Seq() {
+		f(key, value)
+	}
+}
+
+func (m *multiMap[K, V]) ForAll(predicate func(key K, value V) bool) bool {
+	for key, value := range m.Seq() {
+		if !predicate(key, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *multiMap[K, V]) Exists(predicate func(key K, value V) bool) bool {
+	for key, value := range m.Seq() {
+		if predicate(key, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *multiMap[K, V]) Filter(predicate func(key K, value V) bool) MultiMap[K, V] {
+	result := NewMultiMap[K, V](m.hash)
+
+	for key, value := range m.Seq() {
+		if predicate(key, value) {
+			_ = result.Add(key, value) // The pair was hashed when it was added
+		}
+	}
+
+	return result
+}
+
+func (m *multiMap[K, V]) FilterNot(predicate func(key K, value V) bool) MultiMap[K, V] {
+	return m.Filter(func(key K, value V) bool {
+		return !predicate(key, value)
+	})
+}
+
+func (m *multiMap[K, V]) Map(f func(key K, value V) (K, V)) MultiMap[K, V] {
+	result := NewMultiMap[K, V](m.hash)
+
+	for key, value := range m.Seq() {
+		_ = result.Add(f(key, value)) // Pairs that can't be hashed are dropped, as in Map.Map
+	}
+
+	return result
+}
+
+func (m *multiMap[K, V]) FindFirst(predicate func(key K, value V) bool) optional.Value[KeyValuePair[K, V]] {
+	for key, value := range m.Seq() {
+		if predicate(key, value) {
+			return optional.Some(KeyValuePair[K, V]{Key: key, Value: value})
+		}
+	}
+
+	return optional.None[KeyValuePair[K, V]]()
+}
@@ -0,0 +1,186 @@
+package maps_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/maps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tag = hashing.HashableString
+
+func TestMultiMap(t *testing.T) {
+	t.Parallel()
+
+	newMultiMap := func(t *testing.T) maps.MultiMap[testKey, tag] {
+		t.Helper()
+
+		m := maps.NewMultiMap[testKey, tag](hashing.Sha256)
+		require.NoError(t, m.AddAll(testKey{"a"}, "x", "y", "x"))
+		require.NoError(t, m.Add(testKey{"b"}, "z"))
+
+		return m
+	}
+
+	t.Run("groups values by key", func(t *testing.T) {
+		t.Parallel()
+
+		m := newMultiMap(t)
+
+		assert.Equal(t, 3, m.Size())
+		assert.Equal(t, 2, m.KeyCount())
+
+		values, err := m.Get(testKey{"a"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []tag{"x", "y"}, values.Entries())
+
+		// Get returns a copy
+		require.NoError(t, values.Add("w"))
+
+		contains, err := m.ContainsEntry(testKey{"a"}, "w")
+		require.NoError(t, err)
+		assert.False(t, contains)
+
+		values, err = m.Get(testKey{"missing"})
+		require.NoError(t, err)
+		assert.Equal(t, 0, values.Size())
+	})
+
+	t.Run("removing the last value removes the key", func(t *testing.T) {
+		t.Parallel()
+
+		m := newMultiMap(t)
+
+		require.NoError(t, m.Remove(testKey{"a"}, "x"))
+		require.NoError(t, m.Remove(testKey{"a"}, "missing"))
+		assert.Equal(t, 2, m.Size())
+
+		require.NoError(t, m.Remove(testKey{"a"}, "y"))
+
+		contains, err := m.Contains(testKey{"a"})
+		require.NoError(t, err)
+		assert.False(t, contains)
+		assert.Equal(t, 1, m.KeyCount())
+
+		require.NoError(t, m.RemoveKey(testKey{"b"}))
+		assert.Equal(t, 0, m.Size())
+	})
+
+	t.Run("functional API works on pairs", func(t *testing.T) {
+		t.Parallel()
+
+		m := newMultiMap(t)
+
+		pairs := 0
+
+		m.ForEach(func(testKey, tag) { pairs++ })
+		assert.Equal(t, 3, pairs)
+
+		assert.True(t, m.Exists(func(key testKey, value tag) bool { return value == "z" }))
+		assert.False(t, m.ForAll(func(key testKey, _ tag) bool { return key.value == "a" }))
+
+		filtered := m.Filter(func(key testKey, _ tag) bool { return key.value == "a" })
+		assert.Equal(t, 2, filtered.Size())
+		assert.Equal(t, 1, m.FilterNot(func(key testKey, _ tag) bool { return key.value == "a" }).Size())
+
+		// Mapping every pair onto one key groups the values under it
+		merged := m.Map(func(_ testKey, value tag) (testKey, tag) { return testKey{"all"}, value })
+		assert.Equal(t, 1, merged.KeyCount())
+		assert.Equal(t, 3, merged.Size())
+
+		found := m.FindFirst(func(_ testKey, value tag) bool { return value == "z" })
+		assert.Equal(t, "b", found.GetOrPanic().Key.value)
+
+		clone := m.Clone()
+		require.NoError(t, clone.Add(testKey{"a"}, "new"))
+		assert.Equal(t, 3, m.Size())
+		assert.Equal(t, 4, clone.Size())
+	})
+
+	t.Run("keeps colliding keys apart", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewMultiMap[collidingKey, tag](hashing.Sha256)
+		require.NoError(t, m.Add(collidingKey{id: 1, hash: "same"}, "one"))
+		require.NoError(t, m.Add(collidingKey{id: 2, hash: "same"}, "two"))
+
+		values, err := m.Get(collidingKey{id: 2, hash: "same"})
+		require.NoError(t, err)
+		assert.Equal(t, []tag{"two"}, values.Entries())
+	})
+}
+
+func TestOrderedMultiMap(t *testing.T) {
+	t.Parallel()
+
+	pairs := func(m maps.OrderedMultiMap[testKey, tag]) []string {
+		var result []string
+		for key, value := range m.Seq() {
+			result = append(result, key.value+"="+string(value))
+		}
+
+		return result
+	}
+
+	m := maps.NewOrderedMultiMap[testKey, tag](hashing.Sha256)
+	require.NoError(t, m.Add(testKey{"env"}, "prod"))
+	require.NoError(t, m.Add(testKey{"team"}, "core"))
+	require.NoError(t, m.AddAll(testKey{"env"}, "eu", "prod"))
+
+	assert.Equal(t, []string{"env=prod", "env=eu", "team=core"}, pairs(m))
+	assert.Equal(t, []testKey{{"env"}, {"team"}}, m.Keys().Entries())
+
+	values, err := m.Get(testKey{"env"})
+	require.NoError(t, err)
+	assert.Equal(t, []tag{"prod", "eu"}, values.Entries())
+
+	// A key whose values were all removed goes to the back when re-added
+	require.NoError(t, m.RemoveKey(testKey{"env"}))
+	require.NoError(t, m.Add(testKey{"env"}, "us"))
+	assert.Equal(t, []string{"team=core", "env=us"}, pairs(m))
+
+	filtered := m.Filter(func(key testKey, _ tag) bool { return key.value == "env" })
+	assert.Equal(t, []string{"env=us"}, pairs(filtered))
+
+	clone := m.Clone()
+	require.NoError(t, clone.Remove(testKey{"team"}, "core"))
+	assert.Equal(t, []string{"team=core", "env=us"}, pairs(m))
+	assert.Equal(t, []string{"env=us"}, pairs(clone))
+}
+
+func TestThreadSafeMultiMap(t *testing.T) {
+	t.Parallel()
+
+	m := maps.NewThreadSafeMultiMap(maps.NewMultiMap[testKey, tag](hashing.Sha256))
+	assert.Same(t, m, maps.NewThreadSafeMultiMap(m))
+
+	ordered := maps.NewThreadSafeOrderedMultiMap(maps.NewOrderedMultiMap[testKey, tag](hashing.Sha256))
+
+	var wg sync.WaitGroup
+
+	for i := range 10 {
+		wg.Go(func() {
+			for j := range 10 {
+				key := testKey{fmt.Sprint(j)}
+				value := tag(fmt.Sprint(i))
+
+				assert.NoError(t, m.Add(key, value))
+				assert.NoError(t, ordered.Add(key, value))
+
+				// Callbacks run on a snapshot, so they may use the multimap
+				m.ForEach(func(testKey, tag) { _ = m.Size() })
+			}
+		})
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 100, m.Size())
+	assert.Equal(t, 10, m.KeyCount())
+	assert.Equal(t, 100, ordered.Size())
+	assert.Equal(t, 10, ordered.Keys().Size())
+}
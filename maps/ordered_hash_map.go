@@ -4,7 +4,6 @@ import (
 	"iter"
 
 	"github.com/amp-labs/amp-common/collectable"
-	errors2 "github.com/amp-labs/amp-common/errors"
 	"github.com/amp-labs/amp-common/hashing"
 	"github.com/amp-labs/amp-common/optional"
 	"github.com/amp-labs/amp-common/set"
@@ -12,8 +11,9 @@ import (
 )
 
 // NewOrderedHashMap creates a new ordered hash-based OrderedMap implementation using the provided hash function.
-// The hash function must produce consistent hash values for equal keys and should
-// minimize collisions to avoid ErrHashCollision errors during operations.
+// The hash function must produce consistent hash values for equal keys. Distinct keys
+// that share a hash value are kept apart using their Equals method, so fast
+// non-cryptographic hashes such as hashing.XxHash32 are safe to use.
 //
 // Unlike the standard Map interface, the returned OrderedMap preserves insertion order when
 // iterating through entries. The iteration order is deterministic and reflects the order
@@ -33,49 +33,46 @@ import (
 func NewOrderedHashMap[K collectable.Collectable[K], V any](hash hashing.HashFunc) OrderedMap[K, V] {
 	return &orderedHashMap[K, V]{
 		hash: hash,
-		data: make(map[string]KeyValuePair[K, V]),
+		data: make(map[string]bucket[K, V]),
 	}
 }
 
 // orderedHashMap is the concrete implementation of the OrderedMap interface using a hash table
-// combined with a slice to maintain insertion order. It stores entries in a Go map indexed by
-// string hash values for O(1) average-case lookup, while maintaining a separate slice of keys
-// to track insertion order. Collision detection is performed by comparing the full key using
-// the Comparable interface when hash values match. This ensures correctness even with imperfect
-// hash functions.
+// combined with a slice to maintain insertion order. It stores entries in a Go map of buckets
+// indexed by string hash values for O(1) average-case lookup, while maintaining a separate slice
+// of keys to track insertion order. Keys whose hash values match are told apart by comparing the
+// full key using the Comparable interface. This ensures correctness even with imperfect hash
+// functions.
 //
 // The implementation is not thread-safe and provides O(1) average-case lookup time with
 // O(n) insertion-ordered iteration.
 type orderedHashMap[K collectable.Collectable[K], V any] struct {
-	orderedKeys []K                           // Slice of keys in insertion order
-	hash        hashing.HashFunc              // Hash function for converting keys to string hashes
-	data        map[string]KeyValuePair[K, V] // Internal storage indexed by hash values
+	orderedKeys []K                     // Slice of keys in insertion order
+	hash        hashing.HashFunc        // Hash function for converting keys to string hashes
+	data        map[string]bucket[K, V] // Internal storage indexed by hash values
 }
 
 // Get retrieves the value for the given key from the ordered hash map.
 // If the key exists, returns the value with found=true. If the key doesn't exist, returns
-// a zero value with found=false.
-// Returns ErrHashCollision if a different key with the same hash exists in the map.
+// a zero value with found=false. An error is only returned if hashing the key fails.
 func (o *orderedHashMap[K, V]) Get(key K) (value V, found bool, err error) {
 	hashVal, err := o.hash(key)
 	if err != nil {
 		return zero.Value[V](), false, err
 	}
 
-	kv, ok := o.data[hashVal]
-	if !ok {
-		return zero.Value[V](), false, nil
-	}
+	entries := o.data[hashVal]
 
-	if !kv.Key.Equals(key) {
-		return zero.Value[V](), false, errors2.ErrHashCollision
+	i := entries.find(key)
+	if i < 0 {
+		return zero.Value[V](), false, nil
 	}
 
-	return kv.Value, true, nil
+	return entries[i].Value, true, nil
 }
 
 // GetOrElse retrieves the value for the given key, or returns defaultValue if the key doesn't exist.
-// Returns ErrHashCollision if a different key with the same hash exists in the map.
+// An error is only returned if hashing the key fails.
 func (o *orderedHashMap[K, V]) GetOrElse(key K, defaultValue V) (value V, err error) {
 	value, found, err := o.Get(key)
 	if err != nil {
@@ -92,27 +89,25 @@ func (o *orderedHashMap[K, V]) GetOrElse(key K, defaultValue V) (value V, err er
 // Add inserts or updates a key-value pair in the ordered hash map.
 // If the key already exists (determined by both hash and equality), its value is replaced
 // without changing its position in the insertion order. If the key is new, it's added to
-// both the hash map and appended to the end of the orderedKeys slice.
-// Returns ErrHashCollision if a different key produces the same hash value.
+// both the hash map and appended to the end of the orderedKeys slice. A different key that
+// produces the same hash value is stored alongside the existing one.
+// An error is only returned if hashing the key fails.
 func (o *orderedHashMap[K, V]) Add(key K, value V) error {
 	hashVal, err := o.hash(key)
 	if err != nil {
 		return err
 	}
 
-	prev, ok := o.data[hashVal]
+	entries := o.data[hashVal]
 
-	if ok && !key.Equals(prev.Key) {
-		// Hash collision detected
-		return errors2.ErrHashCollision
-	}
+	if i := entries.find(key); i >= 0 {
+		entries[i] = KeyValuePair[K, V]{Key: key, Value: value}
 
-	// If key doesn't exist, add it to orderedKeys
-	if !ok {
-		o.orderedKeys = append(o.orderedKeys, key)
+		return nil
 	}
 
-	o.data[hashVal] = KeyValuePair[K, V]{Key: key, Value: value}
+	o.orderedKeys = append(o.orderedKeys, key)
+	o.data[hashVal] = append(entries, KeyValuePair[K, V]{Key: key, Value: value})
 
 	return nil
 }
@@ -120,33 +115,35 @@ func (o *orderedHashMap[K, V]) Add(key K, value V) error {
 // Remove deletes a key-value pair from the ordered hash map.
 // If the key doesn't exist, this is a no-op and returns nil. If the key exists, it's removed
 // from both the hash map and the orderedKeys slice. This operation is O(n) due to the need
-// to search and remove from the orderedKeys slice.
-// Returns ErrHashCollision if a different key with the same hash exists in the map.
+// to search and remove from the orderedKeys slice. Other keys with the same hash value are
+// left untouched. An error is only returned if hashing the key fails.
 func (o *orderedHashMap[K, V]) Remove(key K) error {
 	hashVal, err := o.hash(key)
 	if err != nil {
 		return err
 	}
 
-	prev, ok := o.data[hashVal]
+	entries := o.data[hashVal]
 
-	if ok && !key.Equals(prev.Key) {
-		// Hash collision detected - the stored key is different
-		return errors2.ErrHashCollision
+	idx := entries.find(key)
+	if idx < 0 {
+		return nil
 	}
 
-	if ok {
-		// Remove from orderedKeys
-		for i, k := range o.orderedKeys {
-			if k.Equals(key) {
-				o.orderedKeys = append(o.orderedKeys[:i], o.orderedKeys[i+1:]...)
+	// Remove from orderedKeys
+	for i, k := range o.orderedKeys {
+		if k.Equals(key) {
+			o.orderedKeys = append(o.orderedKeys[:i], o.orderedKeys[i+1:]...)
 
-				break
-			}
+			break
 		}
 	}
 
-	delete(o.data, hashVal)
+	if rest := entries.without(idx); rest != nil {
+		o.data[hashVal] = rest
+	} else {
+		delete(o.data, hashVal)
+	}
 
 	return nil
 }
@@ -157,36 +154,25 @@ func (o *orderedHashMap[K, V]) Remove(key K) error {
 // to be garbage collected.
 func (o *orderedHashMap[K, V]) Clear() {
 	o.orderedKeys = nil
-	o.data = make(map[string]KeyValuePair[K, V])
+	o.data = make(map[string]bucket[K, V])
 }
 
 // Contains checks whether a key exists in the ordered hash map.
 // Returns true if the key exists, false otherwise.
-// Returns ErrHashCollision if a different key with the same hash exists in the map.
+// An error is only returned if hashing the key fails.
 func (o *orderedHashMap[K, V]) Contains(key K) (bool, error) {
 	hashVal, err := o.hash(key)
 	if err != nil {
 		return false, err
 	}
 
-	prev, ok := o.data[hashVal]
-
-	if !ok {
-		return false, nil
-	}
-
-	if !key.Equals(prev.Key) {
-		// Hash collision detected - the stored key is different
-		return false, errors2.ErrHashCollision
-	}
-
-	return true, nil
+	return o.data[hashVal].find(key) >= 0, nil
 }
 
 // Size returns the number of key-value pairs currently stored in the ordered hash map.
-// This operation is O(1) as it simply returns the length of the internal storage.
+// This operation is O(1) as it simply returns the length of the insertion order slice.
 func (o *orderedHashMap[K, V]) Size() int {
-	return len(o.data)
+	return len(o.orderedKeys)
 }
 
 // Seq returns an iterator for ranging over all key-value pairs in insertion order.
@@ -209,12 +195,14 @@ func (o *orderedHashMap[K, V]) Seq() iter.Seq2[int, KeyValuePair[K, V]] {
 				return
 			}
 
-			entry, ok := o.data[hashVal]
-			if !ok {
+			entries := o.data[hashVal]
+
+			idx := entries.find(key)
+			if idx < 0 {
 				continue
 			}
 
-			if !yield(i, entry) {
+			if !yield(i, entries[idx]) {
 				return
 			}
 		}
@@ -226,7 +214,6 @@ func (o *orderedHashMap[K, V]) Seq() iter.Seq2[int, KeyValuePair[K, V]] {
 // are added. If a key exists in both maps, the value from other takes precedence, but the key
 // maintains its original position from this map (it's not moved to the end).
 // Returns a new OrderedMap instance with entries from both maps merged together.
-// Returns ErrHashCollision if any hash collision occurs during the operation.
 //
 // The time complexity is O(n + m) where n is the size of this map and m is the size of other.
 func (o *orderedHashMap[K, V]) Union(other OrderedMap[K, V]) (OrderedMap[K, V], error) {
@@ -256,7 +243,6 @@ func (o *orderedHashMap[K, V]) Union(other OrderedMap[K, V]) (OrderedMap[K, V],
 // so the result maintains the relative order of keys as they appeared in this map.
 // Keys are compared using both hash and equality.
 // Returns a new OrderedMap instance with only the common entries.
-// Returns ErrHashCollision if any hash collision occurs during the operation.
 //
 // The time complexity is O(n) where n is the size of this map.
 func (o *orderedHashMap[K, V]) Intersection(other OrderedMap[K, V]) (OrderedMap[K, V], error) {
@@ -280,6 +266,57 @@ func (o *orderedHashMap[K, V]) Intersection(other OrderedMap[K, V]) (OrderedMap[
 	return result, nil
 }
 
+// Difference creates a new ordered hash map containing the key-value pairs of this map whose keys
+// are not in other, in this map's insertion order.
+func (o *orderedHashMap[K, V]) Difference(other OrderedMap[K, V]) (OrderedMap[K, V], error) {
+	result := NewOrderedHashMap[K, V](o.hash)
+
+	err := addMissingKeys(orderedPairs(o.Seq()), other, result.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SymmetricDifference creates a new ordered hash map containing the key-value pairs whose keys are in
+// exactly one of the two maps: first those of this map, then those of other, each in insertion order.
+func (o *orderedHashMap[K, V]) SymmetricDifference(other OrderedMap[K, V]) (OrderedMap[K, V], error) {
+	result := NewOrderedHashMap[K, V](o.hash)
+
+	err := addMissingKeys(orderedPairs(o.Seq()), other, result.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	err = addMissingKeys(orderedPairs(other.Seq()), o, result.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// IsSubsetOf reports whether every key of this map is also in other.
+func (o *orderedHashMap[K, V]) IsSubsetOf(other OrderedMap[K, V]) (bool, error) {
+	return isSubsetKeys(o.Size(), orderedPairs(o.Seq()), other)
+}
+
+// IsSupersetOf reports whether every key of other is also in this map.
+func (o *orderedHashMap[K, V]) IsSupersetOf(other OrderedMap[K, V]) (bool, error) {
+	return isSubsetKeys(other.Size(), orderedPairs(other.Seq()), o)
+}
+
+// IsDisjoint reports whether the two maps have no keys in common.
+func (o *orderedHashMap[K, V]) IsDisjoint(other OrderedMap[K, V]) (bool, error) {
+	return isDisjointKeys(orderedPairs(o.Seq()), other)
+}
+
+// Equals reports whether both maps have the same keys with equal values.
+func (o *orderedHashMap[K, V]) Equals(other OrderedMap[K, V], valueEquals func(a, b V) bool) (bool, error) {
+	return isEqualMap(o.Size(), orderedPairs(o.Seq()), other, valueEquals)
+}
+
 // Clone creates a shallow copy of the ordered hash map, duplicating its structure, entries,
 // and insertion order. The keys and values themselves are not deep-copied; they are referenced
 // as-is in the new map. Returns a new OrderedMap instance with the same entries in the same order
@@ -291,8 +328,8 @@ func (o *orderedHashMap[K, V]) Intersection(other OrderedMap[K, V]) (OrderedMap[
 // This operation is O(n) where n is the number of entries in the map, as it iterates through
 // all entries to populate the new map in order.
 //
-// Note: Since the map was already validated during construction, Add operations during cloning
-// should not fail with hash collisions. Any errors are silently ignored.
+// Note: Since the keys were already hashed successfully when they were added, Add operations
+// during cloning should not fail. Any errors are silently ignored.
 //
 // Example:
 //
@@ -117,7 +117,7 @@ func TestOrderedHashMap_Add(t *testing.T) {
 		}
 	})
 
-	t.Run("returns error on hash collision", func(t *testing.T) {
+	t.Run("keeps colliding keys apart", func(t *testing.T) {
 		t.Parallel()
 
 		m := maps.NewOrderedHashMap[collidingKey, string](hashing.Sha256)
@@ -128,7 +128,16 @@ func TestOrderedHashMap_Add(t *testing.T) {
 		require.NoError(t, err)
 
 		err = m.Add(key2, "value2")
-		assert.Error(t, err)
+		require.NoError(t, err)
+		assert.Equal(t, 2, m.Size())
+
+		value, err := m.GetOrElse(key1, "")
+		require.NoError(t, err)
+		assert.Equal(t, "value1", value)
+
+		value, err = m.GetOrElse(key2, "")
+		require.NoError(t, err)
+		assert.Equal(t, "value2", value)
 	})
 }
 
@@ -219,7 +228,7 @@ func TestOrderedHashMap_Remove(t *testing.T) {
 		}
 	})
 
-	t.Run("returns error on hash collision", func(t *testing.T) {
+	t.Run("leaves colliding keys untouched", func(t *testing.T) {
 		t.Parallel()
 
 		m := maps.NewOrderedHashMap[collidingKey, string](hashing.Sha256)
@@ -230,7 +239,22 @@ func TestOrderedHashMap_Remove(t *testing.T) {
 		require.NoError(t, err)
 
 		err = m.Remove(key2)
-		assert.Error(t, err)
+		require.NoError(t, err)
+		assert.Equal(t, 1, m.Size())
+
+		err = m.Add(key2, "value2")
+		require.NoError(t, err)
+
+		err = m.Remove(key1)
+		require.NoError(t, err)
+
+		contains, err := m.Contains(key1)
+		require.NoError(t, err)
+		assert.False(t, contains)
+
+		contains, err = m.Contains(key2)
+		require.NoError(t, err)
+		assert.True(t, contains)
 	})
 }
 
@@ -329,7 +353,7 @@ func TestOrderedHashMap_Contains(t *testing.T) {
 		assert.False(t, contains)
 	})
 
-	t.Run("returns error on hash collision", func(t *testing.T) {
+	t.Run("returns false for colliding key", func(t *testing.T) {
 		t.Parallel()
 
 		m := maps.NewOrderedHashMap[collidingKey, string](hashing.Sha256)
@@ -340,7 +364,7 @@ func TestOrderedHashMap_Contains(t *testing.T) {
 		require.NoError(t, err)
 
 		contains, err := m.Contains(key2)
-		require.Error(t, err)
+		require.NoError(t, err)
 		assert.False(t, contains)
 	})
 }
@@ -869,7 +893,7 @@ func TestOrderedHashMap_Get(t *testing.T) {
 		}
 	})
 
-	t.Run("returns error on hash collision", func(t *testing.T) {
+	t.Run("returns not found for colliding key", func(t *testing.T) {
 		t.Parallel()
 
 		m := maps.NewOrderedHashMap[collidingKey, string](hashing.Sha256)
@@ -879,10 +903,10 @@ func TestOrderedHashMap_Get(t *testing.T) {
 		err := m.Add(key1, "value1")
 		require.NoError(t, err)
 
-		// Try to get with a different key but same hash
+		// Get with a different key but same hash
 		key2 := collidingKey{id: 2, hash: "samehash"}
 		value, found, err := m.Get(key2)
-		require.Error(t, err)
+		require.NoError(t, err)
 		assert.False(t, found)
 		assert.Empty(t, value)
 	})
@@ -0,0 +1,255 @@
+package maps //nolint:revive // Established package name; renaming would break all consumers.
+
+import (
+	"iter"
+
+	"github.com/amp-labs/amp-common/collectable"
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/optional"
+	"github.com/amp-labs/amp-common/set"
+)
+
+// NewOrderedMultiMap creates an empty OrderedMultiMap. Keys are stored in an
+// OrderedMap and each key's values in a set.OrderedSet, both using hash, so
+// iteration follows insertion order.
+//
+// The returned multimap is not thread-safe; wrap it with NewThreadSafeOrderedMultiMap
+// for concurrent use.
+//
+// Example:
+//
+//	tags := maps.NewOrderedMultiMap[hashing.HashableString, hashing.HashableString](hashing.Sha256)
+//	_ = tags.Add("env", "prod")
+//	_ = tags.Add("team", "core")
+//	_ = tags.Add("env", "eu")
+//	for key, value := range tags.Seq() {
+//	    fmt.Println(key, value) // env prod, env eu, team core
+//	}
+func NewOrderedMultiMap[K collectable.Collectable[K], V collectable.Collectable[V]](
+	hash hashing.HashFunc,
+) OrderedMultiMap[K, V] {
+	return &orderedMultiMap[K, V]{
+		hash: hash,
+		data: NewOrderedHashMap[K, set.OrderedSet[V]](hash),
+	}
+}
+
+// multiMap stores a non-empty value set per key, and keeps a running count of pairs.
+type orderedMultiMap[K collectable.Collectable[K], V collectable.Collectable[V]] struct {
+	hash hashing.HashFunc
+	data OrderedMap[K, set.OrderedSet[V]]
+	size int
+}
+
+func (m *orderedMultiMap[K, V]) Get(key K) (set.OrderedSet[V], error) {
+	values, found, err := m.data.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return set.NewOrderedSet[V](m.hash), nil
+	}
+
+	return values.Clone(), nil
+}
+
+func (m *orderedMultiMap[K, V]) Add(key K, value V) error {
+	values, found, err := m.data.Get(key)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		values = set.NewOrderedSet[V](m.hash)
+	}
+
+	// Check the value before touching the map, so a hashing error leaves it unchanged.
+	contains, err := values.Contains(value)
+	if err != nil || contains {
+		return err
+	}
+
+	if err := values.Add(value); err != nil {
+		return err
+	}
+
+	if !found {
+		if err := m.data.Add(key, values); err != nil {
+			return err
+		}
+	}
+
+	m.size++
+
+	return nil
+}
+
+func (m *orderedMultiMap[K, V]) AddAll(key K, values ...V) error {
+	for _, value := range values {
+		if err := m.Add(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *orderedMultiMap[K, V]) Remove(key K, value V) error {
+	values, found, err := m.data.Get(key)
+	if err != nil || !found {
+		return err
+	}
+
+	contains, err := values.Contains(value)
+	if err != nil || !contains {
+		return err
+	}
+
+	if err := values.Remove(value); err != nil {
+		return err
+	}
+
+	m.size--
+
+	if values.Size() == 0 {
+		return m.data.Remove(key)
+	}
+
+	return nil
+}
+
+func (m *orderedMultiMap[K, V]) RemoveKey(key K) error {
+	values, found, err := m.data.Get(key)
+	if err != nil || !found {
+		return err
+	}
+
+	m.size -= values.Size()
+
+	return m.data.Remove(key)
+}
+
+func (m *orderedMultiMap[K, V]) Clear() {
+	m.data.Clear()
+	m.size = 0
+}
+
+func (m *orderedMultiMap[K, V]) Contains(key K) (bool, error) {
+	return m.data.Contains(key)
+}
+
+func (m *orderedMultiMap[K, V]) ContainsEntry(key K, value V) (bool, error) {
+	values, found, err := m.data.Get(key)
+	if err != nil || !found {
+		return false, err
+	}
+
+	return values.Contains(value)
+}
+
+func (m *orderedMultiMap[K, V]) Size() int {
+	return m.size
+}
+
+func (m *orderedMultiMap[K, V]) KeyCount() int {
+	return m.data.Size()
+}
+
+func (m *orderedMultiMap[K, V]) Seq() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, entry := range m.data.Seq() {
+			for _, value := range entry.Value.Seq() {
+				if !yield(entry.Key, value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (m *orderedMultiMap[K, V]) Clone() OrderedMultiMap[K, V] {
+	result := &orderedMultiMap[K, V]{
+		hash: m.hash,
+		data: NewOrderedHashMap[K, set.OrderedSet[V]](m.hash),
+		size: m.size,
+	}
+
+	for _, entry := range m.data.Seq() {
+		_ = result.data.Add(entry.Key, entry.Value.Clone()) // The key was hashed when it was added
+	}
+
+	return result
+}
+
+func (m *orderedMultiMap[K, V]) HashFunction() hashing.HashFunc {
+	return m.hash
+}
+
+func (m *orderedMultiMap[K, V]) Keys() set.OrderedSet[K] {
+	return m.data.Keys()
+}
+
+func (m *orderedMultiMap[K, V]) ForEach(f func(key K, value V)) {
+	for key, value := range m.Seq() {
+		f(key, value)
+	}
+}
+
+func (m *orderedMultiMap[K, V]) ForAll(predicate func(key K, value V) bool) bool {
+	for key, value := range m.Seq() {
+		if !predicate(key, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *orderedMultiMap[K, V]) Exists(predicate func(key K, value V) bool) bool {
+	for key, value := range m.Seq() {
+		if predicate(key, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *orderedMultiMap[K, V]) Filter(predicate func(key K, value V) bool) OrderedMultiMap[K, V] {
+	result := NewOrderedMultiMap[K, V](m.hash)
+
+	for key, value := range m.Seq() {
+		if predicate(key, value) {
+			_ = result.Add(key, value) // The pair was hashed when it was added
+		}
+	}
+
+	return result
+}
+
+func (m *orderedMultiMap[K, V]) FilterNot(predicate func(key K, value V) bool) OrderedMultiMap[K, V] {
+	return m.Filter(func(key K, value V) bool {
+		return !predicate(key, value)
+	})
+}
+
+func (m *orderedMultiMap[K, V]) Map(f func(key K, value V) (K, V)) OrderedMultiMap[K, V] {
+	result := NewOrderedMultiMap[K, V](m.hash)
+
+	for key, value := range m.Seq() {
+		_ = result.Add(f(key, value)) // Pairs that can't be hashed are dropped, as in Map.Map
+	}
+
+	return result
+}
+
+func (m *orderedMultiMap[K, V]) FindFirst(predicate func(key K, value V) bool) optional.Value[KeyValuePair[K, V]] {
+	for key, value := range m.Seq() {
+		if predicate(key, value) {
+			return optional.Some(KeyValuePair[K, V]{Key: key, Value: value})
+		}
+	}
+
+	return optional.None[KeyValuePair[K, V]]()
+}
@@ -0,0 +1,361 @@
+package maps //nolint:revive // Established package name; renaming would break all consumers.
+
+import (
+	"iter"
+
+	"github.com/amp-labs/amp-common/collectable"
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/internal/persistent"
+	"github.com/amp-labs/amp-common/optional"
+	"github.com/amp-labs/amp-common/set"
+	"github.com/amp-labs/amp-common/zero"
+)
+
+// NewPersistentHashMap creates an empty PersistentMap backed by a hash array
+// mapped trie. Adding or removing a key copies only the O(log n) trie nodes on
+// its path, and all other nodes are shared with earlier versions. Keys whose
+// hashes collide are told apart with Equals, as in NewHashMap.
+//
+// Use a persistent map for snapshots that are handed to other goroutines or
+// kept around while the map keeps changing: Clone is O(1), and Union,
+// Intersection and Filter only pay for the entries they add or drop.
+//
+// Example:
+//
+//	m := maps.NewPersistentHashMap[MyKey, string](hashing.XxHash32)
+//	_ = m.Add(key1, "a")
+//	snapshot := m.Clone() // O(1)
+//	_ = m.Add(key2, "b")  // snapshot still only contains key1
+func NewPersistentHashMap[K collectable.Collectable[K], V any](hash hashing.HashFunc) PersistentMap[K, V] {
+	return &persistentHashMap[K, V]{hash: hash}
+}
+
+var _ PersistentMap[hashing.HashableString, string] = (*persistentHashMap[hashing.HashableString, string])(nil)
+
+// persistentHashMap is the PersistentMap returned by NewPersistentHashMap.
+// The trie is immutable; mutating methods swap it for a new version.
+type persistentHashMap[K collectable.Collectable[K], V any] struct {
+	hash hashing.HashFunc
+	trie persistent.Trie[K, V]
+}
+
+func (m *persistentHashMap[K, V]) derive(trie persistent.Trie[K, V]) *persistentHashMap[K, V] {
+	return &persistentHashMap[K, V]{hash: m.hash, trie: trie}
+}
+
+func (m *persistentHashMap[K, V]) hashOf(key K) (uint64, error) {
+	hashVal, err := m.hash(key)
+	if err != nil {
+		return 0, err
+	}
+
+	return persistent.Hash(hashVal), nil
+}
+
+// With returns a version of the map in which key maps to value. The receiver is unchanged.
+func (m *persistentHashMap[K, V]) With(key K, value V) (PersistentMap[K, V], error) {
+	out := m.derive(m.trie)
+
+	err := out.Add(key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Without returns a version of the map without key. The receiver is unchanged.
+func (m *persistentHashMap[K, V]) Without(key K) (PersistentMap[K, V], error) {
+	out := m.derive(m.trie)
+
+	err := out.Remove(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Get retrieves the value for the given key.
+// An error is only returned if hashing the key fails.
+func (m *persistentHashMap[K, V]) Get(key K) (value V, found bool, err error) {
+	h, err := m.hashOf(key)
+	if err != nil {
+		return zero.Value[V](), false, err
+	}
+
+	value, found = m.trie.Get(h, key)
+
+	return value, found, nil
+}
+
+// GetOrElse retrieves the value for the given key, or returns defaultValue if the key doesn't exist.
+// An error is only returned if hashing the key fails.
+func (m *persistentHashMap[K, V]) GetOrElse(key K, defaultValue V) (value V, err error) {
+	value, found, err := m.Get(key)
+	if err != nil {
+		return zero.Value[V](), err
+	}
+
+	if !found {
+		return defaultValue, nil
+	}
+
+	return value, nil
+}
+
+// Add moves the map to a version in which key maps to value.
+// An error is only returned if hashing the key fails.
+func (m *persistentHashMap[K, V]) Add(key K, value V) error {
+	h, err := m.hashOf(key)
+	if err != nil {
+		return err
+	}
+
+	m.trie = m.trie.With(h, key, value)
+
+	return nil
+}
+
+// Remove moves the map to a version without key.
+// An error is only returned if hashing the key fails.
+func (m *persistentHashMap[K, V]) Remove(key K) error {
+	h, err := m.hashOf(key)
+	if err != nil {
+		return err
+	}
+
+	m.trie, _ = m.trie.Without(h, key)
+
+	return nil
+}
+
+// Clear moves the map to an empty version.
+func (m *persistentHashMap[K, V]) Clear() {
+	m.trie = persistent.Trie[K, V]{}
+}
+
+// Contains checks whether a key exists in the map.
+// An error is only returned if hashing the key fails.
+func (m *persistentHashMap[K, V]) Contains(key K) (bool, error) {
+	_, found, err := m.Get(key)
+
+	return found, err
+}
+
+// Size returns the number of key-value pairs in the map in O(1).
+func (m *persistentHashMap[K, V]) Size() int {
+	return m.trie.Len()
+}
+
+// Seq returns an iterator over all key-value pairs. The iteration order is
+// determined by the key hashes and is stable for a given version.
+func (m *persistentHashMap[K, V]) Seq() iter.Seq2[K, V] {
+	return m.trie.All()
+}
+
+// Union returns a version of this map with the entries of other added. If a key
+// exists in both maps, the value from other takes precedence. Only the entries
+// of other are inserted, so the cost is proportional to the size of other.
+func (m *persistentHashMap[K, V]) Union(other Map[K, V]) (Map[K, V], error) {
+	out := m.derive(m.trie)
+
+	for key, value := range other.Seq() {
+		err := out.Add(key, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// Intersection returns a version of this map without the keys that are missing
+// from other. The values are taken from this map.
+func (m *persistentHashMap[K, V]) Intersection(other Map[K, V]) (Map[K, V], error) {
+	out := m.derive(m.trie)
+
+	for key := range m.Seq() {
+		contains, err := other.Contains(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if !contains {
+			err = out.Remove(key)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// Difference returns a version of this map without the keys of other. Only the
+// keys of other are looked up, so the cost is proportional to the size of other.
+func (m *persistentHashMap[K, V]) Difference(other Map[K, V]) (Map[K, V], error) {
+	out := m.derive(m.trie)
+
+	for key := range other.Seq() {
+		err := out.Remove(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// SymmetricDifference returns a version of this map without the keys it shares
+// with other, and with the remaining entries of other added.
+func (m *persistentHashMap[K, V]) SymmetricDifference(other Map[K, V]) (Map[K, V], error) {
+	out := m.derive(m.trie)
+
+	for key, value := range other.Seq() {
+		contains, err := m.Contains(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if contains {
+			err = out.Remove(key)
+		} else {
+			err = out.Add(key, value)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+func (m *persistentHashMap[K, V]) IsSubsetOf(other Map[K, V]) (bool, error) {
+	return isSubsetKeys(m.Size(), m.Seq(), other)
+}
+
+func (m *persistentHashMap[K, V]) IsSupersetOf(other Map[K, V]) (bool, error) {
+	return isSubsetKeys(other.Size(), other.Seq(), m)
+}
+
+func (m *persistentHashMap[K, V]) IsDisjoint(other Map[K, V]) (bool, error) {
+	return isDisjointKeys(m.Seq(), other)
+}
+
+func (m *persistentHashMap[K, V]) Equals(other Map[K, V], valueEquals func(a, b V) bool) (bool, error) {
+	return isEqualMap(m.Size(), m.Seq(), other, valueEquals)
+}
+
+// Clone returns a new map sharing all of its structure with this one. It is
+// O(1); later changes to either map don't affect the other.
+func (m *persistentHashMap[K, V]) Clone() Map[K, V] {
+	if m == nil {
+		return nil
+	}
+
+	return m.derive(m.trie)
+}
+
+// HashFunction returns the hash function used by this map.
+func (m *persistentHashMap[K, V]) HashFunction() hashing.HashFunc {
+	return m.hash
+}
+
+// Keys returns a persistent set containing all keys from the map.
+func (m *persistentHashMap[K, V]) Keys() set.Set[K] {
+	keys := set.NewPersistentSet[K](m.hash)
+
+	for key := range m.Seq() {
+		_ = keys.Add(key) // Add should not fail for existing keys
+	}
+
+	return keys
+}
+
+// ForEach applies the given function to each key-value pair in the map.
+func (m *persistentHashMap[K, V]) ForEach(f func(key K, value V)) {
+	for key, value := range m.Seq() {
+		f(key, value)
+	}
+}
+
+// ForAll tests whether a predicate holds for all key-value pairs in the map.
+// The iteration stops early if the predicate returns false for any entry.
+func (m *persistentHashMap[K, V]) ForAll(predicate func(key K, value V) bool) bool {
+	for key, value := range m.Seq() {
+		if !predicate(key, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Filter returns a version of this map without the entries for which the predicate returns false.
+func (m *persistentHashMap[K, V]) Filter(predicate func(key K, value V) bool) Map[K, V] {
+	out := m.derive(m.trie)
+
+	for key, value := range m.Seq() {
+		if !predicate(key, value) {
+			_ = out.Remove(key) // Hashing succeeded when the key was added
+		}
+	}
+
+	return out
+}
+
+// FilterNot returns a version of this map without the entries for which the predicate returns true.
+func (m *persistentHashMap[K, V]) FilterNot(predicate func(key K, value V) bool) Map[K, V] {
+	return m.Filter(func(key K, value V) bool { return !predicate(key, value) })
+}
+
+// Map transforms all key-value pairs in the map by applying the given function to each entry.
+// Returns a new persistent map containing the transformed entries.
+func (m *persistentHashMap[K, V]) Map(f func(key K, value V) (K, V)) Map[K, V] {
+	out := m.derive(persistent.Trie[K, V]{})
+
+	for key, value := range m.Seq() {
+		newKey, newValue := f(key, value)
+		_ = out.Add(newKey, newValue) // Duplicate keys will be overwritten
+	}
+
+	return out
+}
+
+// FlatMap applies the given function to each key-value pair and merges the
+// returned maps into a new persistent map. Later values take precedence.
+func (m *persistentHashMap[K, V]) FlatMap(f func(key K, value V) Map[K, V]) Map[K, V] {
+	out := m.derive(persistent.Trie[K, V]{})
+
+	for key, value := range m.Seq() {
+		for newKey, newValue := range f(key, value).Seq() {
+			_ = out.Add(newKey, newValue) // Duplicate keys will be overwritten
+		}
+	}
+
+	return out
+}
+
+// Exists tests whether at least one key-value pair in the map satisfies the given predicate.
+func (m *persistentHashMap[K, V]) Exists(predicate func(key K, value V) bool) bool {
+	for key, value := range m.Seq() {
+		if predicate(key, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FindFirst returns the first key-value pair in iteration order that satisfies the predicate.
+func (m *persistentHashMap[K, V]) FindFirst(predicate func(key K, value V) bool) optional.Value[KeyValuePair[K, V]] {
+	for key, value := range m.Seq() {
+		if predicate(key, value) {
+			return optional.Some(KeyValuePair[K, V]{Key: key, Value: value})
+		}
+	}
+
+	return optional.None[KeyValuePair[K, V]]()
+}
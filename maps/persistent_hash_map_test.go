@@ -0,0 +1,194 @@
+package maps_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/maps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentHashMap(t *testing.T) {
+	t.Parallel()
+
+	t.Run("behaves like a map", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewPersistentHashMap[testKey, int](hashing.Sha256)
+
+		require.NoError(t, m.Add(testKey{"a"}, 1))
+		require.NoError(t, m.Add(testKey{"b"}, 2))
+		require.NoError(t, m.Add(testKey{"a"}, 3))
+
+		value, found, err := m.Get(testKey{"a"})
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, 3, value)
+		assert.Equal(t, 2, m.Size())
+
+		value, err = m.GetOrElse(testKey{"missing"}, 42)
+		require.NoError(t, err)
+		assert.Equal(t, 42, value)
+
+		require.NoError(t, m.Remove(testKey{"a"}))
+		require.NoError(t, m.Remove(testKey{"missing"}))
+
+		contains, err := m.Contains(testKey{"a"})
+		require.NoError(t, err)
+		assert.False(t, contains)
+		assert.Equal(t, 1, m.Size())
+
+		m.Clear()
+		assert.Equal(t, 0, m.Size())
+	})
+
+	t.Run("keeps colliding keys apart", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewPersistentHashMap[collidingKey, string](hashing.Sha256)
+
+		require.NoError(t, m.Add(collidingKey{id: 1, hash: "same"}, "one"))
+		require.NoError(t, m.Add(collidingKey{id: 2, hash: "same"}, "two"))
+		assert.Equal(t, 2, m.Size())
+
+		value, found, err := m.Get(collidingKey{id: 2, hash: "same"})
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "two", value)
+
+		require.NoError(t, m.Remove(collidingKey{id: 1, hash: "same"}))
+
+		value, found, err = m.Get(collidingKey{id: 2, hash: "same"})
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "two", value)
+	})
+
+	t.Run("With and Without leave the receiver unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		empty := maps.NewPersistentHashMap[testKey, int](hashing.Sha256)
+
+		one, err := empty.With(testKey{"a"}, 1)
+		require.NoError(t, err)
+
+		two, err := one.With(testKey{"b"}, 2)
+		require.NoError(t, err)
+
+		back, err := two.Without(testKey{"a"})
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, empty.Size())
+		assert.Equal(t, 1, one.Size())
+		assert.Equal(t, 2, two.Size())
+		assert.Equal(t, 1, back.Size())
+
+		contains, err := back.Contains(testKey{"b"})
+		require.NoError(t, err)
+		assert.True(t, contains)
+	})
+
+	t.Run("snapshots are isolated from later changes", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewPersistentHashMap[testKey, int](hashing.Sha256)
+		require.NoError(t, m.Add(testKey{"a"}, 1))
+
+		snapshot := m.Clone()
+
+		require.NoError(t, m.Add(testKey{"a"}, 2))
+		require.NoError(t, m.Add(testKey{"b"}, 3))
+		require.NoError(t, snapshot.Remove(testKey{"a"}))
+
+		assert.Equal(t, 2, m.Size())
+		assert.Equal(t, 0, snapshot.Size())
+
+		value, _, err := m.Get(testKey{"a"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, value)
+	})
+
+	t.Run("derived maps don't affect the original", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewPersistentHashMap[testKey, int](hashing.Sha256)
+		require.NoError(t, m.Add(testKey{"a"}, 1))
+		require.NoError(t, m.Add(testKey{"b"}, 2))
+
+		other := maps.NewHashMap[testKey, int](hashing.Sha256)
+		require.NoError(t, other.Add(testKey{"b"}, 20))
+		require.NoError(t, other.Add(testKey{"c"}, 30))
+
+		union, err := m.Union(other)
+		require.NoError(t, err)
+		assert.Equal(t, 3, union.Size())
+
+		value, _, err := union.Get(testKey{"b"})
+		require.NoError(t, err)
+		assert.Equal(t, 20, value)
+
+		intersection, err := m.Intersection(other)
+		require.NoError(t, err)
+		assert.Equal(t, 1, intersection.Size())
+
+		value, _, err = intersection.Get(testKey{"b"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, value)
+
+		filtered := m.Filter(func(_ testKey, value int) bool { return value > 1 })
+		assert.Equal(t, 1, filtered.Size())
+
+		filteredNot := m.FilterNot(func(_ testKey, value int) bool { return value > 1 })
+		assert.Equal(t, 1, filteredNot.Size())
+
+		mapped := m.Map(func(key testKey, value int) (testKey, int) { return key, value * 10 })
+
+		value, _, err = mapped.Get(testKey{"a"})
+		require.NoError(t, err)
+		assert.Equal(t, 10, value)
+
+		assert.Equal(t, 2, m.Size())
+
+		value, _, err = m.Get(testKey{"b"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, value)
+
+		assert.Equal(t, 2, m.Keys().Size())
+	})
+
+	t.Run("snapshots can be read concurrently while the map changes", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewPersistentHashMap[testKey, int](hashing.Sha256)
+		for i := range 100 {
+			require.NoError(t, m.Add(testKey{fmt.Sprint(i)}, i))
+		}
+
+		snapshot := m.Clone()
+
+		var wg sync.WaitGroup
+
+		for range 4 {
+			wg.Go(func() {
+				for i := range 100 {
+					value, found, err := snapshot.Get(testKey{fmt.Sprint(i)})
+					assert.NoError(t, err)
+					assert.True(t, found)
+					assert.Equal(t, i, value)
+				}
+			})
+		}
+
+		for i := range 100 {
+			require.NoError(t, m.Remove(testKey{fmt.Sprint(i)}))
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, 0, m.Size())
+		assert.Equal(t, 100, snapshot.Size())
+	})
+}
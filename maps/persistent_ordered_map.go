@@ -0,0 +1,398 @@
+package maps //nolint:revive // Established package name; renaming would break all consumers.
+
+import (
+	"cmp"
+	"iter"
+
+	"github.com/amp-labs/amp-common/collectable"
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/internal/persistent"
+	"github.com/amp-labs/amp-common/optional"
+	"github.com/amp-labs/amp-common/set"
+	"github.com/amp-labs/amp-common/zero"
+)
+
+// NewPersistentOrderedHashMap creates an empty PersistentOrderedMap. Entries are
+// iterated in insertion order, as with NewOrderedHashMap, but Clone is O(1) and
+// adding or removing a key copies only O(log n) nodes, so earlier versions stay
+// valid and share structure with later ones.
+func NewPersistentOrderedHashMap[K collectable.Collectable[K], V any](hash hashing.HashFunc) PersistentOrderedMap[K, V] {
+	return &persistentOrderedMap[K, V]{
+		hash:  hash,
+		order: persistent.NewTree[uint64, K](cmp.Compare[uint64]),
+	}
+}
+
+var _ PersistentOrderedMap[hashing.HashableString, string] = (*persistentOrderedMap[hashing.HashableString, string])(nil)
+
+// persistentOrderedMap is the PersistentOrderedMap returned by
+// NewPersistentOrderedHashMap. A trie maps each key to its value and the
+// sequence number it was added with, and a tree keyed by sequence number keeps
+// the insertion order.
+type persistentOrderedMap[K collectable.Collectable[K], V any] struct {
+	hash    hashing.HashFunc
+	entries persistent.Trie[K, orderedValue[V]]
+	order   persistent.Tree[uint64, K]
+	next    uint64
+}
+
+// orderedValue is a value together with the position of its key in the insertion order.
+type orderedValue[V any] struct {
+	value V
+	seq   uint64
+}
+
+func (m *persistentOrderedMap[K, V]) derive() *persistentOrderedMap[K, V] {
+	out := *m
+
+	return &out
+}
+
+func (m *persistentOrderedMap[K, V]) hashOf(key K) (uint64, error) {
+	hashVal, err := m.hash(key)
+	if err != nil {
+		return 0, err
+	}
+
+	return persistent.Hash(hashVal), nil
+}
+
+// With returns a version of the map in which key maps to value. The receiver is unchanged.
+func (m *persistentOrderedMap[K, V]) With(key K, value V) (PersistentOrderedMap[K, V], error) {
+	out := m.derive()
+
+	err := out.Add(key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Without returns a version of the map without key. The receiver is unchanged.
+func (m *persistentOrderedMap[K, V]) Without(key K) (PersistentOrderedMap[K, V], error) {
+	out := m.derive()
+
+	err := out.Remove(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Get retrieves the value for the given key.
+// An error is only returned if hashing the key fails.
+func (m *persistentOrderedMap[K, V]) Get(key K) (value V, found bool, err error) {
+	h, err := m.hashOf(key)
+	if err != nil {
+		return zero.Value[V](), false, err
+	}
+
+	entry, found := m.entries.Get(h, key)
+
+	return entry.value, found, nil
+}
+
+// GetOrElse retrieves the value for the given key, or returns defaultValue if the key doesn't exist.
+// An error is only returned if hashing the key fails.
+func (m *persistentOrderedMap[K, V]) GetOrElse(key K, defaultValue V) (value V, err error) {
+	value, found, err := m.Get(key)
+	if err != nil {
+		return zero.Value[V](), err
+	}
+
+	if !found {
+		return defaultValue, nil
+	}
+
+	return value, nil
+}
+
+// Add moves the map to a version in which key maps to value. A new key is
+// appended to the insertion order; an existing key keeps its position.
+// An error is only returned if hashing the key fails.
+func (m *persistentOrderedMap[K, V]) Add(key K, value V) error {
+	h, err := m.hashOf(key)
+	if err != nil {
+		return err
+	}
+
+	if existing, found := m.entries.Get(h, key); found {
+		m.entries = m.entries.With(h, key, orderedValue[V]{value: value, seq: existing.seq})
+
+		return nil
+	}
+
+	m.entries = m.entries.With(h, key, orderedValue[V]{value: value, seq: m.next})
+	m.order = m.order.With(m.next, key)
+	m.next++
+
+	return nil
+}
+
+// Remove moves the map to a version without key.
+// An error is only returned if hashing the key fails.
+func (m *persistentOrderedMap[K, V]) Remove(key K) error {
+	h, err := m.hashOf(key)
+	if err != nil {
+		return err
+	}
+
+	existing, found := m.entries.Get(h, key)
+	if !found {
+		return nil
+	}
+
+	m.entries, _ = m.entries.Without(h, key)
+	m.order, _ = m.order.Without(existing.seq)
+
+	return nil
+}
+
+// Clear moves the map to an empty version.
+func (m *persistentOrderedMap[K, V]) Clear() {
+	m.entries = persistent.Trie[K, orderedValue[V]]{}
+	m.order = m.order.Clear()
+	m.next = 0
+}
+
+// Contains checks whether a key exists in the map.
+// An error is only returned if hashing the key fails.
+func (m *persistentOrderedMap[K, V]) Contains(key K) (bool, error) {
+	_, found, err := m.Get(key)
+
+	return found, err
+}
+
+// Size returns the number of key-value pairs in the map in O(1).
+func (m *persistentOrderedMap[K, V]) Size() int {
+	return m.entries.Len()
+}
+
+// Seq returns an iterator over all entries in insertion order. The index is
+// the entry's zero-based position in that order.
+func (m *persistentOrderedMap[K, V]) Seq() iter.Seq2[int, KeyValuePair[K, V]] {
+	return func(yield func(int, KeyValuePair[K, V]) bool) {
+		i := 0
+
+		for _, key := range m.order.All() {
+			value, _, err := m.Get(key)
+			if err != nil {
+				return
+			}
+
+			if !yield(i, KeyValuePair[K, V]{Key: key, Value: value}) {
+				return
+			}
+
+			i++
+		}
+	}
+}
+
+// Union returns a version of this map with the entries of other added. If a key
+// exists in both maps, the value from other takes precedence but the key keeps
+// its position; new keys are appended in their order in other.
+func (m *persistentOrderedMap[K, V]) Union(other OrderedMap[K, V]) (OrderedMap[K, V], error) {
+	out := m.derive()
+
+	for _, entry := range other.Seq() {
+		err := out.Add(entry.Key, entry.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// Intersection returns a version of this map without the keys that are missing
+// from other. The values and the order are taken from this map.
+func (m *persistentOrderedMap[K, V]) Intersection(other OrderedMap[K, V]) (OrderedMap[K, V], error) {
+	out := m.derive()
+
+	for _, key := range m.order.All() {
+		contains, err := other.Contains(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if !contains {
+			err = out.Remove(key)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// Difference returns a version of this map without the keys of other. The
+// order of the remaining keys is unchanged.
+func (m *persistentOrderedMap[K, V]) Difference(other OrderedMap[K, V]) (OrderedMap[K, V], error) {
+	out := m.derive()
+
+	for _, entry := range other.Seq() {
+		err := out.Remove(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// SymmetricDifference returns a version of this map without the keys it shares
+// with other, and with the remaining entries of other appended in their order
+// in other.
+func (m *persistentOrderedMap[K, V]) SymmetricDifference(other OrderedMap[K, V]) (OrderedMap[K, V], error) {
+	out := m.derive()
+
+	for _, entry := range other.Seq() {
+		contains, err := m.Contains(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		if contains {
+			err = out.Remove(entry.Key)
+		} else {
+			err = out.Add(entry.Key, entry.Value)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+func (m *persistentOrderedMap[K, V]) IsSubsetOf(other OrderedMap[K, V]) (bool, error) {
+	return isSubsetKeys(m.Size(), orderedPairs(m.Seq()), other)
+}
+
+func (m *persistentOrderedMap[K, V]) IsSupersetOf(other OrderedMap[K, V]) (bool, error) {
+	return isSubsetKeys(other.Size(), orderedPairs(other.Seq()), m)
+}
+
+func (m *persistentOrderedMap[K, V]) IsDisjoint(other OrderedMap[K, V]) (bool, error) {
+	return isDisjointKeys(orderedPairs(m.Seq()), other)
+}
+
+func (m *persistentOrderedMap[K, V]) Equals(other OrderedMap[K, V], valueEquals func(a, b V) bool) (bool, error) {
+	return isEqualMap(m.Size(), orderedPairs(m.Seq()), other, valueEquals)
+}
+
+// Clone returns a new map sharing all of its structure with this one in O(1).
+func (m *persistentOrderedMap[K, V]) Clone() OrderedMap[K, V] {
+	if m == nil {
+		return nil
+	}
+
+	return m.derive()
+}
+
+// HashFunction returns the hash function used by this map.
+func (m *persistentOrderedMap[K, V]) HashFunction() hashing.HashFunc {
+	return m.hash
+}
+
+// Keys returns a persistent ordered set containing all keys in insertion order.
+func (m *persistentOrderedMap[K, V]) Keys() set.OrderedSet[K] {
+	keys := set.NewPersistentOrderedSet[K](m.hash)
+
+	for _, key := range m.order.All() {
+		_ = keys.Add(key) // Add should not fail for existing keys
+	}
+
+	return keys
+}
+
+// ForEach applies the given function to each key-value pair in insertion order.
+func (m *persistentOrderedMap[K, V]) ForEach(f func(key K, value V)) {
+	for _, entry := range m.Seq() {
+		f(entry.Key, entry.Value)
+	}
+}
+
+// ForAll tests whether a predicate holds for all key-value pairs in the map.
+func (m *persistentOrderedMap[K, V]) ForAll(predicate func(key K, value V) bool) bool {
+	for _, entry := range m.Seq() {
+		if !predicate(entry.Key, entry.Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Filter returns a version of this map without the entries for which the
+// predicate returns false. The insertion order is preserved.
+func (m *persistentOrderedMap[K, V]) Filter(predicate func(key K, value V) bool) OrderedMap[K, V] {
+	out := m.derive()
+
+	for _, entry := range m.Seq() {
+		if !predicate(entry.Key, entry.Value) {
+			_ = out.Remove(entry.Key) // Hashing succeeded when the key was added
+		}
+	}
+
+	return out
+}
+
+// FilterNot returns a version of this map without the entries for which the
+// predicate returns true. The insertion order is preserved.
+func (m *persistentOrderedMap[K, V]) FilterNot(predicate func(key K, value V) bool) OrderedMap[K, V] {
+	return m.Filter(func(key K, value V) bool { return !predicate(key, value) })
+}
+
+// Map transforms all key-value pairs in the map by applying the given function
+// to each entry, in insertion order.
+func (m *persistentOrderedMap[K, V]) Map(f func(key K, value V) (K, V)) OrderedMap[K, V] {
+	out := NewPersistentOrderedHashMap[K, V](m.hash)
+
+	for _, entry := range m.Seq() {
+		_ = out.Add(f(entry.Key, entry.Value)) // Duplicate keys will be overwritten
+	}
+
+	return out
+}
+
+// FlatMap applies the given function to each key-value pair and merges the
+// returned maps into a new persistent ordered map. Later values take precedence.
+func (m *persistentOrderedMap[K, V]) FlatMap(f func(key K, value V) OrderedMap[K, V]) OrderedMap[K, V] {
+	out := NewPersistentOrderedHashMap[K, V](m.hash)
+
+	for _, entry := range m.Seq() {
+		for _, mapped := range f(entry.Key, entry.Value).Seq() {
+			_ = out.Add(mapped.Key, mapped.Value) // Duplicate keys will be overwritten
+		}
+	}
+
+	return out
+}
+
+// Exists tests whether at least one key-value pair in the map satisfies the given predicate.
+func (m *persistentOrderedMap[K, V]) Exists(predicate func(key K, value V) bool) bool {
+	for _, entry := range m.Seq() {
+		if predicate(entry.Key, entry.Value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FindFirst returns the earliest inserted key-value pair that satisfies the predicate.
+func (m *persistentOrderedMap[K, V]) FindFirst(predicate func(key K, value V) bool) optional.Value[KeyValuePair[K, V]] {
+	for _, entry := range m.Seq() {
+		if predicate(entry.Key, entry.Value) {
+			return optional.Some(entry)
+		}
+	}
+
+	return optional.None[KeyValuePair[K, V]]()
+}
@@ -0,0 +1,104 @@
+package maps_test
+
+import (
+	"testing"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/maps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func orderedKeys[V any](m maps.OrderedMap[testKey, V]) []string {
+	var keys []string
+	for _, entry := range m.Seq() {
+		keys = append(keys, entry.Key.value)
+	}
+
+	return keys
+}
+
+func TestPersistentOrderedHashMap(t *testing.T) {
+	t.Parallel()
+
+	newMap := func(t *testing.T) maps.PersistentOrderedMap[testKey, int] {
+		t.Helper()
+
+		m := maps.NewPersistentOrderedHashMap[testKey, int](hashing.Sha256)
+		for i, key := range []string{"c", "a", "b"} {
+			require.NoError(t, m.Add(testKey{key}, i))
+		}
+
+		return m
+	}
+
+	t.Run("keeps insertion order", func(t *testing.T) {
+		t.Parallel()
+
+		m := newMap(t)
+
+		// Updating a key keeps its position; re-adding a removed key appends it
+		require.NoError(t, m.Add(testKey{"c"}, 10))
+		require.NoError(t, m.Remove(testKey{"a"}))
+		require.NoError(t, m.Add(testKey{"a"}, 11))
+		require.NoError(t, m.Add(testKey{"d"}, 12))
+
+		assert.Equal(t, []string{"c", "b", "a", "d"}, orderedKeys[int](m))
+
+		value, _, err := m.Get(testKey{"c"})
+		require.NoError(t, err)
+		assert.Equal(t, 10, value)
+
+		assert.Equal(t, []testKey{{"c"}, {"b"}, {"a"}, {"d"}}, m.Keys().Entries())
+	})
+
+	t.Run("versions are independent", func(t *testing.T) {
+		t.Parallel()
+
+		m := newMap(t)
+
+		without, err := m.Without(testKey{"a"})
+		require.NoError(t, err)
+
+		with, err := m.With(testKey{"z"}, 99)
+		require.NoError(t, err)
+
+		snapshot := m.Clone()
+		m.Clear()
+
+		assert.Empty(t, orderedKeys[int](m))
+		assert.Equal(t, []string{"c", "a", "b"}, orderedKeys[int](snapshot))
+		assert.Equal(t, []string{"c", "b"}, orderedKeys[int](without))
+		assert.Equal(t, []string{"c", "a", "b", "z"}, orderedKeys[int](with))
+	})
+
+	t.Run("derived maps keep the order", func(t *testing.T) {
+		t.Parallel()
+
+		m := newMap(t)
+
+		other := maps.NewOrderedHashMap[testKey, int](hashing.Sha256)
+		require.NoError(t, other.Add(testKey{"d"}, 3))
+		require.NoError(t, other.Add(testKey{"a"}, 4))
+
+		union, err := m.Union(other)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c", "a", "b", "d"}, orderedKeys(union))
+
+		value, _, err := union.Get(testKey{"a"})
+		require.NoError(t, err)
+		assert.Equal(t, 4, value)
+
+		intersection, err := m.Intersection(other)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a"}, orderedKeys(intersection))
+
+		filtered := m.Filter(func(key testKey, _ int) bool { return key.value != "a" })
+		assert.Equal(t, []string{"c", "b"}, orderedKeys(filtered))
+
+		first := m.FindFirst(func(_ testKey, value int) bool { return value > 0 })
+		assert.Equal(t, "a", first.GetOrPanic().Key.value)
+
+		assert.Equal(t, []string{"c", "a", "b"}, orderedKeys[int](m))
+	})
+}
@@ -0,0 +1,327 @@
+package maps //nolint:revive // Established package name; renaming would break all consumers.
+
+import (
+	"iter"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/internal/persistent"
+	"github.com/amp-labs/amp-common/optional"
+	"github.com/amp-labs/amp-common/set"
+	"github.com/amp-labs/amp-common/sortable"
+)
+
+// NewPersistentSortedMap creates an empty PersistentSortedMap backed by a
+// path-copying balanced binary tree. Keys are kept in sorted order like in
+// NewRedBlackTreeMap, but adding or removing a key copies only the O(log n)
+// nodes on its path, so Clone is O(1) and earlier versions stay valid.
+func NewPersistentSortedMap[K sortable.Sortable[K], V any]() PersistentSortedMap[K, V] {
+	return &persistentSortedMap[K, V]{tree: persistent.NewTree[K, V](persistent.CompareSortable[K])}
+}
+
+var _ PersistentSortedMap[sortable.Int, string] = (*persistentSortedMap[sortable.Int, string])(nil)
+
+// persistentSortedMap is the PersistentSortedMap returned by NewPersistentSortedMap.
+// The tree is immutable; mutating methods swap it for a new version.
+type persistentSortedMap[K sortable.Sortable[K], V any] struct {
+	tree persistent.Tree[K, V]
+}
+
+func (m *persistentSortedMap[K, V]) derive(tree persistent.Tree[K, V]) *persistentSortedMap[K, V] {
+	return &persistentSortedMap[K, V]{tree: tree}
+}
+
+// With returns a version of the map in which key maps to value. The receiver is unchanged.
+func (m *persistentSortedMap[K, V]) With(key K, value V) (PersistentSortedMap[K, V], error) {
+	return m.derive(m.tree.With(key, value)), nil
+}
+
+// Without returns a version of the map without key. The receiver is unchanged.
+func (m *persistentSortedMap[K, V]) Without(key K) (PersistentSortedMap[K, V], error) {
+	tree, _ := m.tree.Without(key)
+
+	return m.derive(tree), nil
+}
+
+// Get retrieves the value associated with the given key.
+func (m *persistentSortedMap[K, V]) Get(key K) (value V, found bool, err error) {
+	value, found = m.tree.Get(key)
+
+	return value, found, nil
+}
+
+// GetOrElse retrieves the value for the given key, or returns defaultValue if not found.
+func (m *persistentSortedMap[K, V]) GetOrElse(key K, defaultValue V) (value V, err error) {
+	value, found := m.tree.Get(key)
+	if !found {
+		return defaultValue, nil
+	}
+
+	return value, nil
+}
+
+// Add moves the map to a version in which key maps to value.
+func (m *persistentSortedMap[K, V]) Add(key K, value V) error {
+	m.tree = m.tree.With(key, value)
+
+	return nil
+}
+
+// Remove moves the map to a version without key.
+func (m *persistentSortedMap[K, V]) Remove(key K) error {
+	m.tree, _ = m.tree.Without(key)
+
+	return nil
+}
+
+// Clear moves the map to an empty version.
+func (m *persistentSortedMap[K, V]) Clear() {
+	m.tree = m.tree.Clear()
+}
+
+// Contains checks whether a key exists in the map.
+func (m *persistentSortedMap[K, V]) Contains(key K) (bool, error) {
+	_, found := m.tree.Get(key)
+
+	return found, nil
+}
+
+// Size returns the number of key-value pairs in the map in O(1).
+func (m *persistentSortedMap[K, V]) Size() int {
+	return m.tree.Len()
+}
+
+// Seq returns an iterator over all key-value pairs in ascending key order.
+func (m *persistentSortedMap[K, V]) Seq() iter.Seq2[K, V] {
+	return m.tree.All()
+}
+
+// Union returns a version of this map with the entries of other added. If a key
+// exists in both maps, the value from other takes precedence.
+func (m *persistentSortedMap[K, V]) Union(other Map[K, V]) (Map[K, V], error) {
+	out := m.derive(m.tree)
+
+	for key, value := range other.Seq() {
+		out.tree = out.tree.With(key, value)
+	}
+
+	return out, nil
+}
+
+// Intersection returns a version of this map without the keys that are missing
+// from other. The values are taken from this map.
+func (m *persistentSortedMap[K, V]) Intersection(other Map[K, V]) (Map[K, V], error) {
+	out := m.derive(m.tree)
+
+	for key := range m.Seq() {
+		contains, err := other.Contains(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if !contains {
+			out.tree, _ = out.tree.Without(key)
+		}
+	}
+
+	return out, nil
+}
+
+// Difference returns a version of this map without the keys of other.
+func (m *persistentSortedMap[K, V]) Difference(other Map[K, V]) (Map[K, V], error) {
+	out := m.derive(m.tree)
+
+	for key := range other.Seq() {
+		out.tree, _ = out.tree.Without(key)
+	}
+
+	return out, nil
+}
+
+// SymmetricDifference returns a version of this map without the keys it shares
+// with other, and with the remaining entries of other added.
+func (m *persistentSortedMap[K, V]) SymmetricDifference(other Map[K, V]) (Map[K, V], error) {
+	out := m.derive(m.tree)
+
+	for key, value := range other.Seq() {
+		if _, found := m.tree.Get(key); found {
+			out.tree, _ = out.tree.Without(key)
+		} else {
+			out.tree = out.tree.With(key, value)
+		}
+	}
+
+	return out, nil
+}
+
+func (m *persistentSortedMap[K, V]) IsSubsetOf(other Map[K, V]) (bool, error) {
+	return isSubsetKeys(m.Size(), m.Seq(), other)
+}
+
+func (m *persistentSortedMap[K, V]) IsSupersetOf(other Map[K, V]) (bool, error) {
+	return isSubsetKeys(other.Size(), other.Seq(), m)
+}
+
+func (m *persistentSortedMap[K, V]) IsDisjoint(other Map[K, V]) (bool, error) {
+	return isDisjointKeys(m.Seq(), other)
+}
+
+func (m *persistentSortedMap[K, V]) Equals(other Map[K, V], valueEquals func(a, b V) bool) (bool, error) {
+	return isEqualMap(m.Size(), m.Seq(), other, valueEquals)
+}
+
+// Clone returns a new map sharing all of its structure with this one in O(1).
+func (m *persistentSortedMap[K, V]) Clone() Map[K, V] {
+	if m == nil {
+		return nil
+	}
+
+	return m.derive(m.tree)
+}
+
+// HashFunction returns nil as sorted maps order keys instead of hashing them.
+func (m *persistentSortedMap[K, V]) HashFunction() hashing.HashFunc {
+	return nil
+}
+
+// Keys returns a persistent sorted set containing all keys in the map.
+func (m *persistentSortedMap[K, V]) Keys() set.Set[K] {
+	keys := set.NewPersistentSortedSet[K]()
+
+	for key := range m.Seq() {
+		_ = keys.Add(key)
+	}
+
+	return keys
+}
+
+// ForEach applies the given function to each key-value pair in ascending key order.
+func (m *persistentSortedMap[K, V]) ForEach(f func(key K, value V)) {
+	for key, value := range m.Seq() {
+		f(key, value)
+	}
+}
+
+// ForAll tests whether a predicate holds for all key-value pairs in the map.
+func (m *persistentSortedMap[K, V]) ForAll(predicate func(key K, value V) bool) bool {
+	for key, value := range m.Seq() {
+		if !predicate(key, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Filter returns a version of this map without the entries for which the predicate returns false.
+func (m *persistentSortedMap[K, V]) Filter(predicate func(key K, value V) bool) Map[K, V] {
+	out := m.derive(m.tree)
+
+	for key, value := range m.Seq() {
+		if !predicate(key, value) {
+			out.tree, _ = out.tree.Without(key)
+		}
+	}
+
+	return out
+}
+
+// FilterNot returns a version of this map without the entries for which the predicate returns true.
+func (m *persistentSortedMap[K, V]) FilterNot(predicate func(key K, value V) bool) Map[K, V] {
+	return m.Filter(func(key K, value V) bool { return !predicate(key, value) })
+}
+
+// Map transforms all key-value pairs in the map by applying the given function to each entry.
+func (m *persistentSortedMap[K, V]) Map(f func(key K, value V) (K, V)) Map[K, V] {
+	out := m.derive(m.tree.Clear())
+
+	for key, value := range m.Seq() {
+		out.tree = out.tree.With(f(key, value))
+	}
+
+	return out
+}
+
+// FlatMap applies the given function to each key-value pair and merges the
+// returned maps into a new persistent sorted map. Later values take precedence.
+func (m *persistentSortedMap[K, V]) FlatMap(f func(key K, value V) Map[K, V]) Map[K, V] {
+	out := m.derive(m.tree.Clear())
+
+	for key, value := range m.Seq() {
+		for newKey, newValue := range f(key, value).Seq() {
+			out.tree = out.tree.With(newKey, newValue)
+		}
+	}
+
+	return out
+}
+
+// Exists tests whether at least one key-value pair in the map satisfies the given predicate.
+func (m *persistentSortedMap[K, V]) Exists(predicate func(key K, value V) bool) bool {
+	for key, value := range m.Seq() {
+		if predicate(key, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FindFirst returns the entry with the smallest key that satisfies the predicate.
+func (m *persistentSortedMap[K, V]) FindFirst(predicate func(key K, value V) bool) optional.Value[KeyValuePair[K, V]] {
+	for key, value := range m.Seq() {
+		if predicate(key, value) {
+			return optional.Some(KeyValuePair[K, V]{Key: key, Value: value})
+		}
+	}
+
+	return optional.None[KeyValuePair[K, V]]()
+}
+
+func (m *persistentSortedMap[K, V]) Min() optional.Value[KeyValuePair[K, V]] {
+	return pairOf(m.tree.Min())
+}
+
+func (m *persistentSortedMap[K, V]) Max() optional.Value[KeyValuePair[K, V]] {
+	return pairOf(m.tree.Max())
+}
+
+func (m *persistentSortedMap[K, V]) Floor(key K) optional.Value[KeyValuePair[K, V]] {
+	return pairOf(m.tree.Floor(key, true))
+}
+
+func (m *persistentSortedMap[K, V]) Ceiling(key K) optional.Value[KeyValuePair[K, V]] {
+	return pairOf(m.tree.Ceiling(key, true))
+}
+
+func (m *persistentSortedMap[K, V]) Lower(key K) optional.Value[KeyValuePair[K, V]] {
+	return pairOf(m.tree.Floor(key, false))
+}
+
+func (m *persistentSortedMap[K, V]) Higher(key K) optional.Value[KeyValuePair[K, V]] {
+	return pairOf(m.tree.Ceiling(key, false))
+}
+
+func (m *persistentSortedMap[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return m.tree.Range(from, to)
+}
+
+func (m *persistentSortedMap[K, V]) SeqDescending() iter.Seq2[K, V] {
+	return m.tree.Descending()
+}
+
+func (m *persistentSortedMap[K, V]) Rank(key K) int {
+	return m.tree.Rank(key)
+}
+
+func (m *persistentSortedMap[K, V]) Select(index int) optional.Value[KeyValuePair[K, V]] {
+	return pairOf(m.tree.Select(index))
+}
+
+// pairOf converts the result of a tree lookup to an optional KeyValuePair.
+func pairOf[K any, V any](entry persistent.Entry[K, V], found bool) optional.Value[KeyValuePair[K, V]] {
+	if !found {
+		return optional.None[KeyValuePair[K, V]]()
+	}
+
+	return optional.Some(KeyValuePair[K, V]{Key: entry.Key, Value: entry.Value})
+}
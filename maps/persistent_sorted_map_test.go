@@ -0,0 +1,125 @@
+package maps_test
+
+import (
+	"testing"
+
+	"github.com/amp-labs/amp-common/maps"
+	"github.com/amp-labs/amp-common/sortable"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentSortedMap(t *testing.T) {
+	t.Parallel()
+
+	newMap := func(t *testing.T) maps.PersistentSortedMap[sortable.Int, string] {
+		t.Helper()
+
+		m := maps.NewPersistentSortedMap[sortable.Int, string]()
+		for _, key := range []sortable.Int{30, 10, 40, 20} {
+			require.NoError(t, m.Add(key, string(rune('a'+int(key)/10-1))))
+		}
+
+		return m
+	}
+
+	t.Run("iterates in key order", func(t *testing.T) {
+		t.Parallel()
+
+		m := newMap(t)
+
+		var keys []sortable.Int
+		for key := range m.Seq() {
+			keys = append(keys, key)
+		}
+
+		assert.Equal(t, []sortable.Int{10, 20, 30, 40}, keys)
+
+		keys = nil
+		for key := range m.SeqDescending() {
+			keys = append(keys, key)
+		}
+
+		assert.Equal(t, []sortable.Int{40, 30, 20, 10}, keys)
+
+		keys = nil
+		for key := range m.Range(15, 40) {
+			keys = append(keys, key)
+		}
+
+		assert.Equal(t, []sortable.Int{20, 30}, keys)
+	})
+
+	t.Run("answers ordered queries", func(t *testing.T) {
+		t.Parallel()
+
+		m := newMap(t)
+
+		assert.Equal(t, sortable.Int(10), m.Min().GetOrPanic().Key)
+		assert.Equal(t, sortable.Int(40), m.Max().GetOrPanic().Key)
+		assert.Equal(t, sortable.Int(20), m.Floor(25).GetOrPanic().Key)
+		assert.Equal(t, sortable.Int(30), m.Ceiling(25).GetOrPanic().Key)
+		assert.Equal(t, sortable.Int(10), m.Lower(20).GetOrPanic().Key)
+		assert.Equal(t, sortable.Int(30), m.Higher(20).GetOrPanic().Key)
+		assert.True(t, m.Lower(10).Empty())
+		assert.True(t, m.Higher(40).Empty())
+		assert.Equal(t, 2, m.Rank(30))
+		assert.Equal(t, "c", m.Select(2).GetOrPanic().Value)
+		assert.True(t, m.Select(4).Empty())
+	})
+
+	t.Run("versions are independent", func(t *testing.T) {
+		t.Parallel()
+
+		m := newMap(t)
+
+		without, err := m.Without(20)
+		require.NoError(t, err)
+
+		with, err := m.With(50, "e")
+		require.NoError(t, err)
+
+		snapshot := m.Clone()
+		m.Clear()
+
+		assert.Equal(t, 0, m.Size())
+		assert.Equal(t, 4, snapshot.Size())
+		assert.Equal(t, 3, without.Size())
+		assert.Equal(t, 5, with.Size())
+		assert.Equal(t, 2, without.Rank(40))
+		assert.Equal(t, sortable.Int(50), with.Max().GetOrPanic().Key)
+	})
+
+	t.Run("derived maps don't affect the original", func(t *testing.T) {
+		t.Parallel()
+
+		m := newMap(t)
+
+		other := maps.NewRedBlackTreeMap[sortable.Int, string]()
+		require.NoError(t, other.Add(20, "x"))
+		require.NoError(t, other.Add(50, "y"))
+
+		union, err := m.Union(other)
+		require.NoError(t, err)
+		assert.Equal(t, 5, union.Size())
+
+		value, _, err := union.Get(20)
+		require.NoError(t, err)
+		assert.Equal(t, "x", value)
+
+		intersection, err := m.Intersection(other)
+		require.NoError(t, err)
+		assert.Equal(t, 1, intersection.Size())
+
+		filtered := m.Filter(func(key sortable.Int, _ string) bool { return key > 20 })
+		assert.Equal(t, 2, filtered.Size())
+
+		assert.Equal(t, 4, m.Size())
+
+		value, _, err = m.Get(20)
+		require.NoError(t, err)
+		assert.Equal(t, "b", value)
+
+		assert.Equal(t, []sortable.Int{10, 20, 30, 40}, m.Keys().Entries())
+	})
+}
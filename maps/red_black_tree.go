@@ -66,11 +66,13 @@ const (
 
 // rbtNode represents a single node in the red-black tree.
 // Each node stores a key-value pair, maintains pointers to its children and parent,
-// and tracks its color for tree balancing.
+// and tracks its color for tree balancing. The size of the subtree rooted at the node
+// is kept up to date so that Size, Rank and Select don't need to walk the tree.
 type rbtNode[K sortable.Sortable[K], V any] struct {
 	key    K
 	value  V
 	color  color
+	size   int
 	left   *rbtNode[K, V]
 	right  *rbtNode[K, V]
 	parent *rbtNode[K, V]
@@ -96,6 +98,21 @@ func (n *rbtNode[K, V]) Color() color {
 	return n.color
 }
 
+// Size returns the number of nodes in the subtree rooted at this node.
+// A nil node has size zero.
+func (n *rbtNode[K, V]) Size() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.size
+}
+
+// resize recomputes the subtree size of this node from its children.
+func (n *rbtNode[K, V]) resize() {
+	n.size = n.left.Size() + n.right.Size() + 1
+}
+
 // redBlackTreeMap is a self-balancing binary search tree implementation of the Map interface.
 // It maintains O(log n) performance for insertions, deletions, and lookups by enforcing
 // red-black tree properties:
@@ -206,6 +223,9 @@ func (t *redBlackTreeMap[K, V]) rotateRight(y *rbtNode[K, V]) {
 
 	x.right = y
 	y.parent = x
+
+	x.size = y.size
+	y.resize()
 }
 
 // rotateLeft performs a left rotation around node x.
@@ -248,6 +268,9 @@ func (t *redBlackTreeMap[K, V]) rotateLeft(x *rbtNode[K, V]) {
 
 	y.left = x
 	x.parent = y
+
+	y.size = x.size
+	x.resize()
 }
 
 // Get retrieves the value associated with the given key.
@@ -280,7 +303,7 @@ func (t *redBlackTreeMap[K, V]) GetOrElse(key K, defaultValue V) (value V, err e
 // After insertion, the tree is rebalanced to maintain red-black properties.
 func (t *redBlackTreeMap[K, V]) Add(key K, value V) error {
 	if t.root == nil {
-		t.root = &rbtNode[K, V]{key: key, color: black, value: value}
+		t.root = &rbtNode[K, V]{key: key, color: black, value: value, size: 1}
 
 		return nil
 	}
@@ -302,7 +325,7 @@ func (t *redBlackTreeMap[K, V]) Add(key K, value V) error {
 		}
 	} else {
 		if parent != nil {
-			newNode := &rbtNode[K, V]{key: key, parent: parent, value: value}
+			newNode := &rbtNode[K, V]{key: key, parent: parent, value: value, size: 1}
 
 			switch dir {
 			case left:
@@ -314,6 +337,10 @@ func (t *redBlackTreeMap[K, V]) Add(key K, value V) error {
 				panic("unhandled default case")
 			}
 
+			for n := parent; n != nil; n = n.parent {
+				n.size++
+			}
+
 			t.fixupPut(newNode)
 		}
 	}
@@ -340,23 +367,38 @@ func (t *redBlackTreeMap[K, V]) Remove(key K) error {
 
 	var x *rbtNode[K, V] //nolint:varnamelen // Standard red-black tree variable names from CLRS
 
+	// xParent is the parent of x's position. x may be nil, so its parent
+	// can't be read from x itself during the fixup.
+	var xParent *rbtNode[K, V]
+
 	switch {
 	case z.left == nil:
 		x = z.right
+		xParent = z.parent
+		shrinkAncestors(z)
 		t.transplant(z, z.right)
 	case z.right == nil:
 		x = z.left
+		xParent = z.parent
+		shrinkAncestors(z)
 		t.transplant(z, z.left)
 	default:
 		y = t.getMinimum(z.right)
 		yOriginalColor = y.color
 		x = y.right
 
+		// y is spliced out of its position and takes z's place, so everything
+		// above y (including z) loses one descendant.
+		shrinkAncestors(y)
+
 		if y.parent == z {
+			xParent = y
+
 			if x != nil {
 				x.parent = y
 			}
 		} else {
+			xParent = y.parent
 			t.transplant(y, y.right)
 			y.right = z.right
 			y.right.parent = y
@@ -367,10 +409,11 @@ func (t *redBlackTreeMap[K, V]) Remove(key K) error {
 		y.left = z.left
 		y.left.parent = y
 		y.color = z.color
+		y.size = z.size
 	}
 
 	if yOriginalColor == black {
-		t.fixupDelete(x)
+		t.fixupDelete(x, xParent)
 	}
 
 	return nil
@@ -388,34 +431,10 @@ func (t *redBlackTreeMap[K, V]) Contains(key K) (bool, error) {
 	return found, nil
 }
 
-// countingVisitor is a visitor implementation that counts the number of nodes in the tree.
-// It traverses the entire tree in-order and increments the count for each node.
-type countingVisitor[K sortable.Sortable[K], V any] struct {
-	Count int
-}
-
-// Visit recursively traverses the tree in-order (left, current, right) and counts nodes.
-func (v *countingVisitor[K, V]) Visit(node *rbtNode[K, V]) bool {
-	if node == nil {
-		return true
-	}
-
-	if !v.Visit(node.left) {
-		return false
-	}
-
-	v.Count++
-
-	return v.Visit(node.right)
-}
-
 // Size returns the number of key-value pairs in the map.
-// It traverses the entire tree to count nodes, so this is O(n).
+// Every node tracks the size of its subtree, so this is O(1).
 func (t *redBlackTreeMap[K, V]) Size() int {
-	vis := &countingVisitor[K, V]{}
-	t.walk(vis)
-
-	return vis.Count
+	return t.root.Size()
 }
 
 // seqVisitor is a visitor implementation that yields key-value pairs in sorted order.
@@ -496,6 +515,57 @@ func (t *redBlackTreeMap[K, V]) Intersection(other Map[K, V]) (Map[K, V], error)
 	return out, nil
 }
 
+// Difference returns a new map containing the entries of this map whose keys are not in the other map.
+// Time complexity: O(n log m) where n is the size of this map and m is the size of the other map.
+func (t *redBlackTreeMap[K, V]) Difference(other Map[K, V]) (Map[K, V], error) {
+	result := NewRedBlackTreeMap[K, V]()
+
+	err := addMissingKeys(t.Seq(), other, result.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SymmetricDifference returns a new map containing the entries whose keys are in exactly one of the two maps.
+// Time complexity: O((n + m) log(n + m)) where n and m are the sizes of the two maps.
+func (t *redBlackTreeMap[K, V]) SymmetricDifference(other Map[K, V]) (Map[K, V], error) {
+	result := NewRedBlackTreeMap[K, V]()
+
+	err := addMissingKeys(t.Seq(), other, result.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	err = addMissingKeys(other.Seq(), t, result.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// IsSubsetOf reports whether every key of this map is also in other.
+func (t *redBlackTreeMap[K, V]) IsSubsetOf(other Map[K, V]) (bool, error) {
+	return isSubsetKeys(t.Size(), t.Seq(), other)
+}
+
+// IsSupersetOf reports whether every key of other is also in this map.
+func (t *redBlackTreeMap[K, V]) IsSupersetOf(other Map[K, V]) (bool, error) {
+	return isSubsetKeys(other.Size(), other.Seq(), t)
+}
+
+// IsDisjoint reports whether the two maps have no keys in common.
+func (t *redBlackTreeMap[K, V]) IsDisjoint(other Map[K, V]) (bool, error) {
+	return isDisjointKeys(t.Seq(), other)
+}
+
+// Equals reports whether both maps have the same keys with equal values.
+func (t *redBlackTreeMap[K, V]) Equals(other Map[K, V], valueEquals func(a, b V) bool) (bool, error) {
+	return isEqualMap(t.Size(), t.Seq(), other, valueEquals)
+}
+
 // Clone returns a shallow copy of the map with the same key-value pairs.
 func (t *redBlackTreeMap[K, V]) Clone() Map[K, V] {
 	cloned := NewRedBlackTreeMap[K, V]()
@@ -652,10 +722,19 @@ func (t *redBlackTreeMap[K, V]) walk(visitor visitor[K, V]) {
 
 // NewRedBlackTreeMap creates a new empty red-black tree map.
 // The map maintains O(log n) performance for all operations by keeping the tree balanced.
-func NewRedBlackTreeMap[K sortable.Sortable[K], V any]() Map[K, V] {
+// The returned SortedMap can also be used wherever a Map is expected.
+func NewRedBlackTreeMap[K sortable.Sortable[K], V any]() SortedMap[K, V] {
 	return &redBlackTreeMap[K, V]{}
 }
 
+// shrinkAncestors decrements the subtree size of every ancestor of n.
+// It is called when n is about to be unlinked from its current position.
+func shrinkAncestors[K sortable.Sortable[K], V any](n *rbtNode[K, V]) {
+	for p := n.parent; p != nil; p = p.parent {
+		p.size--
+	}
+}
+
 // isRed returns true if the node is red, false if the node is black or nil.
 // nil nodes are considered black by red-black tree convention.
 func isRed[K sortable.Sortable[K], V any](n *rbtNode[K, V]) bool {
@@ -742,83 +821,86 @@ loop:
 //
 // The method is more complex than fixupPut because deletion affects black-height,
 // requiring careful handling of all cases to maintain tree balance.
+//
+// x may be nil (an empty subtree standing in for the removed node), which is why its
+// parent is passed in separately. A nil x still carries the extra black and is fixed up.
 // nolint:varnamelen,dupl // Standard red-black tree variable names; symmetric cases
-func (t *redBlackTreeMap[K, V]) fixupDelete(x *rbtNode[K, V]) {
-	if x == nil {
-		return
-	}
+func (t *redBlackTreeMap[K, V]) fixupDelete(x, parent *rbtNode[K, V]) {
+	for x != t.root && !isRed(x) {
+		if parent == nil {
+			break
+		}
 
-loop:
-	for {
-		switch {
-		case x == t.root:
-			break loop
-		case x.color == red:
-			break loop
-		case x == x.parent.right:
-			w := x.parent.left //nolint:varnamelen // Standard red-black tree variable names from CLRS
+		if x == parent.left {
+			w := parent.right //nolint:varnamelen // Standard red-black tree variable names from CLRS
 			if isRed(w) {
 				w.color = black
-				x.parent.color = red
-				t.rotateRight(x.parent)
-				w = x.parent.left
+				parent.color = red
+				t.rotateLeft(parent)
+				w = parent.right
 			}
 
-			if w != nil {
-				switch {
-				case !isRed(w.left) && !isRed(w.right):
+			if w == nil || (!isRed(w.left) && !isRed(w.right)) {
+				if w != nil {
 					w.color = red
-					x = x.parent // recurse up tree
-				case isRed(w.right) && !isRed(w.left):
-					w.right.color = black
-					w.color = red
-					t.rotateLeft(w)
-					w = x.parent.left
 				}
 
-				if isRed(w.left) {
-					w.color = x.parent.color
-					x.parent.color = black
-					w.left.color = black
+				x, parent = parent, parent.parent // recurse up tree
 
-					t.rotateRight(x.parent)
-					x = t.root
-				}
+				continue
 			}
-		case x == x.parent.left:
-			w := x.parent.right //nolint:varnamelen // Standard red-black tree variable names from CLRS
+
+			if !isRed(w.right) {
+				w.left.color = black
+				w.color = red
+				t.rotateRight(w)
+				w = parent.right
+			}
+
+			w.color = parent.color
+			parent.color = black
+			w.right.color = black
+			t.rotateLeft(parent)
+
+			x, parent = t.root, nil
+		} else {
+			w := parent.left //nolint:varnamelen // Standard red-black tree variable names from CLRS
 			if isRed(w) {
 				w.color = black
-				x.parent.color = red
-				t.rotateLeft(x.parent)
-				w = x.parent.right
+				parent.color = red
+				t.rotateRight(parent)
+				w = parent.left
 			}
 
-			if w != nil {
-				switch {
-				case !isRed(w.left) && !isRed(w.right):
+			if w == nil || (!isRed(w.left) && !isRed(w.right)) {
+				if w != nil {
 					w.color = red
-					x = x.parent // recurse up tree
-				case isRed(w.left) && !isRed(w.right):
-					w.left.color = black
-					w.color = red
-					t.rotateRight(w)
-					w = x.parent.right
 				}
 
-				if isRed(w.right) {
-					w.color = x.parent.color
-					x.parent.color = black
-					w.right.color = black
+				x, parent = parent, parent.parent // recurse up tree
 
-					t.rotateLeft(x.parent)
-					x = t.root
-				}
+				continue
 			}
+
+			if !isRed(w.left) {
+				w.right.color = black
+				w.color = red
+				t.rotateLeft(w)
+				w = parent.left
+			}
+
+			w.color = parent.color
+			parent.color = black
+			w.left.color = black
+			t.rotateRight(parent)
+
+			x, parent = t.root, nil
 		}
 	}
 
-	x.color = black
+	if x != nil {
+		x.color = black
+	}
 }
 
 // getMinimum returns the node with the minimum key in the subtree rooted at x.
@@ -833,3 +915,206 @@ func (t *redBlackTreeMap[K, V]) getMinimum(x *rbtNode[K, V]) *rbtNode[K, V] {
 		}
 	}
 }
+
+// getMaximum returns the node with the maximum key in the subtree rooted at x.
+// This is always the rightmost node in the subtree.
+func (t *redBlackTreeMap[K, V]) getMaximum(x *rbtNode[K, V]) *rbtNode[K, V] {
+	for x.right != nil {
+		x = x.right
+	}
+
+	return x
+}
+
+// successor returns the node with the next larger key, or nil if n holds the largest key.
+func (t *redBlackTreeMap[K, V]) successor(n *rbtNode[K, V]) *rbtNode[K, V] {
+	if n.right != nil {
+		return t.getMinimum(n.right)
+	}
+
+	p := n.parent
+	for p != nil && n == p.right {
+		n, p = p, p.parent
+	}
+
+	return p
+}
+
+// predecessor returns the node with the next smaller key, or nil if n holds the smallest key.
+func (t *redBlackTreeMap[K, V]) predecessor(n *rbtNode[K, V]) *rbtNode[K, V] {
+	if n.left != nil {
+		return t.getMaximum(n.left)
+	}
+
+	p := n.parent
+	for p != nil && n == p.left {
+		n, p = p, p.parent
+	}
+
+	return p
+}
+
+// floorNode returns the node with the greatest key less than or equal to key,
+// or the greatest key strictly less than key if inclusive is false.
+// Returns nil if there is no such node.
+func (t *redBlackTreeMap[K, V]) floorNode(key K, inclusive bool) *rbtNode[K, V] {
+	var candidate *rbtNode[K, V]
+
+	for n := t.root; n != nil; {
+		switch {
+		case inclusive && key.Equals(n.key):
+			return n
+		case n.key.LessThan(key):
+			candidate = n
+			n = n.right
+		default:
+			n = n.left
+		}
+	}
+
+	return candidate
+}
+
+// ceilingNode returns the node with the smallest key greater than or equal to key,
+// or the smallest key strictly greater than key if inclusive is false.
+// Returns nil if there is no such node.
+func (t *redBlackTreeMap[K, V]) ceilingNode(key K, inclusive bool) *rbtNode[K, V] {
+	var candidate *rbtNode[K, V]
+
+	for n := t.root; n != nil; {
+		switch {
+		case inclusive && key.Equals(n.key):
+			return n
+		case key.LessThan(n.key):
+			candidate = n
+			n = n.left
+		default:
+			n = n.right
+		}
+	}
+
+	return candidate
+}
+
+// entryOf wraps the node's key and value in an optional, returning None for a nil node.
+func entryOf[K sortable.Sortable[K], V any](n *rbtNode[K, V]) optional.Value[KeyValuePair[K, V]] {
+	if n == nil {
+		return optional.None[KeyValuePair[K, V]]()
+	}
+
+	return optional.Some(KeyValuePair[K, V]{Key: n.key, Value: n.value})
+}
+
+// Min returns the entry with the smallest key, or None if the map is empty.
+// Time complexity: O(log n).
+func (t *redBlackTreeMap[K, V]) Min() optional.Value[KeyValuePair[K, V]] {
+	if t.root == nil {
+		return optional.None[KeyValuePair[K, V]]()
+	}
+
+	return entryOf(t.getMinimum(t.root))
+}
+
+// Max returns the entry with the largest key, or None if the map is empty.
+// Time complexity: O(log n).
+func (t *redBlackTreeMap[K, V]) Max() optional.Value[KeyValuePair[K, V]] {
+	if t.root == nil {
+		return optional.None[KeyValuePair[K, V]]()
+	}
+
+	return entryOf(t.getMaximum(t.root))
+}
+
+// Floor returns the entry with the greatest key less than or equal to key.
+// Time complexity: O(log n).
+func (t *redBlackTreeMap[K, V]) Floor(key K) optional.Value[KeyValuePair[K, V]] {
+	return entryOf(t.floorNode(key, true))
+}
+
+// Ceiling returns the entry with the smallest key greater than or equal to key.
+// Time complexity: O(log n).
+func (t *redBlackTreeMap[K, V]) Ceiling(key K) optional.Value[KeyValuePair[K, V]] {
+	return entryOf(t.ceilingNode(key, true))
+}
+
+// Lower returns the entry with the greatest key strictly less than key.
+// Time complexity: O(log n).
+func (t *redBlackTreeMap[K, V]) Lower(key K) optional.Value[KeyValuePair[K, V]] {
+	return entryOf(t.floorNode(key, false))
+}
+
+// Higher returns the entry with the smallest key strictly greater than key.
+// Time complexity: O(log n).
+func (t *redBlackTreeMap[K, V]) Higher(key K) optional.Value[KeyValuePair[K, V]] {
+	return entryOf(t.ceilingNode(key, false))
+}
+
+// Range returns an iterator over the entries whose keys fall in [from, to), in ascending order.
+// Time complexity: O(log n + m) where m is the number of entries yielded.
+func (t *redBlackTreeMap[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := t.ceilingNode(from, true); n != nil && n.key.LessThan(to); n = t.successor(n) {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// SeqDescending returns an iterator over the map's key-value pairs in descending key order.
+func (t *redBlackTreeMap[K, V]) SeqDescending() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if t.root == nil {
+			return
+		}
+
+		for n := t.getMaximum(t.root); n != nil; n = t.predecessor(n) {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Rank returns the number of keys in the map that are strictly less than key.
+// If key is present, this is its zero-based index in sorted order.
+// Time complexity: O(log n).
+func (t *redBlackTreeMap[K, V]) Rank(key K) int {
+	rank := 0
+
+	for n := t.root; n != nil; {
+		if n.key.LessThan(key) {
+			rank += n.left.Size() + 1
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+
+	return rank
+}
+
+// Select returns the entry at the given zero-based index in sorted order,
+// or None if the index is out of range.
+// Time complexity: O(log n).
+func (t *redBlackTreeMap[K, V]) Select(index int) optional.Value[KeyValuePair[K, V]] {
+	if index < 0 || index >= t.Size() {
+		return optional.None[KeyValuePair[K, V]]()
+	}
+
+	n := t.root
+
+	for {
+		leftSize := n.left.Size()
+
+		switch {
+		case index < leftSize:
+			n = n.left
+		case index == leftSize:
+			return entryOf(n)
+		default:
+			index -= leftSize + 1
+			n = n.right
+		}
+	}
+}
@@ -2,6 +2,8 @@ package maps_test
 
 import (
 	"fmt"
+	"math/rand/v2"
+	"slices"
 	"testing"
 
 	"github.com/amp-labs/amp-common/maps"
@@ -994,3 +996,231 @@ func TestRedBlackTreeMap_StringKeys(t *testing.T) {
 		}
 	})
 }
+
+// newSortedTestMap returns a red-black tree map holding the keys 10, 20, ..., 50
+// with each value set to the string form of its key.
+func newSortedTestMap(t *testing.T) maps.SortedMap[sortable.Int, string] {
+	t.Helper()
+
+	m := maps.NewRedBlackTreeMap[sortable.Int, string]()
+
+	for _, k := range []int{30, 10, 50, 20, 40} {
+		require.NoError(t, m.Add(sortable.Int(k), fmt.Sprint(k)))
+	}
+
+	return m
+}
+
+func TestRedBlackTreeMap_MinMax(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns none for empty map", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewRedBlackTreeMap[sortable.Int, string]()
+		assert.True(t, m.Min().Empty())
+		assert.True(t, m.Max().Empty())
+	})
+
+	t.Run("returns smallest and largest entries", func(t *testing.T) {
+		t.Parallel()
+
+		m := newSortedTestMap(t)
+
+		minEntry, ok := m.Min().Get()
+		require.True(t, ok)
+		assert.Equal(t, sortable.Int(10), minEntry.Key)
+		assert.Equal(t, "10", minEntry.Value)
+
+		maxEntry, ok := m.Max().Get()
+		require.True(t, ok)
+		assert.Equal(t, sortable.Int(50), maxEntry.Key)
+		assert.Equal(t, "50", maxEntry.Value)
+	})
+}
+
+func TestRedBlackTreeMap_FloorCeiling(t *testing.T) {
+	t.Parallel()
+
+	m := newSortedTestMap(t)
+
+	keyOf := func(entry maps.KeyValuePair[sortable.Int, string], ok bool) int {
+		if !ok {
+			return -1
+		}
+
+		return int(entry.Key)
+	}
+
+	tests := []struct {
+		key                           int
+		floor, ceiling, lower, higher int
+	}{
+		{key: 5, floor: -1, ceiling: 10, lower: -1, higher: 10},
+		{key: 10, floor: 10, ceiling: 10, lower: -1, higher: 20},
+		{key: 25, floor: 20, ceiling: 30, lower: 20, higher: 30},
+		{key: 50, floor: 50, ceiling: 50, lower: 40, higher: -1},
+		{key: 55, floor: 50, ceiling: -1, lower: 50, higher: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.key), func(t *testing.T) {
+			t.Parallel()
+
+			key := sortable.Int(tt.key)
+			assert.Equal(t, tt.floor, keyOf(m.Floor(key).Get()), "floor")
+			assert.Equal(t, tt.ceiling, keyOf(m.Ceiling(key).Get()), "ceiling")
+			assert.Equal(t, tt.lower, keyOf(m.Lower(key).Get()), "lower")
+			assert.Equal(t, tt.higher, keyOf(m.Higher(key).Get()), "higher")
+		})
+	}
+}
+
+func TestRedBlackTreeMap_Range(t *testing.T) {
+	t.Parallel()
+
+	m := newSortedTestMap(t)
+
+	collect := func(from, to int) []int {
+		var keys []int
+
+		for k, v := range m.Range(sortable.Int(from), sortable.Int(to)) {
+			assert.Equal(t, fmt.Sprint(int(k)), v)
+
+			keys = append(keys, int(k))
+		}
+
+		return keys
+	}
+
+	assert.Equal(t, []int{20, 30, 40}, collect(20, 50))
+	assert.Equal(t, []int{20, 30}, collect(15, 35))
+	assert.Equal(t, []int{10, 20, 30, 40, 50}, collect(0, 100))
+	assert.Empty(t, collect(31, 39))
+	assert.Empty(t, collect(40, 20))
+
+	t.Run("stops early", func(t *testing.T) {
+		t.Parallel()
+
+		count := 0
+
+		for range m.Range(sortable.Int(0), sortable.Int(100)) {
+			count++
+			if count == 2 {
+				break
+			}
+		}
+
+		assert.Equal(t, 2, count)
+	})
+}
+
+func TestRedBlackTreeMap_SeqDescending(t *testing.T) {
+	t.Parallel()
+
+	t.Run("yields keys in descending order", func(t *testing.T) {
+		t.Parallel()
+
+		m := newSortedTestMap(t)
+
+		var keys []int
+		for k := range m.SeqDescending() {
+			keys = append(keys, int(k))
+		}
+
+		assert.Equal(t, []int{50, 40, 30, 20, 10}, keys)
+	})
+
+	t.Run("yields nothing for empty map", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewRedBlackTreeMap[sortable.Int, string]()
+
+		for range m.SeqDescending() {
+			t.Fatal("unexpected entry")
+		}
+	})
+}
+
+func TestRedBlackTreeMap_RankSelect(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ranks and selects by position", func(t *testing.T) {
+		t.Parallel()
+
+		m := newSortedTestMap(t)
+
+		assert.Equal(t, 0, m.Rank(sortable.Int(5)))
+		assert.Equal(t, 0, m.Rank(sortable.Int(10)))
+		assert.Equal(t, 2, m.Rank(sortable.Int(25)))
+		assert.Equal(t, 4, m.Rank(sortable.Int(50)))
+		assert.Equal(t, 5, m.Rank(sortable.Int(99)))
+
+		entry, ok := m.Select(2).Get()
+		require.True(t, ok)
+		assert.Equal(t, sortable.Int(30), entry.Key)
+
+		assert.True(t, m.Select(-1).Empty())
+		assert.True(t, m.Select(5).Empty())
+	})
+
+	t.Run("stays consistent under random adds and removes", func(t *testing.T) {
+		t.Parallel()
+
+		m := maps.NewRedBlackTreeMap[sortable.Int, int]()
+		rng := rand.New(rand.NewPCG(1, 2)) //nolint:gosec // Deterministic test data
+
+		var expected []int
+
+		for range 2000 {
+			key := rng.IntN(300)
+
+			idx, present := slices.BinarySearch(expected, key)
+			if rng.IntN(3) == 0 {
+				require.NoError(t, m.Remove(sortable.Int(key)))
+
+				if present {
+					expected = slices.Delete(expected, idx, idx+1)
+				}
+			} else {
+				require.NoError(t, m.Add(sortable.Int(key), key))
+
+				if !present {
+					expected = slices.Insert(expected, idx, key)
+				}
+			}
+
+			require.Equal(t, len(expected), m.Size())
+		}
+
+		for i, key := range expected {
+			entry, ok := m.Select(i).Get()
+			require.True(t, ok)
+			assert.Equal(t, sortable.Int(key), entry.Key)
+			assert.Equal(t, i, m.Rank(sortable.Int(key)))
+		}
+	})
+}
+
+func TestRedBlackTreeMap_RemoveAllInRandomOrder(t *testing.T) {
+	t.Parallel()
+
+	for seed := range uint64(20) {
+		m := maps.NewRedBlackTreeMap[sortable.Int, int]()
+		rng := rand.New(rand.NewPCG(seed, seed)) //nolint:gosec // Deterministic test data
+
+		keys := rng.Perm(200)
+		for _, k := range keys {
+			require.NoError(t, m.Add(sortable.Int(k), k))
+		}
+
+		rng.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+
+		for i, k := range keys {
+			require.NoError(t, m.Remove(sortable.Int(k)))
+			require.Equal(t, len(keys)-i-1, m.Size())
+		}
+
+		assert.True(t, m.Min().Empty())
+	}
+}
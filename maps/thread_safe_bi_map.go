@@ -0,0 +1,195 @@
+package maps //nolint:revive // Established package name; renaming would break all consumers.
+
+import (
+	"iter"
+	"slices"
+	"sync"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/optional"
+	"github.com/amp-labs/amp-common/set"
+)
+
+// NewThreadSafeBiMap wraps a BiMap with a sync.RWMutex, in the same way
+// NewThreadSafeMap wraps a Map. The view returned by Inverse shares the lock,
+// so it is safe to use alongside the original.
+func NewThreadSafeBiMap[K any, V any](m BiMap[K, V]) BiMap[K, V] {
+	if m == nil {
+		return nil
+	}
+
+	if tsm, ok := m.(*threadSafeBiMap[K, V]); ok {
+		// Already thread-safe, return as-is
+		return tsm
+	}
+
+	return &threadSafeBiMap[K, V]{
+		mutex:    &sync.RWMutex{},
+		internal: m,
+	}
+}
+
+// threadSafeBiMap is a decorator that guards a BiMap with a read-write lock.
+// The lock is a pointer so that an inverse view can share it.
+type threadSafeBiMap[K any, V any] struct {
+	mutex    *sync.RWMutex
+	internal BiMap[K, V]
+}
+
+func (t *threadSafeBiMap[K, V]) Get(key K) (V, bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Get(key)
+}
+
+func (t *threadSafeBiMap[K, V]) GetKey(value V) (K, bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.GetKey(value)
+}
+
+func (t *threadSafeBiMap[K, V]) Add(key K, value V) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.Add(key, value)
+}
+
+func (t *threadSafeBiMap[K, V]) ForceAdd(key K, value V) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.ForceAdd(key, value)
+}
+
+func (t *threadSafeBiMap[K, V]) Remove(key K) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.Remove(key)
+}
+
+func (t *threadSafeBiMap[K, V]) RemoveValue(value V) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.RemoveValue(value)
+}
+
+func (t *threadSafeBiMap[K, V]) Clear() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.internal.Clear()
+}
+
+func (t *threadSafeBiMap[K, V]) Contains(key K) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Contains(key)
+}
+
+func (t *threadSafeBiMap[K, V]) ContainsValue(value V) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.ContainsValue(value)
+}
+
+func (t *threadSafeBiMap[K, V]) Size() int {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Size()
+}
+
+func (t *threadSafeBiMap[K, V]) Seq() iter.Seq2[K, V] {
+	return seqPairs(t.snapshot())
+}
+
+func (t *threadSafeBiMap[K, V]) Inverse() BiMap[V, K] {
+	return &threadSafeBiMap[V, K]{
+		mutex:    t.mutex,
+		internal: t.internal.Inverse(),
+	}
+}
+
+func (t *threadSafeBiMap[K, V]) Clone() BiMap[K, V] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return NewThreadSafeBiMap(t.internal.Clone())
+}
+
+func (t *threadSafeBiMap[K, V]) HashFunction() hashing.HashFunc {
+	return t.internal.HashFunction()
+}
+
+func (t *threadSafeBiMap[K, V]) Keys() set.Set[K] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Keys()
+}
+
+func (t *threadSafeBiMap[K, V]) Values() set.Set[V] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Values()
+}
+
+func (t *threadSafeBiMap[K, V]) ForEach(f func(key K, value V)) {
+	for _, pair := range t.snapshot() {
+		f(pair.Key, pair.Value)
+	}
+}
+
+func (t *threadSafeBiMap[K, V]) ForAll(predicate func(key K, value V) bool) bool {
+	return !slices.ContainsFunc(t.snapshot(), func(pair KeyValuePair[K, V]) bool {
+		return !predicate(pair.Key, pair.Value)
+	})
+}
+
+func (t *threadSafeBiMap[K, V]) Exists(predicate func(key K, value V) bool) bool {
+	return slices.ContainsFunc(t.snapshot(), func(pair KeyValuePair[K, V]) bool {
+		return predicate(pair.Key, pair.Value)
+	})
+}
+
+func (t *threadSafeBiMap[K, V]) Filter(predicate func(key K, value V) bool) BiMap[K, V] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return NewThreadSafeBiMap(t.internal.Filter(predicate))
+}
+
+func (t *threadSafeBiMap[K, V]) FilterNot(predicate func(key K, value V) bool) BiMap[K, V] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return NewThreadSafeBiMap(t.internal.FilterNot(predicate))
+}
+
+func (t *threadSafeBiMap[K, V]) Map(f func(key K, value V) (K, V)) BiMap[K, V] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return NewThreadSafeBiMap(t.internal.Map(f))
+}
+
+func (t *threadSafeBiMap[K, V]) FindFirst(predicate func(key K, value V) bool) optional.Value[KeyValuePair[K, V]] {
+	return findFirstPair(t.snapshot(), predicate)
+}
+
+// snapshot copies every entry under the read lock, so callers can iterate
+// without holding it.
+func (t *threadSafeBiMap[K, V]) snapshot() []KeyValuePair[K, V] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return collectPairs(t.internal.Size(), t.internal.Seq())
+}
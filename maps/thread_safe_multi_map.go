@@ -0,0 +1,381 @@
+package maps //nolint:revive // Established package name; renaming would break all consumers.
+
+import (
+	"iter"
+	"slices"
+	"sync"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/optional"
+	"github.com/amp-labs/amp-common/set"
+)
+
+// NewThreadSafeMultiMap wraps a MultiMap with a sync.RWMutex, in the same way
+// NewThreadSafeMap wraps a Map. Iteration and the callback methods work on a
+// snapshot taken under the read lock, so callbacks may use the multimap.
+func NewThreadSafeMultiMap[K any, V any](m MultiMap[K, V]) MultiMap[K, V] {
+	if m == nil {
+		return nil
+	}
+
+	if tsm, ok := m.(*threadSafeMultiMap[K, V]); ok {
+		// Already thread-safe, return as-is
+		return tsm
+	}
+
+	return &threadSafeMultiMap[K, V]{internal: m}
+}
+
+// threadSafeMultiMap is a decorator that guards a MultiMap with a read-write lock.
+type threadSafeMultiMap[K any, V any] struct {
+	mutex    sync.RWMutex
+	internal MultiMap[K, V]
+}
+
+func (t *threadSafeMultiMap[K, V]) Get(key K) (set.Set[V], error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Get(key)
+}
+
+func (t *threadSafeMultiMap[K, V]) Add(key K, value V) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.Add(key, value)
+}
+
+func (t *threadSafeMultiMap[K, V]) AddAll(key K, values ...V) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.AddAll(key, values...)
+}
+
+func (t *threadSafeMultiMap[K, V]) Remove(key K, value V) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.Remove(key, value)
+}
+
+func (t *threadSafeMultiMap[K, V]) RemoveKey(key K) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.RemoveKey(key)
+}
+
+func (t *threadSafeMultiMap[K, V]) Clear() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.internal.Clear()
+}
+
+func (t *threadSafeMultiMap[K, V]) Contains(key K) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Contains(key)
+}
+
+func (t *threadSafeMultiMap[K, V]) ContainsEntry(key K, value V) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.ContainsEntry(key, value)
+}
+
+func (t *threadSafeMultiMap[K, V]) Size() int {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Size()
+}
+
+func (t *threadSafeMultiMap[K, V]) KeyCount() int {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.KeyCount()
+}
+
+func (t *threadSafeMultiMap[K, V]) Seq() iter.Seq2[K, V] {
+	return seqPairs(t.snapshot())
+}
+
+func (t *threadSafeMultiMap[K, V]) Clone() MultiMap[K, V] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return NewThreadSafeMultiMap(t.internal.Clone())
+}
+
+func (t *threadSafeMultiMap[K, V]) HashFunction() hashing.HashFunc {
+	return t.internal.HashFunction()
+}
+
+func (t *threadSafeMultiMap[K, V]) Keys() set.Set[K] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Keys()
+}
+
+func (t *threadSafeMultiMap[K, V]) ForEach(f func(key K, value V)) {
+	for _, pair := range t.snapshot() {
+		f(pair.Key, pair.Value)
+	}
+}
+
+func (t *threadSafeMultiMap[K, V]) ForAll(predicate func(key K, value V) bool) bool {
+	return !slices.ContainsFunc(t.snapshot(), func(pair KeyValuePair[K, V]) bool {
+		return !predicate(pair.Key, pair.Value)
+	})
+}
+
+func (t *threadSafeMultiMap[K, V]) Exists(predicate func(key K, value V) bool) bool {
+	return slices.ContainsFunc(t.snapshot(), func(pair KeyValuePair[K, V]) bool {
+		return predicate(pair.Key, pair.Value)
+	})
+}
+
+func (t *threadSafeMultiMap[K, V]) Filter(predicate func(key K, value V) bool) MultiMap[K, V] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return NewThreadSafeMultiMap(t.internal.Filter(predicate))
+}
+
+func (t *threadSafeMultiMap[K, V]) FilterNot(predicate func(key K, value V) bool) MultiMap[K, V] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return NewThreadSafeMultiMap(t.internal.FilterNot(predicate))
+}
+
+func (t *threadSafeMultiMap[K, V]) Map(f func(key K, value V) (K, V)) MultiMap[K, V] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return NewThreadSafeMultiMap(t.internal.Map(f))
+}
+
+func (t *threadSafeMultiMap[K, V]) FindFirst(predicate func(key K, value V) bool) optional.Value[KeyValuePair[K, V]] {
+	return findFirstPair(t.snapshot(), predicate)
+}
+
+// snapshot copies every pair under the read lock, so callers can iterate
+// without holding it.
+func (t *threadSafeMultiMap[K, V]) snapshot() []KeyValuePair[K, V] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return collectPairs(t.internal.Size(), t.internal.Seq())
+}
+
+// NewThreadSafeOrderedMultiMap wraps an OrderedMultiMap with a sync.RWMutex, in
+// the same way as NewThreadSafeMultiMap. Snapshots keep the multimap's order.
+func NewThreadSafeOrderedMultiMap[K any, V any](m OrderedMultiMap[K, V]) OrderedMultiMap[K, V] {
+	if m == nil {
+		return nil
+	}
+
+	if tsm, ok := m.(*threadSafeOrderedMultiMap[K, V]); ok {
+		// Already thread-safe, return as-is
+		return tsm
+	}
+
+	return &threadSafeOrderedMultiMap[K, V]{internal: m}
+}
+
+// threadSafeOrderedMultiMap is a decorator that guards an OrderedMultiMap with a read-write lock.
+//
+//nolint:dupl // Mirrors threadSafeMultiMap for the ordered interface
+type threadSafeOrderedMultiMap[K any, V any] struct {
+	mutex    sync.RWMutex
+	internal OrderedMultiMap[K, V]
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) Get(key K) (set.OrderedSet[V], error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Get(key)
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) Add(key K, value V) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.Add(key, value)
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) AddAll(key K, values ...V) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.AddAll(key, values...)
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) Remove(key K, value V) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.Remove(key, value)
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) RemoveKey(key K) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.internal.RemoveKey(key)
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) Clear() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.internal.Clear()
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) Contains(key K) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Contains(key)
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) ContainsEntry(key K, value V) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.ContainsEntry(key, value)
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) Size() int {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Size()
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) KeyCount() int {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.KeyCount()
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) Seq() iter.Seq2[K, V] {
+	return seqPairs(t.snapshot())
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) Clone() OrderedMultiMap[K, V] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return NewThreadSafeOrderedMultiMap(t.internal.Clone())
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) HashFunction() hashing.HashFunc {
+	return t.internal.HashFunction()
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) Keys() set.OrderedSet[K] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Keys()
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) ForEach(f func(key K, value V)) {
+	for _, pair := range t.snapshot() {
+		f(pair.Key, pair.Value)
+	}
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) ForAll(predicate func(key K, value V) bool) bool {
+	return !slices.ContainsFunc(t.snapshot(), func(pair KeyValuePair[K, V]) bool {
+		return !predicate(pair.Key, pair.Value)
+	})
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) Exists(predicate func(key K, value V) bool) bool {
+	return slices.ContainsFunc(t.snapshot(), func(pair KeyValuePair[K, V]) bool {
+		return predicate(pair.Key, pair.Value)
+	})
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) Filter(predicate func(key K, value V) bool) OrderedMultiMap[K, V] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return NewThreadSafeOrderedMultiMap(t.internal.Filter(predicate))
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) FilterNot(predicate func(key K, value V) bool) OrderedMultiMap[K, V] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return NewThreadSafeOrderedMultiMap(t.internal.FilterNot(predicate))
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) Map(f func(key K, value V) (K, V)) OrderedMultiMap[K, V] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return NewThreadSafeOrderedMultiMap(t.internal.Map(f))
+}
+
+func (t *threadSafeOrderedMultiMap[K, V]) FindFirst(
+	predicate func(key K, value V) bool,
+) optional.Value[KeyValuePair[K, V]] {
+	return findFirstPair(t.snapshot(), predicate)
+}
+
+// snapshot copies every pair, in order, under the read lock.
+func (t *threadSafeOrderedMultiMap[K, V]) snapshot() []KeyValuePair[K, V] {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return collectPairs(t.internal.Size(), t.internal.Seq())
+}
+
+// collectPairs copies the pairs yielded by seq into a slice with room for size pairs.
+func collectPairs[K any, V any](size int, seq iter.Seq2[K, V]) []KeyValuePair[K, V] {
+	pairs := make([]KeyValuePair[K, V], 0, size)
+
+	for key, value := range seq {
+		pairs = append(pairs, KeyValuePair[K, V]{Key: key, Value: value})
+	}
+
+	return pairs
+}
+
+// seqPairs returns an iterator over a snapshot taken with collectPairs.
+func seqPairs[K any, V any](pairs []KeyValuePair[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, pair := range pairs {
+			if !yield(pair.Key, pair.Value) {
+				return
+			}
+		}
+	}
+}
+
+// findFirstPair returns the first pair in a snapshot that satisfies predicate.
+func findFirstPair[K any, V any](
+	pairs []KeyValuePair[K, V],
+	predicate func(key K, value V) bool,
+) optional.Value[KeyValuePair[K, V]] {
+	for _, pair := range pairs {
+		if predicate(pair.Key, pair.Value) {
+			return optional.Some(pair)
+		}
+	}
+
+	return optional.None[KeyValuePair[K, V]]()
+}
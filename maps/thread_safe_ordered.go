@@ -48,7 +48,7 @@ type threadSafeOrderedMap[K any, V any] struct {
 // Get retrieves the value for the given key with shared read lock protection.
 // Acquires a read lock, allowing multiple concurrent Get calls without blocking each other.
 // Returns the value and found=true if the key exists, or zero value and found=false if not.
-// Returns ErrHashCollision if a hash collision occurs.
+// Returns an error only if hashing the key fails.
 func (t *threadSafeOrderedMap[K, V]) Get(key K) (value V, found bool, err error) {
 	t.mutex.RLock()
 	defer t.mutex.RUnlock()
@@ -58,7 +58,7 @@ func (t *threadSafeOrderedMap[K, V]) Get(key K) (value V, found bool, err error)
 
 // GetOrElse retrieves the value for the given key, or returns defaultValue if the key doesn't exist.
 // Acquires a read lock during the operation.
-// Returns ErrHashCollision if a different key with the same hash exists in the map.
+// Returns an error only if hashing the key fails.
 func (t *threadSafeOrderedMap[K, V]) GetOrElse(key K, defaultValue V) (value V, err error) {
 	t.mutex.RLock()
 	defer t.mutex.RUnlock()
@@ -178,6 +178,71 @@ func (t *threadSafeOrderedMap[K, V]) Intersection(other OrderedMap[K, V]) (Order
 	return NewThreadSafeOrderedMap(value), nil
 }
 
+// Difference creates a new thread-safe ordered map containing the entries of this map whose keys are not in other.
+// Acquires a read lock on this map during the operation. The returned map is also thread-safe.
+func (t *threadSafeOrderedMap[K, V]) Difference(other OrderedMap[K, V]) (OrderedMap[K, V], error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	value, err := t.internal.Difference(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewThreadSafeOrderedMap(value), nil
+}
+
+// SymmetricDifference creates a new thread-safe ordered map containing the entries whose keys are in exactly
+// one of the two maps. Acquires a read lock on this map during the operation. The returned map is also
+// thread-safe.
+func (t *threadSafeOrderedMap[K, V]) SymmetricDifference(other OrderedMap[K, V]) (OrderedMap[K, V], error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	value, err := t.internal.SymmetricDifference(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewThreadSafeOrderedMap(value), nil
+}
+
+// IsSubsetOf reports whether every key of this map is also in other.
+// Acquires a read lock on this map during the operation.
+func (t *threadSafeOrderedMap[K, V]) IsSubsetOf(other OrderedMap[K, V]) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.IsSubsetOf(other)
+}
+
+// IsSupersetOf reports whether every key of other is also in this map.
+// Acquires a read lock on this map during the operation.
+func (t *threadSafeOrderedMap[K, V]) IsSupersetOf(other OrderedMap[K, V]) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.IsSupersetOf(other)
+}
+
+// IsDisjoint reports whether the two maps have no keys in common.
+// Acquires a read lock on this map during the operation.
+func (t *threadSafeOrderedMap[K, V]) IsDisjoint(other OrderedMap[K, V]) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.IsDisjoint(other)
+}
+
+// Equals reports whether both maps have the same keys with equal values.
+// Acquires a read lock on this map during the operation.
+func (t *threadSafeOrderedMap[K, V]) Equals(other OrderedMap[K, V], valueEquals func(a, b V) bool) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Equals(other, valueEquals)
+}
+
 // Clone creates a deep copy of the map with independent thread-safe access.
 // Acquires a read lock on this map during the clone operation.
 // The returned map is a new thread-safe instance that can be modified independently.
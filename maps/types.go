@@ -28,8 +28,9 @@ type KeyValuePair[K any, V any] struct {
 
 // Map is a generic hash map interface for storing key-value pairs where keys must be
 // both hashable and comparable. It provides set-like operations (Union, Intersection)
-// in addition to standard map operations. All methods that modify the map or query for
-// keys may return ErrHashCollision if the hash function produces collisions.
+// in addition to standard map operations. Keys whose hash values collide are kept apart
+// using Equals, so methods that modify the map or query for keys only return an error
+// when hashing a key fails.
 //
 // Keys must implement the collectable.Collectable interface, which ensures they can be hashed
 // for efficient lookup and compared for equality to resolve hash collisions.
@@ -42,21 +43,21 @@ type Map[K any, V any] interface {
 	// Get retrieves the value for the given key from the hash map.
 	// If the key exists, returns the value with found=true. If the key doesn't exist, returns
 	// a zero value with found=false.
-	// Returns ErrHashCollision if a different key with the same hash exists in the map.
+	// Returns an error only if hashing the key fails.
 	Get(key K) (value V, found bool, err error)
 
 	// GetOrElse retrieves the value for the given key, or returns defaultValue if the key doesn't exist.
-	// Returns ErrHashCollision if a different key with the same hash exists in the map.
+	// Returns an error only if hashing the key fails.
 	GetOrElse(key K, defaultValue V) (value V, err error)
 
 	// Add inserts or updates a key-value pair in the map.
 	// If the key already exists, its value is replaced.
-	// Returns ErrHashCollision if the hash function produces a collision with a different key.
+	// Returns an error only if hashing the key fails.
 	Add(key K, value V) error
 
 	// Remove deletes the key-value pair from the map.
 	// If the key doesn't exist, this is a no-op and returns nil.
-	// Returns ErrHashCollision if the hash function produces a collision with a different key.
+	// Returns an error only if hashing the key fails.
 	Remove(key K) error
 
 	// Clear removes all key-value pairs from the map, leaving it empty.
@@ -64,7 +65,7 @@ type Map[K any, V any] interface {
 
 	// Contains checks if the given key exists in the map.
 	// Returns true if the key exists, false otherwise.
-	// Returns ErrHashCollision if the hash function produces a collision with a different key.
+	// Returns an error only if hashing the key fails.
 	Contains(key K) (bool, error)
 
 	// Size returns the number of key-value pairs currently stored in the map.
@@ -77,14 +78,41 @@ type Map[K any, V any] interface {
 
 	// Union creates a new map containing all key-value pairs from both this map and other.
 	// If a key exists in both maps, the value from other takes precedence.
-	// Returns ErrHashCollision if any hash collision occurs during the operation.
+	// Returns an error if hashing any key fails.
 	Union(other Map[K, V]) (Map[K, V], error)
 
 	// Intersection creates a new map containing only key-value pairs whose keys exist in both maps.
 	// The values are taken from this map, not from other.
-	// Returns ErrHashCollision if any hash collision occurs during the operation.
+	// Returns an error if hashing any key fails.
 	Intersection(other Map[K, V]) (Map[K, V], error)
 
+	// Difference creates a new map containing the key-value pairs of this map whose keys
+	// are not in other.
+	// Returns an error if hashing any key fails.
+	Difference(other Map[K, V]) (Map[K, V], error)
+
+	// SymmetricDifference creates a new map containing the key-value pairs whose keys are in
+	// exactly one of the two maps, with values taken from the map that holds them.
+	// Returns an error if hashing any key fails.
+	SymmetricDifference(other Map[K, V]) (Map[K, V], error)
+
+	// IsSubsetOf reports whether every key of this map is also in other. Values are not compared.
+	// Returns an error if hashing any key fails.
+	IsSubsetOf(other Map[K, V]) (bool, error)
+
+	// IsSupersetOf reports whether every key of other is also in this map. Values are not compared.
+	// Returns an error if hashing any key fails.
+	IsSupersetOf(other Map[K, V]) (bool, error)
+
+	// IsDisjoint reports whether the two maps have no keys in common.
+	// Returns an error if hashing any key fails.
+	IsDisjoint(other Map[K, V]) (bool, error)
+
+	// Equals reports whether both maps have the same keys, with values that are equal
+	// according to valueEquals. Use Diff to find out what the differences are.
+	// Returns an error if hashing any key fails.
+	Equals(other Map[K, V], valueEquals func(a, b V) bool) (bool, error)
+
 	// Clone creates a shallow copy of the map, duplicating its structure and entries.
 	// The keys and values themselves are not deep-copied; they are referenced as-is.
 	// Returns a new Map instance with the same entries.
@@ -147,11 +175,116 @@ type Map[K any, V any] interface {
 	FindFirst(predicate func(key K, value V) bool) optional.Value[KeyValuePair[K, V]]
 }
 
+// SortedMap is a Map whose keys are kept in sorted order. In addition to the Map
+// operations, it supports ordered queries: the smallest and largest keys, the nearest
+// keys around a given key, half-open key ranges, descending iteration, and lookups by
+// position in sorted order. Seq yields entries in ascending key order.
+//
+// Thread-safety: Implementations are not guaranteed to be thread-safe unless
+// explicitly documented. Concurrent access must be synchronized by the caller.
+type SortedMap[K any, V any] interface {
+	Map[K, V]
+
+	// Min returns the entry with the smallest key, or None if the map is empty.
+	Min() optional.Value[KeyValuePair[K, V]]
+
+	// Max returns the entry with the largest key, or None if the map is empty.
+	Max() optional.Value[KeyValuePair[K, V]]
+
+	// Floor returns the entry with the greatest key less than or equal to key, or None if there is none.
+	Floor(key K) optional.Value[KeyValuePair[K, V]]
+
+	// Ceiling returns the entry with the smallest key greater than or equal to key, or None if there is none.
+	Ceiling(key K) optional.Value[KeyValuePair[K, V]]
+
+	// Lower returns the entry with the greatest key strictly less than key, or None if there is none.
+	Lower(key K) optional.Value[KeyValuePair[K, V]]
+
+	// Higher returns the entry with the smallest key strictly greater than key, or None if there is none.
+	Higher(key K) optional.Value[KeyValuePair[K, V]]
+
+	// Range returns an iterator over the entries whose keys are greater than or equal to from
+	// and strictly less than to, in ascending key order.
+	Range(from, to K) iter.Seq2[K, V]
+
+	// SeqDescending returns an iterator over all key-value pairs in descending key order.
+	SeqDescending() iter.Seq2[K, V]
+
+	// Rank returns the number of keys strictly less than key. If key is in the map,
+	// this is its zero-based index in sorted order.
+	Rank(key K) int
+
+	// Select returns the entry at the given zero-based index in sorted order,
+	// or None if the index is out of range.
+	Select(index int) optional.Value[KeyValuePair[K, V]]
+}
+
+// ConcurrentMap is a Map that is safe for concurrent use and offers atomic
+// read-modify-write operations. Each compute function runs while the key's entry
+// is locked, so no other goroutine can observe or change the entry in between;
+// the functions must not access the map themselves.
+type ConcurrentMap[K any, V any] interface {
+	Map[K, V]
+
+	// ComputeIfAbsent returns the value for key if it is present. Otherwise it
+	// stores and returns the value computed by f. If f fails, nothing is stored
+	// and its error is returned. computed reports whether a computed value was
+	// stored, so it is false whenever err is non-nil.
+	ComputeIfAbsent(key K, f func(key K) (V, error)) (value V, computed bool, err error)
+
+	// ComputeIfPresent replaces the value for key with the one computed by f, if
+	// the key is present. If f returns keep=false the key is removed instead.
+	// It returns the value now stored and whether the key is present afterwards.
+	ComputeIfPresent(key K, f func(key K, value V) (newValue V, keep bool, err error)) (value V, present bool, err error)
+
+	// Compute replaces the value for key with the one computed by f, which
+	// receives the current value and whether the key was found. If f returns
+	// keep=false the key is removed (or stays absent). It returns the value now
+	// stored and whether the key is present afterwards.
+	Compute(key K, f func(key K, value V, found bool) (newValue V, keep bool, err error)) (value V, present bool, err error)
+
+	// CompareAndSwap stores newValue for key if the key is present and its value
+	// equals old, and reports whether it did. Like sync.Map.CompareAndSwap, the
+	// values are compared with ==, so V's dynamic type must be comparable.
+	// Returns an error only if hashing the key fails.
+	CompareAndSwap(key K, old, newValue V) (swapped bool, err error)
+}
+
+// PersistentMap is a Map whose versions share structure. With and Without return
+// a new version and leave the receiver unchanged, and Clone is O(1). Add, Remove
+// and Clear move the receiver itself to a new version; versions obtained earlier
+// through Clone, With, Without, Union, Intersection or Filter are not affected.
+// A version that is no longer modified can be read by any number of goroutines
+// without locks.
+type PersistentMap[K any, V any] interface {
+	Map[K, V]
+
+	// With returns a version of the map in which key maps to value.
+	// Returns an error only if hashing the key fails.
+	With(key K, value V) (PersistentMap[K, V], error)
+
+	// Without returns a version of the map without key.
+	// Returns an error only if hashing the key fails.
+	Without(key K) (PersistentMap[K, V], error)
+}
+
+// PersistentSortedMap is a SortedMap whose versions share structure, with the
+// same versioning rules as PersistentMap.
+type PersistentSortedMap[K any, V any] interface {
+	SortedMap[K, V]
+
+	// With returns a version of the map in which key maps to value.
+	With(key K, value V) (PersistentSortedMap[K, V], error)
+
+	// Without returns a version of the map without key.
+	Without(key K) (PersistentSortedMap[K, V], error)
+}
+
 // OrderedMap is a generic ordered hash map interface for storing key-value pairs where keys must be
 // both hashable and comparable. Unlike the standard Map interface, OrderedMap preserves insertion
 // order when iterating. It provides set-like operations (Union, Intersection) in addition to standard
-// map operations. All methods that modify the map or query for keys may return ErrHashCollision if
-// the hash function produces collisions.
+// map operations. Keys whose hash values collide are kept apart using Equals, so methods that
+// modify the map or query for keys only return an error when hashing a key fails.
 //
 // Keys must implement the collectable.Collectable interface, which ensures they can be hashed
 // for efficient lookup and compared for equality to resolve hash collisions.
@@ -164,22 +297,22 @@ type OrderedMap[K any, V any] interface {
 	// Get retrieves the value for the given key from the hash map.
 	// If the key exists, returns the value with found=true. If the key doesn't exist, returns
 	// a zero value with found=false.
-	// Returns ErrHashCollision if a different key with the same hash exists in the map.
+	// Returns an error only if hashing the key fails.
 	Get(key K) (value V, found bool, err error)
 
 	// GetOrElse retrieves the value for the given key, or returns defaultValue if the key doesn't exist.
-	// Returns ErrHashCollision if a different key with the same hash exists in the map.
+	// Returns an error only if hashing the key fails.
 	GetOrElse(key K, defaultValue V) (value V, err error)
 
 	// Add inserts or updates a key-value pair in the map.
 	// If the key already exists, its value is replaced without changing the insertion order.
 	// If the key is new, it's appended to the end of the insertion order.
-	// Returns ErrHashCollision if the hash function produces a collision with a different key.
+	// Returns an error only if hashing the key fails.
 	Add(key K, value V) error
 
 	// Remove deletes the key-value pair from the map.
 	// If the key doesn't exist, this is a no-op and returns nil.
-	// Returns ErrHashCollision if the hash function produces a collision with a different key.
+	// Returns an error only if hashing the key fails.
 	Remove(key K) error
 
 	// Clear removes all key-value pairs from the map, leaving it empty.
@@ -187,7 +320,7 @@ type OrderedMap[K any, V any] interface {
 
 	// Contains checks if the given key exists in the map.
 	// Returns true if the key exists, false otherwise.
-	// Returns ErrHashCollision if the hash function produces a collision with a different key.
+	// Returns an error only if hashing the key fails.
 	Contains(key K) (bool, error)
 
 	// Size returns the number of key-value pairs currently stored in the map.
@@ -203,15 +336,45 @@ type OrderedMap[K any, V any] interface {
 	// Entries from this map are added first (preserving their order), followed by entries from other.
 	// If a key exists in both maps, the value from other takes precedence, but the key maintains
 	// its original position from this map.
-	// Returns ErrHashCollision if any hash collision occurs during the operation.
+	// Returns an error if hashing any key fails.
 	Union(other OrderedMap[K, V]) (OrderedMap[K, V], error)
 
 	// Intersection creates a new map containing only key-value pairs whose keys exist in both maps.
 	// The values are taken from this map, not from other, and the insertion order is preserved
 	// from this map.
-	// Returns ErrHashCollision if any hash collision occurs during the operation.
+	// Returns an error if hashing any key fails.
 	Intersection(other OrderedMap[K, V]) (OrderedMap[K, V], error)
 
+	// Difference creates a new map containing the key-value pairs of this map whose keys
+	// are not in other, in this map's insertion order.
+	// Returns an error if hashing any key fails.
+	Difference(other OrderedMap[K, V]) (OrderedMap[K, V], error)
+
+	// SymmetricDifference creates a new map containing the key-value pairs whose keys are in
+	// exactly one of the two maps: first those of this map, in its insertion order, then those
+	// of other, in its insertion order.
+	// Returns an error if hashing any key fails.
+	SymmetricDifference(other OrderedMap[K, V]) (OrderedMap[K, V], error)
+
+	// IsSubsetOf reports whether every key of this map is also in other. Values and order
+	// are not compared.
+	// Returns an error if hashing any key fails.
+	IsSubsetOf(other OrderedMap[K, V]) (bool, error)
+
+	// IsSupersetOf reports whether every key of other is also in this map. Values and order
+	// are not compared.
+	// Returns an error if hashing any key fails.
+	IsSupersetOf(other OrderedMap[K, V]) (bool, error)
+
+	// IsDisjoint reports whether the two maps have no keys in common.
+	// Returns an error if hashing any key fails.
+	IsDisjoint(other OrderedMap[K, V]) (bool, error)
+
+	// Equals reports whether both maps have the same keys, in any order, with values that are
+	// equal according to valueEquals. Use DiffOrdered to find out what the differences are.
+	// Returns an error if hashing any key fails.
+	Equals(other OrderedMap[K, V], valueEquals func(a, b V) bool) (bool, error)
+
 	// Clone creates a shallow copy of the map, duplicating its structure, entries, and insertion order.
 	// The keys and values themselves are not deep-copied; they are referenced as-is.
 	// Returns a new OrderedMap instance with the same entries in the same order.
@@ -273,3 +436,260 @@ type OrderedMap[K any, V any] interface {
 	// The iteration order is non-deterministic, so "first" is not guaranteed to be consistent.
 	FindFirst(predicate func(key K, value V) bool) optional.Value[KeyValuePair[K, V]]
 }
+
+// PersistentOrderedMap is an OrderedMap whose versions share structure, with
+// the same versioning rules as PersistentMap.
+type PersistentOrderedMap[K any, V any] interface {
+	OrderedMap[K, V]
+
+	// With returns a version of the map in which key maps to value. A new key
+	// is appended to the insertion order; an existing key keeps its position.
+	// Returns an error only if hashing the key fails.
+	With(key K, value V) (PersistentOrderedMap[K, V], error)
+
+	// Without returns a version of the map without key.
+	// Returns an error only if hashing the key fails.
+	Without(key K) (PersistentOrderedMap[K, V], error)
+}
+
+// MultiMap associates each key with a set of distinct values, for data such as
+// HTTP headers or tag indexes. Size counts key-value pairs, not keys; a key is
+// present only while it has at least one value. Values are told apart with the
+// map's hash function and their Equals method, so methods only return an error
+// when hashing a key or value fails.
+//
+// Thread-safety: Implementations are not guaranteed to be thread-safe unless
+// explicitly documented. Concurrent access must be synchronized by the caller.
+//
+//nolint:interfacebloat // MultiMap mirrors the Map API
+type MultiMap[K any, V any] interface {
+	// Get returns a copy of the values for key, which is empty if the key is absent.
+	Get(key K) (set.Set[V], error)
+
+	// Add associates value with key. Adding a pair that is already present is a no-op.
+	Add(key K, value V) error
+
+	// AddAll associates every value with key.
+	AddAll(key K, values ...V) error
+
+	// Remove dissociates value from key, removing the key if it has no values left.
+	// Removing a pair that isn't present is a no-op.
+	Remove(key K, value V) error
+
+	// RemoveKey removes key and all of its values.
+	RemoveKey(key K) error
+
+	// Clear removes all keys and values.
+	Clear()
+
+	// Contains checks whether key has any values.
+	Contains(key K) (bool, error)
+
+	// ContainsEntry checks whether value is associated with key.
+	ContainsEntry(key K, value V) (bool, error)
+
+	// Size returns the number of key-value pairs.
+	Size() int
+
+	// KeyCount returns the number of distinct keys.
+	KeyCount() int
+
+	// Seq returns an iterator over every key-value pair. A key with several values
+	// is yielded once per value. The iteration order is non-deterministic.
+	Seq() iter.Seq2[K, V]
+
+	// Clone returns a copy of the multimap whose value sets are independent of this one's.
+	Clone() MultiMap[K, V]
+
+	// HashFunction returns the hash function used for keys and values.
+	HashFunction() hashing.HashFunc
+
+	// Keys returns a new set containing every key.
+	Keys() set.Set[K]
+
+	// ForEach applies f to every key-value pair.
+	ForEach(f func(key K, value V))
+
+	// ForAll tests whether predicate holds for every key-value pair, stopping at the first failure.
+	ForAll(predicate func(key K, value V) bool) bool
+
+	// Exists tests whether predicate holds for at least one key-value pair, stopping at the first match.
+	Exists(predicate func(key K, value V) bool) bool
+
+	// Filter returns a new multimap with only the pairs for which predicate returns true.
+	Filter(predicate func(key K, value V) bool) MultiMap[K, V]
+
+	// FilterNot returns a new multimap with only the pairs for which predicate returns false.
+	FilterNot(predicate func(key K, value V) bool) MultiMap[K, V]
+
+	// Map returns a new multimap with f applied to every pair. Pairs that map to the
+	// same key are grouped under it.
+	Map(f func(key K, value V) (K, V)) MultiMap[K, V]
+
+	// FindFirst returns a pair for which predicate returns true, or None if there is none.
+	FindFirst(predicate func(key K, value V) bool) optional.Value[KeyValuePair[K, V]]
+}
+
+// OrderedMultiMap is a MultiMap that remembers insertion order: keys are ordered
+// by when they were first added, and each key's values by when they were added
+// to it. Removing a key's last value forgets the key's position.
+//
+// Thread-safety: Implementations are not guaranteed to be thread-safe unless
+// explicitly documented. Concurrent access must be synchronized by the caller.
+//
+//nolint:interfacebloat,dupl // OrderedMultiMap mirrors the MultiMap API
+type OrderedMultiMap[K any, V any] interface {
+	// Get returns a copy of the values for key in insertion order, which is empty if the key is absent.
+	Get(key K) (set.OrderedSet[V], error)
+
+	// Add associates value with key. Adding a pair that is already present is a no-op
+	// and keeps its position.
+	Add(key K, value V) error
+
+	// AddAll associates every value with key, in the order given.
+	AddAll(key K, values ...V) error
+
+	// Remove dissociates value from key, removing the key if it has no values left.
+	// Removing a pair that isn't present is a no-op.
+	Remove(key K, value V) error
+
+	// RemoveKey removes key and all of its values.
+	RemoveKey(key K) error
+
+	// Clear removes all keys and values.
+	Clear()
+
+	// Contains checks whether key has any values.
+	Contains(key K) (bool, error)
+
+	// ContainsEntry checks whether value is associated with key.
+	ContainsEntry(key K, value V) (bool, error)
+
+	// Size returns the number of key-value pairs.
+	Size() int
+
+	// KeyCount returns the number of distinct keys.
+	KeyCount() int
+
+	// Seq returns an iterator over every key-value pair, grouped by key in key
+	// order and then in value order.
+	Seq() iter.Seq2[K, V]
+
+	// Clone returns a copy of the multimap whose value sets are independent of this one's.
+	Clone() OrderedMultiMap[K, V]
+
+	// HashFunction returns the hash function used for keys and values.
+	HashFunction() hashing.HashFunc
+
+	// Keys returns a new set containing every key, in insertion order.
+	Keys() set.OrderedSet[K]
+
+	// ForEach applies f to every key-value pair, in order.
+	ForEach(f func(key K, value V))
+
+	// ForAll tests whether predicate holds for every key-value pair, stopping at the first failure.
+	ForAll(predicate func(key K, value V) bool) bool
+
+	// Exists tests whether predicate holds for at least one key-value pair, stopping at the first match.
+	Exists(predicate func(key K, value V) bool) bool
+
+	// Filter returns a new multimap with only the pairs for which predicate returns true, in order.
+	Filter(predicate func(key K, value V) bool) OrderedMultiMap[K, V]
+
+	// FilterNot returns a new multimap with only the pairs for which predicate returns false, in order.
+	FilterNot(predicate func(key K, value V) bool) OrderedMultiMap[K, V]
+
+	// Map returns a new multimap with f applied to every pair, in order. Pairs that
+	// map to the same key are grouped under it.
+	Map(f func(key K, value V) (K, V)) OrderedMultiMap[K, V]
+
+	// FindFirst returns the first pair, in order, for which predicate returns true,
+	// or None if there is none.
+	FindFirst(predicate func(key K, value V) bool) optional.Value[KeyValuePair[K, V]]
+}
+
+// BiMap is a one-to-one map: every key has one value and every value belongs to
+// one key, so it can be looked up in either direction (for example ID <-> name).
+// Keys and values are both told apart with the map's hash function and their
+// Equals method.
+//
+// Thread-safety: Implementations are not guaranteed to be thread-safe unless
+// explicitly documented. Concurrent access must be synchronized by the caller.
+//
+//nolint:interfacebloat // BiMap mirrors the Map API in both directions
+type BiMap[K any, V any] interface {
+	// Get returns the value for key.
+	// Returns an error only if hashing the key fails.
+	Get(key K) (value V, found bool, err error)
+
+	// GetKey returns the key for value.
+	// Returns an error only if hashing the value fails.
+	GetKey(value V) (key K, found bool, err error)
+
+	// Add associates key with value, replacing the key's previous value. It returns
+	// ErrValueAlreadyBound, and changes nothing, if value belongs to another key.
+	Add(key K, value V) error
+
+	// ForceAdd associates key with value, first removing any other key that value belongs to.
+	// Returns an error only if hashing fails.
+	ForceAdd(key K, value V) error
+
+	// Remove deletes key and its value. Removing a missing key is a no-op.
+	Remove(key K) error
+
+	// RemoveValue deletes value and its key. Removing a missing value is a no-op.
+	RemoveValue(value V) error
+
+	// Clear removes all entries.
+	Clear()
+
+	// Contains checks whether key is present.
+	Contains(key K) (bool, error)
+
+	// ContainsValue checks whether value is present.
+	ContainsValue(value V) (bool, error)
+
+	// Size returns the number of entries.
+	Size() int
+
+	// Seq returns an iterator over the entries. The iteration order is non-deterministic.
+	Seq() iter.Seq2[K, V]
+
+	// Inverse returns a view of the map from values to keys. It shares storage
+	// with this map, so changes made through either are visible in both.
+	Inverse() BiMap[V, K]
+
+	// Clone returns an independent copy of the map.
+	Clone() BiMap[K, V]
+
+	// HashFunction returns the hash function used for keys and values.
+	HashFunction() hashing.HashFunc
+
+	// Keys returns a new set containing every key.
+	Keys() set.Set[K]
+
+	// Values returns a new set containing every value.
+	Values() set.Set[V]
+
+	// ForEach applies f to every entry.
+	ForEach(f func(key K, value V))
+
+	// ForAll tests whether predicate holds for every entry, stopping at the first failure.
+	ForAll(predicate func(key K, value V) bool) bool
+
+	// Exists tests whether predicate holds for at least one entry, stopping at the first match.
+	Exists(predicate func(key K, value V) bool) bool
+
+	// Filter returns a new map with only the entries for which predicate returns true.
+	Filter(predicate func(key K, value V) bool) BiMap[K, V]
+
+	// FilterNot returns a new map with only the entries for which predicate returns false.
+	FilterNot(predicate func(key K, value V) bool) BiMap[K, V]
+
+	// Map returns a new map with f applied to every entry. If several entries map
+	// to the same key or the same value, the one applied last wins, as with ForceAdd.
+	Map(f func(key K, value V) (K, V)) BiMap[K, V]
+
+	// FindFirst returns an entry for which predicate returns true, or None if there is none.
+	FindFirst(predicate func(key K, value V) bool) optional.Value[KeyValuePair[K, V]]
+}
@@ -50,4 +50,29 @@ var (
 		Name: "pool_objects_idle",
 		Help: "The total number of objects idle",
 	}, []string{"pool"})
+
+	poolWaiters = promauto.NewGaugeVec(prometheus.GaugeOpts{ //nolint:gochecknoglobals
+		Name: "pool_waiters",
+		Help: "The number of Get calls waiting for an object",
+	}, []string{"pool"})
+
+	poolWaitCount = promauto.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "pool_wait_total",
+		Help: "The total number of Get calls that had to wait for an object",
+	}, []string{"pool"})
+
+	poolWaitDuration = promauto.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "pool_wait_duration_seconds_total",
+		Help: "The total time Get calls spent waiting for an object",
+	}, []string{"pool"})
+
+	objectsClosedMaxIdle = promauto.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "pool_objects_closed_max_idle_total",
+		Help: "The total number of objects closed because the pool had too many idle objects",
+	}, []string{"pool"})
+
+	objectsClosedMaxLifetime = promauto.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "pool_objects_closed_max_lifetime_total",
+		Help: "The total number of objects closed because they exceeded their maximum lifetime",
+	}, []string{"pool"})
 )
@@ -2,11 +2,13 @@
 package pool
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"math/rand"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -18,10 +20,10 @@ import (
 )
 
 const (
-	// getTimeout is the maximum time to wait when attempting to fetch an object from the pool.
-	getTimeout = 5 * time.Second
-	// putTimeout is the maximum time to wait when attempting to return an object to the pool.
-	putTimeout = 10 * time.Second
+	// defaultGetTimeout is the maximum time Get waits for an object, unless changed with WithGetTimeout.
+	defaultGetTimeout = 5 * time.Second
+	// defaultPutTimeout is the maximum time Put waits to return an object, unless changed with WithPutTimeout.
+	defaultPutTimeout = 10 * time.Second
 	// closeIdleTimeout is the maximum time to wait when attempting to close idle objects.
 	closeIdleTimeout = 30 * time.Second
 
@@ -43,6 +45,12 @@ type Pool[C io.Closer] interface {
 	// available, it will create a new one and return that.
 	Get() (C, error)
 
+	// GetCtx is like Get, but waits for an object until ctx is done
+	// instead of the pool's get timeout. If the pool has reached its
+	// maximum number of open objects, callers wait in FIFO order for
+	// one to be returned.
+	GetCtx(ctx context.Context) (C, error)
+
 	// Put will return an object to the pool.
 	Put(c C)
 
@@ -55,11 +63,46 @@ type Pool[C io.Closer] interface {
 
 	// Close will close the entire pool and close all objects.
 	Close() error
+
+	// Stats returns the current pool statistics.
+	Stats() Stats
+}
+
+// Stats contains pool statistics, as returned by Pool.Stats.
+type Stats struct {
+	// MaxOpen is the maximum number of open objects, or 0 if unlimited.
+	MaxOpen int
+	// Open is the number of objects in the pool, both in use and idle.
+	Open int
+	// Idle is the number of objects waiting in the pool to be reused.
+	Idle int
+	// InUse is the number of objects currently handed out by Get.
+	InUse int
+	// Waiters is the number of Get calls currently waiting for an object.
+	Waiters int
+	// WaitCount is the total number of Get calls that had to wait for an object.
+	WaitCount int64
+	// WaitDuration is the total time Get calls spent waiting for an object.
+	WaitDuration time.Duration
+	// MaxIdleClosed is the total number of objects closed because of WithMaxIdle.
+	MaxIdleClosed int64
+	// MaxLifetimeClosed is the total number of objects closed because of WithMaxLifetime.
+	MaxLifetimeClosed int64
 }
 
+// States of a getRequest. The loop and the caller race to move a request out
+// of getWaiting, so that an object is never handed to a caller that gave up.
+const (
+	getWaiting int32 = iota
+	getServed
+	getAbandoned
+)
+
 // getRequest is used internally to request an object from the pool.
 type getRequest[C io.Closer] struct {
 	resultChan chan try.Try[C]
+	state      *atomic.Int32
+	queuedAt   time.Time // when the request started waiting, zero if it was served right away
 }
 
 // putRequest is used internally to return an object to the pool.
@@ -83,8 +126,14 @@ type closeIdleRequest struct {
 // poolOptions holds configuration options for creating a new Pool.
 // It is used internally by the Option pattern to customize pool behavior.
 type poolOptions[C io.Closer] struct {
-	name       string
-	checkValid func(C) error
+	name               string
+	checkValid         func(C) error
+	maxOpen            int
+	maxIdle            int
+	maxLifetime        time.Duration
+	validationInterval time.Duration
+	getTimeout         time.Duration
+	putTimeout         time.Duration
 }
 
 // Option is a functional option for configuring a Pool during creation.
@@ -110,6 +159,62 @@ func WithCheckValid[C io.Closer](check func(C) error) Option[C] {
 	}
 }
 
+// WithMaxOpen limits the number of objects the pool keeps open, both in use
+// and idle. Once the limit is reached, Get and GetCtx wait in FIFO order for
+// an object to be returned instead of creating a new one. Get fails with
+// ErrTimeout if none becomes available within the get timeout. The default,
+// 0, means no limit.
+func WithMaxOpen[C io.Closer](maxOpen int) Option[C] {
+	return func(p *poolOptions[C]) {
+		p.maxOpen = maxOpen
+	}
+}
+
+// WithMaxIdle limits the number of idle objects the pool keeps. Objects
+// returned while the limit is reached are closed, starting with the ones that
+// have been idle the longest. The default, 0, means no limit.
+func WithMaxIdle[C io.Closer](maxIdle int) Option[C] {
+	return func(p *poolOptions[C]) {
+		p.maxIdle = maxIdle
+	}
+}
+
+// WithMaxLifetime sets the maximum time an object may be used since it was
+// created. Older objects are closed instead of being reused. The default, 0,
+// means objects are reused indefinitely.
+func WithMaxLifetime[C io.Closer](maxLifetime time.Duration) Option[C] {
+	return func(p *poolOptions[C]) {
+		p.maxLifetime = maxLifetime
+	}
+}
+
+// WithValidationInterval makes the pool check its idle objects in the
+// background at the given interval, closing the ones that fail the
+// WithCheckValid function or exceeded WithMaxLifetime. By default idle objects
+// are only checked when they are about to be reused.
+func WithValidationInterval[C io.Closer](interval time.Duration) Option[C] {
+	return func(p *poolOptions[C]) {
+		p.validationInterval = interval
+	}
+}
+
+// WithGetTimeout sets how long Get waits for an object (default 5 seconds).
+// Unless WithMaxOpen is set, Get creates a new object outside the pool's
+// bookkeeping when the timeout expires.
+func WithGetTimeout[C io.Closer](timeout time.Duration) Option[C] {
+	return func(p *poolOptions[C]) {
+		p.getTimeout = timeout
+	}
+}
+
+// WithPutTimeout sets how long Put waits to return an object to the pool
+// (default 10 seconds). If the timeout expires, the object is closed.
+func WithPutTimeout[C io.Closer](timeout time.Duration) Option[C] {
+	return func(p *poolOptions[C]) {
+		p.putTimeout = timeout
+	}
+}
+
 // getTypeName will return the name of the type.
 // If the type is a pointer, it will be dereferenced.
 func getTypeName[C any]() string {
@@ -122,11 +227,14 @@ func getTypeName[C any]() string {
 }
 
 // New will create a new Pool which will grow dynamically as demand
-// increases. All objects are kept indefinitely, until CloseIdle or
-// Close is called.
+// increases, up to the WithMaxOpen limit if one is set. Objects are
+// kept until CloseIdle or Close is called, or until they are closed
+// because of WithMaxIdle, WithMaxLifetime or failed validation.
 func New[C io.Closer](factory func() (C, error), opts ...Option[C]) Pool[C] {
 	options := &poolOptions[C]{
-		name: "pool-" + getTypeName[C](),
+		name:       "pool-" + getTypeName[C](),
+		getTimeout: defaultGetTimeout,
+		putTimeout: defaultPutTimeout,
 	}
 
 	for _, opt := range opts {
@@ -138,15 +246,28 @@ func New[C io.Closer](factory func() (C, error), opts ...Option[C]) Pool[C] {
 	}
 
 	poolInst := &poolImpl[C]{
-		name:        options.name,
-		getCh:       make(chan getRequest[C]),
-		putCh:       make(chan putRequest[C]),
-		ciCh:        make(chan closeIdleRequest),
-		closeCh:     make(chan error, 1),
-		create:      factory,
-		checkValid:  options.checkValid,
-		running:     atomic.NewBool(true),
-		outstanding: atomic.NewInt64(0),
+		name:               options.name,
+		getCh:              make(chan getRequest[C]),
+		putCh:              make(chan putRequest[C]),
+		ciCh:               make(chan closeIdleRequest),
+		closeCh:            make(chan error, 1),
+		create:             factory,
+		checkValid:         options.checkValid,
+		maxOpen:            options.maxOpen,
+		maxIdle:            options.maxIdle,
+		maxLifetime:        options.maxLifetime,
+		validationInterval: options.validationInterval,
+		getTimeout:         options.getTimeout,
+		putTimeout:         options.putTimeout,
+		created:            make(map[any]time.Time),
+		running:            atomic.NewBool(true),
+		outstanding:        atomic.NewInt64(0),
+		idle:               atomic.NewInt64(0),
+		waiting:            atomic.NewInt64(0),
+		waitCount:          atomic.NewInt64(0),
+		waitDuration:       atomic.NewInt64(0),
+		maxIdleClosed:      atomic.NewInt64(0),
+		maxLifetimeClosed:  atomic.NewInt64(0),
 	}
 
 	go poolInst.loop()
@@ -159,6 +280,11 @@ func New[C io.Closer](factory func() (C, error), opts ...Option[C]) Pool[C] {
 	objectsClosedErrors.WithLabelValues(poolInst.name).Add(0)
 	objectsCreated.WithLabelValues(poolInst.name).Add(0)
 	creationErrors.WithLabelValues(poolInst.name).Add(0)
+	poolWaiters.WithLabelValues(poolInst.name).Set(0)
+	poolWaitCount.WithLabelValues(poolInst.name).Add(0)
+	poolWaitDuration.WithLabelValues(poolInst.name).Add(0)
+	objectsClosedMaxIdle.WithLabelValues(poolInst.name).Add(0)
+	objectsClosedMaxLifetime.WithLabelValues(poolInst.name).Add(0)
 
 	poolCreated.WithLabelValues(poolInst.name).Inc()
 
@@ -171,17 +297,33 @@ func New[C io.Closer](factory func() (C, error), opts ...Option[C]) Pool[C] {
 // thread-safety without explicit locking. Objects are stored in an in-memory slice
 // and Prometheus metrics are maintained for observability.
 type poolImpl[C io.Closer] struct {
-	name       string
-	create     func() (C, error)
-	checkValid func(C) error
-	getCh      chan getRequest[C]
-	putCh      chan putRequest[C]
-	ciCh       chan closeIdleRequest
-	drain      sync.WaitGroup
-	closeCh    chan error
-
-	outstanding *atomic.Int64
-	running     *atomic.Bool
+	name               string
+	create             func() (C, error)
+	checkValid         func(C) error
+	maxOpen            int
+	maxIdle            int
+	maxLifetime        time.Duration
+	validationInterval time.Duration
+	getTimeout         time.Duration
+	putTimeout         time.Duration
+	getCh              chan getRequest[C]
+	putCh              chan putRequest[C]
+	ciCh               chan closeIdleRequest
+	drain              sync.WaitGroup
+	closeCh            chan error
+
+	// waiters and created are only accessed by the loop goroutine.
+	waiters []getRequest[C]
+	created map[any]time.Time // creation time of objects, tracked when maxLifetime is set
+
+	outstanding       *atomic.Int64
+	running           *atomic.Bool
+	idle              *atomic.Int64
+	waiting           *atomic.Int64
+	waitCount         *atomic.Int64
+	waitDuration      *atomic.Int64
+	maxIdleClosed     *atomic.Int64
+	maxLifetimeClosed *atomic.Int64
 }
 
 // createObject calls the factory function to create a new pooled object and updates
@@ -195,7 +337,6 @@ func (g *poolImpl[C]) createObject() (C, error) {
 		return obj, err
 	} else {
 		objectsCreated.WithLabelValues(g.name).Inc()
-		poolObjectsTotal.WithLabelValues(g.name).Inc()
 
 		return obj, nil
 	}
@@ -204,89 +345,284 @@ func (g *poolImpl[C]) createObject() (C, error) {
 // poolObject wraps a pooled object along with metadata needed for pool management.
 // The closer is a wrapped version of the object that handles panics and ensures Close
 // is only called once. The lastTouched timestamp tracks when the object was last returned
-// to the pool, enabling idle timeout logic in CloseIdle.
+// to the pool, enabling idle timeout logic in CloseIdle, and createdAt enables WithMaxLifetime.
 type poolObject[C io.Closer] struct {
 	obj         C
 	closer      io.Closer
 	lastTouched time.Time
+	createdAt   time.Time
 }
 
-// handleGet processes a Get request by attempting to reuse an existing idle object from the pool.
-// It iterates through idle objects, validating each one with checkValid. Invalid objects are closed
-// and discarded. If a valid object is found, it is returned to the caller and marked as in-use.
-// If no valid objects exist in the pool, a new object is created via the factory function.
-// This method runs within the pool's central loop goroutine and updates Prometheus metrics.
-func (g *poolImpl[C]) handleGet(get getRequest[C], objectPool *[]poolObject[C]) {
-	foundValid := false
+// newPoolObject wraps an object that is being added to the idle pool.
+func (g *poolImpl[C]) newPoolObject(obj C) poolObject[C] {
+	return poolObject[C]{
+		obj:         obj,
+		closer:      closer.CloseOnce(closer.HandlePanic(obj)),
+		lastTouched: time.Now(),
+		createdAt:   g.createdAt(obj),
+	}
+}
 
+// objectKey returns the key under which the creation time of obj is tracked.
+// Objects that can't be map keys are not tracked.
+func objectKey[C io.Closer](obj C) (any, bool) {
+	key := any(obj)
+	if key == nil || !reflect.TypeOf(key).Comparable() {
+		return nil, false
+	}
+
+	return key, true
+}
+
+// createdAt returns when obj was created. Objects the pool hasn't seen before,
+// such as ones created by Get after a timeout, are considered created now.
+func (g *poolImpl[C]) createdAt(obj C) time.Time {
+	now := time.Now()
+
+	if g.maxLifetime <= 0 {
+		return now
+	}
+
+	key, ok := objectKey(obj)
+	if !ok {
+		return now
+	}
+
+	if created, ok := g.created[key]; ok {
+		return created
+	}
+
+	g.created[key] = now
+
+	return now
+}
+
+// forget stops tracking the creation time of an object that was closed.
+func (g *poolImpl[C]) forget(obj C) {
+	if key, ok := objectKey(obj); ok {
+		delete(g.created, key)
+	}
+}
+
+// usable reports whether an idle object may be handed out again: it must not
+// have exceeded its maximum lifetime and must pass the checkValid function.
+func (g *poolImpl[C]) usable(obj poolObject[C]) bool {
+	if g.maxLifetime > 0 && time.Since(obj.createdAt) >= g.maxLifetime {
+		g.maxLifetimeClosed.Inc()
+		objectsClosedMaxLifetime.WithLabelValues(g.name).Inc()
+
+		return false
+	}
+
+	checkErr := g.checkValid(obj.obj)
+	if checkErr != nil {
+		slog.Warn("pool object is invalid, closing and discarding it", "error", checkErr)
+
+		return false
+	}
+
+	return true
+}
+
+// discard closes an object that is being removed from the pool.
+func (g *poolImpl[C]) discard(obj poolObject[C]) {
+	g.forget(obj.obj)
+
+	err := obj.closer.Close()
+	if err != nil { //nolint:typecheck
+		slog.Warn("unable to close pool object", "error", err)
+
+		objectsClosedErrors.WithLabelValues(g.name).Inc()
+	} else {
+		objectsClosed.WithLabelValues(g.name).Inc()
+	}
+}
+
+// deliver hands the result of a Get to its caller, unless the caller has
+// stopped waiting. It reports whether the caller received the result.
+func (g *poolImpl[C]) deliver(get getRequest[C], result try.Try[C]) bool {
+	if !get.state.CompareAndSwap(getWaiting, getServed) {
+		return false
+	}
+
+	if result.Error == nil {
+		g.outstanding.Inc()
+	}
+
+	if !get.queuedAt.IsZero() {
+		waited := time.Since(get.queuedAt)
+
+		g.waitDuration.Add(int64(waited))
+		poolWaitDuration.WithLabelValues(g.name).Add(waited.Seconds())
+	}
+
+	get.resultChan <- result
+
+	close(get.resultChan)
+
+	return true
+}
+
+// serve attempts to satisfy a Get request by reusing an existing idle object from the pool.
+// It iterates through idle objects, validating each one. Invalid objects are closed and
+// discarded. If no valid objects exist in the pool, a new object is created via the factory
+// function, unless the pool already holds maxOpen objects, in which case serve returns false
+// and the request has to wait. Objects meant for a caller that stopped waiting stay in the pool.
+func (g *poolImpl[C]) serve(get getRequest[C], objectPool *[]poolObject[C]) bool {
 	for len(*objectPool) > 0 {
 		obj := (*objectPool)[0]
 		*objectPool = (*objectPool)[1:]
 
-		poolObjectsIdle.WithLabelValues(g.name).Dec()
+		if !g.usable(obj) {
+			g.discard(obj)
 
-		checkErr := g.checkValid(obj.obj)
-		if checkErr != nil {
-			slog.Warn("pool object is invalid, closing and discarding it", "error", checkErr)
+			continue
+		}
 
-			poolObjectsTotal.WithLabelValues(g.name).Dec()
+		if !g.deliver(get, try.Try[C]{Value: obj.obj}) {
+			*objectPool = append([]poolObject[C]{obj}, *objectPool...)
+		}
 
-			err := obj.closer.Close()
-			if err != nil { //nolint:typecheck
-				slog.Warn("unable to close pool object", "error", err)
+		return true
+	}
 
-				objectsClosedErrors.WithLabelValues(g.name).Inc()
-			}
+	if g.maxOpen > 0 && len(*objectPool)+int(g.outstanding.Load()) >= g.maxOpen {
+		return false
+	}
 
-			continue
-		}
+	obj, err := g.createObject()
+	if err == nil {
+		g.createdAt(obj)
+	}
 
-		foundValid = true
+	if !g.deliver(get, try.Try[C]{Value: obj, Error: err}) && err == nil {
+		*objectPool = append(*objectPool, g.newPoolObject(obj))
+	}
 
-		get.resultChan <- try.Try[C]{Value: obj.obj}
+	return true
+}
 
-		g.outstanding.Inc()
-		poolObjectsInUse.WithLabelValues(g.name).Inc()
-		close(get.resultChan)
+// handleGet processes a Get request. Requests are served right away unless
+// earlier requests are still waiting for an object or the pool is full, in
+// which case the request joins the FIFO queue of waiters.
+// This method runs within the pool's central loop goroutine.
+func (g *poolImpl[C]) handleGet(get getRequest[C], objectPool *[]poolObject[C]) {
+	g.pruneWaiters()
 
-		break
+	if len(g.waiters) == 0 && g.serve(get, objectPool) {
+		return
 	}
 
-	if !foundValid {
-		obj, err := g.createObject()
-		get.resultChan <- try.Try[C]{
-			Value: obj,
-			Error: err,
+	get.queuedAt = time.Now()
+	g.waiters = append(g.waiters, get)
+
+	g.waitCount.Inc()
+	poolWaitCount.WithLabelValues(g.name).Inc()
+}
+
+// serveWaiters hands objects to waiting Get requests, in the order they arrived,
+// for as long as there are objects available or room to create new ones.
+func (g *poolImpl[C]) serveWaiters(objectPool *[]poolObject[C]) {
+	for len(g.waiters) > 0 {
+		get := g.waiters[0]
+
+		if get.state.Load() == getWaiting && !g.serve(get, objectPool) {
+			return
 		}
 
-		if err == nil {
-			g.outstanding.Inc()
-			poolObjectsInUse.WithLabelValues(g.name).Inc()
+		g.waiters = g.waiters[1:]
+	}
+}
+
+// pruneWaiters drops waiters whose callers have stopped waiting.
+func (g *poolImpl[C]) pruneWaiters() {
+	waiters := g.waiters[:0]
+
+	for _, get := range g.waiters {
+		if get.state.Load() == getWaiting {
+			waiters = append(waiters, get)
 		}
+	}
+
+	clear(g.waiters[len(waiters):])
+	g.waiters = waiters
+}
+
+// failWaiters answers all waiting Get requests with err.
+func (g *poolImpl[C]) failWaiters(err error) {
+	for _, get := range g.waiters {
+		g.deliver(get, try.Try[C]{Error: err})
+	}
+
+	g.waiters = nil
+}
+
+// trimIdle closes the objects that have been idle the longest until at most maxIdle remain.
+func (g *poolImpl[C]) trimIdle(objectPool *[]poolObject[C]) {
+	if g.maxIdle <= 0 {
+		return
+	}
+
+	for len(*objectPool) > g.maxIdle {
+		g.discard((*objectPool)[0])
+		*objectPool = (*objectPool)[1:]
 
-		close(get.resultChan)
+		g.maxIdleClosed.Inc()
+		objectsClosedMaxIdle.WithLabelValues(g.name).Inc()
 	}
 }
 
 // handlePut processes a Put request by adding the returned object back to the idle pool.
 // The object is wrapped with a panic-safe closer that ensures Close is only called once,
-// and its lastTouched timestamp is set to the current time for idle tracking. Prometheus
-// metrics are updated to reflect the state transition from in-use to idle.
+// and its lastTouched timestamp is set to the current time for idle tracking. The object
+// then goes to the first waiting Get request, if any; objects exceeding their maximum
+// lifetime or the maximum number of idle objects are closed.
 // This method runs within the pool's central loop goroutine.
 func (g *poolImpl[C]) handlePut(put putRequest[C], objectPool *[]poolObject[C]) {
-	*objectPool = append(*objectPool, poolObject[C]{
-		obj:         put.obj,
-		closer:      closer.CloseOnce(closer.HandlePanic(put.obj)),
-		lastTouched: time.Now(),
-	})
+	*objectPool = append(*objectPool, g.newPoolObject(put.obj))
 
 	g.outstanding.Dec()
-	poolObjectsInUse.WithLabelValues(g.name).Dec()
-	poolObjectsIdle.WithLabelValues(g.name).Inc()
 
 	put.doneChan <- struct{}{}
 
 	close(put.doneChan)
+
+	g.serveWaiters(objectPool)
+	g.trimIdle(objectPool)
+}
+
+// handleValidate checks all idle objects, closing the ones that are no longer usable.
+// This method runs within the pool's central loop goroutine.
+func (g *poolImpl[C]) handleValidate(objectPool *[]poolObject[C]) {
+	var remainder []poolObject[C]
+
+	for _, obj := range *objectPool {
+		if g.usable(obj) {
+			remainder = append(remainder, obj)
+		} else {
+			g.discard(obj)
+		}
+	}
+
+	*objectPool = remainder
+
+	// Closed objects make room for new ones
+	g.serveWaiters(objectPool)
+}
+
+// publishStats records the idle count and updates the pool gauges after each loop iteration.
+func (g *poolImpl[C]) publishStats(idle int) {
+	g.pruneWaiters()
+
+	inUse := g.outstanding.Load()
+
+	g.idle.Store(int64(idle))
+	g.waiting.Store(int64(len(g.waiters)))
+
+	poolObjectsTotal.WithLabelValues(g.name).Set(float64(int64(idle) + inUse))
+	poolObjectsIdle.WithLabelValues(g.name).Set(float64(idle))
+	poolObjectsInUse.WithLabelValues(g.name).Set(float64(inUse))
+	poolWaiters.WithLabelValues(g.name).Set(float64(len(g.waiters)))
 }
 
 // handleCloseIdle processes a CloseIdle request by iterating through all idle objects and closing
@@ -317,16 +653,13 @@ func (g *poolImpl[C]) handleCloseIdle(closeIdleReq closeIdleRequest, objectPool
 			// Object is invalid, close and discard it
 			slog.Warn("pool object is invalid, closing and discarding it", "error", err)
 
-			poolObjectsTotal.WithLabelValues(g.name).Dec()
-
 			if err := obj.closer.Close(); err != nil { //nolint:typecheck,noinlineerr // Inline error handling is clear here
 				errs = append(errs, err)
 				remainder = append(remainder, obj)
 
 				objectsClosedErrors.WithLabelValues(g.name).Inc()
 			} else {
-				poolObjectsTotal.WithLabelValues(g.name).Dec()
-				poolObjectsIdle.WithLabelValues(g.name).Dec()
+				g.forget(obj.obj)
 				objectsClosed.WithLabelValues(g.name).Inc()
 
 				purged++
@@ -342,8 +675,7 @@ func (g *poolImpl[C]) handleCloseIdle(closeIdleReq closeIdleRequest, objectPool
 
 			objectsClosedErrors.WithLabelValues(g.name).Inc()
 		} else {
-			poolObjectsTotal.WithLabelValues(g.name).Dec()
-			poolObjectsIdle.WithLabelValues(g.name).Dec()
+			g.forget(obj.obj)
 			objectsClosed.WithLabelValues(g.name).Inc()
 
 			purged++
@@ -352,6 +684,9 @@ func (g *poolImpl[C]) handleCloseIdle(closeIdleReq closeIdleRequest, objectPool
 
 	*objectPool = remainder
 
+	// Closed objects make room for new ones
+	g.serveWaiters(objectPool)
+
 	return closeIdleResponse{
 		errs:      errs,
 		successes: purged,
@@ -361,7 +696,8 @@ func (g *poolImpl[C]) handleCloseIdle(closeIdleReq closeIdleRequest, objectPool
 // loop is the central event loop that coordinates all pool operations in a single goroutine,
 // providing thread-safety without explicit locking. It processes requests from three channels:
 // getCh for Get operations, putCh for Put operations, and ciCh for CloseIdle operations.
-// The loop also periodically shuffles the object pool to prevent starvation issues.
+// The loop also periodically shuffles the object pool to prevent starvation issues and,
+// if a validation interval is set, checks idle objects in the background.
 //
 // Shutdown is coordinated through channel closures: when Close() is called, channels are closed
 // in sequence (getCh first, then putCh, then ciCh). The loop continues processing until all three
@@ -392,12 +728,24 @@ func (g *poolImpl[C]) loop() {
 	ticker := time.NewTimer(tickerFrequency)
 	defer ticker.Stop()
 
+	var validate <-chan time.Time
+
+	if g.validationInterval > 0 {
+		validationTicker := time.NewTicker(g.validationInterval)
+		defer validationTicker.Stop()
+
+		validate = validationTicker.C
+	}
+
 	for {
 		select {
 		case get, ok := <-g.getCh:
 			if ok {
 				g.handleGet(get, &objectPool)
 			} else {
+				// No more Get calls will come in, and the waiting ones would block Close
+				g.failWaiters(ErrPoolClosed)
+
 				done++
 			}
 		case put, ok := <-g.putCh:
@@ -419,8 +767,12 @@ func (g *poolImpl[C]) loop() {
 			rand.Shuffle(len(objectPool), func(i, j int) {
 				objectPool[i], objectPool[j] = objectPool[j], objectPool[i]
 			})
+		case <-validate:
+			g.handleValidate(&objectPool)
 		}
 
+		g.publishStats(len(objectPool))
+
 		outstanding := g.outstanding.Load()
 
 		if done >= 1 && outstanding > 0 {
@@ -452,9 +804,12 @@ func (g *poolImpl[C]) loop() {
 		}
 	}
 
+	g.idle.Store(0)
+
 	poolObjectsTotal.WithLabelValues(g.name).Set(0)
 	poolObjectsIdle.WithLabelValues(g.name).Set(0)
 	poolObjectsInUse.WithLabelValues(g.name).Set(0)
+	poolWaiters.WithLabelValues(g.name).Set(0)
 
 	g.closeCh <- joinErrors(errs...)
 
@@ -488,15 +843,44 @@ var ErrPoolGet = errors.New("unable to run poolImpl.Get, perhaps the channel is
 // Get will fetch an object from the pool. If there's
 // an existing one, it will use that. If there's none
 // available, it will create a new one and return that.
-func (g *poolImpl[C]) Get() (obj C, err error) {
+func (g *poolImpl[C]) Get() (C, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), g.getTimeout)
+	defer cancel()
+
+	obj, err := g.get(ctx)
+	if err == nil || ctx.Err() == nil || !errors.Is(err, ctx.Err()) {
+		return obj, err
+	}
+
+	if g.maxOpen > 0 {
+		// Creating an object outside the pool would exceed the limit
+		return obj, fmt.Errorf("%w: no pool object became available within %s", ErrTimeout, g.getTimeout)
+	}
+
+	slog.Warn("pool.Get has timed out, creating a new object")
+
+	inst, err := g.createObject()
+	if err == nil {
+		g.outstanding.Inc()
+	}
+
+	return inst, err
+}
+
+// GetCtx is like Get, but waits for an object until ctx is done
+// instead of the pool's get timeout.
+func (g *poolImpl[C]) GetCtx(ctx context.Context) (C, error) {
+	return g.get(ctx)
+}
+
+// get asks the loop for an object and waits for it until ctx is done.
+func (g *poolImpl[C]) get(ctx context.Context) (obj C, err error) {
 	if !g.running.Load() {
 		var zero C
 
 		return zero, ErrPoolClosed
 	}
 
-	rsp := make(chan try.Try[C], 1)
-
 	defer func() {
 		if tmp := recover(); tmp != nil {
 			err = fmt.Errorf("%w: %v", ErrPoolGet, tmp)
@@ -507,35 +891,29 @@ func (g *poolImpl[C]) Get() (obj C, err error) {
 		}
 	}()
 
-	timeoutTimer := time.NewTimer(getTimeout)
-	defer timeoutTimer.Stop()
-
-	r := getRequest[C]{resultChan: rsp}
+	req := getRequest[C]{
+		resultChan: make(chan try.Try[C], 1),
+		state:      atomic.NewInt32(getWaiting),
+	}
 
 	select {
-	case g.getCh <- r:
-		select {
-		case rs := <-rsp:
-			return rs.Value, rs.Error
-		case <-timeoutTimer.C:
-			slog.Warn("pool.Get has timed out, creating a new object")
-
-			inst, err := g.createObject()
-			if err == nil {
-				g.outstanding.Inc()
-			}
+	case g.getCh <- req:
+	case <-ctx.Done():
+		return obj, ctx.Err()
+	}
 
-			return inst, err
+	select {
+	case rs := <-req.resultChan:
+		return rs.Value, rs.Error
+	case <-ctx.Done():
+		if req.state.CompareAndSwap(getWaiting, getAbandoned) {
+			return obj, ctx.Err()
 		}
-	case <-timeoutTimer.C:
-		slog.Warn("pool.Get has timed out, creating a new object")
 
-		inst, err := g.createObject()
-		if err == nil {
-			g.outstanding.Inc()
-		}
+		// The loop handed us an object just in time, so take it
+		rs := <-req.resultChan
 
-		return inst, err
+		return rs.Value, rs.Error
 	}
 }
 
@@ -555,7 +933,7 @@ func (g *poolImpl[C]) Put(obj C) {
 		}
 	}()
 
-	timeoutTimer := time.NewTimer(putTimeout)
+	timeoutTimer := time.NewTimer(g.putTimeout)
 	defer timeoutTimer.Stop()
 
 	req := putRequest[C]{
@@ -572,6 +950,9 @@ func (g *poolImpl[C]) Put(obj C) {
 			return
 		}
 	case <-timeoutTimer.C:
+		// The object is gone for good, so it no longer counts against the pool's limit
+		g.outstanding.Dec()
+
 		should.Close(obj, "unable to close pool object")
 	}
 }
@@ -631,3 +1012,21 @@ func (g *poolImpl[C]) Close() error {
 
 	return <-g.closeCh
 }
+
+// Stats returns the current pool statistics.
+func (g *poolImpl[C]) Stats() Stats {
+	idle := int(g.idle.Load())
+	inUse := int(g.outstanding.Load())
+
+	return Stats{
+		MaxOpen:           g.maxOpen,
+		Open:              idle + inUse,
+		Idle:              idle,
+		InUse:             inUse,
+		Waiters:           int(g.waiting.Load()),
+		WaitCount:         g.waitCount.Load(),
+		WaitDuration:      time.Duration(g.waitDuration.Load()),
+		MaxIdleClosed:     g.maxIdleClosed.Load(),
+		MaxLifetimeClosed: g.maxLifetimeClosed.Load(),
+	}
+}
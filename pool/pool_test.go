@@ -1,6 +1,7 @@
 package pool
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"sync/atomic"
@@ -874,3 +875,238 @@ func TestWithCheckValid_CloseErrorDuringValidation(t *testing.T) {
 	assert.Contains(t, err.Error(), "close error")
 	assert.Equal(t, 0, closed, "should not count as successfully closed")
 }
+
+func TestWithMaxOpen_FIFOWaiters(t *testing.T) {
+	t.Parallel()
+
+	factory := newMockFactory()
+	pool := New(factory.create, WithMaxOpen[*mockCloser](1), WithName[*mockCloser]("test-pool"))
+
+	defer func() {
+		_ = pool.Close()
+	}()
+
+	obj, err := pool.Get()
+	require.NoError(t, err)
+
+	served := make(chan int, 2)
+
+	var wg sync.WaitGroup
+
+	// Start the waiters one at a time so their order is known
+	for waiter := range 2 {
+		wg.Go(func() {
+			got, err := pool.GetCtx(t.Context())
+			assert.NoError(t, err)
+
+			served <- waiter
+
+			pool.Put(got)
+		})
+
+		require.Eventually(t, func() bool {
+			return pool.Stats().Waiters == waiter+1
+		}, time.Second, time.Millisecond)
+	}
+
+	pool.Put(obj)
+	wg.Wait()
+
+	assert.Equal(t, 0, <-served)
+	assert.Equal(t, 1, <-served)
+	assert.Equal(t, 1, factory.CreatedCount(), "the single object should be handed from waiter to waiter")
+
+	stats := pool.Stats()
+	assert.Equal(t, 1, stats.MaxOpen)
+	assert.Equal(t, 1, stats.Open)
+	assert.Equal(t, 1, stats.Idle)
+	assert.Equal(t, 0, stats.InUse)
+	assert.Equal(t, int64(2), stats.WaitCount)
+	assert.Positive(t, stats.WaitDuration)
+}
+
+func TestWithMaxOpen_GetCtxCanceled(t *testing.T) {
+	t.Parallel()
+
+	factory := newMockFactory()
+	pool := New(factory.create, WithMaxOpen[*mockCloser](1), WithName[*mockCloser]("test-pool"))
+
+	defer func() {
+		_ = pool.Close()
+	}()
+
+	obj, err := pool.Get()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = pool.GetCtx(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The canceled waiter must not take the returned object
+	pool.Put(obj)
+
+	stats := pool.Stats()
+	assert.Equal(t, 1, stats.Idle)
+	assert.Equal(t, 0, stats.Waiters)
+
+	again, err := pool.GetCtx(t.Context())
+	require.NoError(t, err)
+	assert.Same(t, obj, again)
+
+	pool.Put(again)
+}
+
+func TestWithMaxOpen_GetTimeout(t *testing.T) {
+	t.Parallel()
+
+	factory := newMockFactory()
+	pool := New(factory.create,
+		WithMaxOpen[*mockCloser](1),
+		WithGetTimeout[*mockCloser](20*time.Millisecond),
+		WithName[*mockCloser]("test-pool"))
+
+	defer func() {
+		_ = pool.Close()
+	}()
+
+	obj, err := pool.Get()
+	require.NoError(t, err)
+
+	// Unlike an unbounded pool, a full pool doesn't create an object on timeout
+	_, err = pool.Get()
+	require.ErrorIs(t, err, ErrTimeout)
+	assert.Equal(t, 1, factory.CreatedCount())
+
+	pool.Put(obj)
+}
+
+func TestWithMaxOpen_CloseFailsWaiters(t *testing.T) {
+	t.Parallel()
+
+	factory := newMockFactory()
+	pool := New(factory.create, WithMaxOpen[*mockCloser](1), WithName[*mockCloser]("test-pool"))
+
+	obj, err := pool.Get()
+	require.NoError(t, err)
+
+	errs := make(chan error, 1)
+
+	go func() {
+		_, err := pool.GetCtx(t.Context())
+		errs <- err
+	}()
+
+	require.Eventually(t, func() bool {
+		return pool.Stats().Waiters == 1
+	}, time.Second, time.Millisecond)
+
+	closed := make(chan error, 1)
+
+	go func() {
+		closed <- pool.Close()
+	}()
+
+	require.ErrorIs(t, <-errs, ErrPoolClosed)
+
+	pool.Put(obj)
+	require.NoError(t, <-closed)
+}
+
+func TestWithMaxIdle(t *testing.T) {
+	t.Parallel()
+
+	factory := newMockFactory()
+	pool := New(factory.create, WithMaxIdle[*mockCloser](1), WithName[*mockCloser]("test-pool"))
+
+	defer func() {
+		_ = pool.Close()
+	}()
+
+	obj1, err := pool.Get()
+	require.NoError(t, err)
+
+	obj2, err := pool.Get()
+	require.NoError(t, err)
+
+	pool.Put(obj1)
+	pool.Put(obj2)
+
+	// The object idle the longest is closed
+	assert.True(t, obj1.IsClosed())
+	assert.False(t, obj2.IsClosed())
+
+	stats := pool.Stats()
+	assert.Equal(t, 1, stats.Idle)
+	assert.Equal(t, int64(1), stats.MaxIdleClosed)
+}
+
+func TestWithMaxLifetime(t *testing.T) {
+	t.Parallel()
+
+	factory := newMockFactory()
+	pool := New(factory.create, WithMaxLifetime[*mockCloser](50*time.Millisecond), WithName[*mockCloser]("test-pool"))
+
+	defer func() {
+		_ = pool.Close()
+	}()
+
+	obj1, err := pool.Get()
+	require.NoError(t, err)
+	pool.Put(obj1)
+
+	// Reused while young
+	obj2, err := pool.Get()
+	require.NoError(t, err)
+	assert.Same(t, obj1, obj2)
+	pool.Put(obj2)
+
+	time.Sleep(60 * time.Millisecond)
+
+	// Replaced once too old
+	obj3, err := pool.Get()
+	require.NoError(t, err)
+	assert.NotSame(t, obj1, obj3)
+	assert.True(t, obj1.IsClosed())
+	assert.Equal(t, int64(1), pool.Stats().MaxLifetimeClosed)
+
+	pool.Put(obj3)
+}
+
+func TestWithValidationInterval(t *testing.T) {
+	t.Parallel()
+
+	factory := newMockFactory()
+
+	var invalid atomic.Bool
+
+	checkValid := func(*mockCloser) error {
+		if invalid.Load() {
+			return errInvalid
+		}
+
+		return nil
+	}
+
+	pool := New(factory.create,
+		WithCheckValid(checkValid),
+		WithValidationInterval[*mockCloser](10*time.Millisecond),
+		WithName[*mockCloser]("test-pool"))
+
+	defer func() {
+		_ = pool.Close()
+	}()
+
+	obj, err := pool.Get()
+	require.NoError(t, err)
+	pool.Put(obj)
+
+	invalid.Store(true)
+
+	// The idle object is closed in the background, without a Get or CloseIdle
+	require.Eventually(t, obj.IsClosed, time.Second, time.Millisecond)
+	require.Eventually(t, func() bool {
+		return pool.Stats().Open == 0
+	}, time.Second, time.Millisecond)
+}
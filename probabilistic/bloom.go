@@ -0,0 +1,152 @@
+package probabilistic
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+	"sync"
+
+	"github.com/amp-labs/amp-common/hashing"
+)
+
+// BloomFilter is a probabilistic set. Contains never reports false for a value
+// that was added, but may report true for one that wasn't, with a probability
+// close to the rate the filter was sized for as long as no more than the
+// expected number of values are added. Values cannot be removed; use a
+// CountingBloomFilter for that.
+type BloomFilter struct {
+	mutex  sync.RWMutex
+	bits   []uint64
+	slots  uint64
+	hashes uint64
+}
+
+// NewBloomFilter creates a Bloom filter sized to hold expected values with the
+// given false-positive rate, for example 0.01 for 1%. The filter takes about
+// 1.44 * log2(1/rate) bits per expected value, so 10 bits per value for 1%.
+func NewBloomFilter(expected int, falsePositiveRate float64) (*BloomFilter, error) {
+	slots, hashes, err := filterSize(expected, falsePositiveRate)
+	if err != nil {
+		return nil, fmt.Errorf("%w, got %d values at rate %f", err, expected, falsePositiveRate)
+	}
+
+	return &BloomFilter{
+		bits:   make([]uint64, (slots+63)/64),
+		slots:  slots,
+		hashes: hashes,
+	}, nil
+}
+
+// Add adds value to the filter. Returns an error if hashing the value fails.
+func (b *BloomFilter) Add(value hashing.Hashable) error {
+	_, err := b.AddIfAbsent(value)
+
+	return err
+}
+
+// AddIfAbsent adds value to the filter and reports whether it was definitely
+// absent before, as a single atomic step. This makes the filter usable for
+// deduplication: a false result means the value was probably seen already.
+// Returns an error if hashing the value fails.
+func (b *BloomFilter) AddIfAbsent(value hashing.Hashable) (bool, error) {
+	sum, err := hash128(value)
+	if err != nil {
+		return false, err
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	added := false
+
+	slotIndexes(sum, b.slots, b.hashes, func(index uint64) {
+		word, mask := index/64, uint64(1)<<(index%64)
+		if b.bits[word]&mask == 0 {
+			b.bits[word] |= mask
+			added = true
+		}
+	})
+
+	return added, nil
+}
+
+// Contains reports whether value may have been added to the filter. A false
+// result is always correct; a true result is wrong with the false-positive rate.
+// Returns an error if hashing the value fails.
+func (b *BloomFilter) Contains(value hashing.Hashable) (bool, error) {
+	sum, err := hash128(value)
+	if err != nil {
+		return false, err
+	}
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	contains := true
+
+	slotIndexes(sum, b.slots, b.hashes, func(index uint64) {
+		if b.bits[index/64]&(uint64(1)<<(index%64)) == 0 {
+			contains = false
+		}
+	})
+
+	return contains, nil
+}
+
+// Merge adds every value of other to this filter, so that it answers as if
+// both had been filled with the same values. Returns ErrIncompatible unless
+// both filters were created with the same expected count and rate.
+func (b *BloomFilter) Merge(other *BloomFilter) error {
+	if b == other {
+		return nil
+	}
+
+	// Copy other first, so the two locks are never held at the same time
+	other.mutex.RLock()
+	otherBits := slices.Clone(other.bits)
+	otherSlots, otherHashes := other.slots, other.hashes
+	other.mutex.RUnlock()
+
+	if otherSlots != b.slots || otherHashes != b.hashes {
+		return fmt.Errorf("%w: %d bits and %d hashes, want %d bits and %d hashes",
+			ErrIncompatible, otherSlots, otherHashes, b.slots, b.hashes)
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for i, word := range otherBits {
+		b.bits[i] |= word
+	}
+
+	return nil
+}
+
+// EstimatedCount estimates how many distinct values have been added, from the
+// share of bits that are set.
+func (b *BloomFilter) EstimatedCount() uint64 {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	set := 0
+	for _, word := range b.bits {
+		set += bits.OnesCount64(word)
+	}
+
+	if uint64(set) >= b.slots {
+		return b.slots
+	}
+
+	m, k := float64(b.slots), float64(b.hashes)
+
+	return uint64(math.Round(-m / k * math.Log(1-float64(set)/m)))
+}
+
+// Clear removes every value from the filter.
+func (b *BloomFilter) Clear() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	clear(b.bits)
+}
@@ -0,0 +1,185 @@
+package probabilistic
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func value(i int) hashing.HashableString {
+	return hashing.HashableString(fmt.Sprintf("value-%d", i))
+}
+
+func TestBloomFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("has no false negatives and few false positives", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := NewBloomFilter(10_000, 0.01)
+		require.NoError(t, err)
+
+		for i := range 10_000 {
+			require.NoError(t, filter.Add(value(i)))
+		}
+
+		for i := range 10_000 {
+			contains, err := filter.Contains(value(i))
+			require.NoError(t, err)
+			require.True(t, contains)
+		}
+
+		falsePositives := 0
+
+		for i := 10_000; i < 20_000; i++ {
+			contains, err := filter.Contains(value(i))
+			require.NoError(t, err)
+
+			if contains {
+				falsePositives++
+			}
+		}
+
+		assert.Less(t, falsePositives, 200, "false-positive rate should stay close to 1%")
+		assert.InEpsilon(t, 10_000, filter.EstimatedCount(), 0.05)
+	})
+
+	t.Run("AddIfAbsent deduplicates", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := NewBloomFilter(100, 0.001)
+		require.NoError(t, err)
+
+		added, err := filter.AddIfAbsent(value(1))
+		require.NoError(t, err)
+		assert.True(t, added)
+
+		added, err = filter.AddIfAbsent(value(1))
+		require.NoError(t, err)
+		assert.False(t, added)
+
+		filter.Clear()
+
+		contains, err := filter.Contains(value(1))
+		require.NoError(t, err)
+		assert.False(t, contains)
+	})
+
+	t.Run("merges filters with the same parameters", func(t *testing.T) {
+		t.Parallel()
+
+		first, err := NewBloomFilter(100, 0.01)
+		require.NoError(t, err)
+		require.NoError(t, first.Add(value(1)))
+
+		second, err := NewBloomFilter(100, 0.01)
+		require.NoError(t, err)
+		require.NoError(t, second.Add(value(2)))
+
+		require.NoError(t, first.Merge(second))
+
+		for _, v := range []hashing.HashableString{value(1), value(2)} {
+			contains, err := first.Contains(v)
+			require.NoError(t, err)
+			assert.True(t, contains)
+		}
+
+		other, err := NewBloomFilter(1000, 0.01)
+		require.NoError(t, err)
+		require.ErrorIs(t, first.Merge(other), ErrIncompatible)
+	})
+
+	t.Run("rejects invalid parameters", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewBloomFilter(0, 0.01)
+		require.ErrorIs(t, err, ErrInvalidCapacity)
+
+		_, err = NewBloomFilter(100, 1)
+		require.ErrorIs(t, err, ErrInvalidFalsePositiveRate)
+	})
+
+	t.Run("is safe for concurrent use", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := NewBloomFilter(1000, 0.01)
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+
+		for i := range 10 {
+			wg.Go(func() {
+				for j := range 100 {
+					assert.NoError(t, filter.Add(value(i*100+j)))
+				}
+			})
+		}
+
+		wg.Wait()
+
+		for i := range 1000 {
+			contains, err := filter.Contains(value(i))
+			require.NoError(t, err)
+			require.True(t, contains)
+		}
+	})
+}
+
+func TestCountingBloomFilter(t *testing.T) {
+	t.Parallel()
+
+	filter, err := NewCountingBloomFilter(1000, 0.01)
+	require.NoError(t, err)
+
+	for i := range 1000 {
+		require.NoError(t, filter.Add(value(i)))
+	}
+
+	require.NoError(t, filter.Add(value(0)))
+
+	for i := range 500 {
+		require.NoError(t, filter.Remove(value(i)))
+	}
+
+	// Added twice, so still there after one removal
+	contains, err := filter.Contains(value(0))
+	require.NoError(t, err)
+	assert.True(t, contains)
+
+	// Removals don't cause false negatives for the values that were kept
+	for i := 500; i < 1000; i++ {
+		contains, err := filter.Contains(value(i))
+		require.NoError(t, err)
+		require.True(t, contains)
+	}
+
+	removed := 0
+
+	for i := 1; i < 500; i++ {
+		contains, err := filter.Contains(value(i))
+		require.NoError(t, err)
+
+		if !contains {
+			removed++
+		}
+	}
+
+	assert.Greater(t, removed, 480)
+
+	other, err := NewCountingBloomFilter(1000, 0.01)
+	require.NoError(t, err)
+	require.NoError(t, other.Add(value(1)))
+	require.NoError(t, filter.Merge(other))
+
+	contains, err = filter.Contains(value(1))
+	require.NoError(t, err)
+	assert.True(t, contains)
+
+	smaller, err := NewCountingBloomFilter(10, 0.01)
+	require.NoError(t, err)
+	require.ErrorIs(t, filter.Merge(smaller), ErrIncompatible)
+}
@@ -0,0 +1,147 @@
+package probabilistic
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/zeebo/xxh3"
+)
+
+// CountingBloomFilter is a Bloom filter that keeps a small counter instead of
+// a bit per slot, so values can be removed again. It uses 8 times the memory
+// of a BloomFilter with the same parameters. Counters stop at 255 and are never
+// decremented after that, which keeps Contains free of false negatives at the
+// cost of a slightly higher false-positive rate once that many values share a slot.
+type CountingBloomFilter struct {
+	mutex    sync.RWMutex
+	counters []uint8
+	hashes   uint64
+}
+
+// NewCountingBloomFilter creates a counting Bloom filter sized to hold expected
+// values with the given false-positive rate, like NewBloomFilter.
+func NewCountingBloomFilter(expected int, falsePositiveRate float64) (*CountingBloomFilter, error) {
+	slots, hashes, err := filterSize(expected, falsePositiveRate)
+	if err != nil {
+		return nil, fmt.Errorf("%w, got %d values at rate %f", err, expected, falsePositiveRate)
+	}
+
+	return &CountingBloomFilter{
+		counters: make([]uint8, slots),
+		hashes:   hashes,
+	}, nil
+}
+
+// Add adds value to the filter. Adding a value twice means it must also be
+// removed twice. Returns an error if hashing the value fails.
+func (c *CountingBloomFilter) Add(value hashing.Hashable) error {
+	sum, err := hash128(value)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	slotIndexes(sum, uint64(len(c.counters)), c.hashes, func(index uint64) {
+		if c.counters[index] < math.MaxUint8 {
+			c.counters[index]++
+		}
+	})
+
+	return nil
+}
+
+// Remove removes one occurrence of value from the filter. Values the filter
+// does not contain are ignored, since removing a value that was never added
+// would cause false negatives for others. Removing a false positive still does,
+// so only remove values that are known to have been added.
+// Returns an error if hashing the value fails.
+func (c *CountingBloomFilter) Remove(value hashing.Hashable) error {
+	sum, err := hash128(value)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.containsLocked(sum) {
+		return nil
+	}
+
+	slotIndexes(sum, uint64(len(c.counters)), c.hashes, func(index uint64) {
+		if c.counters[index] < math.MaxUint8 {
+			c.counters[index]--
+		}
+	})
+
+	return nil
+}
+
+// Contains reports whether value may be in the filter. A false result is always
+// correct; a true result is wrong with the false-positive rate.
+// Returns an error if hashing the value fails.
+func (c *CountingBloomFilter) Contains(value hashing.Hashable) (bool, error) {
+	sum, err := hash128(value)
+	if err != nil {
+		return false, err
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.containsLocked(sum), nil
+}
+
+func (c *CountingBloomFilter) containsLocked(sum xxh3.Uint128) bool {
+	contains := true
+
+	slotIndexes(sum, uint64(len(c.counters)), c.hashes, func(index uint64) {
+		if c.counters[index] == 0 {
+			contains = false
+		}
+	})
+
+	return contains
+}
+
+// Merge adds the values of other to this filter by summing the counters.
+// Returns ErrIncompatible unless both filters were created with the same
+// expected count and rate.
+func (c *CountingBloomFilter) Merge(other *CountingBloomFilter) error {
+	if c == other {
+		return nil
+	}
+
+	// Copy other first, so the two locks are never held at the same time
+	other.mutex.RLock()
+	otherCounters := slices.Clone(other.counters)
+	otherHashes := other.hashes
+	other.mutex.RUnlock()
+
+	if len(otherCounters) != len(c.counters) || otherHashes != c.hashes {
+		return fmt.Errorf("%w: %d counters and %d hashes, want %d counters and %d hashes",
+			ErrIncompatible, len(otherCounters), otherHashes, len(c.counters), c.hashes)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for i, count := range otherCounters {
+		c.counters[i] = uint8(min(int(c.counters[i])+int(count), math.MaxUint8))
+	}
+
+	return nil
+}
+
+// Clear removes every value from the filter.
+func (c *CountingBloomFilter) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	clear(c.counters)
+}
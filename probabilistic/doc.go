@@ -0,0 +1,11 @@
+// Package probabilistic provides memory-bounded approximate set structures:
+// Bloom filters for membership tests with a configurable false-positive rate,
+// counting Bloom filters that also support removal, and HyperLogLog sketches
+// for estimating the number of distinct values.
+//
+// Unlike set.Set, these never store the values themselves, so their size is
+// fixed when they are created no matter how many values are added. All of them
+// accept any hashing.Hashable, are safe for concurrent use, and can be merged
+// with another instance created with the same parameters, for example to
+// combine the results of several workers.
+package probabilistic
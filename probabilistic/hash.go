@@ -0,0 +1,60 @@
+package probabilistic
+
+import (
+	"errors"
+	"math"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/zeebo/xxh3"
+)
+
+var (
+	// ErrInvalidCapacity is returned when a filter is created for fewer than one value.
+	ErrInvalidCapacity = errors.New("expected number of values must be positive")
+
+	// ErrInvalidFalsePositiveRate is returned when a false-positive rate is not between 0 and 1.
+	ErrInvalidFalsePositiveRate = errors.New("false-positive rate must be between 0 and 1")
+
+	// ErrIncompatible is returned when merging instances created with different parameters.
+	ErrIncompatible = errors.New("cannot merge instances with different parameters")
+)
+
+// hash128 hashes value once with 128-bit xxh3. Both halves are well mixed, so
+// every index a filter needs can be derived from this single pass.
+func hash128(value hashing.Hashable) (xxh3.Uint128, error) {
+	h := xxh3.New128()
+
+	err := value.UpdateHash(h)
+	if err != nil {
+		return xxh3.Uint128{}, err
+	}
+
+	return h.Sum128(), nil
+}
+
+// filterSize returns the number of slots and hash functions that keep the
+// false-positive rate at rate once expected values have been added.
+func filterSize(expected int, rate float64) (slots uint64, hashes uint64, err error) {
+	if expected < 1 {
+		return 0, 0, ErrInvalidCapacity
+	}
+
+	if rate <= 0 || rate >= 1 {
+		return 0, 0, ErrInvalidFalsePositiveRate
+	}
+
+	m := math.Ceil(-float64(expected) * math.Log(rate) / (math.Ln2 * math.Ln2))
+	k := math.Round(m / float64(expected) * math.Ln2)
+
+	return uint64(m), uint64(max(k, 1)), nil
+}
+
+// slotIndexes calls f with each of the hashes slots for sum, using double
+// hashing (Kirsch and Mitzenmacher) instead of hashing the value k times.
+func slotIndexes(sum xxh3.Uint128, slots, hashes uint64, f func(index uint64)) {
+	step := sum.Hi | 1
+
+	for i := range hashes {
+		f((sum.Lo + i*step) % slots)
+	}
+}
@@ -0,0 +1,144 @@
+package probabilistic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+	"sync"
+
+	"github.com/amp-labs/amp-common/hashing"
+)
+
+const (
+	// MinPrecision and MaxPrecision bound the precision of a HyperLogLog sketch.
+	MinPrecision = 4
+	MaxPrecision = 18
+
+	// DefaultPrecision uses 16 KiB of registers for a standard error of about 0.8%.
+	DefaultPrecision = 14
+)
+
+// ErrInvalidPrecision is returned when a HyperLogLog precision is outside MinPrecision and MaxPrecision.
+var ErrInvalidPrecision = errors.New("precision must be between 4 and 18")
+
+// HyperLogLog estimates the number of distinct values added to it using a
+// fixed 2^precision bytes of memory, with a standard error of about
+// 1.04 / sqrt(2^precision). Adding a value again does not change the estimate.
+type HyperLogLog struct {
+	mutex     sync.RWMutex
+	registers []uint8
+	precision uint8
+}
+
+// NewHyperLogLog creates an empty HyperLogLog sketch with 2^precision registers.
+// Use DefaultPrecision unless memory or accuracy requirements say otherwise.
+func NewHyperLogLog(precision uint8) (*HyperLogLog, error) {
+	if precision < MinPrecision || precision > MaxPrecision {
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidPrecision, precision)
+	}
+
+	return &HyperLogLog{
+		registers: make([]uint8, 1<<precision),
+		precision: precision,
+	}, nil
+}
+
+// Add records value in the sketch. Returns an error if hashing the value fails.
+func (h *HyperLogLog) Add(value hashing.Hashable) error {
+	sum, err := hash128(value)
+	if err != nil {
+		return err
+	}
+
+	// The first bits pick a register, which keeps the longest run of leading
+	// zeros seen in the remaining ones. The marker bit bounds the run length.
+	index := sum.Lo >> (64 - h.precision)
+	rank := uint8(bits.LeadingZeros64(sum.Lo<<h.precision|1<<(h.precision-1))) + 1 //nolint:gosec // At most 64
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.registers[index] = max(h.registers[index], rank)
+
+	return nil
+}
+
+// Count returns the estimated number of distinct values added to the sketch.
+func (h *HyperLogLog) Count() uint64 {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	m := float64(len(h.registers))
+	sum := 0.0
+	zeros := 0
+
+	for _, register := range h.registers {
+		sum += math.Ldexp(1, -int(register))
+
+		if register == 0 {
+			zeros++
+		}
+	}
+
+	estimate := alpha(len(h.registers)) * m * m / sum
+
+	// Small cardinalities are estimated better by linear counting of the empty
+	// registers. With 64-bit hashes no large-range correction is needed.
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(math.Round(estimate))
+}
+
+// alpha is the bias correction constant for the given number of registers.
+func alpha(registers int) float64 {
+	switch registers {
+	case 16: //nolint:mnd // Constants from the HyperLogLog paper
+		return 0.673
+	case 32: //nolint:mnd // Constants from the HyperLogLog paper
+		return 0.697
+	case 64: //nolint:mnd // Constants from the HyperLogLog paper
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(registers))
+	}
+}
+
+// Merge folds other into this sketch, so that Count estimates the number of
+// distinct values added to either. Returns ErrIncompatible unless both
+// sketches have the same precision.
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if h == other {
+		return nil
+	}
+
+	// Copy other first, so the two locks are never held at the same time
+	other.mutex.RLock()
+	otherRegisters := slices.Clone(other.registers)
+	otherPrecision := other.precision
+	other.mutex.RUnlock()
+
+	if otherPrecision != h.precision {
+		return fmt.Errorf("%w: precision %d, want %d", ErrIncompatible, otherPrecision, h.precision)
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for i, register := range otherRegisters {
+		h.registers[i] = max(h.registers[i], register)
+	}
+
+	return nil
+}
+
+// Clear resets the sketch to empty.
+func (h *HyperLogLog) Clear() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	clear(h.registers)
+}
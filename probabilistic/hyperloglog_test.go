@@ -0,0 +1,62 @@
+package probabilistic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	t.Parallel()
+
+	t.Run("estimates distinct values", func(t *testing.T) {
+		t.Parallel()
+
+		for _, distinct := range []int{10, 1000, 100_000} {
+			sketch, err := NewHyperLogLog(DefaultPrecision)
+			require.NoError(t, err)
+
+			// Every value is added twice; duplicates must not count
+			for range 2 {
+				for i := range distinct {
+					require.NoError(t, sketch.Add(value(i)))
+				}
+			}
+
+			assert.InEpsilon(t, distinct, sketch.Count(), 0.03, "distinct values: %d", distinct)
+		}
+	})
+
+	t.Run("merges sketches", func(t *testing.T) {
+		t.Parallel()
+
+		first, err := NewHyperLogLog(DefaultPrecision)
+		require.NoError(t, err)
+
+		second, err := NewHyperLogLog(DefaultPrecision)
+		require.NoError(t, err)
+
+		for i := range 20_000 {
+			require.NoError(t, first.Add(value(i)))
+			require.NoError(t, second.Add(value(i+10_000)))
+		}
+
+		require.NoError(t, first.Merge(second))
+		assert.InEpsilon(t, 30_000, first.Count(), 0.03)
+
+		first.Clear()
+		assert.Zero(t, first.Count())
+
+		coarse, err := NewHyperLogLog(MinPrecision)
+		require.NoError(t, err)
+		require.ErrorIs(t, first.Merge(coarse), ErrIncompatible)
+	})
+
+	t.Run("rejects invalid precision", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewHyperLogLog(MaxPrecision + 1)
+		require.ErrorIs(t, err, ErrInvalidPrecision)
+	})
+}
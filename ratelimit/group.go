@@ -0,0 +1,126 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Group keeps an independent Limiter per key, such as per customer, per
+// provider or per host, so that each key gets its own quota. Limiters are
+// created on first use by the Group's factory, and the limiters of keys left
+// unused for the idle timeout (see WithIdleTimeout) are evicted, so keys may
+// come from an unbounded set. A limiter of this package is kept until the
+// slots booked with it have passed. All methods are safe for concurrent use.
+type Group struct {
+	opts       *options
+	newLimiter func(key string) Limiter
+
+	mutex     sync.Mutex
+	limiters  map[string]*groupEntry
+	lastSweep time.Time
+}
+
+// booker is implemented by the limiters of this package, which report their
+// latest booked slot so that a Group doesn't evict them while it's ahead.
+type booker interface {
+	bookedUntil() time.Time
+}
+
+// groupEntry is a Group's limiter for one key.
+type groupEntry struct {
+	limiter  Limiter
+	lastUsed time.Time
+}
+
+// NewGroup creates an empty Group whose limiters are created by newLimiter.
+// Of the options, only WithIdleTimeout applies; the factory names its limiters.
+//
+// Example:
+//
+//	customers := ratelimit.NewGroup(func(string) ratelimit.Limiter {
+//	    return ratelimit.NewTokenBucket(5, 10, ratelimit.WithName("customer"))
+//	})
+//
+//	if !customers.Allow(customerID) {
+//	    return errTooManyRequests
+//	}
+func NewGroup(newLimiter func(key string) Limiter, opts ...Option) *Group {
+	o := newOptions(opts)
+
+	return &Group{
+		opts:       o,
+		newLimiter: newLimiter,
+		limiters:   make(map[string]*groupEntry),
+		lastSweep:  o.now(),
+	}
+}
+
+// Get returns the limiter for key, creating it if needed, and marks the key as used.
+func (g *Group) Get(key string) Limiter {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	now := g.opts.now()
+	g.evictIdleLocked(now)
+
+	entry, ok := g.limiters[key]
+	if !ok {
+		entry = &groupEntry{limiter: g.newLimiter(key)}
+		g.limiters[key] = entry
+	}
+
+	entry.lastUsed = now
+
+	return entry.limiter
+}
+
+// Allow calls Allow on the limiter for key.
+func (g *Group) Allow(key string) bool {
+	return g.Get(key).Allow()
+}
+
+// Wait calls Wait on the limiter for key.
+func (g *Group) Wait(ctx context.Context, key string) error {
+	return g.Get(key).Wait(ctx)
+}
+
+// Reserve calls Reserve on the limiter for key.
+func (g *Group) Reserve(key string) *Reservation {
+	return g.Get(key).Reserve()
+}
+
+// Len returns the number of keys that currently have a limiter.
+func (g *Group) Len() int {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.evictIdleLocked(g.opts.now())
+
+	return len(g.limiters)
+}
+
+// evictIdleLocked drops the limiters of keys unused for the idle timeout, except
+// those with slots booked (with Reserve or Wait) that are still ahead: a fresh
+// limiter would not know about them and let callers exceed the limit. It
+// scans the map at most once per idle timeout, so Get stays cheap and a key is
+// evicted between one and two idle timeouts after its last use.
+func (g *Group) evictIdleLocked(now time.Time) {
+	if g.opts.idleTimeout <= 0 || now.Sub(g.lastSweep) < g.opts.idleTimeout {
+		return
+	}
+
+	g.lastSweep = now
+
+	for key, entry := range g.limiters {
+		if now.Sub(entry.lastUsed) < g.opts.idleTimeout {
+			continue
+		}
+
+		if b, ok := entry.limiter.(booker); ok && b.bookedUntil().After(now) {
+			continue
+		}
+
+		delete(g.limiters, key)
+	}
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupLimitsKeysIndependently(t *testing.T) {
+	t.Parallel()
+
+	c := newClock()
+	g := NewGroup(func(string) Limiter {
+		return NewTokenBucket(1, 1, WithName("group_keys"), withNow(c.Now))
+	}, withNow(c.Now))
+
+	assert.True(t, g.Allow("a"))
+	assert.False(t, g.Allow("a"))
+	assert.True(t, g.Allow("b"), "keys have their own quota")
+	assert.Same(t, g.Get("a"), g.Get("a"))
+	assert.Equal(t, 2, g.Len())
+
+	assert.Equal(t, time.Second, g.Reserve("b").Delay())
+}
+
+func TestGroupEvictsIdleKeys(t *testing.T) {
+	t.Parallel()
+
+	c := newClock()
+	created := 0
+	g := NewGroup(func(string) Limiter {
+		created++
+
+		return NewTokenBucket(1, 1, WithName("group_evict"), withNow(c.Now))
+	}, WithIdleTimeout(time.Minute), withNow(c.Now))
+
+	g.Get("idle")
+	c.Advance(30 * time.Second)
+	g.Get("busy")
+	c.Advance(30 * time.Second)
+	assert.Equal(t, 1, g.Len(), "idle is evicted, busy is kept")
+
+	g.Get("idle")
+	assert.Equal(t, 3, created, "an evicted key gets a fresh limiter")
+}
+
+func TestGroupKeepsKeysWithBookedSlots(t *testing.T) {
+	t.Parallel()
+
+	c := newClock()
+	g := NewGroup(func(string) Limiter {
+		return NewSlidingWindow(1, time.Hour, WithName("group_booked"), withNow(c.Now))
+	}, WithIdleTimeout(time.Minute), withNow(c.Now))
+
+	assert.True(t, g.Allow("a"))
+	assert.Equal(t, time.Hour, g.Reserve("a").Delay())
+
+	c.Advance(2 * time.Minute)
+	assert.Equal(t, 1, g.Len(), "the booked slot is still ahead")
+	assert.False(t, g.Allow("a"), "the limiter still counts the booked slot")
+
+	c.Advance(time.Hour)
+	assert.Zero(t, g.Len(), "the booked slot has passed")
+}
+
+func TestGroupWithoutIdleTimeoutKeepsKeys(t *testing.T) {
+	t.Parallel()
+
+	c := newClock()
+	g := NewGroup(func(string) Limiter {
+		return NewTokenBucket(1, 1, WithName("group_keep"))
+	}, WithIdleTimeout(0), withNow(c.Now))
+
+	g.Get("a")
+	c.Advance(24 * time.Hour)
+	assert.Equal(t, 1, g.Len())
+}
@@ -0,0 +1,212 @@
+// Package ratelimit throttles calls to a rate, such as outbound calls to a
+// provider API that enforces quotas.
+//
+// Two algorithms are available: a token bucket (NewTokenBucket), which allows
+// bursts on top of a steady rate, and a sliding window (NewSlidingWindow),
+// which allows at most a number of calls within any window of time. Both
+// offer three ways to take a slot:
+//   - Allow takes a slot if one is free right now, and never waits
+//   - Wait blocks until a slot is free, or fails if the context ends first
+//   - Reserve books the next free slot and says how long to wait for it
+//
+// A Group keeps one limiter per key, such as per customer or per provider,
+// and evicts the limiters of idle keys. transport.NewRateLimitTransport
+// applies a Group per host to outbound HTTP requests. Throttled calls are
+// exported as Prometheus metrics.
+//
+// Example:
+//
+//	limiter := ratelimit.NewTokenBucket(10, 5, ratelimit.WithName("provider"))
+//
+//	if err := limiter.Wait(ctx); err != nil {
+//	    return err
+//	}
+//
+//	return callProvider(ctx)
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrDeadlineTooSoon is returned by Wait when the context's deadline would
+// expire before a slot frees up. No slot is taken in that case.
+var ErrDeadlineTooSoon = errors.New("rate limit wait would exceed the context deadline")
+
+// InfDuration is the maximum wait, used when waiting has no deadline.
+const InfDuration = time.Duration(math.MaxInt64)
+
+// Limiter limits how often calls can proceed. All methods are safe for concurrent use.
+type Limiter interface {
+	// Allow takes a slot if one is free now and reports whether it did. It never waits.
+	Allow() bool
+
+	// Wait blocks until a slot is free and takes it. It returns the context's
+	// error if the context ends first, and ErrDeadlineTooSoon without waiting
+	// if the context's deadline would expire before a slot frees up.
+	Wait(ctx context.Context) error
+
+	// Reserve books the next free slot, which may be in the future. The caller
+	// must wait for the reservation's Delay before proceeding, or Cancel it.
+	Reserve() *Reservation
+}
+
+// Reservation is a slot booked with Limiter.Reserve.
+type Reservation struct {
+	limiter *limiter
+	at      time.Time
+
+	once sync.Once
+}
+
+// Delay returns how long the caller must wait before proceeding, or zero if it
+// may proceed now.
+func (r *Reservation) Delay() time.Duration {
+	return max(r.at.Sub(r.limiter.opts.now()), 0)
+}
+
+// Cancel gives the slot back, if it's still in the future, so that other
+// callers can use it. Calling Cancel more than once has no further effect.
+func (r *Reservation) Cancel() {
+	r.once.Do(func() {
+		r.limiter.cancel(r.at)
+	})
+}
+
+// policy is a rate-limiting algorithm. Its methods are called with the limiter's lock held.
+type policy interface {
+	// reserve books the first free slot no later than maxWait from now and
+	// returns its time, or returns false without booking if there is none.
+	reserve(now time.Time, maxWait time.Duration) (time.Time, bool)
+
+	// cancel gives back a slot booked for at, if at is still in the future.
+	cancel(at, now time.Time)
+}
+
+// limiter implements Limiter on top of a policy.
+type limiter struct {
+	opts *options
+
+	mutex  sync.Mutex
+	policy policy
+	latest time.Time // latest slot booked, to keep a Group from evicting the limiter before it
+}
+
+func newLimiter(opts []Option, p policy) Limiter {
+	o := newOptions(opts)
+
+	throttledCalls.WithLabelValues(o.name, resultDelayed).Add(0)
+	throttledCalls.WithLabelValues(o.name, resultRejected).Add(0)
+
+	return &limiter{
+		opts:   o,
+		policy: p,
+	}
+}
+
+func (l *limiter) Allow() bool {
+	_, ok := l.reserve(0)
+	if !ok {
+		throttledCalls.WithLabelValues(l.opts.name, resultRejected).Inc()
+	}
+
+	return ok
+}
+
+func (l *limiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	maxWait := InfDuration
+	if deadline, ok := ctx.Deadline(); ok {
+		maxWait = deadline.Sub(l.opts.now())
+	}
+
+	at, ok := l.reserve(maxWait)
+	if !ok {
+		throttledCalls.WithLabelValues(l.opts.name, resultRejected).Inc()
+
+		return fmt.Errorf("%w: %s", ErrDeadlineTooSoon, l.opts.name)
+	}
+
+	delay := at.Sub(l.opts.now())
+	if delay <= 0 {
+		return nil
+	}
+
+	l.observeDelay(delay)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.cancel(at)
+
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (l *limiter) Reserve() *Reservation {
+	at, _ := l.reserve(InfDuration)
+
+	if delay := at.Sub(l.opts.now()); delay > 0 {
+		l.observeDelay(delay)
+	}
+
+	return &Reservation{
+		limiter: l,
+		at:      at,
+	}
+}
+
+func (l *limiter) reserve(maxWait time.Duration) (time.Time, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	at, ok := l.policy.reserve(l.opts.now(), maxWait)
+	if ok && at.After(l.latest) {
+		l.latest = at
+	}
+
+	return at, ok
+}
+
+// bookedUntil returns the latest slot booked with the limiter. Cancelled
+// slots are not taken into account.
+func (l *limiter) bookedUntil() time.Time {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.latest
+}
+
+func (l *limiter) cancel(at time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.policy.cancel(at, l.opts.now())
+}
+
+// observeDelay records a call made to wait.
+func (l *limiter) observeDelay(delay time.Duration) {
+	throttledCalls.WithLabelValues(l.opts.name, resultDelayed).Inc()
+	waitDuration.WithLabelValues(l.opts.name).Observe(delay.Seconds())
+}
+
+// unlimited is the policy of limiters configured without a limit.
+type unlimited struct{}
+
+func (unlimited) reserve(now time.Time, _ time.Duration) (time.Time, bool) {
+	return now, true
+}
+
+func (unlimited) cancel(time.Time, time.Time) {}
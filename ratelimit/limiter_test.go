@@ -0,0 +1,191 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clock is a manually advanced time source.
+type clock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func newClock() *clock {
+	return &clock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *clock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func TestTokenBucketAllowsBurstThenRate(t *testing.T) {
+	t.Parallel()
+
+	c := newClock()
+	l := NewTokenBucket(2, 3, WithName("bucket_allow"), withNow(c.Now))
+
+	for range 3 {
+		assert.True(t, l.Allow(), "the bucket starts full")
+	}
+
+	assert.False(t, l.Allow())
+
+	c.Advance(500 * time.Millisecond)
+	assert.True(t, l.Allow(), "one token refills every half second")
+	assert.False(t, l.Allow())
+
+	c.Advance(time.Hour)
+
+	for range 3 {
+		assert.True(t, l.Allow(), "refills are capped at the burst")
+	}
+
+	assert.False(t, l.Allow())
+}
+
+func TestTokenBucketReserve(t *testing.T) {
+	t.Parallel()
+
+	c := newClock()
+	l := NewTokenBucket(1, 1, WithName("bucket_reserve"), withNow(c.Now))
+
+	assert.Zero(t, l.Reserve().Delay())
+
+	first := l.Reserve()
+	assert.Equal(t, time.Second, first.Delay())
+
+	second := l.Reserve()
+	assert.Equal(t, 2*time.Second, second.Delay())
+
+	second.Cancel()
+	second.Cancel()
+	assert.Equal(t, 2*time.Second, l.Reserve().Delay(), "a cancelled slot is given back once")
+
+	c.Advance(time.Second)
+	assert.Zero(t, first.Delay())
+	first.Cancel()
+	assert.False(t, l.Allow(), "a slot already reached can't be given back")
+}
+
+func TestSlidingWindowLimitsCallsPerWindow(t *testing.T) {
+	t.Parallel()
+
+	c := newClock()
+	l := NewSlidingWindow(2, time.Minute, WithName("window_allow"), withNow(c.Now))
+
+	assert.True(t, l.Allow())
+	c.Advance(30 * time.Second)
+	assert.True(t, l.Allow())
+	assert.False(t, l.Allow())
+
+	c.Advance(30 * time.Second)
+	assert.True(t, l.Allow(), "the first call left the window")
+	assert.False(t, l.Allow())
+
+	c.Advance(29 * time.Second)
+	assert.False(t, l.Allow())
+	c.Advance(time.Second)
+	assert.True(t, l.Allow())
+}
+
+func TestSlidingWindowReserve(t *testing.T) {
+	t.Parallel()
+
+	c := newClock()
+	l := NewSlidingWindow(2, time.Minute, WithName("window_reserve"), withNow(c.Now))
+
+	assert.Zero(t, l.Reserve().Delay())
+	c.Advance(10 * time.Second)
+	assert.Zero(t, l.Reserve().Delay())
+
+	third := l.Reserve()
+	assert.Equal(t, 50*time.Second, third.Delay())
+
+	fourth := l.Reserve()
+	assert.Equal(t, time.Minute, fourth.Delay())
+
+	third.Cancel()
+	assert.Equal(t, time.Minute, l.Reserve().Delay(), "the cancelled slot frees capacity, which would otherwise open at 110s")
+}
+
+func TestUnlimited(t *testing.T) {
+	t.Parallel()
+
+	for name, l := range map[string]Limiter{
+		"token bucket":   NewTokenBucket(0, 0, WithName("unlimited_bucket")),
+		"sliding window": NewSlidingWindow(0, time.Minute, WithName("unlimited_window")),
+	} {
+		for range 1000 {
+			require.True(t, l.Allow(), name)
+		}
+
+		assert.Zero(t, l.Reserve().Delay(), name)
+		require.NoError(t, l.Wait(t.Context()), name)
+	}
+}
+
+func TestWaitDelaysUntilSlot(t *testing.T) {
+	t.Parallel()
+
+	l := NewTokenBucket(20, 1, WithName("wait_delay"))
+
+	require.NoError(t, l.Wait(t.Context()))
+
+	start := time.Now()
+	require.NoError(t, l.Wait(t.Context()))
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+}
+
+func TestWaitRejectsWhenDeadlineTooSoon(t *testing.T) {
+	t.Parallel()
+
+	l := NewSlidingWindow(1, time.Minute, WithName("wait_deadline"))
+
+	require.True(t, l.Allow())
+
+	ctx, cancel := context.WithTimeout(t.Context(), 30*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	require.ErrorIs(t, l.Wait(ctx), ErrDeadlineTooSoon)
+	assert.Less(t, time.Since(start), time.Second, "the wait fails without waiting")
+	assert.Equal(t, time.Minute, l.Reserve().Delay().Round(time.Second), "a rejected wait takes no slot")
+}
+
+func TestWaitGivesSlotBackWhenCancelled(t *testing.T) {
+	t.Parallel()
+
+	c := newClock()
+	l := NewTokenBucket(1, 1, WithName("wait_cancel"), withNow(c.Now))
+
+	require.True(t, l.Allow())
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+
+	// The fake clock never advances, so the wait can only end with the context.
+	require.ErrorIs(t, l.Wait(ctx), context.DeadlineExceeded)
+
+	c.Advance(time.Second)
+	assert.True(t, l.Allow(), "the cancelled wait gave its slot back")
+
+	cancelled, cancelNow := context.WithCancel(t.Context())
+	cancelNow()
+	require.ErrorIs(t, l.Wait(cancelled), context.Canceled)
+}
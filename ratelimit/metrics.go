@@ -0,0 +1,30 @@
+package ratelimit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Prometheus metrics for monitoring rate limiters. Every metric is labeled
+// with the limiter's name (see WithName).
+
+var (
+	// throttledCalls counts calls the limiter slowed down ("delayed") or refused ("rejected").
+	throttledCalls = promauto.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "ratelimit_throttled_total",
+		Help: "The total number of calls delayed or rejected by the rate limiter",
+	}, []string{"limiter", "result"})
+
+	// waitDuration observes how long Wait and Reserve made callers wait.
+	waitDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{ //nolint:gochecknoglobals
+		Name:    "ratelimit_wait_seconds",
+		Help:    "The time callers were made to wait by the rate limiter",
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 10), //nolint:mnd // 1ms to ~4min
+	}, []string{"limiter"})
+)
+
+// Values of the result label of ratelimit_throttled_total.
+const (
+	resultDelayed  = "delayed"
+	resultRejected = "rejected"
+)
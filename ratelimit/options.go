@@ -0,0 +1,59 @@
+package ratelimit
+
+import "time"
+
+const (
+	defaultName        = "ratelimit"
+	defaultIdleTimeout = 10 * time.Minute
+)
+
+// options holds the configuration accumulated from Option values before a limiter or Group is built.
+type options struct {
+	name        string
+	idleTimeout time.Duration
+	now         func() time.Time
+}
+
+// Option is a functional option for configuring a Limiter or Group during creation.
+type Option func(*options)
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		name:        defaultName,
+		idleTimeout: defaultIdleTimeout,
+		now:         time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithName sets the name used in Prometheus metrics labels to distinguish
+// between limiters. If not specified, the default name "ratelimit" is used.
+// It has no effect on a Group, whose limiters are named by its factory.
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// WithIdleTimeout sets how long a Group keeps the limiter of a key that isn't
+// used. It should exceed the time the limiter takes to recover its full
+// capacity, or callers could get around the limit by waiting for eviction.
+// Non-positive values mean limiters are never evicted. The default is 10
+// minutes. It has no effect on a single Limiter.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.idleTimeout = timeout
+	}
+}
+
+// withNow replaces the clock, for tests.
+func withNow(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"slices"
+	"time"
+)
+
+// NewSlidingWindow creates a Limiter that allows at most limit calls within
+// any period of length window. Unlike a token bucket, it never allows more
+// than limit calls in a window, which suits providers enforcing quotas such as
+// "100 requests per minute" over a rolling window.
+//
+// It remembers the time of each call within the window, so memory grows with
+// limit. A non-positive limit or window disables the limit, letting every
+// call through.
+//
+// Example:
+//
+//	limiter := ratelimit.NewSlidingWindow(100, time.Minute, ratelimit.WithName("provider"))
+func NewSlidingWindow(limit int, window time.Duration, opts ...Option) Limiter {
+	if limit <= 0 || window <= 0 {
+		return newLimiter(opts, unlimited{})
+	}
+
+	return newLimiter(opts, &slidingWindow{
+		limit:  limit,
+		window: window,
+	})
+}
+
+// slidingWindow is the sliding window policy. It keeps the sorted times of
+// the calls within the window, including calls booked in the future.
+type slidingWindow struct {
+	limit  int
+	window time.Duration
+	calls  []time.Time
+}
+
+// prune forgets the calls that have left the window.
+func (w *slidingWindow) prune(now time.Time) {
+	cutoff := now.Add(-w.window)
+
+	expired := 0
+	for expired < len(w.calls) && !w.calls[expired].After(cutoff) {
+		expired++
+	}
+
+	w.calls = w.calls[expired:]
+}
+
+func (w *slidingWindow) reserve(now time.Time, maxWait time.Duration) (time.Time, bool) {
+	w.prune(now)
+
+	at := now
+	if len(w.calls) >= w.limit {
+		// The next slot opens once the call limit places back leaves the window.
+		// After a cancellation this can be later than strictly needed, never earlier.
+		at = w.calls[len(w.calls)-w.limit].Add(w.window)
+	}
+
+	if at.Sub(now) > maxWait {
+		return time.Time{}, false
+	}
+
+	// Cancellations can leave calls booked in the future while the window has
+	// room, so insert in order rather than append.
+	i, _ := slices.BinarySearchFunc(w.calls, at, func(call, target time.Time) int {
+		return call.Compare(target)
+	})
+	w.calls = slices.Insert(w.calls, i, at)
+
+	return at, true
+}
+
+func (w *slidingWindow) cancel(at, now time.Time) {
+	if !at.After(now) {
+		return
+	}
+
+	if i := slices.Index(w.calls, at); i >= 0 {
+		w.calls = slices.Delete(w.calls, i, i+1)
+	}
+}
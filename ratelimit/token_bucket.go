@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"time"
+)
+
+// NewTokenBucket creates a Limiter that allows rate calls per second on
+// average, with bursts of up to burst calls. The bucket holds up to burst
+// tokens and starts full; each call takes a token, and tokens are added back
+// at rate per second.
+//
+// A non-positive rate disables the limit, letting every call through, which
+// suits limits read from configuration where zero means "off". A burst below
+// one counts as one.
+//
+// Example:
+//
+//	// 100 calls per minute, in bursts of up to 10
+//	limiter := ratelimit.NewTokenBucket(100.0/60, 10, ratelimit.WithName("provider"))
+func NewTokenBucket(rate float64, burst int, opts ...Option) Limiter {
+	if rate <= 0 {
+		return newLimiter(opts, unlimited{})
+	}
+
+	return newLimiter(opts, &tokenBucket{
+		rate:   rate,
+		burst:  float64(max(burst, 1)),
+		tokens: float64(max(burst, 1)),
+	})
+}
+
+// tokenBucket is the token bucket policy.
+type tokenBucket struct {
+	rate   float64 // tokens added per second
+	burst  float64 // capacity of the bucket
+	tokens float64 // tokens available as of last, negative while calls are booked ahead
+	last   time.Time
+}
+
+// advance adds the tokens accumulated since the last update.
+func (b *tokenBucket) advance(now time.Time) {
+	if b.last.IsZero() {
+		b.last = now
+
+		return
+	}
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+func (b *tokenBucket) reserve(now time.Time, maxWait time.Duration) (time.Time, bool) {
+	b.advance(now)
+
+	tokens := b.tokens - 1
+
+	var wait time.Duration
+	if tokens < 0 {
+		wait = secondsToDuration(-tokens / b.rate)
+	}
+
+	if wait > maxWait {
+		return time.Time{}, false
+	}
+
+	b.tokens = tokens
+
+	return now.Add(wait), true
+}
+
+func (b *tokenBucket) cancel(at, now time.Time) {
+	if !at.After(now) {
+		return
+	}
+
+	b.advance(now)
+	b.tokens = min(b.burst, b.tokens+1)
+}
+
+// secondsToDuration converts seconds to a Duration, saturating at InfDuration.
+func secondsToDuration(seconds float64) time.Duration {
+	d := seconds * float64(time.Second)
+	if d >= float64(InfDuration) {
+		return InfDuration
+	}
+
+	return time.Duration(d)
+}
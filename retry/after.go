@@ -0,0 +1,62 @@
+package retry
+
+import (
+	"errors"
+	"time"
+)
+
+// delayedError wraps an error to request a specific delay before the next attempt.
+// This is used internally by the After function.
+type delayedError struct {
+	error
+	delay time.Duration
+}
+
+// Temporary returns true to indicate this error should be retried.
+func (e *delayedError) Temporary() bool { return true }
+
+// Unwrap returns the underlying error for error chain unwrapping.
+func (e *delayedError) Unwrap() error {
+	return e.error
+}
+
+// After wraps an error to mark it as retryable no sooner than delay from now.
+// The delay replaces the backoff (and jitter) for the next attempt only, and
+// the error is retried even if it matches none of the WithRetryIf predicates.
+// If the context's deadline would expire before the delay elapses, the retry
+// loop gives up immediately and returns the error. Negative delays count as zero.
+//
+// Example:
+//
+//	if quota.Exhausted() {
+//	    return retry.After(errQuotaExhausted, quota.ResetIn())
+//	}
+func After(err error, delay time.Duration) Error {
+	return &delayedError{error: err, delay: max(delay, 0)}
+}
+
+// afterDelay returns the delay requested with After, if any.
+func afterDelay(err error) (time.Duration, bool) {
+	var delayed *delayedError
+	if errors.As(err, &delayed) {
+		return delayed.delay, true
+	}
+
+	return 0, false
+}
+
+// requestedDelay returns the delay to wait before retrying err instead of backing
+// off: the one requested with After, or else the one a server asked for in a
+// response checked with CheckResponse.
+func requestedDelay(err error) (time.Duration, bool) {
+	if delay, ok := afterDelay(err); ok {
+		return delay, true
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.RetryAfter()
+	}
+
+	return 0, false
+}
@@ -0,0 +1,88 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAfter(t *testing.T) {
+	t.Parallel()
+
+	err := After(io.EOF, time.Second)
+
+	assert.True(t, err.Temporary())
+	require.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, io.EOF.Error(), err.Error())
+
+	delay, ok := afterDelay(err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, delay)
+
+	delay, ok = afterDelay(After(io.EOF, -time.Second))
+	assert.True(t, ok)
+	assert.Zero(t, delay)
+
+	_, ok = afterDelay(io.EOF)
+	assert.False(t, ok)
+}
+
+func TestDo_AfterOverridesBackoff(t *testing.T) {
+	t.Parallel()
+
+	callTimes := []time.Time{}
+	err := Do(t.Context(), func(ctx context.Context) error {
+		callTimes = append(callTimes, time.Now())
+		if len(callTimes) < 2 {
+			return After(io.EOF, 100*time.Millisecond)
+		}
+
+		return nil
+	}, WithBackoff(ExpBackoff{Base: time.Hour, Max: time.Hour, Factor: 1}))
+
+	require.NoError(t, err)
+	require.Len(t, callTimes, 2)
+
+	delay := callTimes[1].Sub(callTimes[0])
+	assert.GreaterOrEqual(t, delay, 100*time.Millisecond, "waits at least the requested delay, without jitter")
+	assert.Less(t, delay, time.Hour)
+}
+
+func TestDo_AfterBeyondDeadline(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+	defer cancel()
+
+	callCount := 0
+	start := time.Now()
+	err := Do(ctx, func(ctx context.Context) error {
+		callCount++
+
+		return After(io.EOF, time.Minute)
+	})
+
+	require.ErrorIs(t, err, io.EOF)
+	assert.False(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, 1, callCount)
+	assert.Less(t, time.Since(start), time.Second, "gives up without waiting for the deadline")
+}
+
+func TestDo_AbortOverridesAfter(t *testing.T) {
+	t.Parallel()
+
+	callCount := 0
+	err := Do(t.Context(), func(ctx context.Context) error {
+		callCount++
+
+		return Abort(After(io.EOF, time.Millisecond))
+	})
+
+	require.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, 1, callCount)
+}
@@ -2,6 +2,7 @@ package retry
 
 import (
 	"math"
+	"math/rand"
 	"time"
 )
 
@@ -48,3 +49,198 @@ func (b ExpBackoff) Delay(attempt uint) time.Duration {
 
 	return d
 }
+
+// ConstantBackoff waits the same Interval before every retry.
+//
+// Example:
+//
+//	backoff := retry.ConstantBackoff{Interval: 500 * time.Millisecond}
+//	// Delays: 500ms, 500ms, 500ms, ...
+type ConstantBackoff struct {
+	// Interval is the delay before every retry.
+	Interval time.Duration
+}
+
+// Delay returns Interval regardless of the attempt.
+func (b ConstantBackoff) Delay(uint) time.Duration {
+	return b.Interval
+}
+
+// LinearBackoff grows the delay by a fixed Step with each attempt: Base + Step*attempt.
+// The delay is capped at Max, unless Max is zero.
+//
+// Example:
+//
+//	backoff := retry.LinearBackoff{
+//	    Base: 100 * time.Millisecond,
+//	    Step: 250 * time.Millisecond,
+//	    Max:  time.Second,
+//	}
+//	// Delays: 100ms, 350ms, 600ms, 850ms, 1s, 1s, ...
+type LinearBackoff struct {
+	// Base is the initial delay duration.
+	Base time.Duration
+	// Step is added to the delay with each attempt.
+	Step time.Duration
+	// Max is the maximum delay duration (cap), or zero for no cap.
+	Max time.Duration
+}
+
+// Delay calculates the linear backoff delay for the given attempt.
+func (b LinearBackoff) Delay(attempt uint) time.Duration {
+	f := float64(b.Base) + float64(b.Step)*float64(attempt)
+
+	return capDelay(f, b.Max)
+}
+
+// FibonacciBackoff grows the delay along the Fibonacci sequence: Base, Base,
+// 2*Base, 3*Base, 5*Base, ... This grows more gently than doubling. The delay
+// is capped at Max, unless Max is zero.
+//
+// Example:
+//
+//	backoff := retry.FibonacciBackoff{Base: 100 * time.Millisecond, Max: time.Second}
+//	// Delays: 100ms, 100ms, 200ms, 300ms, 500ms, 800ms, 1s, 1s, ...
+type FibonacciBackoff struct {
+	// Base is the initial delay duration.
+	Base time.Duration
+	// Max is the maximum delay duration (cap), or zero for no cap.
+	Max time.Duration
+}
+
+// Delay calculates the Fibonacci backoff delay for the given attempt.
+func (b FibonacciBackoff) Delay(attempt uint) time.Duration {
+	previous, current := 0.0, 1.0
+
+	for range attempt {
+		previous, current = current, previous+current
+
+		// Stop once the delay can only be capped; this also keeps the loop short
+		// and the arithmetic finite for large attempts.
+		if b.Max > 0 && float64(b.Base)*current >= float64(b.Max) {
+			return b.Max
+		}
+
+		if math.IsInf(current, 1) {
+			break
+		}
+	}
+
+	return capDelay(float64(b.Base)*current, b.Max)
+}
+
+// DecorrelatedJitterBackoff implements the "decorrelated jitter" algorithm from
+// the AWS Architecture Blog: each delay is a random duration between Base and
+// three times the previous delay, capped at Max. It spreads retries out at
+// least as well as full jitter while growing more slowly than exponential
+// backoff.
+//
+// The delay is randomized already, so combine it with WithJitter(WithoutJitter).
+// Since a Backoff is shared between concurrent calls, Delay simulates the chain
+// of previous delays for each attempt rather than remembering the last delay
+// it returned, which yields the same distribution of delays.
+//
+// Example:
+//
+//	runner := retry.NewRunner(
+//	    retry.WithBackoff(retry.DecorrelatedJitterBackoff{Base: 100 * time.Millisecond, Max: 10 * time.Second}),
+//	    retry.WithJitter(retry.WithoutJitter),
+//	)
+type DecorrelatedJitterBackoff struct {
+	// Base is the minimum delay duration.
+	Base time.Duration
+	// Max is the maximum delay duration (cap).
+	Max time.Duration
+}
+
+const (
+	// decorrelatedJitterGrowth is the largest factor between consecutive decorrelated-jitter delays.
+	decorrelatedJitterGrowth = 3
+
+	// decorrelatedJitterMaxSteps bounds the simulated chain of delays. Later
+	// delays no longer depend on the attempt, so longer chains add nothing.
+	decorrelatedJitterMaxSteps = 64
+)
+
+// Delay calculates a random decorrelated-jitter delay for the given attempt.
+func (b DecorrelatedJitterBackoff) Delay(attempt uint) time.Duration {
+	delay := float64(b.Base)
+
+	for range min(attempt, decorrelatedJitterMaxSteps-1) + 1 {
+		upper := math.Min(delay*decorrelatedJitterGrowth, float64(b.Max))
+		if upper <= float64(b.Base) {
+			return min(b.Base, b.Max)
+		}
+
+		//nolint:gosec // G404: math/rand is sufficient for jitter; crypto/rand is unnecessary overhead
+		delay = float64(b.Base) + rand.Float64()*(upper-float64(b.Base))
+	}
+
+	return time.Duration(delay)
+}
+
+// AdaptiveBackoff widens the delays of another Backoff while the Budget sees
+// many retries, slowing everyone down when a dependency is struggling and
+// backing off to the regular delays once it recovers.
+//
+// The delay is multiplied by a factor between 1 and MaxFactor that grows with
+// the Budget's current ratio of retries to initial calls, reaching MaxFactor
+// when the ratio hits Budget.Ratio (or 1, if Budget.Ratio isn't positive).
+// Pass the same Budget to WithBudget so that it observes the calls.
+//
+// Example:
+//
+//	budget := &retry.Budget{Rate: 10, Ratio: 0.2}
+//	runner := retry.NewRunner(
+//	    retry.WithBudget(budget),
+//	    retry.WithBackoff(retry.AdaptiveBackoff{
+//	        Backoff:   retry.ExpBackoff{Base: 100 * time.Millisecond, Max: 2 * time.Second, Factor: 2},
+//	        Budget:    budget,
+//	        MaxFactor: 4,
+//	    }),
+//	)
+type AdaptiveBackoff struct {
+	// Backoff calculates the regular delays.
+	Backoff Backoff
+	// Budget supplies the observed ratio of retries to initial calls.
+	Budget *Budget
+	// MaxFactor is the largest multiplier applied to the regular delays. Values
+	// at or below 1 leave the delays unchanged.
+	MaxFactor float64
+}
+
+// Delay calculates the regular delay for the given attempt and widens it
+// according to the Budget's current retry ratio.
+func (b AdaptiveBackoff) Delay(attempt uint) time.Duration {
+	if b.Backoff == nil {
+		return 0
+	}
+
+	delay := b.Backoff.Delay(attempt)
+	if b.Budget == nil || b.MaxFactor <= 1 {
+		return delay
+	}
+
+	limit := b.Budget.Ratio
+	if limit <= 0 {
+		limit = 1
+	}
+
+	pressure := math.Min(b.Budget.retryRatio()/limit, 1)
+
+	return capDelay(float64(delay)*(1+(b.MaxFactor-1)*pressure), 0)
+}
+
+// capDelay converts f to a Duration no greater than limit (if limit is
+// positive) and no greater than the largest representable Duration.
+func capDelay(f float64, limit time.Duration) time.Duration {
+	if limit > 0 && f > float64(limit) {
+		return limit
+	}
+
+	if f >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return time.Duration(max(f, 0))
+}
@@ -1,6 +1,7 @@
 package retry
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -117,3 +118,127 @@ func TestExpBackoff_ZeroFactor(t *testing.T) {
 		assert.Equal(t, 100*time.Millisecond, delay)
 	}
 }
+
+func TestConstantBackoff_Delay(t *testing.T) {
+	t.Parallel()
+
+	backoff := ConstantBackoff{Interval: 250 * time.Millisecond}
+
+	for attempt := range uint(5) {
+		assert.Equal(t, 250*time.Millisecond, backoff.Delay(attempt))
+	}
+}
+
+func TestLinearBackoff_Delay(t *testing.T) {
+	t.Parallel()
+
+	backoff := LinearBackoff{
+		Base: 100 * time.Millisecond,
+		Step: 250 * time.Millisecond,
+		Max:  time.Second,
+	}
+
+	assert.Equal(t, 100*time.Millisecond, backoff.Delay(0))
+	assert.Equal(t, 350*time.Millisecond, backoff.Delay(1))
+	assert.Equal(t, 850*time.Millisecond, backoff.Delay(3))
+	assert.Equal(t, time.Second, backoff.Delay(4))
+	assert.Equal(t, time.Second, backoff.Delay(100))
+
+	uncapped := LinearBackoff{Base: time.Second, Step: time.Second}
+	assert.Equal(t, 101*time.Second, uncapped.Delay(100))
+}
+
+func TestFibonacciBackoff_Delay(t *testing.T) {
+	t.Parallel()
+
+	backoff := FibonacciBackoff{Base: 100 * time.Millisecond, Max: time.Second}
+
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		300 * time.Millisecond,
+		500 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+
+	for attempt, want := range expected {
+		assert.Equal(t, want, backoff.Delay(uint(attempt)), "attempt %d", attempt)
+	}
+
+	assert.Equal(t, time.Second, backoff.Delay(^uint(0)), "large attempts stay capped")
+
+	uncapped := FibonacciBackoff{Base: time.Nanosecond}
+	assert.Equal(t, 55*time.Nanosecond, uncapped.Delay(9))
+	assert.Equal(t, time.Duration(math.MaxInt64), uncapped.Delay(10_000), "overflow saturates")
+}
+
+func TestDecorrelatedJitterBackoff_Delay(t *testing.T) {
+	t.Parallel()
+
+	backoff := DecorrelatedJitterBackoff{Base: 100 * time.Millisecond, Max: 2 * time.Second}
+
+	for attempt := range uint(20) {
+		for range 50 {
+			delay := backoff.Delay(attempt)
+			assert.GreaterOrEqual(t, delay, backoff.Base)
+			assert.LessOrEqual(t, delay, backoff.Max)
+		}
+	}
+
+	// The first delay is at most three times Base.
+	for range 50 {
+		assert.LessOrEqual(t, backoff.Delay(0), 300*time.Millisecond)
+	}
+
+	assert.Equal(t, time.Second, DecorrelatedJitterBackoff{Base: time.Second, Max: time.Second}.Delay(3))
+	assert.Equal(t, time.Second, DecorrelatedJitterBackoff{Base: 2 * time.Second, Max: time.Second}.Delay(3))
+	assert.LessOrEqual(t, backoff.Delay(^uint(0)), backoff.Max)
+}
+
+func TestAdaptiveBackoff_Delay(t *testing.T) {
+	t.Parallel()
+
+	inner := ConstantBackoff{Interval: 100 * time.Millisecond}
+
+	t.Run("no budget leaves delays unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		backoff := AdaptiveBackoff{Backoff: inner, MaxFactor: 4}
+		assert.Equal(t, 100*time.Millisecond, backoff.Delay(3))
+		assert.Zero(t, AdaptiveBackoff{}.Delay(3))
+	})
+
+	t.Run("quiet budget leaves delays unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		budget := &Budget{Rate: 1, Ratio: 0.5}
+		for range 10 {
+			budget.sendOK(false)
+		}
+
+		backoff := AdaptiveBackoff{Backoff: inner, Budget: budget, MaxFactor: 4}
+		assert.Equal(t, 100*time.Millisecond, backoff.Delay(0))
+	})
+
+	t.Run("busy budget widens delays", func(t *testing.T) {
+		t.Parallel()
+
+		budget := &Budget{Rate: 1000, Ratio: 0.5}
+		for range 10 {
+			budget.sendOK(false)
+		}
+
+		for range 10 {
+			budget.sendOK(true)
+		}
+
+		backoff := AdaptiveBackoff{Backoff: inner, Budget: budget, MaxFactor: 4}
+		assert.Equal(t, 400*time.Millisecond, backoff.Delay(0), "a retry ratio at Budget.Ratio applies MaxFactor")
+
+		gentle := AdaptiveBackoff{Backoff: inner, Budget: budget, MaxFactor: 1}
+		assert.Equal(t, 100*time.Millisecond, gentle.Delay(0))
+	})
+}
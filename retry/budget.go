@@ -127,6 +127,32 @@ func (b *Budget) overload(isRetry bool) bool {
 	return totalRate > b.Rate && retriedRate/totalRate > b.Ratio
 }
 
+// retryRatio returns the current ratio of retried calls to initial calls, or
+// zero if no initial calls were seen recently. It records nothing.
+func (b *Budget) retryRatio() float64 {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.initialCalls == nil || b.retriedCalls == nil {
+		return 0
+	}
+
+	currentTime := time.Now()
+
+	initialRate := b.initialCalls.Rate(currentTime)
+	retriedRate := b.retriedCalls.Rate(currentTime)
+
+	if !(initialRate > 0) || math.IsNaN(retriedRate) {
+		return 0
+	}
+
+	return retriedRate / initialRate
+}
+
 // timeRoundDown rounds a time down to the nearest multiple of the duration.
 // This is used by movingRate to align timestamps to bucket boundaries.
 //
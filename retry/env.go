@@ -2,6 +2,7 @@ package retry
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/amp-labs/amp-common/envutil"
@@ -91,33 +92,131 @@ func readBudget(ctx context.Context, key string) optional.Value[*Budget] {
 	return optional.Some[*Budget](budget)
 }
 
+// Backoff types accepted by the <baseKey>_BACKOFF_TYPE environment variable.
+const (
+	backoffTypeExponential  = "exponential"
+	backoffTypeConstant     = "constant"
+	backoffTypeLinear       = "linear"
+	backoffTypeFibonacci    = "fibonacci"
+	backoffTypeDecorrelated = "decorrelated"
+)
+
 func readBackoff(ctx context.Context, key string) optional.Value[Backoff] {
+	backoffType := strings.ToLower(envutil.String(ctx, key+"_TYPE").ValueOrElse(backoffTypeExponential))
+
 	baseEnv := readOptionalDuration(ctx, key+"_BASE")
 	maxEnv := readOptionalDuration(ctx, key+"_MAX")
-	factorEnv := readOptionalFloat(ctx, key+"_FACTOR")
 
 	base, baseOk := baseEnv.Get()
 	maxVal, maxOk := maxEnv.Get()
-	factor, factorOk := factorEnv.Get()
 
-	if !baseOk || !maxOk || !factorOk {
+	switch backoffType {
+	case backoffTypeExponential:
+		factor, factorOk := readOptionalFloat(ctx, key+"_FACTOR").Get()
+		if !baseOk || !maxOk || !factorOk {
+			return optional.None[Backoff]()
+		}
+
+		return optional.Some[Backoff](ExpBackoff{
+			Base:   base,
+			Max:    maxVal,
+			Factor: factor,
+		})
+	case backoffTypeConstant:
+		if !baseOk {
+			return optional.None[Backoff]()
+		}
+
+		return optional.Some[Backoff](ConstantBackoff{Interval: base})
+	case backoffTypeLinear:
+		if !baseOk {
+			return optional.None[Backoff]()
+		}
+
+		return optional.Some[Backoff](LinearBackoff{
+			Base: base,
+			Step: readOptionalDuration(ctx, key+"_STEP").GetOrElse(base),
+			Max:  maxVal,
+		})
+	case backoffTypeFibonacci:
+		if !baseOk {
+			return optional.None[Backoff]()
+		}
+
+		return optional.Some[Backoff](FibonacciBackoff{
+			Base: base,
+			Max:  maxVal,
+		})
+	case backoffTypeDecorrelated:
+		if !baseOk || !maxOk {
+			return optional.None[Backoff]()
+		}
+
+		return optional.Some[Backoff](DecorrelatedJitterBackoff{
+			Base: base,
+			Max:  maxVal,
+		})
+	default:
+		logger.Warn(ctx, "unknown backoff type in env var", "key", key+"_TYPE", "value", backoffType)
+
 		return optional.None[Backoff]()
 	}
+}
+
+// readJitter reads a Jitter either as a number or as one of the names
+// "full", "equal" and "none".
+func readJitter(ctx context.Context, key string) optional.Value[Jitter] {
+	name := envutil.String(ctx, key)
+	if !name.HasValue() {
+		return optional.None[Jitter]()
+	}
 
-	backoff := ExpBackoff{
-		Base:   base,
-		Max:    maxVal,
-		Factor: factor,
+	switch strings.ToLower(strings.TrimSpace(name.ValueOrElse(""))) {
+	case "full":
+		return optional.Some(FullJitter)
+	case "equal":
+		return optional.Some(EqualJitter)
+	case "none":
+		return optional.Some(WithoutJitter)
 	}
 
-	return optional.Some[Backoff](backoff)
+	jitter, present := readOptionalFloat(ctx, key).Get()
+	if !present {
+		return optional.None[Jitter]()
+	}
+
+	return optional.Some(Jitter(jitter))
 }
 
+// OptionsFromEnv reads retry options from the environment variables sharing
+// the prefix baseKey. Only the settings that are present produce options, so
+// the result can be appended to a runner's own defaults. The variables are:
+//
+//   - <baseKey>_ATTEMPTS: maximum number of attempts
+//   - <baseKey>_TIMEOUT: timeout for each attempt
+//   - <baseKey>_JITTER: a jitter amount between 0 and 1, or one of "full", "equal" and "none"
+//   - <baseKey>_BUDGET_RATE and <baseKey>_BUDGET_RATIO: a retry Budget (both required)
+//   - <baseKey>_BACKOFF_TYPE: one of "exponential" (the default), "constant",
+//     "linear", "fibonacci" and "decorrelated"
+//   - <baseKey>_BACKOFF_BASE, <baseKey>_BACKOFF_MAX, <baseKey>_BACKOFF_FACTOR and
+//     <baseKey>_BACKOFF_STEP: the backoff's parameters. Exponential backoff needs
+//     BASE, MAX and FACTOR; decorrelated jitter needs BASE and MAX; the others need
+//     BASE, with MAX an optional cap and STEP the linear increment (BASE by default).
+//   - <baseKey>_BACKOFF_ADAPTIVE_MAX_FACTOR: wraps the backoff in an AdaptiveBackoff
+//     driven by the retry budget, which must be configured too
+//
+// Decorrelated jitter randomizes delays itself, so it turns jitter off unless
+// <baseKey>_JITTER says otherwise.
+//
+// Example:
+//
+//	// MY_SERVICE_RETRY_BACKOFF_TYPE=fibonacci MY_SERVICE_RETRY_BACKOFF_BASE=50ms ...
+//	runner := retry.NewRunner(retry.OptionsFromEnv(ctx, "MY_SERVICE_RETRY")...)
 func OptionsFromEnv(ctx context.Context, baseKey string) []Option {
 	var opts []Option
 
-	budget, present := readBudget(ctx, baseKey+"_BUDGET").Get()
-	if present {
+	budget, budgetPresent := readBudget(ctx, baseKey+"_BUDGET").Get()
+	if budgetPresent {
 		opts = append(opts, WithBudget(budget))
 	}
 
@@ -131,14 +230,27 @@ func OptionsFromEnv(ctx context.Context, baseKey string) []Option {
 		opts = append(opts, WithAttempts(Attempts(attempts)))
 	}
 
-	jitter, present := readOptionalFloat(ctx, baseKey+"_JITTER").Get()
-	if present {
-		opts = append(opts, WithJitter(Jitter(jitter)))
+	backoff, backoffPresent := readBackoff(ctx, baseKey+"_BACKOFF").Get()
+	if backoffPresent {
+		if _, ok := backoff.(DecorrelatedJitterBackoff); ok {
+			opts = append(opts, WithJitter(WithoutJitter))
+		}
+
+		maxFactor, present := readOptionalFloat(ctx, baseKey+"_BACKOFF_ADAPTIVE_MAX_FACTOR").Get()
+		if present && budgetPresent {
+			backoff = AdaptiveBackoff{
+				Backoff:   backoff,
+				Budget:    budget,
+				MaxFactor: maxFactor,
+			}
+		}
+
+		opts = append(opts, WithBackoff(backoff))
 	}
 
-	backoff, present := readBackoff(ctx, baseKey+"_BACKOFF").Get()
+	jitter, present := readJitter(ctx, baseKey+"_JITTER").Get()
 	if present {
-		opts = append(opts, WithBackoff(backoff))
+		opts = append(opts, WithJitter(jitter))
 	}
 
 	return opts
@@ -0,0 +1,173 @@
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/amp-labs/amp-common/envutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// optionsFrom applies OptionsFromEnv with the given variables to empty options.
+func optionsFrom(t *testing.T, values map[string]string) *options {
+	t.Helper()
+
+	ctx := envutil.WithEnvOverrides(t.Context(), values)
+
+	opts := &options{}
+	for _, opt := range OptionsFromEnv(ctx, "TEST_RETRY") {
+		opt(opts)
+	}
+
+	return opts
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty environment", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := envutil.WithEnvOverrides(t.Context(), map[string]string{})
+		assert.Empty(t, OptionsFromEnv(ctx, "TEST_RETRY_UNSET"))
+	})
+
+	t.Run("exponential backoff by default", func(t *testing.T) {
+		t.Parallel()
+
+		opts := optionsFrom(t, map[string]string{
+			"TEST_RETRY_ATTEMPTS":       "6",
+			"TEST_RETRY_TIMEOUT":        "3s",
+			"TEST_RETRY_BACKOFF_BASE":   "10ms",
+			"TEST_RETRY_BACKOFF_MAX":    "1s",
+			"TEST_RETRY_BACKOFF_FACTOR": "3",
+		})
+
+		assert.Equal(t, Attempts(6), opts.attempts)
+		assert.Equal(t, Timeout(3*time.Second), opts.timeout)
+		assert.Equal(t, ExpBackoff{Base: 10 * time.Millisecond, Max: time.Second, Factor: 3}, opts.backoff)
+	})
+
+	t.Run("exponential backoff needs every parameter", func(t *testing.T) {
+		t.Parallel()
+
+		opts := optionsFrom(t, map[string]string{
+			"TEST_RETRY_BACKOFF_BASE": "10ms",
+			"TEST_RETRY_BACKOFF_MAX":  "1s",
+		})
+
+		assert.Nil(t, opts.backoff)
+	})
+
+	t.Run("backoff types", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name     string
+			values   map[string]string
+			expected Backoff
+		}{
+			{
+				name:     "constant",
+				values:   map[string]string{"TEST_RETRY_BACKOFF_TYPE": "constant", "TEST_RETRY_BACKOFF_BASE": "50ms"},
+				expected: ConstantBackoff{Interval: 50 * time.Millisecond},
+			},
+			{
+				name: "linear",
+				values: map[string]string{
+					"TEST_RETRY_BACKOFF_TYPE": "Linear",
+					"TEST_RETRY_BACKOFF_BASE": "50ms",
+					"TEST_RETRY_BACKOFF_STEP": "20ms",
+					"TEST_RETRY_BACKOFF_MAX":  "1s",
+				},
+				expected: LinearBackoff{Base: 50 * time.Millisecond, Step: 20 * time.Millisecond, Max: time.Second},
+			},
+			{
+				name:     "linear step defaults to base",
+				values:   map[string]string{"TEST_RETRY_BACKOFF_TYPE": "linear", "TEST_RETRY_BACKOFF_BASE": "50ms"},
+				expected: LinearBackoff{Base: 50 * time.Millisecond, Step: 50 * time.Millisecond},
+			},
+			{
+				name:     "fibonacci",
+				values:   map[string]string{"TEST_RETRY_BACKOFF_TYPE": "fibonacci", "TEST_RETRY_BACKOFF_BASE": "50ms"},
+				expected: FibonacciBackoff{Base: 50 * time.Millisecond},
+			},
+			{
+				name: "decorrelated",
+				values: map[string]string{
+					"TEST_RETRY_BACKOFF_TYPE": "decorrelated",
+					"TEST_RETRY_BACKOFF_BASE": "50ms",
+					"TEST_RETRY_BACKOFF_MAX":  "5s",
+				},
+				expected: DecorrelatedJitterBackoff{Base: 50 * time.Millisecond, Max: 5 * time.Second},
+			},
+			{
+				name:   "decorrelated needs max",
+				values: map[string]string{"TEST_RETRY_BACKOFF_TYPE": "decorrelated", "TEST_RETRY_BACKOFF_BASE": "50ms"},
+			},
+			{
+				name:   "unknown",
+				values: map[string]string{"TEST_RETRY_BACKOFF_TYPE": "quadratic", "TEST_RETRY_BACKOFF_BASE": "50ms"},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				assert.Equal(t, tt.expected, optionsFrom(t, tt.values).backoff)
+			})
+		}
+	})
+
+	t.Run("jitter", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, FullJitter, optionsFrom(t, map[string]string{"TEST_RETRY_JITTER": "full"}).jitter)
+		assert.Equal(t, EqualJitter, optionsFrom(t, map[string]string{"TEST_RETRY_JITTER": "Equal"}).jitter)
+		assert.Equal(t, WithoutJitter, optionsFrom(t, map[string]string{"TEST_RETRY_JITTER": "none"}).jitter)
+		assert.Equal(t, Jitter(0.25), optionsFrom(t, map[string]string{"TEST_RETRY_JITTER": "0.25"}).jitter)
+		assert.Zero(t, optionsFrom(t, map[string]string{"TEST_RETRY_JITTER": "lots"}).jitter)
+	})
+
+	t.Run("decorrelated backoff disables jitter unless configured", func(t *testing.T) {
+		t.Parallel()
+
+		values := map[string]string{
+			"TEST_RETRY_BACKOFF_TYPE": "decorrelated",
+			"TEST_RETRY_BACKOFF_BASE": "50ms",
+			"TEST_RETRY_BACKOFF_MAX":  "5s",
+		}
+
+		assert.Equal(t, WithoutJitter, optionsFrom(t, values).jitter)
+
+		values["TEST_RETRY_JITTER"] = "equal"
+		assert.Equal(t, EqualJitter, optionsFrom(t, values).jitter)
+	})
+
+	t.Run("adaptive backoff uses the budget", func(t *testing.T) {
+		t.Parallel()
+
+		values := map[string]string{
+			"TEST_RETRY_BACKOFF_TYPE":                "constant",
+			"TEST_RETRY_BACKOFF_BASE":                "50ms",
+			"TEST_RETRY_BACKOFF_ADAPTIVE_MAX_FACTOR": "3",
+		}
+
+		assert.Equal(t, ConstantBackoff{Interval: 50 * time.Millisecond}, optionsFrom(t, values).backoff,
+			"adaptive backoff needs a budget")
+
+		values["TEST_RETRY_BUDGET_RATE"] = "10"
+		values["TEST_RETRY_BUDGET_RATIO"] = "0.1"
+
+		opts := optionsFrom(t, values)
+		require.NotNil(t, opts.budget)
+
+		adaptive, ok := opts.backoff.(AdaptiveBackoff)
+		require.True(t, ok)
+		assert.Equal(t, ConstantBackoff{Interval: 50 * time.Millisecond}, adaptive.Backoff)
+		assert.Same(t, opts.budget, adaptive.Budget)
+		assert.InDelta(t, 3.0, adaptive.MaxFactor, 0)
+	})
+}
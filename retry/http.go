@@ -0,0 +1,138 @@
+package retry
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// statusClassDivisor turns an HTTP status code into its class (5 for 5xx).
+	statusClassDivisor = 100
+
+	// unixTimestampThreshold separates X-RateLimit-Reset values that are Unix
+	// timestamps (in seconds) from those that are a number of seconds to wait.
+	unixTimestampThreshold = 1_000_000_000
+)
+
+// StatusError is returned by CheckResponse for HTTP responses with an error
+// status. Use HasStatus and HasStatusClass to decide which ones to retry.
+type StatusError struct {
+	// StatusCode is the response's status code, such as 503.
+	StatusCode int
+	// Status is the response's status line, such as "503 Service Unavailable".
+	Status string
+	// Header holds the response's headers.
+	Header http.Header
+
+	retryAfter    time.Duration
+	hasRetryAfter bool
+}
+
+// RetryAfter returns how long the server asked to wait before trying again,
+// as read by CheckResponse, and false if it didn't say.
+func (e *StatusError) RetryAfter() (time.Duration, bool) {
+	return e.retryAfter, e.hasRetryAfter
+}
+
+// Error returns a message containing the response's status.
+func (e *StatusError) Error() string {
+	status := e.Status
+	if status == "" {
+		status = strconv.Itoa(e.StatusCode)
+	}
+
+	return "unexpected HTTP status: " + status
+}
+
+// CheckResponse turns an HTTP response with a 4xx or 5xx status into a
+// *StatusError, and returns nil for any other response. The caller still owns
+// the response body.
+//
+// For 429 Too Many Requests and 503 Service Unavailable, the statuses where
+// Retry-After applies, the error records when the server asked to try again,
+// through a Retry-After or rate-limit header (see ParseRetryAfter). If the
+// WithRetryIf predicates accept the error, the next attempt waits that long
+// instead of backing off. Other statuses ignore these headers, since many APIs
+// send rate-limit headers on every response.
+//
+// Example:
+//
+//	resp, err := retry.DoValue(ctx, func(ctx context.Context) (*http.Response, error) {
+//	    resp, err := client.Do(req.WithContext(ctx))
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//
+//	    if err := retry.CheckResponse(resp); err != nil {
+//	        _ = resp.Body.Close()
+//
+//	        return nil, err
+//	    }
+//
+//	    return resp, nil
+//	}, retry.WithRetryIf(retry.IsTimeout, retry.HasStatusClass(5)))
+func CheckResponse(resp *http.Response) error {
+	if resp == nil || resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	err := &StatusError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header,
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		err.retryAfter, err.hasRetryAfter = ParseRetryAfter(resp.Header, time.Now())
+	}
+
+	return err
+}
+
+// ParseRetryAfter reads how long to wait before retrying from HTTP response
+// headers, relative to now. It understands, in order of precedence:
+//   - Retry-After, as a number of seconds or an HTTP date
+//   - RateLimit-Reset, as a number of seconds
+//   - X-RateLimit-Reset, as a number of seconds or a Unix timestamp in seconds
+//
+// It returns false if none of the headers is present and valid. Dates in the
+// past yield a zero delay.
+func ParseRetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, ok := parseSeconds(value); ok {
+			return time.Duration(seconds) * time.Second, true
+		}
+
+		if date, err := http.ParseTime(value); err == nil {
+			return max(date.Sub(now), 0), true
+		}
+	}
+
+	if seconds, ok := parseSeconds(header.Get("RateLimit-Reset")); ok {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if seconds, ok := parseSeconds(header.Get("X-RateLimit-Reset")); ok {
+		if seconds >= unixTimestampThreshold {
+			return max(time.Unix(seconds, 0).Sub(now), 0), true
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	return 0, false
+}
+
+// parseSeconds parses a non-negative whole number of seconds small enough to
+// convert to a time.Duration.
+func parseSeconds(value string) (int64, bool) {
+	seconds, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || seconds < 0 || seconds > math.MaxInt64/int64(time.Second) {
+		return 0, false
+	}
+
+	return seconds, true
+}
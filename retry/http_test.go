@@ -0,0 +1,216 @@
+package retry
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckResponse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("accepts successful responses", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, CheckResponse(nil))
+		require.NoError(t, CheckResponse(&http.Response{StatusCode: http.StatusOK}))
+		require.NoError(t, CheckResponse(&http.Response{StatusCode: http.StatusNotModified}))
+	})
+
+	t.Run("returns a StatusError for error responses", func(t *testing.T) {
+		t.Parallel()
+
+		err := CheckResponse(&http.Response{
+			StatusCode: http.StatusBadGateway,
+			Status:     "502 Bad Gateway",
+			Header:     http.Header{},
+		})
+
+		var statusErr *StatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusBadGateway, statusErr.StatusCode)
+		assert.Equal(t, "unexpected HTTP status: 502 Bad Gateway", err.Error())
+
+		_, ok := requestedDelay(err)
+		assert.False(t, ok)
+	})
+
+	t.Run("honors Retry-After", func(t *testing.T) {
+		t.Parallel()
+
+		err := CheckResponse(&http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Retry-After": []string{"7"}},
+		})
+
+		assert.True(t, HasStatus(http.StatusTooManyRequests)(err))
+		assert.Equal(t, "unexpected HTTP status: 429", err.Error())
+
+		_, ok := afterDelay(err)
+		assert.False(t, ok, "a server's delay is not an explicit After")
+
+		delay, ok := requestedDelay(err)
+		assert.True(t, ok)
+		assert.Equal(t, 7*time.Second, delay)
+	})
+
+	t.Run("ignores rate-limit headers on other statuses", func(t *testing.T) {
+		t.Parallel()
+
+		err := CheckResponse(&http.Response{
+			StatusCode: http.StatusNotFound,
+			Header: http.Header{
+				"Retry-After":       []string{"7"},
+				"X-Ratelimit-Reset": []string{strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)},
+			},
+		})
+
+		var statusErr *StatusError
+		require.ErrorAs(t, err, &statusErr)
+
+		_, ok := statusErr.RetryAfter()
+		assert.False(t, ok)
+	})
+}
+
+func TestCheckResponseHonorsPredicates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{name: "404 with a rate-limit reset", status: http.StatusNotFound},
+		{name: "429 rejected by the predicates", status: http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := http.Header{
+				"X-Ratelimit-Reset": []string{strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)},
+			}
+
+			calls := 0
+			start := time.Now()
+
+			err := Do(t.Context(), func(context.Context) error {
+				calls++
+
+				return CheckResponse(&http.Response{StatusCode: tt.status, Header: header})
+			}, WithRetryIf(HasStatus(http.StatusServiceUnavailable)))
+
+			require.True(t, HasStatus(tt.status)(err))
+			assert.Equal(t, 1, calls, "the error is not retried")
+			assert.Less(t, time.Since(start), time.Second, "nothing waits for the reset")
+		})
+	}
+
+	t.Run("accepted 429 waits as asked", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+
+		err := Do(t.Context(), func(context.Context) error {
+			calls++
+			if calls == 1 {
+				return CheckResponse(&http.Response{
+					StatusCode: http.StatusTooManyRequests,
+					Header:     http.Header{"Retry-After": []string{"0"}},
+				})
+			}
+
+			return nil
+		}, WithRetryIf(HasStatus(http.StatusTooManyRequests)), WithBackoff(ExpBackoff{Base: time.Hour, Max: time.Hour, Factor: 1}))
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls, "Retry-After replaces the hour-long backoff")
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+		wantOK bool
+	}{
+		{
+			name: "no headers",
+		},
+		{
+			name:   "Retry-After seconds",
+			header: http.Header{"Retry-After": []string{"120"}},
+			want:   2 * time.Minute,
+			wantOK: true,
+		},
+		{
+			name:   "Retry-After date",
+			header: http.Header{"Retry-After": []string{now.Add(30 * time.Second).Format(http.TimeFormat)}},
+			want:   30 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "Retry-After date in the past",
+			header: http.Header{"Retry-After": []string{now.Add(-time.Minute).Format(http.TimeFormat)}},
+			want:   0,
+			wantOK: true,
+		},
+		{
+			name:   "invalid Retry-After falls back to rate-limit headers",
+			header: http.Header{"Retry-After": []string{"soon"}, "Ratelimit-Reset": []string{"5"}},
+			want:   5 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "negative Retry-After",
+			header: http.Header{"Retry-After": []string{"-1"}},
+		},
+		{
+			name:   "Retry-After too large",
+			header: http.Header{"Retry-After": []string{"99999999999999"}},
+		},
+		{
+			name:   "X-RateLimit-Reset seconds",
+			header: http.Header{"X-Ratelimit-Reset": []string{"42"}},
+			want:   42 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "X-RateLimit-Reset timestamp",
+			header: http.Header{"X-Ratelimit-Reset": []string{strconv.FormatInt(now.Add(time.Minute).Unix(), 10)}},
+			want:   time.Minute,
+			wantOK: true,
+		},
+		{
+			name: "Retry-After takes precedence",
+			header: http.Header{
+				"Retry-After":       []string{"1"},
+				"Ratelimit-Reset":   []string{"2"},
+				"X-Ratelimit-Reset": []string{"3"},
+			},
+			want:   time.Second,
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := ParseRetryAfter(tt.header, now)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package retry
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Prometheus metrics for monitoring retried calls. Every metric is labeled
+// with the call's operation name (see WithOperation), or "unnamed".
+
+var (
+	// retryAttempts observes the number of attempts made per call, labeled with its Outcome.
+	retryAttempts = promauto.NewHistogramVec(prometheus.HistogramOpts{ //nolint:gochecknoglobals
+		Name:    "retry_attempts",
+		Help:    "The number of attempts made per retried call",
+		Buckets: []float64{1, 2, 3, 4, 5, 6, 8, 10, 15, 20},
+	}, []string{"operation", "outcome"})
+
+	// retryDelay observes the total time each call spent waiting between attempts.
+	retryDelay = promauto.NewHistogramVec(prometheus.HistogramOpts{ //nolint:gochecknoglobals
+		Name:    "retry_delay_seconds",
+		Help:    "The total time spent waiting after failed attempts per retried call",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 12), //nolint:mnd // 10ms to ~20s
+	}, []string{"operation"})
+)
@@ -0,0 +1,206 @@
+package retry
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultOperation labels the metrics of calls made without WithOperation.
+const defaultOperation = "unnamed"
+
+// Span event and attribute names recorded for each call.
+const (
+	eventAttempt     = "retry.attempt"
+	eventGiveUp      = "retry.give_up"
+	attrOperation    = "retry.operation"
+	attrAttempt      = "retry.attempt"
+	attrAttempts     = "retry.attempts"
+	attrDelay        = "retry.delay_ms"
+	attrTotalDelay   = "retry.total_delay_ms"
+	attrOutcome      = "retry.outcome"
+	attrErrorMessage = "error.message"
+)
+
+// Outcome describes how a retried call ended, as reported to WithOnGiveUp
+// hooks and in the outcome label of the retry_attempts metric.
+type Outcome int
+
+const (
+	// Succeeded means an attempt succeeded.
+	Succeeded Outcome = iota
+	// AttemptsExhausted means every attempt allowed by WithAttempts failed.
+	AttemptsExhausted
+	// PermanentError means an attempt failed with an error wrapped with Abort
+	// or another Error that isn't Temporary.
+	PermanentError
+	// NotRetryable means an attempt failed with an error matching none of the WithRetryIf predicates.
+	NotRetryable
+	// BudgetExhausted means the retry Budget refused another attempt.
+	BudgetExhausted
+	// CircuitOpen means the circuit breaker refused another attempt.
+	CircuitOpen
+	// ContextDone means the context was canceled or its deadline passed.
+	ContextDone
+	// DeadlineTooSoon means the delay requested with After would outlast the context's deadline.
+	DeadlineTooSoon
+)
+
+// String returns the metric label for the outcome.
+func (o Outcome) String() string {
+	switch o {
+	case Succeeded:
+		return "succeeded"
+	case AttemptsExhausted:
+		return "attempts_exhausted"
+	case PermanentError:
+		return "permanent_error"
+	case NotRetryable:
+		return "not_retryable"
+	case BudgetExhausted:
+		return "budget_exhausted"
+	case CircuitOpen:
+		return "circuit_open"
+	case ContextDone:
+		return "context_done"
+	case DeadlineTooSoon:
+		return "deadline_too_soon"
+	default:
+		return "unknown"
+	}
+}
+
+// RetryInfo describes a failed attempt that is about to be retried, as passed
+// to WithOnRetry hooks.
+type RetryInfo struct {
+	// Operation is the name set with WithOperation, if any.
+	Operation string
+	// Attempt is the zero-based index of the attempt that failed (see Attempt).
+	Attempt uint
+	// Err is the error the attempt failed with.
+	Err error
+	// Delay is how long the loop waits before the next attempt.
+	Delay time.Duration
+	// Elapsed is the time since the call started.
+	Elapsed time.Duration
+}
+
+// GiveUpInfo describes a call that failed for good, as passed to WithOnGiveUp hooks.
+type GiveUpInfo struct {
+	// Operation is the name set with WithOperation, if any.
+	Operation string
+	// Outcome tells why the loop stopped.
+	Outcome Outcome
+	// Err is the error returned to the caller.
+	Err error
+	// Attempts is the number of attempts made.
+	Attempts uint
+	// TotalDelay is the total time actually spent waiting after failed attempts.
+	TotalDelay time.Duration
+	// Elapsed is the time since the call started.
+	Elapsed time.Duration
+}
+
+// observer reports the progress of one call to the hooks, the span and the metrics.
+type observer struct {
+	opts       *options
+	span       trace.Span
+	start      time.Time
+	attempts   uint
+	totalDelay time.Duration
+}
+
+func newObserver(opts *options, span trace.Span) *observer {
+	return &observer{
+		opts:  opts,
+		span:  span,
+		start: time.Now(),
+	}
+}
+
+// run executes the retry loop and reports how the call ended.
+func (o *observer) run(ctx context.Context, operation func(ctx context.Context) error) error {
+	outcome, err := loop(ctx, o.opts, o, operation)
+
+	name := o.opts.operation
+	if name == "" {
+		name = defaultOperation
+	}
+
+	retryAttempts.WithLabelValues(name, outcome.String()).Observe(float64(o.attempts))
+	retryDelay.WithLabelValues(name).Observe(o.totalDelay.Seconds())
+
+	if outcome == Succeeded {
+		return nil
+	}
+
+	o.addEvent(eventGiveUp,
+		attribute.String(attrOutcome, outcome.String()),
+		attribute.Int64(attrAttempts, int64(o.attempts)), //nolint:gosec // Attempt counts are small
+		attribute.Int64(attrTotalDelay, o.totalDelay.Milliseconds()),
+		attribute.String(attrErrorMessage, errorMessage(err)),
+	)
+
+	if o.opts.onGiveUp != nil {
+		o.opts.onGiveUp(ctx, GiveUpInfo{
+			Operation:  o.opts.operation,
+			Outcome:    outcome,
+			Err:        err,
+			Attempts:   o.attempts,
+			TotalDelay: o.totalDelay,
+			Elapsed:    time.Since(o.start),
+		})
+	}
+
+	return err
+}
+
+// attemptSucceeded records a successful attempt.
+func (o *observer) attemptSucceeded(attempt uint) {
+	o.addEvent(eventAttempt,
+		attribute.Int64(attrAttempt, int64(attempt)), //nolint:gosec // Attempt counts are small
+	)
+}
+
+// attemptFailed records a failed attempt that won't be retried.
+func (o *observer) attemptFailed(attempt uint, err error) {
+	o.addEvent(eventAttempt,
+		attribute.Int64(attrAttempt, int64(attempt)), //nolint:gosec // Attempt counts are small
+		attribute.String(attrErrorMessage, errorMessage(err)),
+	)
+}
+
+// retrying records a failed attempt that will be retried after delay.
+func (o *observer) retrying(ctx context.Context, attempt uint, err error, delay time.Duration) {
+	o.addEvent(eventAttempt,
+		attribute.Int64(attrAttempt, int64(attempt)), //nolint:gosec // Attempt counts are small
+		attribute.String(attrErrorMessage, errorMessage(err)),
+		attribute.Int64(attrDelay, delay.Milliseconds()),
+	)
+
+	if o.opts.onRetry != nil {
+		o.opts.onRetry(ctx, RetryInfo{
+			Operation: o.opts.operation,
+			Attempt:   attempt,
+			Err:       err,
+			Delay:     delay,
+			Elapsed:   time.Since(o.start),
+		})
+	}
+}
+
+func (o *observer) addEvent(name string, attrs ...attribute.KeyValue) {
+	if o.span.IsRecording() {
+		o.span.AddEvent(name, trace.WithAttributes(attrs...))
+	}
+}
+
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
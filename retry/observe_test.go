@@ -0,0 +1,242 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/amp-common/spans"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var fastBackoff = WithBackoff(ExpBackoff{Base: time.Millisecond, Max: time.Millisecond, Factor: 1})
+
+func TestWithOnRetry_Option(t *testing.T) {
+	t.Parallel()
+
+	var retries []RetryInfo
+
+	callCount := 0
+	err := Do(t.Context(), func(ctx context.Context) error {
+		callCount++
+		if callCount < 3 {
+			return io.EOF
+		}
+
+		return nil
+	}, WithOperation("on-retry"), fastBackoff, WithJitter(WithoutJitter),
+		WithOnRetry(func(ctx context.Context, info RetryInfo) {
+			retries = append(retries, info)
+		}),
+		WithOnGiveUp(func(ctx context.Context, info GiveUpInfo) {
+			t.Error("a successful call must not give up")
+		}))
+
+	require.NoError(t, err)
+	require.Len(t, retries, 2)
+
+	for i, info := range retries {
+		assert.Equal(t, "on-retry", info.Operation)
+		assert.Equal(t, uint(i), info.Attempt)
+		require.ErrorIs(t, info.Err, io.EOF)
+		assert.Equal(t, time.Millisecond, info.Delay)
+	}
+}
+
+func TestWithOnGiveUp_Option(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		operation func(ctx context.Context) error
+		opts      []Option
+		outcome   Outcome
+		attempts  uint
+		retries   int
+	}{
+		{
+			name:      "attempts exhausted",
+			operation: func(ctx context.Context) error { return io.EOF },
+			opts:      []Option{WithAttempts(3)},
+			outcome:   AttemptsExhausted,
+			attempts:  3,
+			retries:   2,
+		},
+		{
+			name:      "permanent error",
+			operation: func(ctx context.Context) error { return Abort(io.EOF) },
+			outcome:   PermanentError,
+			attempts:  1,
+		},
+		{
+			name:      "not retryable",
+			operation: func(ctx context.Context) error { return io.EOF },
+			opts:      []Option{WithRetryIf(IsTimeout)},
+			outcome:   NotRetryable,
+			attempts:  1,
+		},
+		{
+			name:      "deadline too soon",
+			operation: func(ctx context.Context) error { return After(io.EOF, time.Hour) },
+			outcome:   DeadlineTooSoon,
+			attempts:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(t.Context(), time.Minute)
+			defer cancel()
+
+			var (
+				retries int
+				gaveUp  []GiveUpInfo
+			)
+
+			opts := append([]Option{
+				fastBackoff,
+				WithOnRetry(func(ctx context.Context, info RetryInfo) { retries++ }),
+				WithOnGiveUp(func(ctx context.Context, info GiveUpInfo) { gaveUp = append(gaveUp, info) }),
+			}, tt.opts...)
+
+			err := Do(ctx, tt.operation, opts...)
+
+			require.ErrorIs(t, err, io.EOF)
+			require.Len(t, gaveUp, 1)
+			assert.Equal(t, tt.outcome, gaveUp[0].Outcome)
+			assert.Equal(t, tt.attempts, gaveUp[0].Attempts)
+			require.ErrorIs(t, gaveUp[0].Err, io.EOF)
+			assert.Equal(t, tt.retries, retries)
+		})
+	}
+}
+
+func TestWithOnGiveUp_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	var outcome Outcome
+
+	err := Do(ctx, func(ctx context.Context) error {
+		cancel()
+
+		return io.EOF
+	}, WithOnGiveUp(func(ctx context.Context, info GiveUpInfo) { outcome = info.Outcome }))
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, ContextDone, outcome)
+}
+
+func TestWithOnRetry_NotCalledAfterLastAttempt(t *testing.T) {
+	t.Parallel()
+
+	var retries []uint
+
+	var info GiveUpInfo
+
+	err := Do(t.Context(), func(ctx context.Context) error {
+		return io.EOF
+	}, WithAttempts(2), WithBackoff(ExpBackoff{Base: 10 * time.Millisecond, Max: 10 * time.Millisecond, Factor: 1}),
+		WithJitter(WithoutJitter),
+		WithOnRetry(func(ctx context.Context, info RetryInfo) { retries = append(retries, info.Attempt) }),
+		WithOnGiveUp(func(ctx context.Context, gaveUp GiveUpInfo) { info = gaveUp }))
+
+	require.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, []uint{0}, retries, "the wait after the last attempt is not a retry")
+	assert.Equal(t, AttemptsExhausted, info.Outcome)
+	assert.GreaterOrEqual(t, info.TotalDelay, 20*time.Millisecond, "both waits actually happened")
+}
+
+func TestDo_SpanEvents(t *testing.T) {
+	t.Parallel()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	ctx := spans.WithTracer(t.Context(), provider.Tracer("retry-test"))
+
+	callCount := 0
+	err := Do(ctx, func(ctx context.Context) error {
+		callCount++
+		if callCount < 2 {
+			return io.EOF
+		}
+
+		return nil
+	}, WithOperation("span-events"), fastBackoff, WithJitter(WithoutJitter))
+	require.NoError(t, err)
+
+	err = Do(ctx, func(ctx context.Context) error {
+		return errors.New("broken") //nolint:err113 // Test error
+	}, WithOperation("span-give-up"), WithAttempts(2), fastBackoff)
+	require.Error(t, err)
+
+	exported := exporter.GetSpans()
+	require.Len(t, exported, 2)
+
+	succeeded := exported[0]
+	assert.Equal(t, "span-events", succeeded.Name)
+	assert.Contains(t, succeeded.Attributes, attribute.String(attrOperation, "span-events"))
+	require.Len(t, succeeded.Events, 2)
+	assert.Equal(t, eventAttempt, succeeded.Events[0].Name)
+	assert.Contains(t, succeeded.Events[0].Attributes, attribute.Int64(attrAttempt, 0))
+	assert.Contains(t, succeeded.Events[0].Attributes, attribute.String(attrErrorMessage, io.EOF.Error()))
+	assert.Contains(t, succeeded.Events[0].Attributes, attribute.Int64(attrDelay, 1))
+	assert.Contains(t, succeeded.Events[1].Attributes, attribute.Int64(attrAttempt, 1))
+
+	failed := exported[1]
+	assert.Equal(t, "span-give-up", failed.Name)
+	require.Len(t, failed.Events, 4, "two attempts, the give-up event and the recorded error")
+
+	var giveUp *sdktrace.Event
+
+	for i := range failed.Events {
+		if failed.Events[i].Name == eventGiveUp {
+			giveUp = &failed.Events[i]
+		}
+	}
+
+	require.NotNil(t, giveUp)
+	assert.Contains(t, giveUp.Attributes, attribute.String(attrOutcome, AttemptsExhausted.String()))
+	assert.Contains(t, giveUp.Attributes, attribute.Int64(attrAttempts, 2))
+}
+
+func TestDo_ConcurrentHooks(t *testing.T) {
+	t.Parallel()
+
+	runner := NewRunner(WithOperation("concurrent"), WithAttempts(2), fastBackoff,
+		WithOnRetry(func(ctx context.Context, info RetryInfo) {}),
+		WithOnGiveUp(func(ctx context.Context, info GiveUpInfo) {}))
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_ = runner.Do(t.Context(), func(ctx context.Context) error { return io.EOF })
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestOutcome_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "succeeded", Succeeded.String())
+	assert.Equal(t, "circuit_open", CircuitOpen.String())
+	assert.Equal(t, "budget_exhausted", BudgetExhausted.String())
+	assert.Equal(t, "unknown", Outcome(-1).String())
+}
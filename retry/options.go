@@ -1,16 +1,28 @@
 package retry
 
+import (
+	"context"
+
+	"github.com/amp-labs/amp-common/circuitbreaker"
+)
+
 // Option is a function that configures a Runner or ValueRunner.
 // Options follow the functional options pattern for flexible configuration.
 type Option func(*options)
 
 // options holds the internal configuration for retry behavior.
 type options struct {
-	attempts Attempts // Maximum number of retry attempts
-	backoff  Backoff  // Backoff strategy for calculating delays
-	budget   *Budget  // Retry budget to prevent cascading failures
-	jitter   Jitter   // Jitter strategy for randomizing delays
-	timeout  Timeout  // Timeout for each individual attempt
+	attempts Attempts                // Maximum number of retry attempts
+	backoff  Backoff                 // Backoff strategy for calculating delays
+	budget   *Budget                 // Retry budget to prevent cascading failures
+	breaker  *circuitbreaker.Breaker // Circuit breaker to stop calling a failing dependency
+	jitter   Jitter                  // Jitter strategy for randomizing delays
+	retryIf  []Predicate             // Predicates selecting retryable errors (all errors if empty)
+	timeout  Timeout                 // Timeout for each individual attempt
+
+	operation string                                     // Name used for metrics labels and the call's span
+	onRetry   func(ctx context.Context, info RetryInfo)  // Hook called before waiting to retry
+	onGiveUp  func(ctx context.Context, info GiveUpInfo) // Hook called when the call fails for good
 }
 
 // WithBudget configures a retry budget to prevent cascading failures.
@@ -80,3 +92,82 @@ func WithJitter(j Jitter) Option {
 		o.jitter = j
 	}
 }
+
+// WithCircuitBreaker routes every attempt through a circuit breaker. Each
+// attempt's outcome is recorded with the breaker, and once the breaker opens
+// the retry loop stops immediately, returning circuitbreaker.ErrOpen (or
+// circuitbreaker.ErrTooManyRequests) joined with the last attempt's error.
+// Share one breaker, or one per key from a circuitbreaker.Group, across the
+// runners calling the same dependency.
+//
+// Example:
+//
+//	breaker := circuitbreaker.New(circuitbreaker.WithName("billing"))
+//	runner := retry.NewRunner(retry.WithCircuitBreaker(breaker))
+func WithCircuitBreaker(breaker *circuitbreaker.Breaker) Option {
+	return func(o *options) {
+		o.breaker = breaker
+	}
+}
+
+// WithRetryIf restricts retries to errors matching at least one of the
+// predicates; any other error stops the retry loop and is returned as is.
+// Errors wrapped with Abort are never retried and errors wrapped with After
+// always are, whatever the predicates say. Without this option every error
+// is retried.
+//
+// Example:
+//
+//	runner := retry.NewRunner(retry.WithRetryIf(
+//	    retry.IsTimeout,
+//	    retry.HasStatusClass(5),
+//	    retry.HasStatus(http.StatusTooManyRequests),
+//	))
+func WithRetryIf(predicates ...Predicate) Option {
+	return func(o *options) {
+		o.retryIf = predicates
+	}
+}
+
+// WithOperation names the retried operation. The name labels the retry
+// metrics and is passed to the hooks, and each call gets an OpenTelemetry span
+// with that name (see the spans package) holding an event per attempt.
+// Without a name, the events are added to the caller's span instead.
+//
+// Example:
+//
+//	runner := retry.NewRunner(retry.WithOperation("billing.charge"))
+func WithOperation(name string) Option {
+	return func(o *options) {
+		o.operation = name
+	}
+}
+
+// WithOnRetry registers a hook called after each failed attempt that will be
+// retried, just before waiting for the next one. It runs on the retry loop's
+// goroutine, so it should return quickly.
+//
+// Example:
+//
+//	runner := retry.NewRunner(retry.WithOnRetry(func(ctx context.Context, info retry.RetryInfo) {
+//	    logger.Warn(ctx, "retrying", "attempt", info.Attempt, "delay", info.Delay, "error", info.Err)
+//	}))
+func WithOnRetry(hook func(ctx context.Context, info RetryInfo)) Option {
+	return func(o *options) {
+		o.onRetry = hook
+	}
+}
+
+// WithOnGiveUp registers a hook called once when a call fails for good, for
+// whatever reason; info.Outcome tells which. It isn't called for calls that succeed.
+//
+// Example:
+//
+//	runner := retry.NewRunner(retry.WithOnGiveUp(func(ctx context.Context, info retry.GiveUpInfo) {
+//	    logger.Error(ctx, "giving up", "attempts", info.Attempts, "outcome", info.Outcome, "error", info.Err)
+//	}))
+func WithOnGiveUp(hook func(ctx context.Context, info GiveUpInfo)) Option {
+	return func(o *options) {
+		o.onGiveUp = hook
+	}
+}
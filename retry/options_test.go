@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/amp-labs/amp-common/circuitbreaker"
 	"github.com/amp-labs/amp-common/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -192,3 +193,55 @@ func TestOptionsIsolation(t *testing.T) {
 	require.Error(t, err2)
 	assert.Equal(t, 5, callCount2)
 }
+
+func TestWithCircuitBreaker_Option(t *testing.T) {
+	t.Parallel()
+
+	errDown := errors.New("down") //nolint:err113 // Test error
+	breaker := circuitbreaker.New(
+		circuitbreaker.WithName("retry-test"),
+		circuitbreaker.WithConsecutiveFailures(2),
+		circuitbreaker.WithOpenTimeout(time.Hour),
+	)
+
+	callCount := 0
+	err := Do(t.Context(), func(ctx context.Context) error {
+		callCount++
+
+		return errDown
+	}, WithCircuitBreaker(breaker), WithAttempts(5), WithBackoff(ExpBackoff{Base: time.Millisecond, Max: time.Millisecond, Factor: 1}))
+
+	require.ErrorIs(t, err, circuitbreaker.ErrOpen)
+	require.ErrorIs(t, err, errDown)
+	assert.Equal(t, 2, callCount, "the breaker stops the loop once it opens")
+	assert.Equal(t, circuitbreaker.Open, breaker.State())
+
+	// Later runs fail fast without calling the operation.
+	err = Do(t.Context(), func(ctx context.Context) error {
+		callCount++
+
+		return nil
+	}, WithCircuitBreaker(breaker))
+
+	require.ErrorIs(t, err, circuitbreaker.ErrOpen)
+	assert.Equal(t, 2, callCount)
+}
+
+func TestWithCircuitBreaker_RecordsSuccess(t *testing.T) {
+	t.Parallel()
+
+	breaker := circuitbreaker.New(circuitbreaker.WithName("retry-test-success"))
+
+	callCount := 0
+	err := Do(t.Context(), func(ctx context.Context) error {
+		callCount++
+		if callCount < 2 {
+			return errors.New("retry") //nolint:err113 // Test error
+		}
+
+		return nil
+	}, WithCircuitBreaker(breaker), WithBackoff(ExpBackoff{Base: time.Millisecond, Max: time.Millisecond, Factor: 1}))
+
+	require.NoError(t, err)
+	assert.Equal(t, circuitbreaker.Counts{Requests: 2, Failures: 1}, breaker.Counts())
+}
@@ -0,0 +1,113 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+)
+
+// Predicate reports whether an error is worth retrying. Predicates are passed
+// to WithRetryIf to restrict which errors the retry loop retries.
+type Predicate func(err error) bool
+
+// IsError returns a Predicate matching errors for which errors.Is reports a
+// match with any of the targets.
+//
+// Example:
+//
+//	runner := retry.NewRunner(retry.WithRetryIf(retry.IsError(io.ErrUnexpectedEOF, syscall.ECONNRESET)))
+func IsError(targets ...error) Predicate {
+	return func(err error) bool {
+		for _, target := range targets {
+			if errors.Is(err, target) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// AsError returns a Predicate matching errors whose chain contains an error
+// of type E, as determined by errors.As.
+//
+// Example:
+//
+//	runner := retry.NewRunner(retry.WithRetryIf(retry.AsError[*net.OpError]()))
+func AsError[E error]() Predicate {
+	return func(err error) bool {
+		var target E
+
+		return errors.As(err, &target)
+	}
+}
+
+// IsTimeout is a Predicate matching timeouts: context.DeadlineExceeded and
+// net.Error values whose Timeout method reports true. This includes attempts
+// cut short by WithTimeout.
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// HasStatus returns a Predicate matching a *StatusError (see CheckResponse)
+// with any of the given HTTP status codes.
+//
+// Example:
+//
+//	runner := retry.NewRunner(retry.WithRetryIf(retry.HasStatus(http.StatusTooManyRequests)))
+func HasStatus(codes ...int) Predicate {
+	return func(err error) bool {
+		var statusErr *StatusError
+
+		return errors.As(err, &statusErr) && slices.Contains(codes, statusErr.StatusCode)
+	}
+}
+
+// HasStatusClass returns a Predicate matching a *StatusError (see
+// CheckResponse) whose HTTP status code is in any of the given classes, where
+// class 5 means 5xx, class 4 means 4xx and so on.
+//
+// Example:
+//
+//	// Retry server errors and throttling, but not other client errors
+//	runner := retry.NewRunner(retry.WithRetryIf(
+//	    retry.HasStatusClass(5),
+//	    retry.HasStatus(http.StatusTooManyRequests),
+//	))
+func HasStatusClass(classes ...int) Predicate {
+	return func(err error) bool {
+		var statusErr *StatusError
+
+		return errors.As(err, &statusErr) && slices.Contains(classes, statusErr.StatusCode/statusClassDivisor)
+	}
+}
+
+// shouldRetry reports whether err is retryable under the configured
+// predicates. Without predicates every error is retryable, and errors wrapped
+// with After always are, since the operation asked for the retry explicitly.
+// A delay read by CheckResponse doesn't count as such a request: it only
+// applies once the predicates accept the error.
+func (o *options) shouldRetry(err error) bool {
+	if len(o.retryIf) == 0 {
+		return true
+	}
+
+	if _, ok := afterDelay(err); ok {
+		return true
+	}
+
+	for _, predicate := range o.retryIf {
+		if predicate(err) {
+			return true
+		}
+	}
+
+	return false
+}
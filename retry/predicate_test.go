@@ -0,0 +1,123 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type codedError struct {
+	code int
+}
+
+func (e *codedError) Error() string { return fmt.Sprintf("code %d", e.code) }
+
+func TestIsError(t *testing.T) {
+	t.Parallel()
+
+	predicate := IsError(io.ErrUnexpectedEOF, os.ErrNotExist)
+
+	assert.True(t, predicate(io.ErrUnexpectedEOF))
+	assert.True(t, predicate(fmt.Errorf("reading: %w", os.ErrNotExist)))
+	assert.False(t, predicate(io.EOF))
+	assert.False(t, IsError()(io.EOF))
+}
+
+func TestAsError(t *testing.T) {
+	t.Parallel()
+
+	predicate := AsError[*codedError]()
+
+	assert.True(t, predicate(&codedError{code: 1}))
+	assert.True(t, predicate(fmt.Errorf("wrapped: %w", &codedError{code: 2})))
+	assert.False(t, predicate(io.EOF))
+}
+
+func TestIsTimeout(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsTimeout(context.DeadlineExceeded))
+	assert.True(t, IsTimeout(fmt.Errorf("call: %w", os.ErrDeadlineExceeded)))
+	assert.True(t, IsTimeout(&net.OpError{Op: "dial", Err: os.ErrDeadlineExceeded}))
+	assert.False(t, IsTimeout(&net.OpError{Op: "dial", Err: errors.New("refused")})) //nolint:err113 // Test error
+	assert.False(t, IsTimeout(context.Canceled))
+}
+
+func TestHasStatus(t *testing.T) {
+	t.Parallel()
+
+	throttled := &StatusError{StatusCode: http.StatusTooManyRequests}
+	unavailable := fmt.Errorf("call: %w", &StatusError{StatusCode: http.StatusServiceUnavailable})
+	notFound := &StatusError{StatusCode: http.StatusNotFound}
+
+	assert.True(t, HasStatus(http.StatusTooManyRequests)(throttled))
+	assert.False(t, HasStatus(http.StatusTooManyRequests)(unavailable))
+	assert.False(t, HasStatus(http.StatusTooManyRequests)(io.EOF))
+
+	assert.True(t, HasStatusClass(5)(unavailable))
+	assert.False(t, HasStatusClass(5)(notFound))
+	assert.True(t, HasStatusClass(4, 5)(notFound))
+	assert.False(t, HasStatusClass(5)(io.EOF))
+}
+
+func TestWithRetryIf_Option(t *testing.T) {
+	t.Parallel()
+
+	backoff := WithBackoff(ExpBackoff{Base: time.Millisecond, Max: time.Millisecond, Factor: 1})
+
+	t.Run("retries matching errors", func(t *testing.T) {
+		t.Parallel()
+
+		callCount := 0
+		err := Do(t.Context(), func(ctx context.Context) error {
+			callCount++
+
+			return &StatusError{StatusCode: http.StatusBadGateway}
+		}, WithRetryIf(IsTimeout, HasStatusClass(5)), WithAttempts(3), backoff)
+
+		require.Error(t, err)
+		assert.Equal(t, 3, callCount)
+	})
+
+	t.Run("stops on other errors", func(t *testing.T) {
+		t.Parallel()
+
+		callCount := 0
+		err := Do(t.Context(), func(ctx context.Context) error {
+			callCount++
+
+			return &StatusError{StatusCode: http.StatusBadRequest}
+		}, WithRetryIf(IsTimeout, HasStatusClass(5)), WithAttempts(3), backoff)
+
+		var statusErr *StatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusBadRequest, statusErr.StatusCode)
+		assert.Equal(t, 1, callCount)
+	})
+
+	t.Run("always retries errors wrapped with After", func(t *testing.T) {
+		t.Parallel()
+
+		callCount := 0
+		err := Do(t.Context(), func(ctx context.Context) error {
+			callCount++
+			if callCount < 2 {
+				return After(io.EOF, time.Millisecond)
+			}
+
+			return nil
+		}, WithRetryIf(IsTimeout), backoff)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, callCount)
+	})
+}
@@ -5,6 +5,15 @@
 // The package offers both simple one-shot functions (Do, DoValue) and reusable Runner interfaces
 // for operations that need consistent retry behavior.
 //
+// By default every error is retried except those wrapped with Abort. WithRetryIf narrows this down
+// with predicates such as IsTimeout, IsError and HasStatusClass, and After lets an operation say
+// how long to wait before the next attempt. CheckResponse turns HTTP error responses into errors
+// these understand, honoring Retry-After and rate-limit headers.
+//
+// Each call records an OpenTelemetry span event per attempt and observes the retry_attempts and
+// retry_delay_seconds Prometheus histograms, labeled with the name given by WithOperation.
+// WithOnRetry and WithOnGiveUp hook into the loop, for example to log failed attempts.
+//
 // Basic usage:
 //
 //	err := retry.Do(ctx, func(ctx context.Context) error {
@@ -33,7 +42,10 @@ import (
 	"time"
 
 	"github.com/amp-labs/amp-common/future"
+	"github.com/amp-labs/amp-common/spans"
 	"github.com/amp-labs/amp-common/zero"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
 )
 
@@ -164,22 +176,48 @@ func (v valueRunnerImpl[T]) Do(ctx context.Context, f func(ctx context.Context)
 	return out, nil
 }
 
-// do is the core retry loop that executes the provided function with retry logic.
+// do runs the retry loop for one call and reports it: it feeds the WithOnRetry
+// and WithOnGiveUp hooks, records an OpenTelemetry span event per attempt and
+// observes the retry metrics. Calls named with WithOperation get a span of
+// their own (see the spans package); others add events to the caller's span.
+func do(ctx context.Context, opts *options, operation func(ctx context.Context) error) error {
+	if opts.operation == "" {
+		return newObserver(opts, trace.SpanFromContext(ctx)).run(ctx, operation)
+	}
+
+	return spans.StartErr(ctx, opts.operation,
+		spans.WithSpanKind(trace.SpanKindInternal),
+		spans.WithAttribute(attrOperation, attribute.StringValue(opts.operation)),
+	).Enter(func(ctx context.Context, span trace.Span) error {
+		return newObserver(opts, span).run(ctx, operation)
+	})
+}
+
+// loop is the core retry loop that executes the provided function with retry logic.
 // It handles:
 //   - Attempt tracking via context
 //   - Budget enforcement to prevent cascading failures
+//   - Circuit breaking to stop calling a dependency that is down
 //   - Timeout handling for each attempt
 //   - Backoff and jitter between retries
 //   - Context cancellation
-//   - Permanent vs temporary error handling
+//   - Permanent vs temporary error handling, and WithRetryIf predicates
+//   - Delays requested with After
 //
-// The function returns:
+// The function returns how the call ended, along with:
 //   - nil if the operation succeeds
 //   - ctx.Err() if the context is canceled
 //   - ErrExhausted if the retry budget is exhausted
+//   - The circuit breaker's error, joined with the last error, if the breaker rejects an attempt
 //   - The permanent error if one is returned
+//   - The error as is if it matches none of the WithRetryIf predicates
 //   - The last error if all retries are exhausted
-func do(ctx context.Context, opts *options, operation func(ctx context.Context) error) error {
+func loop(
+	ctx context.Context,
+	opts *options,
+	obs *observer,
+	operation func(ctx context.Context) error,
+) (Outcome, error) {
 	var err error
 
 	var mut sync.Mutex
@@ -194,9 +232,17 @@ func do(ctx context.Context, opts *options, operation func(ctx context.Context)
 
 		// Check if retry budget allows this attempt (prevents cascading failures)
 		if !opts.budget.sendOK(attemptIndex != 0) {
-			return ErrExhausted
+			return BudgetExhausted, ErrExhausted
 		}
 
+		// Check if the circuit breaker allows this attempt (fails fast while the dependency is down)
+		done, breakerErr := opts.breaker.Allow(loopCtx)
+		if breakerErr != nil {
+			return CircuitOpen, errors.Join(breakerErr, err)
+		}
+
+		obs.attempts++
+
 		// Execute the operation in a goroutine to support timeout handling.
 		// NB: This automatically handles panics as well, which will be translated
 		// in to a normal error. So go right ahead and panic.
@@ -217,38 +263,71 @@ func do(ctx context.Context, opts *options, operation func(ctx context.Context)
 
 		// Wait for either the operation to complete or context cancellation
 		_, err = fut.AwaitContext(loopCtx)
+		done(opts.breaker.Classify(err))
+
 		if err == nil {
-			return nil
+			obs.attemptSucceeded(attemptIndex)
+
+			return Succeeded, nil
 		}
 
 		// Check if the error is permanent (non-retryable)
 		var retryErr Error
 		if errors.As(err, &retryErr) && !retryErr.Temporary() {
+			obs.attemptFailed(attemptIndex, err)
+
 			var p permanentError
 			if errors.As(err, &p) {
-				return p.error
+				return PermanentError, p.error
 			}
 
-			return err
+			return PermanentError, err
+		}
+
+		// Check if the configured predicates consider the error retryable
+		if !opts.shouldRetry(err) {
+			obs.attemptFailed(attemptIndex, err)
+
+			return NotRetryable, err
 		}
 
-		// Calculate backoff delay with jitter
-		delay := opts.backoff.Delay(attemptIndex)
-		delay = opts.jitter.jitter(delay)
+		// Calculate backoff delay with jitter, unless the operation or the server asked for a specific delay
+		delay, requested := requestedDelay(err)
+		if requested {
+			// Don't wait for a retry the context's deadline would cut short anyway
+			if deadline, ok := loopCtx.Deadline(); ok && time.Until(deadline) < delay {
+				obs.attemptFailed(attemptIndex, err)
+
+				return DeadlineTooSoon, err
+			}
+		} else {
+			delay = opts.backoff.Delay(attemptIndex)
+			delay = opts.jitter.jitter(delay)
+		}
+
+		// The loop still waits after the last attempt, but only reports a retry if one follows
+		if opts.attempts != 0 && Attempts(attemptIndex+1) >= opts.attempts {
+			obs.attemptFailed(attemptIndex, err)
+		} else {
+			obs.retrying(loopCtx, attemptIndex, err, delay)
+		}
 
 		// Wait for the delay period, respecting context cancellation
+		sleepStart := time.Now()
 		ticker := time.NewTimer(delay)
 		select {
 		case <-loopCtx.Done():
 			ticker.Stop()
+			obs.totalDelay += time.Since(sleepStart)
 
-			return loopCtx.Err()
+			return ContextDone, loopCtx.Err()
 		case <-ticker.C:
 			ticker.Stop()
+			obs.totalDelay += time.Since(sleepStart)
 		}
 	}
 
-	return err
+	return AttemptsExhausted, err
 }
 
 // callWithTimeout wraps a function call with a timeout. If the function does not complete
@@ -0,0 +1,85 @@
+package set
+
+import "iter"
+
+// membership is the part of Set and OrderedSet that the set algebra needs
+// from the other operand.
+type membership[T any] interface {
+	Contains(element T) (bool, error)
+	Size() int
+}
+
+// elementsOf drops the positions from an ordered set's iterator.
+func elementsOf[T any](s OrderedSet[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, element := range s.Seq() {
+			if !yield(element) {
+				return
+			}
+		}
+	}
+}
+
+// addMissing calls add for each element that other does not contain.
+func addMissing[T any](elements iter.Seq[T], other membership[T], add func(T) error) error {
+	for element := range elements {
+		contains, err := other.Contains(element)
+		if err != nil {
+			return err
+		}
+
+		if !contains {
+			err = add(element)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// isSubset reports whether other contains each of the size elements.
+func isSubset[T any](size int, elements iter.Seq[T], other membership[T]) (bool, error) {
+	if size > other.Size() {
+		return false, nil
+	}
+
+	for element := range elements {
+		contains, err := other.Contains(element)
+		if err != nil {
+			return false, err
+		}
+
+		if !contains {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// isDisjoint reports whether other contains none of the elements.
+func isDisjoint[T any](elements iter.Seq[T], other membership[T]) (bool, error) {
+	for element := range elements {
+		contains, err := other.Contains(element)
+		if err != nil {
+			return false, err
+		}
+
+		if contains {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// isEqual reports whether the size elements and other hold the same elements.
+func isEqual[T any](size int, elements iter.Seq[T], other membership[T]) (bool, error) {
+	if size != other.Size() {
+		return false, nil
+	}
+
+	return isSubset(size, elements, other)
+}
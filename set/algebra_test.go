@@ -0,0 +1,157 @@
+package set
+
+import (
+	"testing"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/sortable"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetAlgebra(t *testing.T) {
+	t.Parallel()
+
+	constructors := map[string]func(items ...hashing.HashableString) Set[hashing.HashableString]{
+		"hash": func(items ...hashing.HashableString) Set[hashing.HashableString] {
+			return NewSet(hashing.Sha256, items...)
+		},
+		"persistent": func(items ...hashing.HashableString) Set[hashing.HashableString] {
+			return NewPersistentSet(hashing.Sha256, items...)
+		},
+		"thread-safe": func(items ...hashing.HashableString) Set[hashing.HashableString] {
+			return NewThreadSafeSet(NewSet(hashing.Sha256, items...))
+		},
+	}
+
+	for name, newSet := range constructors {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			abc := newSet("a", "b", "c")
+			bcd := newSet("b", "c", "d")
+
+			difference, err := abc.Difference(bcd)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []hashing.HashableString{"a"}, difference.Entries())
+
+			symmetric, err := abc.SymmetricDifference(bcd)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []hashing.HashableString{"a", "d"}, symmetric.Entries())
+
+			// The operands are left alone
+			assert.Equal(t, 3, abc.Size())
+			assert.Equal(t, 3, bcd.Size())
+
+			subset, err := newSet("b").IsSubsetOf(abc)
+			require.NoError(t, err)
+			assert.True(t, subset)
+
+			subset, err = abc.IsSubsetOf(bcd)
+			require.NoError(t, err)
+			assert.False(t, subset)
+
+			superset, err := abc.IsSupersetOf(newSet("a", "c"))
+			require.NoError(t, err)
+			assert.True(t, superset)
+
+			disjoint, err := abc.IsDisjoint(newSet("x", "y"))
+			require.NoError(t, err)
+			assert.True(t, disjoint)
+
+			disjoint, err = abc.IsDisjoint(bcd)
+			require.NoError(t, err)
+			assert.False(t, disjoint)
+
+			equal, err := abc.Equals(newSet("c", "b", "a"))
+			require.NoError(t, err)
+			assert.True(t, equal)
+
+			equal, err = abc.Equals(bcd)
+			require.NoError(t, err)
+			assert.False(t, equal)
+		})
+	}
+}
+
+func TestOrderedSetAlgebra(t *testing.T) {
+	t.Parallel()
+
+	newOrderedSet := func(items ...hashing.HashableString) OrderedSet[hashing.HashableString] {
+		s := NewOrderedSet[hashing.HashableString](hashing.Sha256)
+		require.NoError(t, s.AddAll(items...))
+
+		return s
+	}
+
+	abc := newOrderedSet("c", "a", "b")
+	bcd := newOrderedSet("e", "d", "b", "c")
+
+	for name, s := range map[string]OrderedSet[hashing.HashableString]{
+		"ordered":     abc,
+		"persistent":  persistentOrderedCopy(t, abc),
+		"thread-safe": NewThreadSafeOrderedSet(abc.Clone()),
+	} {
+		difference, err := s.Difference(bcd)
+		require.NoError(t, err, name)
+		assert.Equal(t, []hashing.HashableString{"a"}, difference.Entries(), name)
+
+		symmetric, err := s.SymmetricDifference(bcd)
+		require.NoError(t, err, name)
+		assert.Equal(t, []hashing.HashableString{"a", "e", "d"}, symmetric.Entries(), name)
+
+		equal, err := s.Equals(newOrderedSet("a", "b", "c"))
+		require.NoError(t, err, name)
+		assert.True(t, equal, name)
+
+		superset, err := bcd.IsSupersetOf(s)
+		require.NoError(t, err, name)
+		assert.False(t, superset, name)
+	}
+}
+
+func TestSortedSetAlgebra(t *testing.T) {
+	t.Parallel()
+
+	tree := NewRedBlackTreeSet[sortable.Int]()
+	require.NoError(t, tree.AddAll(5, 1, 3))
+
+	persistentTree := NewPersistentSortedSet[sortable.Int]()
+	require.NoError(t, persistentTree.AddAll(3, 4))
+
+	difference, err := tree.Difference(persistentTree)
+	require.NoError(t, err)
+	assert.Equal(t, []sortable.Int{1, 5}, difference.Entries())
+
+	symmetric, err := persistentTree.SymmetricDifference(tree)
+	require.NoError(t, err)
+	assert.Equal(t, []sortable.Int{1, 4, 5}, symmetric.Entries())
+	assert.Equal(t, []sortable.Int{3, 4}, persistentTree.Entries())
+}
+
+func TestStringSetAlgebra(t *testing.T) {
+	t.Parallel()
+
+	abc := NewStringSet(hashing.Sha256)
+	require.NoError(t, abc.AddAll("a", "b", "c"))
+
+	bc := NewStringSet(hashing.Sha256)
+	require.NoError(t, bc.AddAll("b", "c"))
+
+	difference, err := abc.Difference(bc)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, difference.Entries())
+
+	subset, err := bc.IsSubsetOf(abc)
+	require.NoError(t, err)
+	assert.True(t, subset)
+}
+
+func persistentOrderedCopy(t *testing.T, s OrderedSet[hashing.HashableString]) OrderedSet[hashing.HashableString] {
+	t.Helper()
+
+	out := NewPersistentOrderedSet[hashing.HashableString](hashing.Sha256)
+	require.NoError(t, out.AddAll(s.Entries()...))
+
+	return out
+}
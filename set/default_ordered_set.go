@@ -65,7 +65,7 @@ var _ OrderedSet[collectableWhatever[any]] = (*defaultOrderedSet[collectableWhat
 // AddAll adds multiple elements to the set in order.
 // This operation bypasses the default value function and directly adds the provided elements.
 // If an element already exists, it is not added again and its position in the order is not changed.
-// Returns an error if any element causes a hash collision or if hashing fails.
+// Returns an error if hashing any element fails.
 func (d *defaultOrderedSet[T]) AddAll(elements ...T) error {
 	return d.s.AddAll(elements...)
 }
@@ -74,14 +74,14 @@ func (d *defaultOrderedSet[T]) AddAll(elements ...T) error {
 // This operation bypasses the default value function and directly adds the provided element.
 // If the element already exists, no error is returned and its position in the order is not changed.
 // If the element is new, it's appended to the end of the insertion order.
-// Returns an error if the element causes a hash collision or if hashing fails.
+// Returns an error if hashing the element fails.
 func (d *defaultOrderedSet[T]) Add(element T) error {
 	return d.s.Add(element)
 }
 
 // Remove deletes the element from the set.
 // If the element doesn't exist, this is a no-op and returns nil.
-// Returns an error if hashing fails or a collision is detected.
+// Returns an error if hashing fails.
 func (d *defaultOrderedSet[T]) Remove(element T) error {
 	return d.s.Remove(element)
 }
@@ -97,7 +97,7 @@ func (d *defaultOrderedSet[T]) Clear() {
 //   - If the function returns ErrNoDefaultValue, returns false
 //   - If the function returns another error, returns that error
 //
-// Returns an error if hashing fails or a hash collision occurs during lookup or insertion.
+// Returns an error if hashing fails during lookup or insertion.
 func (d *defaultOrderedSet[T]) Contains(element T) (bool, error) {
 	contains, err := d.s.Contains(element)
 	if err != nil {
@@ -167,7 +167,7 @@ func (d *defaultOrderedSet[T]) Seq() iter.Seq2[int, T] {
 // Union creates a new defaultOrderedSet containing all elements from both this set and other.
 // Elements from this set are added first (preserving their order), followed by elements from other.
 // The returned set uses the same default value function as this set.
-// Returns an error if any element causes a hash collision or if hashing fails.
+// Returns an error if hashing any element fails.
 func (d *defaultOrderedSet[T]) Union(other OrderedSet[T]) (OrderedSet[T], error) {
 	tmp, err := d.s.Union(other)
 	if err != nil {
@@ -183,7 +183,7 @@ func (d *defaultOrderedSet[T]) Union(other OrderedSet[T]) (OrderedSet[T], error)
 // Intersection creates a new defaultOrderedSet containing only elements that exist in both sets.
 // The insertion order is preserved from this set.
 // The returned set uses the same default value function as this set.
-// Returns an error if any element causes a hash collision or if hashing fails.
+// Returns an error if hashing any element fails.
 func (d *defaultOrderedSet[T]) Intersection(other OrderedSet[T]) (OrderedSet[T], error) {
 	tmp, err := d.s.Intersection(other)
 	if err != nil {
@@ -196,6 +196,57 @@ func (d *defaultOrderedSet[T]) Intersection(other OrderedSet[T]) (OrderedSet[T],
 	}, nil
 }
 
+// Difference creates a new defaultOrderedSet containing the elements of this set that are not in other.
+// The insertion order is preserved from this set.
+// The returned set uses the same default value function as this set.
+// Returns an error if hashing any element fails.
+func (d *defaultOrderedSet[T]) Difference(other OrderedSet[T]) (OrderedSet[T], error) {
+	tmp, err := d.s.Difference(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return &defaultOrderedSet[T]{
+		s: tmp,
+		f: d.f,
+	}, nil
+}
+
+// SymmetricDifference creates a new defaultOrderedSet containing the elements that are in exactly one of the two sets.
+// The returned set uses the same default value function as this set.
+// Returns an error if hashing any element fails.
+func (d *defaultOrderedSet[T]) SymmetricDifference(other OrderedSet[T]) (OrderedSet[T], error) {
+	tmp, err := d.s.SymmetricDifference(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return &defaultOrderedSet[T]{
+		s: tmp,
+		f: d.f,
+	}, nil
+}
+
+// IsSubsetOf reports whether every element of this set is also in other.
+func (d *defaultOrderedSet[T]) IsSubsetOf(other OrderedSet[T]) (bool, error) {
+	return d.s.IsSubsetOf(other)
+}
+
+// IsSupersetOf reports whether every element of other is also in this set.
+func (d *defaultOrderedSet[T]) IsSupersetOf(other OrderedSet[T]) (bool, error) {
+	return d.s.IsSupersetOf(other)
+}
+
+// IsDisjoint reports whether the two sets have no elements in common.
+func (d *defaultOrderedSet[T]) IsDisjoint(other OrderedSet[T]) (bool, error) {
+	return d.s.IsDisjoint(other)
+}
+
+// Equals reports whether both sets contain the same elements.
+func (d *defaultOrderedSet[T]) Equals(other OrderedSet[T]) (bool, error) {
+	return d.s.Equals(other)
+}
+
 // HashFunction returns the hash function used by the underlying ordered set.
 // This allows callers to inspect the hash function or create compatible sets.
 func (d *defaultOrderedSet[T]) HashFunction() hashing.HashFunc {
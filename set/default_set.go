@@ -65,21 +65,21 @@ var _ Set[collectableWhatever[any]] = (*defaultSet[collectableWhatever[any]])(ni
 
 // AddAll adds multiple elements to the set.
 // This operation bypasses the default value function and directly adds the provided elements.
-// Returns an error if any element causes a hash collision or if hashing fails.
+// Returns an error if hashing any element fails.
 func (d *defaultSet[T]) AddAll(elements ...T) error {
 	return d.s.AddAll(elements...)
 }
 
 // Add inserts an element into the set.
 // This operation bypasses the default value function and directly adds the provided element.
-// Returns an error if the element causes a hash collision or if hashing fails.
+// Returns an error if hashing the element fails.
 func (d *defaultSet[T]) Add(element T) error {
 	return d.s.Add(element)
 }
 
 // Remove deletes the element from the set.
 // If the element doesn't exist, this is a no-op and returns nil.
-// Returns an error if hashing fails or a collision is detected.
+// Returns an error if hashing fails.
 func (d *defaultSet[T]) Remove(element T) error {
 	return d.s.Remove(element)
 }
@@ -95,7 +95,7 @@ func (d *defaultSet[T]) Clear() {
 //   - If the function returns ErrNoDefaultValue, returns false
 //   - If the function returns another error, returns that error
 //
-// Returns an error if hashing fails or a hash collision occurs during lookup or insertion.
+// Returns an error if hashing fails during lookup or insertion.
 func (d *defaultSet[T]) Contains(element T) (bool, error) {
 	contains, err := d.s.Contains(element)
 	if err != nil {
@@ -161,7 +161,7 @@ func (d *defaultSet[T]) Seq() iter.Seq[T] {
 
 // Union creates a new defaultSet containing all elements from both this set and other.
 // The returned set uses the same default value function as this set.
-// Returns an error if any element causes a hash collision or if hashing fails.
+// Returns an error if hashing any element fails.
 func (d *defaultSet[T]) Union(other Set[T]) (Set[T], error) {
 	tmp, err := d.s.Union(other)
 	if err != nil {
@@ -176,7 +176,7 @@ func (d *defaultSet[T]) Union(other Set[T]) (Set[T], error) {
 
 // Intersection creates a new defaultSet containing only elements that exist in both sets.
 // The returned set uses the same default value function as this set.
-// Returns an error if any element causes a hash collision or if hashing fails.
+// Returns an error if hashing any element fails.
 func (d *defaultSet[T]) Intersection(other Set[T]) (Set[T], error) {
 	tmp, err := d.s.Intersection(other)
 	if err != nil {
@@ -189,6 +189,56 @@ func (d *defaultSet[T]) Intersection(other Set[T]) (Set[T], error) {
 	}, nil
 }
 
+// Difference creates a new defaultSet containing the elements of this set that are not in other.
+// The returned set uses the same default value function as this set.
+// Returns an error if hashing any element fails.
+func (d *defaultSet[T]) Difference(other Set[T]) (Set[T], error) {
+	tmp, err := d.s.Difference(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return &defaultSet[T]{
+		s: tmp,
+		f: d.f,
+	}, nil
+}
+
+// SymmetricDifference creates a new defaultSet containing the elements that are in exactly one of the two sets.
+// The returned set uses the same default value function as this set.
+// Returns an error if hashing any element fails.
+func (d *defaultSet[T]) SymmetricDifference(other Set[T]) (Set[T], error) {
+	tmp, err := d.s.SymmetricDifference(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return &defaultSet[T]{
+		s: tmp,
+		f: d.f,
+	}, nil
+}
+
+// IsSubsetOf reports whether every element of this set is also in other.
+func (d *defaultSet[T]) IsSubsetOf(other Set[T]) (bool, error) {
+	return d.s.IsSubsetOf(other)
+}
+
+// IsSupersetOf reports whether every element of other is also in this set.
+func (d *defaultSet[T]) IsSupersetOf(other Set[T]) (bool, error) {
+	return d.s.IsSupersetOf(other)
+}
+
+// IsDisjoint reports whether the two sets have no elements in common.
+func (d *defaultSet[T]) IsDisjoint(other Set[T]) (bool, error) {
+	return d.s.IsDisjoint(other)
+}
+
+// Equals reports whether both sets contain the same elements.
+func (d *defaultSet[T]) Equals(other Set[T]) (bool, error) {
+	return d.s.Equals(other)
+}
+
 // HashFunction returns the hash function used by the underlying set.
 // This allows callers to inspect the hash function or create compatible sets.
 func (d *defaultSet[T]) HashFunction() hashing.HashFunc {
@@ -0,0 +1,491 @@
+package set
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+
+	"github.com/amp-labs/amp-common/collectable"
+	"github.com/amp-labs/amp-common/hashing"
+	"gopkg.in/yaml.v3"
+)
+
+// Sets are encoded as a JSON array, YAML sequence or gob-encoded slice of their
+// elements. Ordered sets keep their insertion order and sorted sets their element
+// order. Decoding replaces the set's contents. Since the set implementations are
+// unexported, use UnmarshalJSON, UnmarshalYAML and their ordered variants to decode
+// into a new set, or decode into an existing one created with the right hash function.
+
+// UnmarshalJSON decodes a JSON array into a new hash set that uses hash.
+func UnmarshalJSON[T collectable.Collectable[T]](data []byte, hash hashing.HashFunc) (Set[T], error) {
+	s := NewSet[T](hash)
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// UnmarshalOrderedJSON decodes a JSON array into a new ordered set that uses hash,
+// keeping the order of the encoded elements.
+func UnmarshalOrderedJSON[T collectable.Collectable[T]](data []byte, hash hashing.HashFunc) (OrderedSet[T], error) {
+	s := NewOrderedSet[T](hash)
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// UnmarshalYAML decodes a YAML sequence into a new hash set that uses hash.
+func UnmarshalYAML[T collectable.Collectable[T]](data []byte, hash hashing.HashFunc) (Set[T], error) {
+	s := NewSet[T](hash)
+
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// UnmarshalOrderedYAML decodes a YAML sequence into a new ordered set that uses hash,
+// keeping the order of the encoded elements.
+func UnmarshalOrderedYAML[T collectable.Collectable[T]](data []byte, hash hashing.HashFunc) (OrderedSet[T], error) {
+	s := NewOrderedSet[T](hash)
+
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// gobEncodeElements encodes elements as a gob-encoded slice.
+func gobEncodeElements[T any](elements []T) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := gob.NewEncoder(&buf).Encode(elements); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decodeElements runs decode into a slice of elements.
+func decodeElements[T any](decode func(elements *[]T) error) ([]T, error) {
+	var elements []T
+
+	if err := decode(&elements); err != nil {
+		return nil, err
+	}
+
+	return elements, nil
+}
+
+// jsonElements, yamlElements and gobElements decode a slice of elements in each format.
+func jsonElements[T any](data []byte) ([]T, error) {
+	return decodeElements(func(elements *[]T) error { return json.Unmarshal(data, elements) })
+}
+
+func yamlElements[T any](node *yaml.Node) ([]T, error) {
+	return decodeElements(func(elements *[]T) error { return node.Decode(elements) })
+}
+
+func gobElements[T any](data []byte) ([]T, error) {
+	return decodeElements(func(elements *[]T) error { return gob.NewDecoder(bytes.NewReader(data)).Decode(elements) })
+}
+
+// replaceElements replaces a set's contents with elements, once decoding has succeeded.
+func replaceElements[T any](clearAll func(), addAll func(elements ...T) error, elements []T, err error) error {
+	if err != nil {
+		return err
+	}
+
+	clearAll()
+
+	return addAll(elements...)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s *setImpl[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Entries())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the set's contents.
+func (s *setImpl[T]) UnmarshalJSON(data []byte) error {
+	elements, err := jsonElements[T](data)
+
+	return replaceElements(s.Clear, s.AddAll, elements, err)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (s *setImpl[T]) MarshalYAML() (any, error) {
+	return s.Entries(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the set's contents.
+func (s *setImpl[T]) UnmarshalYAML(node *yaml.Node) error {
+	elements, err := yamlElements[T](node)
+
+	return replaceElements(s.Clear, s.AddAll, elements, err)
+}
+
+// GobEncode implements gob.GobEncoder.
+func (s *setImpl[T]) GobEncode() ([]byte, error) {
+	return gobEncodeElements(s.Entries())
+}
+
+// GobDecode implements gob.GobDecoder, replacing the set's contents.
+func (s *setImpl[T]) GobDecode(data []byte) error {
+	elements, err := gobElements[T](data)
+
+	return replaceElements(s.Clear, s.AddAll, elements, err)
+}
+
+// MarshalJSON implements json.Marshaler, in insertion order.
+func (s *orderedSetImpl[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Entries())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the set's contents.
+func (s *orderedSetImpl[T]) UnmarshalJSON(data []byte) error {
+	elements, err := jsonElements[T](data)
+
+	return replaceElements(s.Clear, s.AddAll, elements, err)
+}
+
+// MarshalYAML implements yaml.Marshaler, in insertion order.
+func (s *orderedSetImpl[T]) MarshalYAML() (any, error) {
+	return s.Entries(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the set's contents.
+func (s *orderedSetImpl[T]) UnmarshalYAML(node *yaml.Node) error {
+	elements, err := yamlElements[T](node)
+
+	return replaceElements(s.Clear, s.AddAll, elements, err)
+}
+
+// GobEncode implements gob.GobEncoder, in insertion order.
+func (s *orderedSetImpl[T]) GobEncode() ([]byte, error) {
+	return gobEncodeElements(s.Entries())
+}
+
+// GobDecode implements gob.GobDecoder, replacing the set's contents.
+func (s *orderedSetImpl[T]) GobDecode(data []byte) error {
+	elements, err := gobElements[T](data)
+
+	return replaceElements(s.Clear, s.AddAll, elements, err)
+}
+
+// MarshalJSON implements json.Marshaler, in sorted order.
+func (r *redBlackTreeSet[K]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Entries())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the set's contents.
+func (r *redBlackTreeSet[K]) UnmarshalJSON(data []byte) error {
+	elements, err := jsonElements[K](data)
+
+	return replaceElements(r.Clear, r.AddAll, elements, err)
+}
+
+// MarshalYAML implements yaml.Marshaler, in sorted order.
+func (r *redBlackTreeSet[K]) MarshalYAML() (any, error) {
+	return r.Entries(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the set's contents.
+func (r *redBlackTreeSet[K]) UnmarshalYAML(node *yaml.Node) error {
+	elements, err := yamlElements[K](node)
+
+	return replaceElements(r.Clear, r.AddAll, elements, err)
+}
+
+// GobEncode implements gob.GobEncoder, in sorted order.
+func (r *redBlackTreeSet[K]) GobEncode() ([]byte, error) {
+	return gobEncodeElements(r.Entries())
+}
+
+// GobDecode implements gob.GobDecoder, replacing the set's contents.
+func (r *redBlackTreeSet[K]) GobDecode(data []byte) error {
+	elements, err := gobElements[K](data)
+
+	return replaceElements(r.Clear, r.AddAll, elements, err)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d *defaultSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Entries())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the set's contents.
+func (d *defaultSet[T]) UnmarshalJSON(data []byte) error {
+	elements, err := jsonElements[T](data)
+
+	return replaceElements(d.Clear, d.AddAll, elements, err)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (d *defaultSet[T]) MarshalYAML() (any, error) {
+	return d.Entries(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the set's contents.
+func (d *defaultSet[T]) UnmarshalYAML(node *yaml.Node) error {
+	elements, err := yamlElements[T](node)
+
+	return replaceElements(d.Clear, d.AddAll, elements, err)
+}
+
+// GobEncode implements gob.GobEncoder.
+func (d *defaultSet[T]) GobEncode() ([]byte, error) {
+	return gobEncodeElements(d.Entries())
+}
+
+// GobDecode implements gob.GobDecoder, replacing the set's contents.
+func (d *defaultSet[T]) GobDecode(data []byte) error {
+	elements, err := gobElements[T](data)
+
+	return replaceElements(d.Clear, d.AddAll, elements, err)
+}
+
+// MarshalJSON implements json.Marshaler, in insertion order.
+func (d *defaultOrderedSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Entries())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the set's contents.
+func (d *defaultOrderedSet[T]) UnmarshalJSON(data []byte) error {
+	elements, err := jsonElements[T](data)
+
+	return replaceElements(d.Clear, d.AddAll, elements, err)
+}
+
+// MarshalYAML implements yaml.Marshaler, in insertion order.
+func (d *defaultOrderedSet[T]) MarshalYAML() (any, error) {
+	return d.Entries(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the set's contents.
+func (d *defaultOrderedSet[T]) UnmarshalYAML(node *yaml.Node) error {
+	elements, err := yamlElements[T](node)
+
+	return replaceElements(d.Clear, d.AddAll, elements, err)
+}
+
+// GobEncode implements gob.GobEncoder, in insertion order.
+func (d *defaultOrderedSet[T]) GobEncode() ([]byte, error) {
+	return gobEncodeElements(d.Entries())
+}
+
+// GobDecode implements gob.GobDecoder, replacing the set's contents.
+func (d *defaultOrderedSet[T]) GobDecode(data []byte) error {
+	elements, err := gobElements[T](data)
+
+	return replaceElements(d.Clear, d.AddAll, elements, err)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s *persistentSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Entries())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the set's contents.
+func (s *persistentSet[T]) UnmarshalJSON(data []byte) error {
+	elements, err := jsonElements[T](data)
+
+	return replaceElements(s.Clear, s.AddAll, elements, err)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (s *persistentSet[T]) MarshalYAML() (any, error) {
+	return s.Entries(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the set's contents.
+func (s *persistentSet[T]) UnmarshalYAML(node *yaml.Node) error {
+	elements, err := yamlElements[T](node)
+
+	return replaceElements(s.Clear, s.AddAll, elements, err)
+}
+
+// GobEncode implements gob.GobEncoder.
+func (s *persistentSet[T]) GobEncode() ([]byte, error) {
+	return gobEncodeElements(s.Entries())
+}
+
+// GobDecode implements gob.GobDecoder, replacing the set's contents.
+func (s *persistentSet[T]) GobDecode(data []byte) error {
+	elements, err := gobElements[T](data)
+
+	return replaceElements(s.Clear, s.AddAll, elements, err)
+}
+
+// MarshalJSON implements json.Marshaler, in insertion order.
+func (s *persistentOrderedSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Entries())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the set's contents.
+func (s *persistentOrderedSet[T]) UnmarshalJSON(data []byte) error {
+	elements, err := jsonElements[T](data)
+
+	return replaceElements(s.Clear, s.AddAll, elements, err)
+}
+
+// MarshalYAML implements yaml.Marshaler, in insertion order.
+func (s *persistentOrderedSet[T]) MarshalYAML() (any, error) {
+	return s.Entries(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the set's contents.
+func (s *persistentOrderedSet[T]) UnmarshalYAML(node *yaml.Node) error {
+	elements, err := yamlElements[T](node)
+
+	return replaceElements(s.Clear, s.AddAll, elements, err)
+}
+
+// GobEncode implements gob.GobEncoder, in insertion order.
+func (s *persistentOrderedSet[T]) GobEncode() ([]byte, error) {
+	return gobEncodeElements(s.Entries())
+}
+
+// GobDecode implements gob.GobDecoder, replacing the set's contents.
+func (s *persistentOrderedSet[T]) GobDecode(data []byte) error {
+	elements, err := gobElements[T](data)
+
+	return replaceElements(s.Clear, s.AddAll, elements, err)
+}
+
+// MarshalJSON implements json.Marshaler, in sorted order.
+func (s *persistentSortedSet[K]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Entries())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the set's contents.
+func (s *persistentSortedSet[K]) UnmarshalJSON(data []byte) error {
+	elements, err := jsonElements[K](data)
+
+	return replaceElements(s.Clear, s.AddAll, elements, err)
+}
+
+// MarshalYAML implements yaml.Marshaler, in sorted order.
+func (s *persistentSortedSet[K]) MarshalYAML() (any, error) {
+	return s.Entries(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the set's contents.
+func (s *persistentSortedSet[K]) UnmarshalYAML(node *yaml.Node) error {
+	elements, err := yamlElements[K](node)
+
+	return replaceElements(s.Clear, s.AddAll, elements, err)
+}
+
+// GobEncode implements gob.GobEncoder, in sorted order.
+func (s *persistentSortedSet[K]) GobEncode() ([]byte, error) {
+	return gobEncodeElements(s.Entries())
+}
+
+// GobDecode implements gob.GobDecoder, replacing the set's contents.
+func (s *persistentSortedSet[K]) GobDecode(data []byte) error {
+	elements, err := gobElements[K](data)
+
+	return replaceElements(s.Clear, s.AddAll, elements, err)
+}
+
+// MarshalJSON implements json.Marshaler, encoding a snapshot of the set.
+func (t *threadSafeSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Entries())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the set's contents under the write lock.
+func (t *threadSafeSet[T]) UnmarshalJSON(data []byte) error {
+	elements, err := jsonElements[T](data)
+
+	return t.replace(elements, err)
+}
+
+// MarshalYAML implements yaml.Marshaler, encoding a snapshot of the set.
+func (t *threadSafeSet[T]) MarshalYAML() (any, error) {
+	return t.Entries(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the set's contents under the write lock.
+func (t *threadSafeSet[T]) UnmarshalYAML(node *yaml.Node) error {
+	elements, err := yamlElements[T](node)
+
+	return t.replace(elements, err)
+}
+
+// GobEncode implements gob.GobEncoder, encoding a snapshot of the set.
+func (t *threadSafeSet[T]) GobEncode() ([]byte, error) {
+	return gobEncodeElements(t.Entries())
+}
+
+// GobDecode implements gob.GobDecoder, replacing the set's contents under the write lock.
+func (t *threadSafeSet[T]) GobDecode(data []byte) error {
+	elements, err := gobElements[T](data)
+
+	return t.replace(elements, err)
+}
+
+func (t *threadSafeSet[T]) replace(elements []T, err error) error {
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return replaceElements(t.internal.Clear, t.internal.AddAll, elements, nil)
+}
+
+// MarshalJSON implements json.Marshaler, encoding a snapshot of the set in insertion order.
+func (t *threadSafeOrderedSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Entries())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the set's contents under the write lock.
+func (t *threadSafeOrderedSet[T]) UnmarshalJSON(data []byte) error {
+	elements, err := jsonElements[T](data)
+
+	return t.replace(elements, err)
+}
+
+// MarshalYAML implements yaml.Marshaler, encoding a snapshot of the set in insertion order.
+func (t *threadSafeOrderedSet[T]) MarshalYAML() (any, error) {
+	return t.Entries(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, replacing the set's contents under the write lock.
+func (t *threadSafeOrderedSet[T]) UnmarshalYAML(node *yaml.Node) error {
+	elements, err := yamlElements[T](node)
+
+	return t.replace(elements, err)
+}
+
+// GobEncode implements gob.GobEncoder, encoding a snapshot of the set in insertion order.
+func (t *threadSafeOrderedSet[T]) GobEncode() ([]byte, error) {
+	return gobEncodeElements(t.Entries())
+}
+
+// GobDecode implements gob.GobDecoder, replacing the set's contents under the write lock.
+func (t *threadSafeOrderedSet[T]) GobDecode(data []byte) error {
+	elements, err := gobElements[T](data)
+
+	return t.replace(elements, err)
+}
+
+func (t *threadSafeOrderedSet[T]) replace(elements []T, err error) error {
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return replaceElements(t.internal.Clear, t.internal.AddAll, elements, nil)
+}
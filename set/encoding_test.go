@@ -0,0 +1,99 @@
+package set
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/sortable"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSetJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("hash sets round-trip", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewSet[hashing.HashableString](hashing.Sha256, "a", "b")
+
+		data, err := json.Marshal(s)
+		require.NoError(t, err)
+
+		decoded, err := UnmarshalJSON[hashing.HashableString](data, hashing.Sha256)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []hashing.HashableString{"a", "b"}, decoded.Entries())
+	})
+
+	t.Run("ordered sets keep their order", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewOrderedSet[hashing.HashableString](hashing.Sha256)
+		require.NoError(t, s.AddAll("z", "a", "m"))
+
+		data, err := json.Marshal(s)
+		require.NoError(t, err)
+		assert.JSONEq(t, `["z", "a", "m"]`, string(data))
+
+		decoded, err := UnmarshalOrderedJSON[hashing.HashableString](data, hashing.Sha256)
+		require.NoError(t, err)
+		assert.Equal(t, []hashing.HashableString{"z", "a", "m"}, decoded.Entries())
+	})
+
+	t.Run("decoding replaces the contents", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewRedBlackTreeSet[sortable.Int]()
+		require.NoError(t, s.Add(9))
+		require.NoError(t, json.Unmarshal([]byte(`[3, 1, 2]`), s))
+		assert.Equal(t, []sortable.Int{1, 2, 3}, s.Entries())
+
+		data, err := json.Marshal(s)
+		require.NoError(t, err)
+		assert.JSONEq(t, `[1, 2, 3]`, string(data))
+	})
+
+	t.Run("thread-safe sets", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewThreadSafeSet(NewSet[hashing.HashableString](hashing.Sha256, "old"))
+		require.NoError(t, json.Unmarshal([]byte(`["new"]`), s))
+		assert.Equal(t, []hashing.HashableString{"new"}, s.Entries())
+
+		require.Error(t, json.Unmarshal([]byte(`{"not": "a list"}`), s))
+		assert.Equal(t, []hashing.HashableString{"new"}, s.Entries())
+	})
+}
+
+func TestSetYAML(t *testing.T) {
+	t.Parallel()
+
+	s := NewThreadSafeOrderedSet(NewOrderedSet[hashing.HashableString](hashing.Sha256))
+	require.NoError(t, s.AddAll("z", "a"))
+
+	data, err := yaml.Marshal(s)
+	require.NoError(t, err)
+	assert.Equal(t, "- z\n- a\n", string(data))
+
+	decoded, err := UnmarshalOrderedYAML[hashing.HashableString](data, hashing.Sha256)
+	require.NoError(t, err)
+	assert.Equal(t, []hashing.HashableString{"z", "a"}, decoded.Entries())
+}
+
+func TestSetGob(t *testing.T) {
+	t.Parallel()
+
+	s := NewPersistentOrderedSet[hashing.HashableString](hashing.Sha256)
+	require.NoError(t, s.AddAll("b", "a"))
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(s))
+
+	decoded := NewOrderedSet[hashing.HashableString](hashing.Sha256)
+	require.NoError(t, gob.NewDecoder(&buf).Decode(decoded))
+	assert.Equal(t, []hashing.HashableString{"b", "a"}, decoded.Entries())
+}
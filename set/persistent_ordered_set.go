@@ -0,0 +1,282 @@
+package set
+
+import (
+	"cmp"
+	"iter"
+
+	"github.com/amp-labs/amp-common/collectable"
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/internal/persistent"
+)
+
+// persistentOrderedSet is a PersistentOrderedSet. A trie maps each element to
+// the sequence number it was added with, and a tree keyed by sequence number
+// keeps the insertion order, so both lookups and removals are O(log n) and
+// every version shares structure with the one it was derived from.
+type persistentOrderedSet[T collectable.Collectable[T]] struct {
+	hash  hashing.HashFunc
+	index persistent.Trie[T, uint64]
+	order persistent.Tree[uint64, T]
+	next  uint64
+}
+
+// NewPersistentOrderedSet creates an empty PersistentOrderedSet with the provided
+// hash function. Elements are returned in insertion order, as with NewOrderedSet.
+func NewPersistentOrderedSet[T collectable.Collectable[T]](hash hashing.HashFunc) PersistentOrderedSet[T] {
+	return &persistentOrderedSet[T]{
+		hash:  hash,
+		order: persistent.NewTree[uint64, T](cmp.Compare[uint64]),
+	}
+}
+
+var _ PersistentOrderedSet[collectableWhatever[any]] = (*persistentOrderedSet[collectableWhatever[any]])(nil)
+
+func (s *persistentOrderedSet[T]) derive() *persistentOrderedSet[T] {
+	out := *s
+
+	return &out
+}
+
+func (s *persistentOrderedSet[T]) With(element T) (PersistentOrderedSet[T], error) {
+	out := s.derive()
+
+	err := out.Add(element)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *persistentOrderedSet[T]) Without(element T) (PersistentOrderedSet[T], error) {
+	out := s.derive()
+
+	err := out.Remove(element)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *persistentOrderedSet[T]) AddAll(elements ...T) error {
+	for _, elem := range elements {
+		err := s.Add(elem)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *persistentOrderedSet[T]) Add(element T) error {
+	hashVal, err := s.hash(element)
+	if err != nil {
+		return err
+	}
+
+	h := persistent.Hash(hashVal)
+
+	// Existing elements keep their position
+	if _, found := s.index.Get(h, element); found {
+		return nil
+	}
+
+	s.index = s.index.With(h, element, s.next)
+	s.order = s.order.With(s.next, element)
+	s.next++
+
+	return nil
+}
+
+func (s *persistentOrderedSet[T]) Remove(element T) error {
+	hashVal, err := s.hash(element)
+	if err != nil {
+		return err
+	}
+
+	h := persistent.Hash(hashVal)
+
+	seq, found := s.index.Get(h, element)
+	if !found {
+		return nil
+	}
+
+	s.index, _ = s.index.Without(h, element)
+	s.order, _ = s.order.Without(seq)
+
+	return nil
+}
+
+func (s *persistentOrderedSet[T]) Clear() {
+	s.index = persistent.Trie[T, uint64]{}
+	s.order = s.order.Clear()
+	s.next = 0
+}
+
+func (s *persistentOrderedSet[T]) Contains(element T) (bool, error) {
+	hashVal, err := s.hash(element)
+	if err != nil {
+		return false, err
+	}
+
+	_, found := s.index.Get(persistent.Hash(hashVal), element)
+
+	return found, nil
+}
+
+func (s *persistentOrderedSet[T]) Size() int {
+	return s.index.Len()
+}
+
+func (s *persistentOrderedSet[T]) Entries() []T {
+	result := make([]T, 0, s.index.Len())
+	for _, element := range s.order.All() {
+		result = append(result, element)
+	}
+
+	return result
+}
+
+func (s *persistentOrderedSet[T]) Seq() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+
+		for _, element := range s.order.All() {
+			if !yield(i, element) {
+				return
+			}
+
+			i++
+		}
+	}
+}
+
+// Union returns a version of this set with the elements of other that it
+// doesn't contain yet appended in their order in other.
+func (s *persistentOrderedSet[T]) Union(other OrderedSet[T]) (OrderedSet[T], error) {
+	out := s.derive()
+
+	for _, element := range other.Seq() {
+		err := out.Add(element)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// Intersection returns a version of this set without the elements that are
+// missing from other. The order of the remaining elements is unchanged.
+func (s *persistentOrderedSet[T]) Intersection(other OrderedSet[T]) (OrderedSet[T], error) {
+	out := s.derive()
+
+	for _, element := range s.order.All() {
+		contains, err := other.Contains(element)
+		if err != nil {
+			return nil, err
+		}
+
+		if !contains {
+			err = out.Remove(element)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// Difference returns a version of this set without the elements of other.
+// The order of the remaining elements is unchanged.
+func (s *persistentOrderedSet[T]) Difference(other OrderedSet[T]) (OrderedSet[T], error) {
+	out := s.derive()
+
+	for _, element := range other.Seq() {
+		err := out.Remove(element)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// SymmetricDifference returns a version of this set without the elements it
+// shares with other, and with the remaining elements of other appended in
+// their order in other.
+func (s *persistentOrderedSet[T]) SymmetricDifference(other OrderedSet[T]) (OrderedSet[T], error) {
+	out := s.derive()
+
+	for _, element := range other.Seq() {
+		contains, err := s.Contains(element)
+		if err != nil {
+			return nil, err
+		}
+
+		if contains {
+			err = out.Remove(element)
+		} else {
+			err = out.Add(element)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+func (s *persistentOrderedSet[T]) IsSubsetOf(other OrderedSet[T]) (bool, error) {
+	return isSubset(s.Size(), elementsOf[T](s), other)
+}
+
+func (s *persistentOrderedSet[T]) IsSupersetOf(other OrderedSet[T]) (bool, error) {
+	return isSubset(other.Size(), elementsOf(other), s)
+}
+
+func (s *persistentOrderedSet[T]) IsDisjoint(other OrderedSet[T]) (bool, error) {
+	return isDisjoint(elementsOf[T](s), other)
+}
+
+func (s *persistentOrderedSet[T]) Equals(other OrderedSet[T]) (bool, error) {
+	return isEqual(s.Size(), elementsOf[T](s), other)
+}
+
+// HashFunction returns the hash function used by this ordered set.
+func (s *persistentOrderedSet[T]) HashFunction() hashing.HashFunc {
+	return s.hash
+}
+
+// Filter returns a version of this set without the elements that don't
+// satisfy the predicate. The insertion order is preserved.
+func (s *persistentOrderedSet[T]) Filter(predicate func(T) bool) OrderedSet[T] {
+	out := s.derive()
+
+	for _, element := range s.order.All() {
+		if !predicate(element) {
+			_ = out.Remove(element) // Hashing succeeded when the element was added
+		}
+	}
+
+	return out
+}
+
+// FilterNot returns a version of this set without the elements that satisfy
+// the predicate. The insertion order is preserved.
+func (s *persistentOrderedSet[T]) FilterNot(predicate func(T) bool) OrderedSet[T] {
+	return s.Filter(func(element T) bool { return !predicate(element) })
+}
+
+// Clone returns a new set sharing all of its structure with this one in O(1).
+func (s *persistentOrderedSet[T]) Clone() OrderedSet[T] {
+	if s == nil {
+		return nil
+	}
+
+	return s.derive()
+}
@@ -0,0 +1,272 @@
+package set
+
+import (
+	"iter"
+
+	"github.com/amp-labs/amp-common/collectable"
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/internal/persistent"
+)
+
+// persistentSet is a PersistentSet backed by a hash array mapped trie. Adding or
+// removing an element copies only the O(log n) trie nodes on its path.
+type persistentSet[T collectable.Collectable[T]] struct {
+	hash hashing.HashFunc
+	trie persistent.Trie[T, struct{}]
+}
+
+// NewPersistentSet creates an empty PersistentSet with the provided hash function.
+// Like NewSet, elements whose hashes collide are told apart with Equals.
+//
+// Use a persistent set for snapshots that are handed to other goroutines or
+// kept around while the set keeps changing: Clone, Union, Intersection and
+// Filter share structure with the original instead of copying it.
+//
+// Example:
+//
+//	s := set.NewPersistentSet[hashing.HashableString](hashing.XxHash32)
+//	_ = s.Add("a")
+//	snapshot := s.Clone() // O(1)
+//	_ = s.Add("b")        // snapshot still only contains "a"
+func NewPersistentSet[T collectable.Collectable[T]](hash hashing.HashFunc, items ...T) PersistentSet[T] {
+	s := &persistentSet[T]{hash: hash}
+
+	if len(items) > 0 {
+		_ = s.AddAll(items...)
+	}
+
+	return s
+}
+
+var _ PersistentSet[collectableWhatever[any]] = (*persistentSet[collectableWhatever[any]])(nil)
+
+func (s *persistentSet[T]) with(element T) (persistent.Trie[T, struct{}], error) {
+	hashVal, err := s.hash(element)
+	if err != nil {
+		return s.trie, err
+	}
+
+	return s.trie.With(persistent.Hash(hashVal), element, struct{}{}), nil
+}
+
+func (s *persistentSet[T]) without(element T) (persistent.Trie[T, struct{}], error) {
+	hashVal, err := s.hash(element)
+	if err != nil {
+		return s.trie, err
+	}
+
+	trie, _ := s.trie.Without(persistent.Hash(hashVal), element)
+
+	return trie, nil
+}
+
+func (s *persistentSet[T]) With(element T) (PersistentSet[T], error) {
+	trie, err := s.with(element)
+	if err != nil {
+		return nil, err
+	}
+
+	return &persistentSet[T]{hash: s.hash, trie: trie}, nil
+}
+
+func (s *persistentSet[T]) Without(element T) (PersistentSet[T], error) {
+	trie, err := s.without(element)
+	if err != nil {
+		return nil, err
+	}
+
+	return &persistentSet[T]{hash: s.hash, trie: trie}, nil
+}
+
+func (s *persistentSet[T]) AddAll(elements ...T) error {
+	for _, elem := range elements {
+		err := s.Add(elem)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *persistentSet[T]) Add(element T) error {
+	trie, err := s.with(element)
+	if err != nil {
+		return err
+	}
+
+	s.trie = trie
+
+	return nil
+}
+
+func (s *persistentSet[T]) Remove(element T) error {
+	trie, err := s.without(element)
+	if err != nil {
+		return err
+	}
+
+	s.trie = trie
+
+	return nil
+}
+
+func (s *persistentSet[T]) Clear() {
+	s.trie = persistent.Trie[T, struct{}]{}
+}
+
+func (s *persistentSet[T]) Contains(element T) (bool, error) {
+	hashVal, err := s.hash(element)
+	if err != nil {
+		return false, err
+	}
+
+	_, found := s.trie.Get(persistent.Hash(hashVal), element)
+
+	return found, nil
+}
+
+func (s *persistentSet[T]) Size() int {
+	return s.trie.Len()
+}
+
+func (s *persistentSet[T]) Entries() []T {
+	items := make([]T, 0, s.trie.Len())
+	for item := range s.Seq() {
+		items = append(items, item)
+	}
+
+	return items
+}
+
+func (s *persistentSet[T]) Seq() iter.Seq[T] {
+	return keys(s.trie.All())
+}
+
+// Union returns a version of this set that also contains the elements of other.
+// Only the elements of other are inserted, so the cost is proportional to the
+// size of other rather than the size of the result.
+func (s *persistentSet[T]) Union(other Set[T]) (Set[T], error) {
+	out := &persistentSet[T]{hash: s.hash, trie: s.trie}
+
+	for item := range other.Seq() {
+		err := out.Add(item)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// Intersection returns a version of this set without the elements that are
+// missing from other. Removed elements are the only ones copied.
+func (s *persistentSet[T]) Intersection(other Set[T]) (Set[T], error) {
+	out := &persistentSet[T]{hash: s.hash, trie: s.trie}
+
+	for item := range s.Seq() {
+		contains, err := other.Contains(item)
+		if err != nil {
+			return nil, err
+		}
+
+		if !contains {
+			err = out.Remove(item)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// Difference returns a version of this set without the elements of other.
+// Only the elements of other are looked up, so the cost is proportional to
+// the size of other.
+func (s *persistentSet[T]) Difference(other Set[T]) (Set[T], error) {
+	out := &persistentSet[T]{hash: s.hash, trie: s.trie}
+
+	for item := range other.Seq() {
+		err := out.Remove(item)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// SymmetricDifference returns a version of this set without the elements it
+// shares with other, and with the remaining elements of other added.
+func (s *persistentSet[T]) SymmetricDifference(other Set[T]) (Set[T], error) {
+	out := &persistentSet[T]{hash: s.hash, trie: s.trie}
+
+	for item := range other.Seq() {
+		contains, err := s.Contains(item)
+		if err != nil {
+			return nil, err
+		}
+
+		if contains {
+			err = out.Remove(item)
+		} else {
+			err = out.Add(item)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+func (s *persistentSet[T]) IsSubsetOf(other Set[T]) (bool, error) {
+	return isSubset(s.Size(), s.Seq(), other)
+}
+
+func (s *persistentSet[T]) IsSupersetOf(other Set[T]) (bool, error) {
+	return isSubset(other.Size(), other.Seq(), s)
+}
+
+func (s *persistentSet[T]) IsDisjoint(other Set[T]) (bool, error) {
+	return isDisjoint(s.Seq(), other)
+}
+
+func (s *persistentSet[T]) Equals(other Set[T]) (bool, error) {
+	return isEqual(s.Size(), s.Seq(), other)
+}
+
+// HashFunction returns the hash function used by this set.
+func (s *persistentSet[T]) HashFunction() hashing.HashFunc {
+	return s.hash
+}
+
+// Filter returns a version of this set without the elements that don't satisfy the predicate.
+func (s *persistentSet[T]) Filter(predicate func(T) bool) Set[T] {
+	out := &persistentSet[T]{hash: s.hash, trie: s.trie}
+
+	for item := range s.Seq() {
+		if !predicate(item) {
+			_ = out.Remove(item) // Hashing succeeded when the element was added
+		}
+	}
+
+	return out
+}
+
+// FilterNot returns a version of this set without the elements that satisfy the predicate.
+func (s *persistentSet[T]) FilterNot(predicate func(T) bool) Set[T] {
+	return s.Filter(func(item T) bool { return !predicate(item) })
+}
+
+// Clone returns a new set sharing all of its structure with this one. It is
+// O(1); later changes to either set don't affect the other.
+func (s *persistentSet[T]) Clone() Set[T] {
+	if s == nil {
+		return nil
+	}
+
+	return &persistentSet[T]{hash: s.hash, trie: s.trie}
+}
@@ -0,0 +1,217 @@
+package set
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/sortable"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentSet(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Add, Remove and Contains", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewPersistentSet[hashing.HashableString](hashing.Sha256, "a", "b")
+
+		require.NoError(t, s.Add("a"))
+		require.NoError(t, s.Add("c"))
+		require.NoError(t, s.Remove("b"))
+		require.NoError(t, s.Remove("missing"))
+
+		contains, err := s.Contains("c")
+		require.NoError(t, err)
+		assert.True(t, contains)
+
+		contains, err = s.Contains("b")
+		require.NoError(t, err)
+		assert.False(t, contains)
+
+		assert.ElementsMatch(t, []hashing.HashableString{"a", "c"}, s.Entries())
+	})
+
+	t.Run("Add colliding elements", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewPersistentSet[collidingElement](hashing.Sha256)
+
+		require.NoError(t, s.Add(collidingElement{id: 1, hash: "same"}))
+		require.NoError(t, s.Add(collidingElement{id: 2, hash: "same"}))
+		assert.Equal(t, 2, s.Size())
+
+		require.NoError(t, s.Remove(collidingElement{id: 1, hash: "same"}))
+
+		contains, err := s.Contains(collidingElement{id: 2, hash: "same"})
+		require.NoError(t, err)
+		assert.True(t, contains)
+	})
+
+	t.Run("versions are independent", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewPersistentSet[hashing.HashableString](hashing.Sha256, "a")
+
+		with, err := s.With("b")
+		require.NoError(t, err)
+
+		without, err := with.Without("a")
+		require.NoError(t, err)
+
+		snapshot := s.Clone()
+		s.Clear()
+
+		assert.Equal(t, 0, s.Size())
+		assert.Equal(t, []hashing.HashableString{"a"}, snapshot.Entries())
+		assert.ElementsMatch(t, []hashing.HashableString{"a", "b"}, with.Entries())
+		assert.Equal(t, []hashing.HashableString{"b"}, without.Entries())
+	})
+
+	t.Run("derived sets don't affect the original", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewPersistentSet[hashing.HashableString](hashing.Sha256, "a", "b")
+		other := NewSet[hashing.HashableString](hashing.Sha256, "b", "c")
+
+		union, err := s.Union(other)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []hashing.HashableString{"a", "b", "c"}, union.Entries())
+
+		intersection, err := s.Intersection(other)
+		require.NoError(t, err)
+		assert.Equal(t, []hashing.HashableString{"b"}, intersection.Entries())
+
+		filtered := s.Filter(func(item hashing.HashableString) bool { return item == "a" })
+		assert.Equal(t, []hashing.HashableString{"a"}, filtered.Entries())
+
+		filteredNot := s.FilterNot(func(item hashing.HashableString) bool { return item == "a" })
+		assert.Equal(t, []hashing.HashableString{"b"}, filteredNot.Entries())
+
+		assert.ElementsMatch(t, []hashing.HashableString{"a", "b"}, s.Entries())
+	})
+}
+
+func TestPersistentSortedSet(t *testing.T) {
+	t.Parallel()
+
+	newSet := func() PersistentSortedSet[sortable.Int] {
+		s := NewPersistentSortedSet[sortable.Int]()
+		_ = s.AddAll(30, 10, 40, 20)
+
+		return s
+	}
+
+	t.Run("answers ordered queries", func(t *testing.T) {
+		t.Parallel()
+
+		s := newSet()
+
+		assert.Equal(t, []sortable.Int{10, 20, 30, 40}, s.Entries())
+		assert.Equal(t, []sortable.Int{40, 30, 20, 10}, slices.Collect(s.SeqDescending()))
+		assert.Equal(t, []sortable.Int{20, 30}, slices.Collect(s.Range(15, 40)))
+		assert.Equal(t, sortable.Int(10), s.Min().GetOrPanic())
+		assert.Equal(t, sortable.Int(40), s.Max().GetOrPanic())
+		assert.Equal(t, sortable.Int(20), s.Floor(25).GetOrPanic())
+		assert.Equal(t, sortable.Int(30), s.Ceiling(25).GetOrPanic())
+		assert.Equal(t, sortable.Int(10), s.Lower(20).GetOrPanic())
+		assert.Equal(t, sortable.Int(30), s.Higher(20).GetOrPanic())
+		assert.Equal(t, 1, s.Rank(20))
+		assert.Equal(t, sortable.Int(30), s.Select(2).GetOrPanic())
+		assert.True(t, s.Select(-1).Empty())
+	})
+
+	t.Run("versions are independent", func(t *testing.T) {
+		t.Parallel()
+
+		s := newSet()
+
+		with, err := s.With(50)
+		require.NoError(t, err)
+
+		without, err := s.Without(10)
+		require.NoError(t, err)
+
+		snapshot := s.Clone()
+		require.NoError(t, s.Remove(20))
+
+		filtered := snapshot.Filter(func(item sortable.Int) bool { return item > 20 })
+
+		assert.Equal(t, []sortable.Int{10, 30, 40}, s.Entries())
+		assert.Equal(t, []sortable.Int{10, 20, 30, 40}, snapshot.Entries())
+		assert.Equal(t, []sortable.Int{10, 20, 30, 40, 50}, with.Entries())
+		assert.Equal(t, []sortable.Int{20, 30, 40}, without.Entries())
+		assert.Equal(t, []sortable.Int{30, 40}, filtered.Entries())
+	})
+}
+
+func TestPersistentOrderedSet(t *testing.T) {
+	t.Parallel()
+
+	newSet := func() PersistentOrderedSet[hashing.HashableString] {
+		s := NewPersistentOrderedSet[hashing.HashableString](hashing.Sha256)
+		_ = s.AddAll("c", "a", "b")
+
+		return s
+	}
+
+	t.Run("keeps insertion order", func(t *testing.T) {
+		t.Parallel()
+
+		s := newSet()
+
+		require.NoError(t, s.Add("c"))
+		require.NoError(t, s.Remove("a"))
+		require.NoError(t, s.Add("a"))
+
+		assert.Equal(t, []hashing.HashableString{"c", "b", "a"}, s.Entries())
+
+		for i, item := range s.Seq() {
+			assert.Equal(t, s.Entries()[i], item)
+		}
+	})
+
+	t.Run("versions are independent", func(t *testing.T) {
+		t.Parallel()
+
+		s := newSet()
+
+		with, err := s.With("d")
+		require.NoError(t, err)
+
+		without, err := s.Without("c")
+		require.NoError(t, err)
+
+		snapshot := s.Clone()
+		s.Clear()
+
+		assert.Empty(t, s.Entries())
+		assert.Equal(t, []hashing.HashableString{"c", "a", "b"}, snapshot.Entries())
+		assert.Equal(t, []hashing.HashableString{"c", "a", "b", "d"}, with.Entries())
+		assert.Equal(t, []hashing.HashableString{"a", "b"}, without.Entries())
+	})
+
+	t.Run("derived sets keep the order", func(t *testing.T) {
+		t.Parallel()
+
+		s := newSet()
+
+		other := NewOrderedSet[hashing.HashableString](hashing.Sha256)
+		_ = other.AddAll("d", "a")
+
+		union, err := s.Union(other)
+		require.NoError(t, err)
+		assert.Equal(t, []hashing.HashableString{"c", "a", "b", "d"}, union.Entries())
+
+		intersection, err := s.Intersection(other)
+		require.NoError(t, err)
+		assert.Equal(t, []hashing.HashableString{"a"}, intersection.Entries())
+
+		filtered := s.FilterNot(func(item hashing.HashableString) bool { return item == "a" })
+		assert.Equal(t, []hashing.HashableString{"c", "b"}, filtered.Entries())
+
+		assert.Equal(t, []hashing.HashableString{"c", "a", "b"}, s.Entries())
+	})
+}
@@ -0,0 +1,249 @@
+package set
+
+import (
+	"iter"
+
+	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/internal/persistent"
+	"github.com/amp-labs/amp-common/optional"
+	"github.com/amp-labs/amp-common/sortable"
+)
+
+// persistentSortedSet is a PersistentSortedSet backed by a path-copying balanced
+// binary tree. Adding or removing an element copies only the O(log n) nodes on
+// its path from the root.
+type persistentSortedSet[K sortable.Sortable[K]] struct {
+	tree persistent.Tree[K, struct{}]
+}
+
+// NewPersistentSortedSet creates an empty PersistentSortedSet. Elements are kept
+// in sorted order like in NewRedBlackTreeSet, but Clone is O(1) and earlier
+// versions stay valid as the set changes.
+func NewPersistentSortedSet[K sortable.Sortable[K]]() PersistentSortedSet[K] {
+	return &persistentSortedSet[K]{tree: persistent.NewTree[K, struct{}](persistent.CompareSortable[K])}
+}
+
+var _ PersistentSortedSet[sortable.Int] = (*persistentSortedSet[sortable.Int])(nil)
+
+func (s *persistentSortedSet[K]) derive(tree persistent.Tree[K, struct{}]) *persistentSortedSet[K] {
+	return &persistentSortedSet[K]{tree: tree}
+}
+
+func (s *persistentSortedSet[K]) With(element K) (PersistentSortedSet[K], error) {
+	return s.derive(s.tree.With(element, struct{}{})), nil
+}
+
+func (s *persistentSortedSet[K]) Without(element K) (PersistentSortedSet[K], error) {
+	tree, _ := s.tree.Without(element)
+
+	return s.derive(tree), nil
+}
+
+func (s *persistentSortedSet[K]) AddAll(elements ...K) error {
+	for _, element := range elements {
+		s.tree = s.tree.With(element, struct{}{})
+	}
+
+	return nil
+}
+
+func (s *persistentSortedSet[K]) Add(element K) error {
+	s.tree = s.tree.With(element, struct{}{})
+
+	return nil
+}
+
+func (s *persistentSortedSet[K]) Remove(element K) error {
+	s.tree, _ = s.tree.Without(element)
+
+	return nil
+}
+
+func (s *persistentSortedSet[K]) Clear() {
+	s.tree = s.tree.Clear()
+}
+
+func (s *persistentSortedSet[K]) Contains(element K) (bool, error) {
+	_, found := s.tree.Get(element)
+
+	return found, nil
+}
+
+func (s *persistentSortedSet[K]) Size() int {
+	return s.tree.Len()
+}
+
+// Entries returns all elements in ascending order.
+func (s *persistentSortedSet[K]) Entries() []K {
+	entries := make([]K, 0, s.tree.Len())
+	for element := range s.Seq() {
+		entries = append(entries, element)
+	}
+
+	return entries
+}
+
+// Seq returns an iterator over all elements in ascending order.
+func (s *persistentSortedSet[K]) Seq() iter.Seq[K] {
+	return keys(s.tree.All())
+}
+
+// Union returns a version of this set that also contains the elements of other.
+func (s *persistentSortedSet[K]) Union(other Set[K]) (Set[K], error) {
+	out := s.derive(s.tree)
+
+	for element := range other.Seq() {
+		out.tree = out.tree.With(element, struct{}{})
+	}
+
+	return out, nil
+}
+
+// Intersection returns a version of this set without the elements that are missing from other.
+func (s *persistentSortedSet[K]) Intersection(other Set[K]) (Set[K], error) {
+	out := s.derive(s.tree)
+
+	for element := range s.Seq() {
+		contains, err := other.Contains(element)
+		if err != nil {
+			return nil, err
+		}
+
+		if !contains {
+			out.tree, _ = out.tree.Without(element)
+		}
+	}
+
+	return out, nil
+}
+
+// Difference returns a version of this set without the elements of other.
+func (s *persistentSortedSet[K]) Difference(other Set[K]) (Set[K], error) {
+	out := s.derive(s.tree)
+
+	for element := range other.Seq() {
+		out.tree, _ = out.tree.Without(element)
+	}
+
+	return out, nil
+}
+
+// SymmetricDifference returns a version of this set without the elements it
+// shares with other, and with the remaining elements of other added.
+func (s *persistentSortedSet[K]) SymmetricDifference(other Set[K]) (Set[K], error) {
+	out := s.derive(s.tree)
+
+	for element := range other.Seq() {
+		if _, found := s.tree.Get(element); found {
+			out.tree, _ = out.tree.Without(element)
+		} else {
+			out.tree = out.tree.With(element, struct{}{})
+		}
+	}
+
+	return out, nil
+}
+
+func (s *persistentSortedSet[K]) IsSubsetOf(other Set[K]) (bool, error) {
+	return isSubset(s.Size(), s.Seq(), other)
+}
+
+func (s *persistentSortedSet[K]) IsSupersetOf(other Set[K]) (bool, error) {
+	return isSubset(other.Size(), other.Seq(), s)
+}
+
+func (s *persistentSortedSet[K]) IsDisjoint(other Set[K]) (bool, error) {
+	return isDisjoint(s.Seq(), other)
+}
+
+func (s *persistentSortedSet[K]) Equals(other Set[K]) (bool, error) {
+	return isEqual(s.Size(), s.Seq(), other)
+}
+
+// HashFunction returns nil as sorted sets order elements instead of hashing them.
+func (s *persistentSortedSet[K]) HashFunction() hashing.HashFunc {
+	return nil
+}
+
+// Filter returns a version of this set without the elements that don't satisfy the predicate.
+func (s *persistentSortedSet[K]) Filter(predicate func(K) bool) Set[K] {
+	out := s.derive(s.tree)
+
+	for element := range s.Seq() {
+		if !predicate(element) {
+			out.tree, _ = out.tree.Without(element)
+		}
+	}
+
+	return out
+}
+
+// FilterNot returns a version of this set without the elements that satisfy the predicate.
+func (s *persistentSortedSet[K]) FilterNot(predicate func(K) bool) Set[K] {
+	return s.Filter(func(element K) bool { return !predicate(element) })
+}
+
+// Clone returns a new set sharing all of its structure with this one in O(1).
+func (s *persistentSortedSet[K]) Clone() Set[K] {
+	return s.derive(s.tree)
+}
+
+func (s *persistentSortedSet[K]) Min() optional.Value[K] {
+	return entryKey(s.tree.Min())
+}
+
+func (s *persistentSortedSet[K]) Max() optional.Value[K] {
+	return entryKey(s.tree.Max())
+}
+
+func (s *persistentSortedSet[K]) Floor(element K) optional.Value[K] {
+	return entryKey(s.tree.Floor(element, true))
+}
+
+func (s *persistentSortedSet[K]) Ceiling(element K) optional.Value[K] {
+	return entryKey(s.tree.Ceiling(element, true))
+}
+
+func (s *persistentSortedSet[K]) Lower(element K) optional.Value[K] {
+	return entryKey(s.tree.Floor(element, false))
+}
+
+func (s *persistentSortedSet[K]) Higher(element K) optional.Value[K] {
+	return entryKey(s.tree.Ceiling(element, false))
+}
+
+func (s *persistentSortedSet[K]) Range(from, to K) iter.Seq[K] {
+	return keys(s.tree.Range(from, to))
+}
+
+func (s *persistentSortedSet[K]) SeqDescending() iter.Seq[K] {
+	return keys(s.tree.Descending())
+}
+
+func (s *persistentSortedSet[K]) Rank(element K) int {
+	return s.tree.Rank(element)
+}
+
+func (s *persistentSortedSet[K]) Select(index int) optional.Value[K] {
+	return entryKey(s.tree.Select(index))
+}
+
+// keys drops the values of a key-value iterator.
+func keys[K any, V any](seq iter.Seq2[K, V]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range seq {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// entryKey converts the result of a tree lookup to an optional key.
+func entryKey[K any, V any](entry persistent.Entry[K, V], found bool) optional.Value[K] {
+	if !found {
+		return optional.None[K]()
+	}
+
+	return optional.Some(entry.Key)
+}
@@ -5,6 +5,7 @@ import (
 	"iter"
 
 	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/optional"
 	"github.com/amp-labs/amp-common/sortable"
 )
 
@@ -14,28 +15,6 @@ type visitor[K sortable.Sortable[K]] interface {
 	Visit(node *rbtNode[K]) bool
 }
 
-// countingVisitor is a visitor implementation that counts the total number of nodes in the tree.
-// It performs an in-order traversal and increments Count for each non-nil node visited.
-type countingVisitor[K sortable.Sortable[K]] struct {
-	Count int
-}
-
-// Visit performs an in-order traversal of the tree, incrementing the count for each node.
-// It recursively visits the left subtree, increments the count, then visits the right subtree.
-func (v *countingVisitor[K]) Visit(node *rbtNode[K]) bool {
-	if node == nil {
-		return true
-	}
-
-	if !v.Visit(node.left) {
-		return false
-	}
-
-	v.Count++
-
-	return v.Visit(node.right)
-}
-
 // color represents the color of a node in the red-black tree.
 // Red-black trees maintain balance by coloring nodes either red or black
 // and enforcing specific color properties during insertions and deletions.
@@ -83,10 +62,12 @@ const (
 )
 
 // rbtNode represents a single node in the red-black tree.
-// Each node contains a key, color, and pointers to its parent and children.
+// Each node contains a key, color, the size of the subtree rooted at it,
+// and pointers to its parent and children.
 type rbtNode[K sortable.Sortable[K]] struct {
 	key    K
 	color  color
+	size   int
 	left   *rbtNode[K]
 	right  *rbtNode[K]
 	parent *rbtNode[K]
@@ -112,6 +93,21 @@ func (n *rbtNode[K]) Color() color {
 	return n.color
 }
 
+// Size returns the number of nodes in the subtree rooted at this node.
+// A nil node has size zero.
+func (n *rbtNode[K]) Size() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.size
+}
+
+// resize recomputes the subtree size of this node from its children.
+func (n *rbtNode[K]) resize() {
+	n.size = n.left.Size() + n.right.Size() + 1
+}
+
 // redBlackTreeSet is a Set implementation backed by a red-black tree.
 //
 // Red-black trees are self-balancing binary search trees that maintain the following properties:
@@ -148,7 +144,7 @@ func (r *redBlackTreeSet[K]) AddAll(elements ...K) error {
 // Time complexity: O(log n).
 func (r *redBlackTreeSet[K]) Add(element K) error {
 	if r.root == nil {
-		r.root = &rbtNode[K]{key: element, color: black}
+		r.root = &rbtNode[K]{key: element, color: black, size: 1}
 
 		return nil
 	}
@@ -159,7 +155,7 @@ func (r *redBlackTreeSet[K]) Add(element K) error {
 	}
 
 	if parent != nil {
-		newNode := &rbtNode[K]{key: element, parent: parent}
+		newNode := &rbtNode[K]{key: element, parent: parent, size: 1}
 
 		switch dir {
 		case left:
@@ -169,6 +165,10 @@ func (r *redBlackTreeSet[K]) Add(element K) error {
 		case nodir:
 		}
 
+		for n := parent; n != nil; n = n.parent {
+			n.size++
+		}
+
 		r.fixupPut(newNode)
 	}
 
@@ -202,23 +202,38 @@ func (r *redBlackTreeSet[K]) Remove(element K) error {
 
 	var x *rbtNode[K] //nolint:varnamelen // Standard red-black tree variable names from CLRS
 
+	// xParent is the parent of x's position. x may be nil, so its parent
+	// can't be read from x itself during the fixup.
+	var xParent *rbtNode[K]
+
 	switch {
 	case z.left == nil:
 		x = z.right
+		xParent = z.parent
+		shrinkAncestors(z)
 		r.transplant(z, z.right)
 	case z.right == nil:
 		x = z.left
+		xParent = z.parent
+		shrinkAncestors(z)
 		r.transplant(z, z.left)
 	default:
 		y = r.getMinimum(z.right)
 		yOriginalColor = y.color
 		x = y.right
 
+		// y is spliced out of its position and takes z's place, so everything
+		// above y (including z) loses one descendant.
+		shrinkAncestors(y)
+
 		if y.parent == z {
+			xParent = y
+
 			if x != nil {
 				x.parent = y
 			}
 		} else {
+			xParent = y.parent
 			r.transplant(y, y.right)
 			y.right = z.right
 			y.right.parent = y
@@ -229,10 +244,11 @@ func (r *redBlackTreeSet[K]) Remove(element K) error {
 		y.left = z.left
 		y.left.parent = y
 		y.color = z.color
+		y.size = z.size
 	}
 
 	if yOriginalColor == black {
-		r.fixupDelete(x)
+		r.fixupDelete(x, xParent)
 	}
 
 	return nil
@@ -253,13 +269,10 @@ func (r *redBlackTreeSet[K]) Contains(element K) (bool, error) {
 }
 
 // Size returns the number of elements in the set.
-// It performs a full tree traversal using a counting visitor.
-// Time complexity: O(n).
+// Every node tracks the size of its subtree, so no traversal is needed.
+// Time complexity: O(1).
 func (r *redBlackTreeSet[K]) Size() int {
-	vis := &countingVisitor[K]{}
-	r.walk(vis)
-
-	return vis.Count
+	return r.root.Size()
 }
 
 // Entries returns all elements in the set as a slice, in sorted order.
@@ -359,6 +372,57 @@ func (r *redBlackTreeSet[K]) Intersection(other Set[K]) (Set[K], error) {
 	return out, nil
 }
 
+// Difference returns a new set containing the elements of this set that are not in the other set.
+// Time complexity: O(n log m) where n is the size of this set and m is the size of the other set.
+func (r *redBlackTreeSet[K]) Difference(other Set[K]) (Set[K], error) {
+	ns := NewRedBlackTreeSet[K]()
+
+	err := addMissing(r.Seq(), other, ns.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns, nil
+}
+
+// SymmetricDifference returns a new set containing the elements that are in exactly one of the two sets.
+// Time complexity: O((n + m) log(n + m)) where n and m are the sizes of the two sets.
+func (r *redBlackTreeSet[K]) SymmetricDifference(other Set[K]) (Set[K], error) {
+	ns := NewRedBlackTreeSet[K]()
+
+	err := addMissing(r.Seq(), other, ns.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	err = addMissing(other.Seq(), r, ns.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns, nil
+}
+
+// IsSubsetOf reports whether every element of this set is also in the other set.
+func (r *redBlackTreeSet[K]) IsSubsetOf(other Set[K]) (bool, error) {
+	return isSubset(r.Size(), r.Seq(), other)
+}
+
+// IsSupersetOf reports whether every element of the other set is also in this set.
+func (r *redBlackTreeSet[K]) IsSupersetOf(other Set[K]) (bool, error) {
+	return isSubset(other.Size(), other.Seq(), r)
+}
+
+// IsDisjoint reports whether the two sets have no elements in common.
+func (r *redBlackTreeSet[K]) IsDisjoint(other Set[K]) (bool, error) {
+	return isDisjoint(r.Seq(), other)
+}
+
+// Equals reports whether both sets contain the same elements.
+func (r *redBlackTreeSet[K]) Equals(other Set[K]) (bool, error) {
+	return isEqual(r.Size(), r.Seq(), other)
+}
+
 // HashFunction returns nil because red-black tree sets do not use hashing.
 // This method exists to satisfy the Set interface.
 func (r *redBlackTreeSet[K]) HashFunction() hashing.HashFunc {
@@ -379,7 +443,8 @@ func (r *redBlackTreeSet[K]) Clone() Set[K] {
 
 // NewRedBlackTreeSet creates a new empty red-black tree set.
 // The returned set maintains elements in sorted order and provides O(log n) operations.
-func NewRedBlackTreeSet[K sortable.Sortable[K]]() Set[K] {
+// The returned SortedSet can also be used wherever a Set is expected.
+func NewRedBlackTreeSet[K sortable.Sortable[K]]() SortedSet[K] {
 	return &redBlackTreeSet[K]{}
 }
 
@@ -492,6 +557,9 @@ func (r *redBlackTreeSet[K]) rotateRight(y *rbtNode[K]) {
 
 	x.right = y
 	y.parent = x
+
+	x.size = y.size
+	y.resize()
 }
 
 // rotateLeft performs a left rotation around node x.
@@ -538,6 +606,9 @@ func (r *redBlackTreeSet[K]) rotateLeft(x *rbtNode[K]) {
 
 	y.left = x
 	x.parent = y
+
+	y.size = x.size
+	x.resize()
 }
 
 // transplant replaces subtree rooted at u with subtree rooted at v.
@@ -646,83 +717,94 @@ loop:
 //
 // The implementation handles both left and right symmetric cases.
 //
+// x may be nil (an empty subtree standing in for the removed node), which is why its
+// parent is passed in separately. A nil x still carries the extra black and is fixed up.
+//
 // nolint:varnamelen,dupl // Standard red-black tree variable names; symmetric cases
-func (r *redBlackTreeSet[K]) fixupDelete(x *rbtNode[K]) {
-	if x == nil {
-		return
-	}
+func (r *redBlackTreeSet[K]) fixupDelete(x, parent *rbtNode[K]) {
+	for x != r.root && !isRed(x) {
+		if parent == nil {
+			break
+		}
 
-loop:
-	for {
-		switch {
-		case x == r.root:
-			break loop
-		case x.color == red:
-			break loop
-		case x == x.parent.right:
-			w := x.parent.left //nolint:varnamelen // Standard red-black tree variable names
+		if x == parent.left {
+			w := parent.right //nolint:varnamelen // Standard red-black tree variable names from CLRS
 			if isRed(w) {
 				w.color = black
-				x.parent.color = red
-				r.rotateRight(x.parent)
-				w = x.parent.left
+				parent.color = red
+				r.rotateLeft(parent)
+				w = parent.right
 			}
 
-			if w != nil {
-				switch {
-				case !isRed(w.left) && !isRed(w.right):
+			if w == nil || (!isRed(w.left) && !isRed(w.right)) {
+				if w != nil {
 					w.color = red
-					x = x.parent // recurse up tree
-				case isRed(w.right) && !isRed(w.left):
-					w.right.color = black
-					w.color = red
-					r.rotateLeft(w)
-					w = x.parent.left
 				}
 
-				if isRed(w.left) {
-					w.color = x.parent.color
-					x.parent.color = black
-					w.left.color = black
+				x, parent = parent, parent.parent // recurse up tree
 
-					r.rotateRight(x.parent)
-					x = r.root
-				}
+				continue
 			}
-		case x == x.parent.left:
-			w := x.parent.right //nolint:varnamelen // Standard red-black tree variable names from CLRS
+
+			if !isRed(w.right) {
+				w.left.color = black
+				w.color = red
+				r.rotateRight(w)
+				w = parent.right
+			}
+
+			w.color = parent.color
+			parent.color = black
+			w.right.color = black
+			r.rotateLeft(parent)
+
+			x, parent = r.root, nil
+		} else {
+			w := parent.left //nolint:varnamelen // Standard red-black tree variable names from CLRS
 			if isRed(w) {
 				w.color = black
-				x.parent.color = red
-				r.rotateLeft(x.parent)
-				w = x.parent.right
+				parent.color = red
+				r.rotateRight(parent)
+				w = parent.left
 			}
 
-			if w != nil {
-				switch {
-				case !isRed(w.left) && !isRed(w.right):
-					w.color = red
-					x = x.parent // recurse up tree
-				case isRed(w.left) && !isRed(w.right):
-					w.left.color = black
+			if w == nil || (!isRed(w.left) && !isRed(w.right)) {
+				if w != nil {
 					w.color = red
-					r.rotateRight(w)
-					w = x.parent.right
 				}
 
-				if isRed(w.right) {
-					w.color = x.parent.color
-					x.parent.color = black
-					w.right.color = black
+				x, parent = parent, parent.parent // recurse up tree
 
-					r.rotateLeft(x.parent)
-					x = r.root
-				}
+				continue
+			}
+
+			if !isRed(w.left) {
+				w.right.color = black
+				w.color = red
+				r.rotateLeft(w)
+				w = parent.left
 			}
+
+			w.color = parent.color
+			parent.color = black
+			w.left.color = black
+			r.rotateRight(parent)
+
+			x, parent = r.root, nil
 		}
 	}
 
-	x.color = black
+	if x != nil {
+		x.color = black
+	}
+}
+
+// shrinkAncestors decrements the subtree size of every ancestor of n.
+// It is called when n is about to be unlinked from its current position.
+func shrinkAncestors[K sortable.Sortable[K]](n *rbtNode[K]) {
+	for p := n.parent; p != nil; p = p.parent {
+		p.size--
+	}
 }
 
 // isRed checks if a node is red.
@@ -777,3 +859,204 @@ func (r *redBlackTreeSet[K]) FilterNot(predicate func(K) bool) Set[K] {
 
 	return out
 }
+
+// getMaximum finds the node with the largest key in the subtree rooted at x.
+// This is always the rightmost node in the subtree.
+func (r *redBlackTreeSet[K]) getMaximum(x *rbtNode[K]) *rbtNode[K] {
+	for x.right != nil {
+		x = x.right
+	}
+
+	return x
+}
+
+// successor returns the node with the next larger key, or nil if n holds the largest key.
+func (r *redBlackTreeSet[K]) successor(n *rbtNode[K]) *rbtNode[K] {
+	if n.right != nil {
+		return r.getMinimum(n.right)
+	}
+
+	p := n.parent
+	for p != nil && n == p.right {
+		n, p = p, p.parent
+	}
+
+	return p
+}
+
+// predecessor returns the node with the next smaller key, or nil if n holds the smallest key.
+func (r *redBlackTreeSet[K]) predecessor(n *rbtNode[K]) *rbtNode[K] {
+	if n.left != nil {
+		return r.getMaximum(n.left)
+	}
+
+	p := n.parent
+	for p != nil && n == p.left {
+		n, p = p, p.parent
+	}
+
+	return p
+}
+
+// floorNode finds the node with the greatest key less than or equal to key,
+// or strictly less than key if inclusive is false. Returns nil if there is none.
+func (r *redBlackTreeSet[K]) floorNode(key K, inclusive bool) *rbtNode[K] {
+	var candidate *rbtNode[K]
+
+	for n := r.root; n != nil; {
+		switch {
+		case inclusive && key.Equals(n.key):
+			return n
+		case n.key.LessThan(key):
+			candidate = n
+			n = n.right
+		default:
+			n = n.left
+		}
+	}
+
+	return candidate
+}
+
+// ceilingNode finds the node with the smallest key greater than or equal to key,
+// or strictly greater than key if inclusive is false. Returns nil if there is none.
+func (r *redBlackTreeSet[K]) ceilingNode(key K, inclusive bool) *rbtNode[K] {
+	var candidate *rbtNode[K]
+
+	for n := r.root; n != nil; {
+		switch {
+		case inclusive && key.Equals(n.key):
+			return n
+		case key.LessThan(n.key):
+			candidate = n
+			n = n.left
+		default:
+			n = n.right
+		}
+	}
+
+	return candidate
+}
+
+// keyOf wraps the node's key in an optional, returning None for a nil node.
+func keyOf[K sortable.Sortable[K]](n *rbtNode[K]) optional.Value[K] {
+	if n == nil {
+		return optional.None[K]()
+	}
+
+	return optional.Some(n.key)
+}
+
+// Min returns the smallest element in the set, or None if the set is empty.
+// Time complexity: O(log n).
+func (r *redBlackTreeSet[K]) Min() optional.Value[K] {
+	if r.root == nil {
+		return optional.None[K]()
+	}
+
+	return keyOf(r.getMinimum(r.root))
+}
+
+// Max returns the largest element in the set, or None if the set is empty.
+// Time complexity: O(log n).
+func (r *redBlackTreeSet[K]) Max() optional.Value[K] {
+	if r.root == nil {
+		return optional.None[K]()
+	}
+
+	return keyOf(r.getMaximum(r.root))
+}
+
+// Floor returns the greatest element less than or equal to element.
+// Time complexity: O(log n).
+func (r *redBlackTreeSet[K]) Floor(element K) optional.Value[K] {
+	return keyOf(r.floorNode(element, true))
+}
+
+// Ceiling returns the smallest element greater than or equal to element.
+// Time complexity: O(log n).
+func (r *redBlackTreeSet[K]) Ceiling(element K) optional.Value[K] {
+	return keyOf(r.ceilingNode(element, true))
+}
+
+// Lower returns the greatest element strictly less than element.
+// Time complexity: O(log n).
+func (r *redBlackTreeSet[K]) Lower(element K) optional.Value[K] {
+	return keyOf(r.floorNode(element, false))
+}
+
+// Higher returns the smallest element strictly greater than element.
+// Time complexity: O(log n).
+func (r *redBlackTreeSet[K]) Higher(element K) optional.Value[K] {
+	return keyOf(r.ceilingNode(element, false))
+}
+
+// Range returns an iterator over the elements in [from, to), in ascending order.
+// Time complexity: O(log n + m) where m is the number of elements yielded.
+func (r *redBlackTreeSet[K]) Range(from, to K) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for n := r.ceilingNode(from, true); n != nil && n.key.LessThan(to); n = r.successor(n) {
+			if !yield(n.key) {
+				return
+			}
+		}
+	}
+}
+
+// SeqDescending returns an iterator that yields elements in descending order.
+// Time complexity: O(n) to iterate all elements.
+func (r *redBlackTreeSet[K]) SeqDescending() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		if r.root == nil {
+			return
+		}
+
+		for n := r.getMaximum(r.root); n != nil; n = r.predecessor(n) {
+			if !yield(n.key) {
+				return
+			}
+		}
+	}
+}
+
+// Rank returns the number of elements in the set that are strictly less than element.
+// Time complexity: O(log n).
+func (r *redBlackTreeSet[K]) Rank(element K) int {
+	rank := 0
+
+	for n := r.root; n != nil; {
+		if n.key.LessThan(element) {
+			rank += n.left.Size() + 1
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+
+	return rank
+}
+
+// Select returns the element at the given zero-based index in sorted order,
+// or None if the index is out of range.
+// Time complexity: O(log n).
+func (r *redBlackTreeSet[K]) Select(index int) optional.Value[K] {
+	if index < 0 || index >= r.Size() {
+		return optional.None[K]()
+	}
+
+	n := r.root
+
+	for {
+		leftSize := n.left.Size()
+
+		switch {
+		case index < leftSize:
+			n = n.left
+		case index == leftSize:
+			return keyOf(n)
+		default:
+			index -= leftSize + 1
+			n = n.right
+		}
+	}
+}
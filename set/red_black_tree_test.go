@@ -2,6 +2,8 @@ package set
 
 import (
 	"fmt"
+	"math/rand/v2"
+	"slices"
 	"testing"
 
 	"github.com/amp-labs/amp-common/sortable"
@@ -1209,3 +1211,175 @@ func ExampleNewRedBlackTreeSet() {
 	// 5
 	// 8
 }
+
+// requireRedBlackInvariants fails the test if the tree behind s has a red root,
+// a red node with a red child, unequal black heights, or a stale subtree size.
+func requireRedBlackInvariants(t *testing.T, s SortedSet[sortable.Int]) {
+	t.Helper()
+
+	tree, ok := s.(*redBlackTreeSet[sortable.Int])
+	require.True(t, ok)
+	require.False(t, isRed(tree.root), "root must be black")
+
+	var check func(n *rbtNode[sortable.Int]) int
+
+	check = func(n *rbtNode[sortable.Int]) int {
+		if n == nil {
+			return 1
+		}
+
+		if isRed(n) {
+			require.False(t, isRed(n.left) || isRed(n.right), "red node %v has a red child", n.key)
+		}
+
+		require.Equal(t, n.left.Size()+n.right.Size()+1, n.size, "stale size at %v", n.key)
+
+		leftHeight, rightHeight := check(n.left), check(n.right)
+		require.Equal(t, leftHeight, rightHeight, "unequal black height at %v", n.key)
+
+		if isRed(n) {
+			return leftHeight
+		}
+
+		return leftHeight + 1
+	}
+
+	check(tree.root)
+}
+
+// newSortedTestSet returns a red-black tree set holding 10, 20, ..., 50.
+func newSortedTestSet(t *testing.T) SortedSet[sortable.Int] {
+	t.Helper()
+
+	s := NewRedBlackTreeSet[sortable.Int]()
+	require.NoError(t, s.AddAll(30, 10, 50, 20, 40))
+
+	return s
+}
+
+func TestRedBlackTreeSet_MinMax(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns none for empty set", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewRedBlackTreeSet[sortable.Int]()
+		assert.True(t, s.Min().Empty())
+		assert.True(t, s.Max().Empty())
+	})
+
+	t.Run("returns smallest and largest elements", func(t *testing.T) {
+		t.Parallel()
+
+		s := newSortedTestSet(t)
+		assert.Equal(t, sortable.Int(10), s.Min().GetOrPanic())
+		assert.Equal(t, sortable.Int(50), s.Max().GetOrPanic())
+	})
+}
+
+func TestRedBlackTreeSet_FloorCeiling(t *testing.T) {
+	t.Parallel()
+
+	s := newSortedTestSet(t)
+
+	tests := []struct {
+		element                       sortable.Int
+		floor, ceiling, lower, higher sortable.Int
+	}{
+		{element: 5, floor: -1, ceiling: 10, lower: -1, higher: 10},
+		{element: 10, floor: 10, ceiling: 10, lower: -1, higher: 20},
+		{element: 25, floor: 20, ceiling: 30, lower: 20, higher: 30},
+		{element: 50, floor: 50, ceiling: 50, lower: 40, higher: -1},
+		{element: 55, floor: 50, ceiling: -1, lower: 50, higher: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.element), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.floor, s.Floor(tt.element).GetOrElse(-1), "floor")
+			assert.Equal(t, tt.ceiling, s.Ceiling(tt.element).GetOrElse(-1), "ceiling")
+			assert.Equal(t, tt.lower, s.Lower(tt.element).GetOrElse(-1), "lower")
+			assert.Equal(t, tt.higher, s.Higher(tt.element).GetOrElse(-1), "higher")
+		})
+	}
+}
+
+func TestRedBlackTreeSet_Range(t *testing.T) {
+	t.Parallel()
+
+	s := newSortedTestSet(t)
+
+	assert.Equal(t, []sortable.Int{20, 30, 40}, slices.Collect(s.Range(20, 50)))
+	assert.Equal(t, []sortable.Int{20, 30}, slices.Collect(s.Range(15, 35)))
+	assert.Equal(t, []sortable.Int{10, 20, 30, 40, 50}, slices.Collect(s.Range(0, 100)))
+	assert.Empty(t, slices.Collect(s.Range(31, 39)))
+	assert.Empty(t, slices.Collect(s.Range(40, 20)))
+}
+
+func TestRedBlackTreeSet_SeqDescending(t *testing.T) {
+	t.Parallel()
+
+	s := newSortedTestSet(t)
+	assert.Equal(t, []sortable.Int{50, 40, 30, 20, 10}, slices.Collect(s.SeqDescending()))
+
+	empty := NewRedBlackTreeSet[sortable.Int]()
+	assert.Empty(t, slices.Collect(empty.SeqDescending()))
+}
+
+func TestRedBlackTreeSet_RankSelect(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ranks and selects by position", func(t *testing.T) {
+		t.Parallel()
+
+		s := newSortedTestSet(t)
+
+		assert.Equal(t, 0, s.Rank(5))
+		assert.Equal(t, 0, s.Rank(10))
+		assert.Equal(t, 2, s.Rank(25))
+		assert.Equal(t, 5, s.Rank(99))
+
+		assert.Equal(t, sortable.Int(30), s.Select(2).GetOrPanic())
+		assert.True(t, s.Select(-1).Empty())
+		assert.True(t, s.Select(5).Empty())
+	})
+
+	t.Run("stays consistent under random adds and removes", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewRedBlackTreeSet[sortable.Int]()
+		rng := rand.New(rand.NewPCG(3, 4)) //nolint:gosec // Deterministic test data
+
+		var expected []sortable.Int
+
+		for range 2000 {
+			element := sortable.Int(rng.IntN(300))
+
+			idx, present := slices.BinarySearch(expected, element)
+			if rng.IntN(3) == 0 {
+				require.NoError(t, s.Remove(element))
+
+				if present {
+					expected = slices.Delete(expected, idx, idx+1)
+				}
+			} else {
+				require.NoError(t, s.Add(element))
+
+				if !present {
+					expected = slices.Insert(expected, idx, element)
+				}
+			}
+
+			require.Equal(t, len(expected), s.Size())
+			requireRedBlackInvariants(t, s)
+		}
+
+		assert.Equal(t, expected, s.Entries())
+
+		for i, element := range expected {
+			assert.Equal(t, element, s.Select(i).GetOrPanic())
+			assert.Equal(t, i, s.Rank(element))
+		}
+	})
+}
@@ -3,29 +3,30 @@ package set
 
 import (
 	"iter"
+	"slices"
 	"sort"
 
 	"facette.io/natsort"
 	"github.com/amp-labs/amp-common/collectable"
 	"github.com/amp-labs/amp-common/compare"
-	errors2 "github.com/amp-labs/amp-common/errors"
 	"github.com/amp-labs/amp-common/hashing"
+	"github.com/amp-labs/amp-common/optional"
 )
 
 // A Set is a collection of unique elements. Uniqueness is
 // determined by the HashFunc provided when the Set is created,
 // as well as how the object has implemented the Hashable and
-// Comparable interfaces. If a collision is detected, an error
-// is returned.
+// Comparable interfaces. Elements whose hashes collide are told
+// apart with Equals, so errors are only returned if hashing fails.
 //
 //nolint:interfacebloat // Set requires these methods for complete functionality
 type Set[T any] interface {
-	// AddAll adds multiple elements to the set. Returns an error if any element
-	// causes a hash collision or if hashing fails.
+	// AddAll adds multiple elements to the set. Returns an error if hashing
+	// any element fails.
 	AddAll(elements ...T) error
 
-	// Add adds a single element to the set. Returns an error if the element
-	// causes a hash collision or if hashing fails. If the element already exists
+	// Add adds a single element to the set. Returns an error if hashing
+	// the element fails. If the element already exists
 	// in the set, no error is returned.
 	Add(element T) error
 
@@ -37,7 +38,7 @@ type Set[T any] interface {
 	Clear()
 
 	// Contains checks if an element exists in the set. Returns true if the element
-	// exists, false otherwise. Returns an error if hashing fails or a collision is detected.
+	// exists, false otherwise. Returns an error if hashing fails.
 	Contains(element T) (bool, error)
 
 	// Size returns the number of elements in the set.
@@ -50,13 +51,37 @@ type Set[T any] interface {
 	Seq() iter.Seq[T]
 
 	// Union returns a new set containing all elements from both sets. Returns an error
-	// if any element causes a hash collision or if hashing fails.
+	// if hashing any element fails.
 	Union(other Set[T]) (Set[T], error)
 
 	// Intersection returns a new set containing only elements present in both sets.
-	// Returns an error if any element causes a hash collision or if hashing fails.
+	// Returns an error if hashing any element fails.
 	Intersection(other Set[T]) (Set[T], error)
 
+	// Difference returns a new set containing the elements of this set that are
+	// not in other. Returns an error if hashing any element fails.
+	Difference(other Set[T]) (Set[T], error)
+
+	// SymmetricDifference returns a new set containing the elements that are in
+	// exactly one of the two sets. Returns an error if hashing any element fails.
+	SymmetricDifference(other Set[T]) (Set[T], error)
+
+	// IsSubsetOf reports whether every element of this set is also in other.
+	// Returns an error if hashing any element fails.
+	IsSubsetOf(other Set[T]) (bool, error)
+
+	// IsSupersetOf reports whether every element of other is also in this set.
+	// Returns an error if hashing any element fails.
+	IsSupersetOf(other Set[T]) (bool, error)
+
+	// IsDisjoint reports whether the two sets have no elements in common.
+	// Returns an error if hashing any element fails.
+	IsDisjoint(other Set[T]) (bool, error)
+
+	// Equals reports whether both sets contain the same elements.
+	// Returns an error if hashing any element fails.
+	Equals(other Set[T]) (bool, error)
+
 	// HashFunction returns the hash function used by this set.
 	HashFunction() hashing.HashFunc
 
@@ -74,17 +99,94 @@ type Set[T any] interface {
 	Clone() Set[T]
 }
 
+// A SortedSet is a Set whose elements are kept in sorted order. In addition
+// to the Set operations, it supports ordered queries: the smallest and largest
+// elements, the nearest elements around a given value, half-open ranges,
+// descending iteration, and lookups by position in sorted order. Seq and
+// Entries return elements in ascending order.
+type SortedSet[T any] interface {
+	Set[T]
+
+	// Min returns the smallest element, or None if the set is empty.
+	Min() optional.Value[T]
+
+	// Max returns the largest element, or None if the set is empty.
+	Max() optional.Value[T]
+
+	// Floor returns the greatest element less than or equal to element, or None if there is none.
+	Floor(element T) optional.Value[T]
+
+	// Ceiling returns the smallest element greater than or equal to element, or None if there is none.
+	Ceiling(element T) optional.Value[T]
+
+	// Lower returns the greatest element strictly less than element, or None if there is none.
+	Lower(element T) optional.Value[T]
+
+	// Higher returns the smallest element strictly greater than element, or None if there is none.
+	Higher(element T) optional.Value[T]
+
+	// Range returns an iterator over the elements greater than or equal to from
+	// and strictly less than to, in ascending order.
+	Range(from, to T) iter.Seq[T]
+
+	// SeqDescending returns an iterator over all elements in descending order.
+	SeqDescending() iter.Seq[T]
+
+	// Rank returns the number of elements strictly less than element. If element
+	// is in the set, this is its zero-based index in sorted order.
+	Rank(element T) int
+
+	// Select returns the element at the given zero-based index in sorted order,
+	// or None if the index is out of range.
+	Select(index int) optional.Value[T]
+}
+
+// A PersistentSet is a Set whose versions share structure. With and Without
+// return a new version and leave the receiver unchanged, and Clone is O(1).
+// Add, Remove and Clear move the receiver itself to a new version; versions
+// obtained earlier through Clone, With, Without, Union, Intersection or Filter
+// are not affected. A version that is no longer modified can be read by any
+// number of goroutines without locks.
+type PersistentSet[T any] interface {
+	Set[T]
+
+	// With returns a version of the set that also contains element.
+	// Returns an error if hashing the element fails.
+	With(element T) (PersistentSet[T], error)
+
+	// Without returns a version of the set that does not contain element.
+	// Returns an error if hashing the element fails.
+	Without(element T) (PersistentSet[T], error)
+}
+
+// A PersistentSortedSet is a SortedSet whose versions share structure, with
+// the same versioning rules as PersistentSet.
+type PersistentSortedSet[T any] interface {
+	SortedSet[T]
+
+	// With returns a version of the set that also contains element.
+	With(element T) (PersistentSortedSet[T], error)
+
+	// Without returns a version of the set that does not contain element.
+	Without(element T) (PersistentSortedSet[T], error)
+}
+
+// setImpl stores elements in buckets keyed by their hash. Elements that share a
+// hash value are kept in the same bucket and told apart with Equals.
 type setImpl[T collectable.Collectable[T]] struct {
 	hash     hashing.HashFunc
-	elements map[string]T
+	elements map[string][]T
+	size     int
 }
 
 // NewSet creates a new Set with the provided hash function.
-// The hash function is used to determine uniqueness of elements.
+// The hash function is used to determine uniqueness of elements. Distinct
+// elements with the same hash value are disambiguated using Equals, so fast
+// non-cryptographic hashes such as hashing.XxHash32 are safe to use.
 func NewSet[T collectable.Collectable[T]](hash hashing.HashFunc, items ...T) Set[T] {
 	s := &setImpl[T]{
 		hash:     hash,
-		elements: make(map[string]T),
+		elements: make(map[string][]T),
 	}
 
 	if len(items) > 0 {
@@ -100,7 +202,7 @@ func NewSet[T collectable.Collectable[T]](hash hashing.HashFunc, items ...T) Set
 func NewSetWithSize[T collectable.Collectable[T]](hash hashing.HashFunc, size int, items ...T) Set[T] {
 	s := &setImpl[T]{
 		hash:     hash,
-		elements: make(map[string]T, size),
+		elements: make(map[string][]T, size),
 	}
 
 	if len(items) > 0 {
@@ -121,28 +223,37 @@ func (s *setImpl[T]) AddAll(element ...T) error {
 	return nil
 }
 
+// indexOf returns the position of element in bucket, or -1 if it isn't there.
+func indexOf[T collectable.Collectable[T]](bucket []T, element T) int {
+	for i, item := range bucket {
+		if compare.Equals(item, element) {
+			return i
+		}
+	}
+
+	return -1
+}
+
 func (s *setImpl[T]) Add(element T) error {
 	hashVal, err := s.hash(element)
 	if err != nil {
 		return err
 	}
 
-	prev, ok := s.elements[hashVal]
-	if ok {
-		if compare.Equals(prev, element) {
-			return nil
-		} else {
-			return errors2.ErrHashCollision
-		}
+	bucket := s.elements[hashVal]
+	if indexOf(bucket, element) >= 0 {
+		return nil
 	}
 
-	s.elements[hashVal] = element
+	s.elements[hashVal] = append(bucket, element)
+	s.size++
 
 	return nil
 }
 
 func (s *setImpl[T]) Clear() {
-	s.elements = make(map[string]T)
+	s.elements = make(map[string][]T)
+	s.size = 0
 }
 
 func (s *setImpl[T]) Remove(element T) error {
@@ -151,15 +262,21 @@ func (s *setImpl[T]) Remove(element T) error {
 		return err
 	}
 
-	prev, ok := s.elements[hashVal]
-	if ok {
-		if compare.Equals(prev, element) {
-			delete(s.elements, hashVal)
+	bucket := s.elements[hashVal]
 
-			return nil
-		}
+	i := indexOf(bucket, element)
+	if i < 0 {
+		return nil
+	}
+
+	if len(bucket) == 1 {
+		delete(s.elements, hashVal)
+	} else {
+		s.elements[hashVal] = slices.Delete(slices.Clone(bucket), i, i+1)
 	}
 
+	s.size--
+
 	return nil
 }
 
@@ -169,26 +286,17 @@ func (s *setImpl[T]) Contains(element T) (bool, error) {
 		return false, err
 	}
 
-	prev, ok := s.elements[hashVal]
-	if ok {
-		if compare.Equals(prev, element) {
-			return true, nil
-		} else {
-			return true, errors2.ErrHashCollision
-		}
-	}
-
-	return false, nil
+	return indexOf(s.elements[hashVal], element) >= 0, nil
 }
 
 func (s *setImpl[T]) Size() int {
-	return len(s.elements)
+	return s.size
 }
 
 func (s *setImpl[T]) Entries() []T {
-	items := make([]T, 0, len(s.elements))
-	for _, item := range s.elements {
-		items = append(items, item)
+	items := make([]T, 0, s.size)
+	for _, bucket := range s.elements {
+		items = append(items, bucket...)
 	}
 
 	return items
@@ -196,9 +304,11 @@ func (s *setImpl[T]) Entries() []T {
 
 func (s *setImpl[T]) Seq() iter.Seq[T] {
 	return func(yield func(T) bool) {
-		for _, item := range s.elements {
-			if !yield(item) {
-				return
+		for _, bucket := range s.elements {
+			for _, item := range bucket {
+				if !yield(item) {
+					return
+				}
 			}
 		}
 	}
@@ -240,6 +350,49 @@ func (s *setImpl[T]) Intersection(other Set[T]) (Set[T], error) {
 	return ns, nil
 }
 
+func (s *setImpl[T]) Difference(other Set[T]) (Set[T], error) {
+	ns := NewSet[T](s.hash)
+
+	err := addMissing(s.Seq(), other, ns.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns, nil
+}
+
+func (s *setImpl[T]) SymmetricDifference(other Set[T]) (Set[T], error) {
+	ns := NewSet[T](s.hash)
+
+	err := addMissing(s.Seq(), other, ns.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	err = addMissing(other.Seq(), s, ns.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns, nil
+}
+
+func (s *setImpl[T]) IsSubsetOf(other Set[T]) (bool, error) {
+	return isSubset(s.Size(), s.Seq(), other)
+}
+
+func (s *setImpl[T]) IsSupersetOf(other Set[T]) (bool, error) {
+	return isSubset(other.Size(), other.Seq(), s)
+}
+
+func (s *setImpl[T]) IsDisjoint(other Set[T]) (bool, error) {
+	return isDisjoint(s.Seq(), other)
+}
+
+func (s *setImpl[T]) Equals(other Set[T]) (bool, error) {
+	return isEqual(s.Size(), s.Seq(), other)
+}
+
 // HashFunction returns the hash function used by this set.
 func (s *setImpl[T]) HashFunction() hashing.HashFunc {
 	return s.hash
@@ -255,7 +408,7 @@ func (s *setImpl[T]) Clone() Set[T] {
 
 	cloned := NewSet[T](s.hash)
 
-	for _, v := range s.elements {
+	for v := range s.Seq() {
 		_ = cloned.Add(v)
 	}
 
@@ -424,6 +577,52 @@ func (s *StringSet) Intersection(other *StringSet) (*StringSet, error) {
 	}, nil
 }
 
+// Difference returns a new StringSet containing the elements of this set that are not in other.
+func (s *StringSet) Difference(other *StringSet) (*StringSet, error) {
+	ns, err := s.set.Difference(other.set)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StringSet{
+		hash: s.hash,
+		set:  ns,
+	}, nil
+}
+
+// SymmetricDifference returns a new StringSet containing the elements that are in exactly one of the two sets.
+func (s *StringSet) SymmetricDifference(other *StringSet) (*StringSet, error) {
+	ns, err := s.set.SymmetricDifference(other.set)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StringSet{
+		hash: s.hash,
+		set:  ns,
+	}, nil
+}
+
+// IsSubsetOf reports whether every element of this set is also in other.
+func (s *StringSet) IsSubsetOf(other *StringSet) (bool, error) {
+	return s.set.IsSubsetOf(other.set)
+}
+
+// IsSupersetOf reports whether every element of other is also in this set.
+func (s *StringSet) IsSupersetOf(other *StringSet) (bool, error) {
+	return s.set.IsSupersetOf(other.set)
+}
+
+// IsDisjoint reports whether the two sets have no elements in common.
+func (s *StringSet) IsDisjoint(other *StringSet) (bool, error) {
+	return s.set.IsDisjoint(other.set)
+}
+
+// Equals reports whether both sets contain the same elements.
+func (s *StringSet) Equals(other *StringSet) (bool, error) {
+	return s.set.Equals(other.set)
+}
+
 // Clone creates a shallow copy of the StringSet, duplicating its structure and entries.
 // The strings themselves are not deep-copied (strings in Go are immutable).
 // Returns a new StringSet instance with the same entries.
@@ -447,13 +646,13 @@ func (s *StringSet) Clone() *StringSet {
 //
 //nolint:interfacebloat // OrderedSet requires these methods for complete functionality
 type OrderedSet[T any] interface {
-	// AddAll adds multiple elements to the set in order. Returns an error if any element
-	// causes a hash collision or if hashing fails. If an element already exists, it is not
+	// AddAll adds multiple elements to the set in order. Returns an error if hashing
+	// any element fails. If an element already exists, it is not
 	// added again and its position in the order is not changed.
 	AddAll(elements ...T) error
 
-	// Add adds a single element to the set. Returns an error if the element
-	// causes a hash collision or if hashing fails. If the element already exists
+	// Add adds a single element to the set. Returns an error if hashing
+	// the element fails. If the element already exists
 	// in the set, no error is returned and its position in the order is not changed.
 	Add(element T) error
 
@@ -465,7 +664,7 @@ type OrderedSet[T any] interface {
 	Clear()
 
 	// Contains checks if an element exists in the set. Returns true if the element
-	// exists, false otherwise. Returns an error if hashing fails or a collision is detected.
+	// exists, false otherwise. Returns an error if hashing fails.
 	Contains(element T) (bool, error)
 
 	// Size returns the number of elements in the set.
@@ -481,14 +680,40 @@ type OrderedSet[T any] interface {
 	// Union returns a new ordered set containing all elements from both sets.
 	// Elements from the current set appear first in insertion order, followed by
 	// elements from the other set that are not already present. Returns an error
-	// if any element causes a hash collision or if hashing fails.
+	// if hashing any element fails.
 	Union(other OrderedSet[T]) (OrderedSet[T], error)
 
 	// Intersection returns a new ordered set containing only elements present in both sets.
-	// The order is preserved from the current set. Returns an error if any element causes
-	// a hash collision or if hashing fails.
+	// The order is preserved from the current set. Returns an error if hashing any
+	// element fails.
 	Intersection(other OrderedSet[T]) (OrderedSet[T], error)
 
+	// Difference returns a new ordered set containing the elements of this set
+	// that are not in other, in this set's order. Returns an error if hashing any
+	// element fails.
+	Difference(other OrderedSet[T]) (OrderedSet[T], error)
+
+	// SymmetricDifference returns a new ordered set containing the elements that
+	// are in exactly one of the two sets: first those of this set, in its order,
+	// then those of other, in its order. Returns an error if hashing any element fails.
+	SymmetricDifference(other OrderedSet[T]) (OrderedSet[T], error)
+
+	// IsSubsetOf reports whether every element of this set is also in other.
+	// Order is ignored. Returns an error if hashing any element fails.
+	IsSubsetOf(other OrderedSet[T]) (bool, error)
+
+	// IsSupersetOf reports whether every element of other is also in this set.
+	// Order is ignored. Returns an error if hashing any element fails.
+	IsSupersetOf(other OrderedSet[T]) (bool, error)
+
+	// IsDisjoint reports whether the two sets have no elements in common.
+	// Returns an error if hashing any element fails.
+	IsDisjoint(other OrderedSet[T]) (bool, error)
+
+	// Equals reports whether both sets contain the same elements, in any order.
+	// Returns an error if hashing any element fails.
+	Equals(other OrderedSet[T]) (bool, error)
+
 	// HashFunction returns the hash function used by this ordered set.
 	HashFunction() hashing.HashFunc
 
@@ -508,6 +733,21 @@ type OrderedSet[T any] interface {
 	Clone() OrderedSet[T]
 }
 
+// A PersistentOrderedSet is an OrderedSet whose versions share structure,
+// with the same versioning rules as PersistentSet.
+type PersistentOrderedSet[T any] interface {
+	OrderedSet[T]
+
+	// With returns a version of the set that also contains element, appended
+	// to the insertion order if it is new.
+	// Returns an error if hashing the element fails.
+	With(element T) (PersistentOrderedSet[T], error)
+
+	// Without returns a version of the set that does not contain element.
+	// Returns an error if hashing the element fails.
+	Without(element T) (PersistentOrderedSet[T], error)
+}
+
 type orderedSetImpl[T collectable.Collectable[T]] struct {
 	hash  hashing.HashFunc
 	set   Set[T]
@@ -660,6 +900,49 @@ func (s *orderedSetImpl[T]) Intersection(other OrderedSet[T]) (OrderedSet[T], er
 	return ns, nil
 }
 
+func (s *orderedSetImpl[T]) Difference(other OrderedSet[T]) (OrderedSet[T], error) {
+	ns := NewOrderedSet[T](s.hash)
+
+	err := addMissing(slices.Values(s.order), other, ns.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns, nil
+}
+
+func (s *orderedSetImpl[T]) SymmetricDifference(other OrderedSet[T]) (OrderedSet[T], error) {
+	ns := NewOrderedSet[T](s.hash)
+
+	err := addMissing(slices.Values(s.order), other, ns.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	err = addMissing(elementsOf(other), s, ns.Add)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns, nil
+}
+
+func (s *orderedSetImpl[T]) IsSubsetOf(other OrderedSet[T]) (bool, error) {
+	return isSubset(s.Size(), slices.Values(s.order), other)
+}
+
+func (s *orderedSetImpl[T]) IsSupersetOf(other OrderedSet[T]) (bool, error) {
+	return isSubset(other.Size(), elementsOf(other), s)
+}
+
+func (s *orderedSetImpl[T]) IsDisjoint(other OrderedSet[T]) (bool, error) {
+	return isDisjoint(slices.Values(s.order), other)
+}
+
+func (s *orderedSetImpl[T]) Equals(other OrderedSet[T]) (bool, error) {
+	return isEqual(s.Size(), slices.Values(s.order), other)
+}
+
 // HashFunction returns the hash function used by this ordered set.
 func (s *orderedSetImpl[T]) HashFunction() hashing.HashFunc {
 	return s.hash
@@ -826,6 +1109,52 @@ func (s *StringOrderedSet) Intersection(other *StringOrderedSet) (*StringOrdered
 	}, nil
 }
 
+// Difference returns a new StringOrderedSet containing the elements of this set that are not in other.
+func (s *StringOrderedSet) Difference(other *StringOrderedSet) (*StringOrderedSet, error) {
+	ns, err := s.set.Difference(other.set)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StringOrderedSet{
+		hash: s.hash,
+		set:  ns,
+	}, nil
+}
+
+// SymmetricDifference returns a new StringOrderedSet containing the elements that are in exactly one of the two sets.
+func (s *StringOrderedSet) SymmetricDifference(other *StringOrderedSet) (*StringOrderedSet, error) {
+	ns, err := s.set.SymmetricDifference(other.set)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StringOrderedSet{
+		hash: s.hash,
+		set:  ns,
+	}, nil
+}
+
+// IsSubsetOf reports whether every element of this set is also in other.
+func (s *StringOrderedSet) IsSubsetOf(other *StringOrderedSet) (bool, error) {
+	return s.set.IsSubsetOf(other.set)
+}
+
+// IsSupersetOf reports whether every element of other is also in this set.
+func (s *StringOrderedSet) IsSupersetOf(other *StringOrderedSet) (bool, error) {
+	return s.set.IsSupersetOf(other.set)
+}
+
+// IsDisjoint reports whether the two sets have no elements in common.
+func (s *StringOrderedSet) IsDisjoint(other *StringOrderedSet) (bool, error) {
+	return s.set.IsDisjoint(other.set)
+}
+
+// Equals reports whether both sets contain the same elements.
+func (s *StringOrderedSet) Equals(other *StringOrderedSet) (bool, error) {
+	return s.set.Equals(other.set)
+}
+
 // HashFunction returns the hash function used by this set.
 func (s *StringOrderedSet) HashFunction() hashing.HashFunc {
 	return s.hash
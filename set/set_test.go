@@ -1,6 +1,7 @@
 package set
 
 import (
+	"hash"
 	"testing"
 
 	"github.com/amp-labs/amp-common/hashing"
@@ -8,6 +9,23 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// collidingElement is an element type whose hash is chosen by the test,
+// so distinct elements can be made to collide.
+type collidingElement struct {
+	id   int
+	hash string
+}
+
+func (e collidingElement) UpdateHash(h hash.Hash) error {
+	_, err := h.Write([]byte(e.hash))
+
+	return err
+}
+
+func (e collidingElement) Equals(other collidingElement) bool {
+	return e.id == other.id
+}
+
 // TestSet tests the generic Set implementation.
 func TestSet(t *testing.T) {
 	t.Parallel()
@@ -44,6 +62,34 @@ func TestSet(t *testing.T) {
 		assert.Equal(t, 1, s.Size())
 	})
 
+	t.Run("Add colliding elements", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewSet[collidingElement](hashing.XxHash32)
+		elem1 := collidingElement{id: 1, hash: "same"}
+		elem2 := collidingElement{id: 2, hash: "same"}
+		elem3 := collidingElement{id: 3, hash: "same"}
+
+		require.NoError(t, s.AddAll(elem1, elem2))
+		assert.Equal(t, 2, s.Size())
+		assert.ElementsMatch(t, []collidingElement{elem1, elem2}, s.Entries())
+
+		contains, err := s.Contains(elem2)
+		require.NoError(t, err)
+		assert.True(t, contains)
+
+		contains, err = s.Contains(elem3)
+		require.NoError(t, err)
+		assert.False(t, contains)
+
+		require.NoError(t, s.Remove(elem1))
+		assert.Equal(t, 1, s.Size())
+
+		contains, err = s.Contains(elem2)
+		require.NoError(t, err)
+		assert.True(t, contains)
+	})
+
 	t.Run("AddAll", func(t *testing.T) {
 		t.Parallel()
 
@@ -170,6 +170,71 @@ func (t *threadSafeSet[T]) Intersection(other Set[T]) (Set[T], error) {
 	return NewThreadSafeSet(value), nil
 }
 
+// Difference creates a new thread-safe set containing the elements of this set that are not in other.
+// Acquires a read lock on this set during the operation. The returned set is also thread-safe.
+func (t *threadSafeSet[T]) Difference(other Set[T]) (Set[T], error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	value, err := t.internal.Difference(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewThreadSafeSet(value), nil
+}
+
+// SymmetricDifference creates a new thread-safe set containing the elements that are in exactly
+// one of the two sets. Acquires a read lock on this set during the operation. The returned set is
+// also thread-safe.
+func (t *threadSafeSet[T]) SymmetricDifference(other Set[T]) (Set[T], error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	value, err := t.internal.SymmetricDifference(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewThreadSafeSet(value), nil
+}
+
+// IsSubsetOf reports whether every element of this set is also in other.
+// Acquires a read lock on this set during the operation.
+func (t *threadSafeSet[T]) IsSubsetOf(other Set[T]) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.IsSubsetOf(other)
+}
+
+// IsSupersetOf reports whether every element of other is also in this set.
+// Acquires a read lock on this set during the operation.
+func (t *threadSafeSet[T]) IsSupersetOf(other Set[T]) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.IsSupersetOf(other)
+}
+
+// IsDisjoint reports whether the two sets have no elements in common.
+// Acquires a read lock on this set during the operation.
+func (t *threadSafeSet[T]) IsDisjoint(other Set[T]) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.IsDisjoint(other)
+}
+
+// Equals reports whether both sets contain the same elements.
+// Acquires a read lock on this set during the operation.
+func (t *threadSafeSet[T]) Equals(other Set[T]) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Equals(other)
+}
+
 // HashFunction returns the hash function used by the underlying set.
 // Acquires a read lock to safely access the internal set's hash function.
 func (t *threadSafeSet[T]) HashFunction() hashing.HashFunc {
@@ -351,6 +416,71 @@ func (t *threadSafeOrderedSet[T]) Intersection(other OrderedSet[T]) (OrderedSet[
 	return NewThreadSafeOrderedSet(value), nil
 }
 
+// Difference creates a new thread-safe ordered set containing the elements of this set that are not in other.
+// Acquires a read lock on this set during the operation. The returned set is also thread-safe.
+func (t *threadSafeOrderedSet[T]) Difference(other OrderedSet[T]) (OrderedSet[T], error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	value, err := t.internal.Difference(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewThreadSafeOrderedSet(value), nil
+}
+
+// SymmetricDifference creates a new thread-safe ordered set containing the elements that are in exactly
+// one of the two sets. Acquires a read lock on this set during the operation. The returned set is
+// also thread-safe.
+func (t *threadSafeOrderedSet[T]) SymmetricDifference(other OrderedSet[T]) (OrderedSet[T], error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	value, err := t.internal.SymmetricDifference(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewThreadSafeOrderedSet(value), nil
+}
+
+// IsSubsetOf reports whether every element of this set is also in other.
+// Acquires a read lock on this set during the operation.
+func (t *threadSafeOrderedSet[T]) IsSubsetOf(other OrderedSet[T]) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.IsSubsetOf(other)
+}
+
+// IsSupersetOf reports whether every element of other is also in this set.
+// Acquires a read lock on this set during the operation.
+func (t *threadSafeOrderedSet[T]) IsSupersetOf(other OrderedSet[T]) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.IsSupersetOf(other)
+}
+
+// IsDisjoint reports whether the two sets have no elements in common.
+// Acquires a read lock on this set during the operation.
+func (t *threadSafeOrderedSet[T]) IsDisjoint(other OrderedSet[T]) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.IsDisjoint(other)
+}
+
+// Equals reports whether both sets contain the same elements.
+// Acquires a read lock on this set during the operation.
+func (t *threadSafeOrderedSet[T]) Equals(other OrderedSet[T]) (bool, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.internal.Equals(other)
+}
+
 // HashFunction returns the hash function used by the underlying ordered set.
 // Acquires a read lock to safely access the internal ordered set's hash function.
 func (t *threadSafeOrderedSet[T]) HashFunction() hashing.HashFunc {
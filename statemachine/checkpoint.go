@@ -0,0 +1,379 @@
+package statemachine
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	checkpointDirPerm  = 0o750
+	checkpointFilePerm = 0o600
+	checkpointFileExt  = ".json"
+)
+
+// Checkpoint is a serializable snapshot of a Context, written by the engine
+// after every state transition so that an execution can be resumed later.
+// CurrentState is the next state to execute, or the wait state the execution
+// is suspended in if Suspended is set.
+//
+// Version counts the checkpoints saved for an execution. Stores use it to
+// reject a save that doesn't follow the stored checkpoint (see
+// CheckpointStore.Save), so that two callers resuming the same execution
+// can't both advance it.
+type Checkpoint struct {
+	SessionID      string            `json:"sessionId"`
+	ProjectID      string            `json:"projectId"`
+	CurrentState   string            `json:"currentState"`
+	Data           map[string]any    `json:"data"`
+	History        []StateTransition `json:"history"`
+	PathHistory    []string          `json:"pathHistory"`
+	Metadata       map[string]any    `json:"metadata"`
+	Provider       string            `json:"provider"`
+	ContextChunkID string            `json:"contextChunkId"`
+	ToolName       string            `json:"toolName"`
+	CreatedAt      time.Time         `json:"createdAt"`
+	UpdatedAt      time.Time         `json:"updatedAt"`
+	Completed      bool              `json:"completed"`
+	Suspended      *Suspended        `json:"suspended,omitempty"`
+	SavedAt        time.Time         `json:"savedAt"`
+	Version        int64             `json:"version"`
+}
+
+// CheckpointStore persists execution checkpoints keyed by session ID.
+// Implementations must be safe for concurrent use.
+type CheckpointStore interface {
+	// Save stores the checkpoint, replacing any previous checkpoint for the
+	// session. A checkpoint with a Version above 1 continues an execution and
+	// must only replace the checkpoint it follows: if a checkpoint is stored
+	// for the session and its Version isn't checkpoint.Version-1, Save fails
+	// with ErrCheckpointConflict. The check and the write must be atomic.
+	Save(ctx context.Context, checkpoint *Checkpoint) error
+	// Load returns the latest checkpoint for the session, or ErrCheckpointNotFound.
+	Load(ctx context.Context, sessionID string) (*Checkpoint, error)
+	// Delete removes the checkpoint for the session. Deleting a missing checkpoint is not an error.
+	Delete(ctx context.Context, sessionID string) error
+}
+
+// SuspendedLister is implemented by checkpoint stores that can enumerate the
+// executions currently suspended in a wait state. Engine.ResumeExpired needs
+// it to find waits whose timeout has passed.
+type SuspendedLister interface {
+	// ListSuspended returns the suspension of every stored, uncompleted execution
+	// that is waiting for an event.
+	ListSuspended(ctx context.Context) ([]*Suspended, error)
+}
+
+// Checkpoint captures the current state of the context.
+func (c *Context) Checkpoint() *Checkpoint {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	checkpoint := &Checkpoint{
+		SessionID:      c.SessionID,
+		ProjectID:      c.ProjectID,
+		CurrentState:   c.CurrentState,
+		Data:           make(map[string]any, len(c.Data)),
+		History:        make([]StateTransition, len(c.History)),
+		PathHistory:    make([]string, len(c.PathHistory)),
+		Metadata:       make(map[string]any, len(c.Metadata)),
+		Provider:       c.Provider,
+		ContextChunkID: c.ContextChunkID,
+		ToolName:       c.ToolName,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+		SavedAt:        time.Now(),
+		Version:        c.checkpointVersion,
+	}
+
+	maps.Copy(checkpoint.Data, c.Data)
+	maps.Copy(checkpoint.Metadata, c.Metadata)
+	copy(checkpoint.History, c.History)
+	copy(checkpoint.PathHistory, c.PathHistory)
+
+	return checkpoint
+}
+
+// Context rebuilds a state machine context from the checkpoint.
+func (cp *Checkpoint) Context() *Context {
+	smCtx := &Context{
+		SessionID:      cp.SessionID,
+		ProjectID:      cp.ProjectID,
+		CurrentState:   cp.CurrentState,
+		Data:           make(map[string]any, len(cp.Data)),
+		History:        make([]StateTransition, len(cp.History)),
+		Metadata:       make(map[string]any, len(cp.Metadata)),
+		CreatedAt:      cp.CreatedAt,
+		UpdatedAt:      cp.UpdatedAt,
+		Provider:       cp.Provider,
+		ContextChunkID: cp.ContextChunkID,
+		ToolName:       cp.ToolName,
+		PathHistory:    make([]string, len(cp.PathHistory)),
+
+		checkpointVersion: cp.Version,
+	}
+
+	maps.Copy(smCtx.Data, cp.Data)
+	maps.Copy(smCtx.Metadata, cp.Metadata)
+	copy(smCtx.History, cp.History)
+	copy(smCtx.PathHistory, cp.PathHistory)
+
+	return smCtx
+}
+
+// clone returns a copy of the checkpoint that shares no top-level maps or slices.
+func (cp *Checkpoint) clone() *Checkpoint {
+	clone := cp.Context().Checkpoint()
+	clone.Completed = cp.Completed
+	clone.SavedAt = cp.SavedAt
+
+	if cp.Suspended != nil {
+		suspended := *cp.Suspended
+		clone.Suspended = &suspended
+	}
+
+	return clone
+}
+
+// checkVersion reports whether checkpoint may replace stored, which is nil if
+// no checkpoint is stored for the session (see CheckpointStore.Save).
+func checkVersion(stored, checkpoint *Checkpoint) error {
+	if checkpoint.Version <= 1 || stored == nil || stored.Version == checkpoint.Version-1 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: session %s is at version %d, cannot save version %d",
+		ErrCheckpointConflict, checkpoint.SessionID, stored.Version, checkpoint.Version)
+}
+
+// MemoryCheckpointStore keeps checkpoints in memory. It survives engine
+// restarts within a process but not process restarts; it is mostly useful for
+// tests and for suspending executions in long-running servers.
+type MemoryCheckpointStore struct {
+	mu          sync.RWMutex
+	checkpoints map[string]*Checkpoint
+}
+
+// NewMemoryCheckpointStore creates an empty in-memory checkpoint store.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{
+		checkpoints: make(map[string]*Checkpoint),
+	}
+}
+
+func (s *MemoryCheckpointStore) Save(_ context.Context, checkpoint *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := checkVersion(s.checkpoints[checkpoint.SessionID], checkpoint)
+	if err != nil {
+		return err
+	}
+
+	s.checkpoints[checkpoint.SessionID] = checkpoint.clone()
+
+	return nil
+}
+
+func (s *MemoryCheckpointStore) Load(_ context.Context, sessionID string) (*Checkpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	checkpoint, ok := s.checkpoints[sessionID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCheckpointNotFound, sessionID)
+	}
+
+	// Hand out a copy so callers can't mutate the stored checkpoint
+	return checkpoint.clone(), nil
+}
+
+func (s *MemoryCheckpointStore) Delete(_ context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.checkpoints, sessionID)
+
+	return nil
+}
+
+func (s *MemoryCheckpointStore) ListSuspended(_ context.Context) ([]*Suspended, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var suspended []*Suspended
+
+	for _, checkpoint := range s.checkpoints {
+		if checkpoint.Completed || checkpoint.Suspended == nil {
+			continue
+		}
+
+		clone := *checkpoint.Suspended
+		suspended = append(suspended, &clone)
+	}
+
+	return suspended, nil
+}
+
+// FileCheckpointStore writes each checkpoint as a JSON file in a directory.
+// Writes go to a temporary file that is renamed into place, so a crash never
+// leaves a partially written checkpoint behind.
+//
+// Values in Context.Data round-trip through JSON, so numbers are restored as
+// float64 and structs as map[string]any.
+//
+// Saves are serialized within the store, which makes the version check of
+// CheckpointStore.Save atomic for a single process. Processes sharing a
+// directory are not protected from each other.
+type FileCheckpointStore struct {
+	dir string
+
+	mu sync.Mutex
+}
+
+// NewFileCheckpointStore creates a file-backed checkpoint store rooted at dir,
+// creating the directory if necessary.
+func NewFileCheckpointStore(dir string) (*FileCheckpointStore, error) {
+	err := os.MkdirAll(dir, checkpointDirPerm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint directory %q: %w", dir, err)
+	}
+
+	return &FileCheckpointStore{dir: dir}, nil
+}
+
+func (s *FileCheckpointStore) Save(ctx context.Context, checkpoint *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if checkpoint.Version > 1 {
+		stored, err := s.Load(ctx, checkpoint.SessionID)
+		if err != nil && !errors.Is(err, ErrCheckpointNotFound) {
+			return err
+		}
+
+		err = checkVersion(stored, checkpoint)
+		if err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".checkpoint-*")
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint file: %w", err)
+	}
+
+	tmpName := tmp.Name()
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Chmod(tmpName, checkpointFilePerm)
+	}
+
+	if err == nil {
+		err = os.Rename(tmpName, s.path(checkpoint.SessionID))
+	}
+
+	if err != nil {
+		_ = os.Remove(tmpName)
+
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileCheckpointStore) Load(_ context.Context, sessionID string) (*Checkpoint, error) {
+	data, err := os.ReadFile(s.path(sessionID))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrCheckpointNotFound, sessionID)
+		}
+
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var checkpoint Checkpoint
+
+	err = json.Unmarshal(data, &checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint: %w", err)
+	}
+
+	return &checkpoint, nil
+}
+
+func (s *FileCheckpointStore) Delete(_ context.Context, sessionID string) error {
+	err := os.Remove(s.path(sessionID))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileCheckpointStore) ListSuspended(ctx context.Context) ([]*Suspended, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	var suspended []*Suspended
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != checkpointFileExt {
+			continue
+		}
+
+		sessionID, err := base64.RawURLEncoding.DecodeString(strings.TrimSuffix(name, checkpointFileExt))
+		if err != nil {
+			continue
+		}
+
+		checkpoint, err := s.Load(ctx, string(sessionID))
+		if err != nil {
+			// The checkpoint may have been deleted since the directory was read
+			if errors.Is(err, ErrCheckpointNotFound) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		if !checkpoint.Completed && checkpoint.Suspended != nil {
+			suspended = append(suspended, checkpoint.Suspended)
+		}
+	}
+
+	return suspended, nil
+}
+
+// path returns the file name for a session. Session IDs are encoded so that
+// arbitrary IDs can't escape the store directory.
+func (s *FileCheckpointStore) path(sessionID string) string {
+	return filepath.Join(s.dir, base64.RawURLEncoding.EncodeToString([]byte(sessionID))+checkpointFileExt)
+}
@@ -0,0 +1,216 @@
+package statemachine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingAction counts executions and fails while failuresLeft is positive.
+type countingAction struct {
+	name         string
+	calls        int
+	failuresLeft int
+}
+
+func (a *countingAction) Name() string {
+	return a.name
+}
+
+func (a *countingAction) Execute(ctx context.Context, smCtx *Context) error {
+	a.calls++
+
+	if a.failuresLeft > 0 {
+		a.failuresLeft--
+
+		return ErrTestTemporary
+	}
+
+	smCtx.Set(a.name+"_done", true)
+
+	return nil
+}
+
+func newCheckpointTestEngine(store CheckpointStore, first, second *countingAction) *Engine {
+	engine := &Engine{
+		states:       make(map[string]State),
+		transitions:  []Transition{},
+		initialState: "first",
+		finalStates:  []string{"end"},
+	}
+
+	engine.RegisterState(NewActionState("first", first, "second"))
+	engine.RegisterState(NewActionState("second", second, "end"))
+	engine.RegisterState(NewFinalState("end"))
+	engine.RegisterTransition(NewSimpleTransition("first", "second"))
+	engine.RegisterTransition(NewSimpleTransition("second", "end"))
+	engine.SetCheckpointStore(store)
+
+	return engine
+}
+
+func TestEngineResumeFromCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	stores := map[string]func(t *testing.T) CheckpointStore{
+		"memory": func(t *testing.T) CheckpointStore {
+			t.Helper()
+
+			return NewMemoryCheckpointStore()
+		},
+		"file": func(t *testing.T) CheckpointStore {
+			t.Helper()
+
+			store, err := NewFileCheckpointStore(t.TempDir())
+			require.NoError(t, err)
+
+			return store
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			store := newStore(t)
+			first := &countingAction{name: "first"}
+			second := &countingAction{name: "second", failuresLeft: 1}
+
+			// The first run fails in the second state, simulating a crash mid-workflow
+			engine := newCheckpointTestEngine(store, first, second)
+			smCtx := NewContext("session-resume", "project")
+			smCtx.Set("input", "value")
+
+			err := engine.Execute(ctx, smCtx)
+			require.ErrorIs(t, err, ErrTestTemporary)
+
+			checkpoint, err := store.Load(ctx, "session-resume")
+			require.NoError(t, err)
+			assert.Equal(t, "second", checkpoint.CurrentState)
+			assert.False(t, checkpoint.Completed)
+
+			// A fresh engine (as after a restart) resumes from the second state
+			restarted := newCheckpointTestEngine(store, first, second)
+
+			resumed, err := restarted.Resume(ctx, "session-resume")
+			require.NoError(t, err)
+
+			assert.Equal(t, 1, first.calls, "completed states must not run again")
+			assert.Equal(t, 2, second.calls)
+			assert.Equal(t, "end", resumed.CurrentState)
+			// The failed attempt happened after the last checkpoint, so it is not part of the path
+			assert.Equal(t, []string{"first", "second"}, resumed.PathHistory)
+			require.Len(t, resumed.History, 1)
+			assert.Equal(t, "first", resumed.History[0].From)
+
+			input, ok := resumed.GetString("input")
+			assert.True(t, ok)
+			assert.Equal(t, "value", input)
+
+			checkpoint, err = store.Load(ctx, "session-resume")
+			require.NoError(t, err)
+			assert.True(t, checkpoint.Completed)
+
+			// Resuming a completed execution is a no-op
+			again, err := restarted.Resume(ctx, "session-resume")
+			require.NoError(t, err)
+			assert.Equal(t, "end", again.CurrentState)
+			assert.Equal(t, 2, second.calls)
+		})
+	}
+}
+
+func TestEngineResumeErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	engine := newCheckpointTestEngine(nil, &countingAction{name: "first"}, &countingAction{name: "second"})
+
+	_, err := engine.Resume(ctx, "missing")
+	require.ErrorIs(t, err, ErrCheckpointStoreRequired)
+
+	engine.SetCheckpointStore(NewMemoryCheckpointStore())
+
+	_, err = engine.Resume(ctx, "missing")
+	require.ErrorIs(t, err, ErrCheckpointNotFound)
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	store, err := NewFileCheckpointStore(t.TempDir())
+	require.NoError(t, err)
+
+	smCtx := NewContext("../../etc/passwd", "project")
+	smCtx.CurrentState = "review"
+	smCtx.Provider = "salesforce"
+	smCtx.Set("count", 3)
+	smCtx.AppendToPath("start")
+	smCtx.AddTransition("start", "review", map[string]any{"ok": true})
+
+	require.NoError(t, store.Save(ctx, smCtx.Checkpoint()))
+
+	loaded, err := store.Load(ctx, "../../etc/passwd")
+	require.NoError(t, err)
+
+	restored := loaded.Context()
+	assert.Equal(t, "review", restored.CurrentState)
+	assert.Equal(t, "salesforce", restored.Provider)
+	assert.Equal(t, []string{"start"}, restored.PathHistory)
+	assert.Equal(t, "review", restored.History[0].To)
+
+	// JSON decodes numbers as float64
+	count, ok := restored.Get("count")
+	assert.True(t, ok)
+	assert.InEpsilon(t, 3.0, count, 0)
+
+	require.NoError(t, store.Delete(ctx, "../../etc/passwd"))
+	require.NoError(t, store.Delete(ctx, "../../etc/passwd"))
+
+	_, err = store.Load(ctx, "../../etc/passwd")
+	require.ErrorIs(t, err, ErrCheckpointNotFound)
+}
+
+func TestCheckpointStoreVersionConflict(t *testing.T) {
+	t.Parallel()
+
+	fileStore, err := NewFileCheckpointStore(t.TempDir())
+	require.NoError(t, err)
+
+	stores := map[string]CheckpointStore{
+		"memory": NewMemoryCheckpointStore(),
+		"file":   fileStore,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			save := func(version int64) error {
+				checkpoint := NewContext("session-version", "project").Checkpoint()
+				checkpoint.Version = version
+
+				return store.Save(ctx, checkpoint)
+			}
+
+			require.NoError(t, save(1))
+			require.NoError(t, save(2))
+			require.ErrorIs(t, save(2), ErrCheckpointConflict, "version 2 was already saved")
+			require.ErrorIs(t, save(4), ErrCheckpointConflict)
+			require.NoError(t, save(1), "a new execution replaces the checkpoint")
+
+			loaded, err := store.Load(ctx, "session-version")
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), loaded.Version)
+			assert.Equal(t, int64(1), loaded.Context().Checkpoint().Version)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package statemachine
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// SubMachineFinalStateKey returns the context data key under which a composite
+// state records the final state its sub-machine ended in. Transition
+// conditions can use it, e.g. "data['setup_final_state'] == 'skipped'".
+func SubMachineFinalStateKey(stateName string) string {
+	return stateName + "_final_state"
+}
+
+// CompositeState runs a nested state machine to completion as a single state
+// of its parent.
+//
+// The sub-machine works on a copy of the parent's data, which is merged back
+// when it finishes. The states it visits are appended to the parent's path
+// history as "parent/child". The final state it reaches is stored under
+// SubMachineFinalStateKey and mapped to the parent's next state through onExit.
+type CompositeState struct {
+	name   string
+	engine *Engine
+	onExit map[string]string
+}
+
+// NewCompositeState creates a state that runs sub to completion. onExit maps
+// the sub-machine's final states to the parent states to transition to; final
+// states without a mapping fall back to the parent's regular transitions.
+func NewCompositeState(name string, sub *Engine, onExit map[string]string) *CompositeState {
+	sub.nested = true
+
+	return &CompositeState{
+		name:   name,
+		engine: sub,
+		onExit: maps.Clone(onExit),
+	}
+}
+
+func (s *CompositeState) Name() string {
+	return s.name
+}
+
+// SubEngine returns the engine that runs the nested state machine.
+func (s *CompositeState) SubEngine() *Engine {
+	return s.engine
+}
+
+func (s *CompositeState) Execute(ctx context.Context, smCtx *Context) (TransitionResult, error) {
+	child := s.childContext(smCtx)
+
+	err := s.engine.Execute(ctx, child)
+
+	for _, state := range child.PathHistory {
+		smCtx.AppendToPath(s.name + "/" + state)
+	}
+
+	if suspended, ok := AsSuspended(err); ok {
+		// Only the parent is checkpointed, so a nested wait could never be signaled
+		return TransitionResult{}, fmt.Errorf("%w: state %s", ErrWaitInSubMachine, suspended.State)
+	}
+
+	if err != nil {
+		return TransitionResult{}, fmt.Errorf("sub-machine: %w", err)
+	}
+
+	// Merge before returning so that the parent's transition conditions can see the results
+	smCtx.Merge(child.Data)
+	smCtx.Set(SubMachineFinalStateKey(s.name), child.CurrentState)
+
+	return TransitionResult{
+		NextState: s.onExit[child.CurrentState],
+		Data:      map[string]any{},
+		Complete:  false,
+	}, nil
+}
+
+// childContext creates the context the sub-machine runs with.
+func (s *CompositeState) childContext(parent *Context) *Context {
+	parent.mu.RLock()
+	defer parent.mu.RUnlock()
+
+	child := NewContext(parent.SessionID, parent.ProjectID)
+	child.Provider = parent.Provider
+	child.ContextChunkID = parent.ContextChunkID
+	child.ToolName = parent.ToolName
+
+	maps.Copy(child.Data, parent.Data)
+	maps.Copy(child.Metadata, parent.Metadata)
+
+	return child
+}
+
+// buildCompositeState resolves the sub-machine of a composite state config and
+// builds an engine for it.
+func buildCompositeState(config StateConfig, factory *ActionFactory, refs []string) (State, error) {
+	sub := config.InlineSubMachine()
+
+	if sub == nil {
+		if slices.Contains(refs, config.SubMachineRef) {
+			return nil, fmt.Errorf("%w: %s", ErrRecursiveSubMachine, strings.Join(append(slices.Clone(refs), config.SubMachineRef), " -> "))
+		}
+
+		var err error
+
+		sub, err = LoadConfig(config.SubMachineRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load sub-machine %q: %w", config.SubMachineRef, err)
+		}
+
+		for childFinal := range config.OnExit {
+			if !slices.Contains(sub.FinalStates, childFinal) {
+				return nil, fmt.Errorf("onExit: %w: %s", ErrSubMachineFinalStateNotFound, childFinal)
+			}
+		}
+
+		refs = append(slices.Clone(refs), config.SubMachineRef)
+	}
+
+	engine, err := newEngine(sub, factory, refs)
+	if err != nil {
+		return nil, fmt.Errorf("sub-machine %s: %w", sub.Name, err)
+	}
+
+	return NewCompositeState(config.Name, engine, config.OnExit), nil
+}
@@ -0,0 +1,298 @@
+package statemachine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTestConfigNotFound = errors.New("config not found")
+
+// setAction copies its parameters into the context data.
+type setAction struct {
+	BaseAction
+
+	values map[string]any
+}
+
+func (a *setAction) Execute(ctx context.Context, smCtx *Context) error {
+	smCtx.Merge(a.values)
+
+	return nil
+}
+
+func newCompositeTestFactory() *ActionFactory {
+	factory := NewActionFactory()
+	factory.Register("set", func(_ *ActionFactory, name string, params map[string]any) (Action, error) {
+		return &setAction{BaseAction: BaseAction{name: name}, values: params}, nil
+	})
+
+	return factory
+}
+
+// mapConfigLoader serves configs from memory.
+type mapConfigLoader map[string]string
+
+func (l mapConfigLoader) LoadByName(name string) ([]byte, error) {
+	data, ok := l[name]
+	if !ok {
+		return nil, errTestConfigNotFound
+	}
+
+	return []byte(data), nil
+}
+
+func (l mapConfigLoader) ListAvailable() []string {
+	names := make([]string, 0, len(l))
+	for name := range l {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+const nestedWorkflowYAML = `
+name: onboarding
+initialState: start
+finalStates: [done, aborted]
+states:
+  - name: start
+    type: action
+    actions:
+      - type: set
+        name: init
+        parameters:
+          provider: salesforce
+  - name: setup
+    type: composite
+    subMachine:
+      initialState: collect
+      finalStates: [configured, skipped]
+      states:
+        - name: collect
+          type: action
+          actions:
+            - type: set
+              name: answer
+              parameters:
+                skip: false
+        - name: configured
+          type: final
+        - name: skipped
+          type: final
+      transitions:
+        - from: collect
+          to: skipped
+          condition: "data.skip"
+        - from: collect
+          to: configured
+          condition: "data.provider == 'salesforce'"
+    onExit:
+      configured: done
+      skipped: aborted
+  - name: done
+    type: final
+  - name: aborted
+    type: final
+transitions:
+  - from: start
+    to: setup
+`
+
+func TestCompositeStateInline(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadConfigFromBytes([]byte(nestedWorkflowYAML))
+	require.NoError(t, err)
+
+	engine, err := NewEngine(config, newCompositeTestFactory())
+	require.NoError(t, err)
+
+	smCtx := NewContext("session", "project")
+
+	require.NoError(t, engine.Execute(context.Background(), smCtx))
+
+	assert.Equal(t, "done", smCtx.CurrentState)
+	assert.Equal(t, []string{"start", "setup", "setup/collect", "setup/configured"}, smCtx.PathHistory)
+
+	// Data written inside the sub-machine is visible to the parent
+	skip, ok := smCtx.GetBool("skip")
+	assert.True(t, ok)
+	assert.False(t, skip)
+
+	finalState, ok := smCtx.GetString(SubMachineFinalStateKey("setup"))
+	assert.True(t, ok)
+	assert.Equal(t, "configured", finalState)
+}
+
+func TestCompositeStateNestedWait(t *testing.T) {
+	t.Parallel()
+
+	parent := &Engine{
+		states:       make(map[string]State),
+		transitions:  []Transition{},
+		initialState: "nested",
+		finalStates:  []string{"done"},
+	}
+
+	parent.RegisterState(NewCompositeState("nested", newTimeoutEngine(time.Hour), nil))
+	parent.RegisterState(NewFinalState("done"))
+	parent.RegisterTransition(NewSimpleTransition("nested", "done"))
+	parent.SetCheckpointStore(NewMemoryCheckpointStore())
+
+	err := parent.Execute(context.Background(), NewContext("session", "project"))
+	require.ErrorIs(t, err, ErrWaitInSubMachine)
+	assert.NotErrorIs(t, err, ErrCheckpointStoreRequired)
+}
+
+//nolint:paralleltest // Test modifies the global config loader
+func TestCompositeStateByReference(t *testing.T) {
+	SetConfigLoader(mapConfigLoader{
+		"collect_answers": `
+name: collect_answers
+initialState: ask
+finalStates: [answered]
+states:
+  - name: ask
+    type: action
+    actions:
+      - type: set
+        name: answer
+        parameters:
+          answer: 42
+  - name: answered
+    type: final
+transitions:
+  - from: ask
+    to: answered
+`,
+		"loop_a": `
+name: loop_a
+initialState: inner
+finalStates: [inner]
+states:
+  - name: inner
+    type: composite
+    subMachineRef: loop_b
+`,
+		"loop_b": `
+name: loop_b
+initialState: inner
+finalStates: [inner]
+states:
+  - name: inner
+    type: composite
+    subMachineRef: loop_a
+`,
+	})
+	t.Cleanup(func() { SetConfigLoader(nil) })
+
+	config := &Config{
+		Name:         "parent",
+		InitialState: "questions",
+		FinalStates:  []string{"end"},
+		States: []StateConfig{
+			{Name: "questions", Type: StateTypeComposite, SubMachineRef: "collect_answers", OnExit: map[string]string{"answered": "end"}},
+			{Name: "end", Type: "final"},
+		},
+	}
+
+	engine, err := NewEngine(config, newCompositeTestFactory())
+	require.NoError(t, err)
+
+	smCtx := NewContext("session", "project")
+	require.NoError(t, engine.Execute(context.Background(), smCtx))
+	assert.Equal(t, "end", smCtx.CurrentState)
+
+	answer, ok := smCtx.Get("answer")
+	assert.True(t, ok)
+	assert.Equal(t, 42, answer)
+
+	// Unknown final state in onExit
+	config.States[0].OnExit = map[string]string{"missing": "end"}
+	_, err = NewEngine(config, newCompositeTestFactory())
+	require.ErrorIs(t, err, ErrSubMachineFinalStateNotFound)
+
+	// Sub-machines that reference each other are rejected
+	loop, err := LoadConfig("loop_a")
+	require.NoError(t, err)
+
+	_, err = NewEngine(loop, newCompositeTestFactory())
+	require.ErrorIs(t, err, ErrRecursiveSubMachine)
+}
+
+func TestCompositeStateValidation(t *testing.T) {
+	t.Parallel()
+
+	sub := &Config{
+		Name:         "sub",
+		InitialState: "a",
+		FinalStates:  []string{"b"},
+		States: []StateConfig{
+			{Name: "a", Type: "action", Actions: []ActionConfig{{Type: "noop", Name: "noop"}}},
+			{Name: "b", Type: "final"},
+		},
+		Transitions: []TransitionConfig{{From: "a", To: "b"}},
+	}
+
+	newConfig := func(state StateConfig) *Config {
+		return &Config{
+			Name:         "parent",
+			InitialState: "nested",
+			FinalStates:  []string{"end"},
+			States:       []StateConfig{state, {Name: "end", Type: "final"}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		state    StateConfig
+		expected error
+	}{
+		{
+			name:     "missing sub-machine",
+			state:    StateConfig{Name: "nested", Type: StateTypeComposite},
+			expected: ErrSubMachineRequired,
+		},
+		{
+			name:     "both inline and reference",
+			state:    StateConfig{Name: "nested", Type: StateTypeComposite, SubMachine: sub, SubMachineRef: "sub"},
+			expected: ErrAmbiguousSubMachine,
+		},
+		{
+			name:     "unknown child final state",
+			state:    StateConfig{Name: "nested", Type: StateTypeComposite, SubMachine: sub, OnExit: map[string]string{"a": "end"}},
+			expected: ErrSubMachineFinalStateNotFound,
+		},
+		{
+			name:     "unknown parent target",
+			state:    StateConfig{Name: "nested", Type: StateTypeComposite, SubMachine: sub, OnExit: map[string]string{"b": "nowhere"}},
+			expected: ErrTransitionToNotFound,
+		},
+		{
+			name: "invalid sub-machine",
+			state: StateConfig{Name: "nested", Type: StateTypeComposite, SubMachine: &Config{
+				InitialState: "a",
+				FinalStates:  []string{"a"},
+			}},
+			expected: ErrStateRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := newConfig(tt.state).Validate()
+			require.ErrorIs(t, err, tt.expected)
+		})
+	}
+
+	valid := newConfig(StateConfig{Name: "nested", Type: StateTypeComposite, SubMachine: sub, OnExit: map[string]string{"b": "end"}})
+	require.NoError(t, valid.Validate())
+	assert.Len(t, valid.EffectiveTransitions(), 1)
+}
@@ -3,7 +3,10 @@ package statemachine
 import (
 	"fmt"
 	"io/fs"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -12,6 +15,8 @@ import (
 const (
 	// StateTypeConditional represents a conditional state type.
 	StateTypeConditional = "conditional"
+	// StateTypeComposite represents a state that runs a nested state machine.
+	StateTypeComposite = "composite"
 )
 
 // ConfigLoader is an interface for loading configurations by name.
@@ -41,13 +46,38 @@ type Config struct {
 }
 
 // StateConfig defines the configuration for a state.
+//
+// Composite states run a nested state machine, declared either inline with
+// SubMachine or by name with SubMachineRef (resolved through LoadConfig).
+// OnExit maps the child's final states to parent states to transition to.
+//
+// Wait states suspend execution until the event described by Await is signaled.
 type StateConfig struct {
-	Name     string         `json:"name"     yaml:"name"`
-	Type     string         `json:"type"     yaml:"type"` // "action", "composite", "conditional", "final"
-	Actions  []ActionConfig `json:"actions"  yaml:"actions"`
-	OnError  string         `json:"onError"  yaml:"onError"`
-	Timeout  string         `json:"timeout"  yaml:"timeout"`
-	Metadata map[string]any `json:"metadata" yaml:"metadata"`
+	Name          string            `json:"name"                    yaml:"name"`
+	Type          string            `json:"type"                    yaml:"type"` // "action", "composite", "conditional", "final"
+	Actions       []ActionConfig    `json:"actions"                 yaml:"actions"`
+	OnError       string            `json:"onError"                 yaml:"onError"`
+	Timeout       string            `json:"timeout"                 yaml:"timeout"`
+	Metadata      map[string]any    `json:"metadata"                yaml:"metadata"`
+	SubMachine    *Config           `json:"subMachine,omitempty"    yaml:"subMachine,omitempty"`
+	SubMachineRef string            `json:"subMachineRef,omitempty" yaml:"subMachineRef,omitempty"`
+	OnExit        map[string]string `json:"onExit,omitempty"        yaml:"onExit,omitempty"`
+	Await         *AwaitConfig      `json:"await,omitempty"         yaml:"await,omitempty"`
+}
+
+// InlineSubMachine returns the inline sub-machine of a composite state, or nil.
+// If the inline config has no name, it is named after the state.
+func (s StateConfig) InlineSubMachine() *Config {
+	if s.SubMachine == nil {
+		return nil
+	}
+
+	child := *s.SubMachine
+	if child.Name == "" {
+		child.Name = s.Name
+	}
+
+	return &child
 }
 
 // ActionConfig defines the configuration for an action.
@@ -197,6 +227,29 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("state %s: %w", state.Name, ErrActionStateMissingAction)
 		}
 
+		if state.Type == StateTypeComposite {
+			err := c.validateComposite(state)
+			if err != nil {
+				return fmt.Errorf("state %s: %w", state.Name, err)
+			}
+		}
+
+		_, err := parseStateTimeout(state)
+		if err != nil {
+			return fmt.Errorf("state %s: %w", state.Name, err)
+		}
+
+		if state.OnError != "" && !c.stateExists(state.OnError) {
+			return fmt.Errorf("state %s: %w: %s", state.Name, ErrOnErrorStateNotFound, state.OnError)
+		}
+
+		if state.Type == StateTypeWait {
+			err := c.validateWait(state)
+			if err != nil {
+				return fmt.Errorf("state %s: %w", state.Name, err)
+			}
+		}
+
 		// Validate actions
 		for i, action := range state.Actions {
 			if action.Type == "" {
@@ -219,7 +272,7 @@ func (c *Config) Validate() error {
 	}
 
 	// Validate transitions
-	for i, transition := range c.Transitions {
+	for i, transition := range c.EffectiveTransitions() {
 		if transition.From == "" {
 			return fmt.Errorf("transition %d: %w", i, ErrTransitionFromRequired)
 		}
@@ -235,6 +288,13 @@ func (c *Config) Validate() error {
 		if !c.stateExists(transition.To) {
 			return fmt.Errorf("transition %d: %w: %s", i, ErrTransitionToNotFound, transition.To)
 		}
+
+		if !isUnconditional(transition.Condition) {
+			_, err := CompileExpression(transition.Condition)
+			if err != nil {
+				return fmt.Errorf("transition %d: %w", i, err)
+			}
+		}
 	}
 
 	// Validate reachability (all states should be reachable from initial state)
@@ -245,6 +305,91 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// validateComposite checks the sub-machine declaration of a composite state.
+// Sub-machines referenced by name are resolved and validated by NewEngine.
+func (c *Config) validateComposite(state StateConfig) error {
+	switch {
+	case state.SubMachine == nil && state.SubMachineRef == "":
+		return ErrSubMachineRequired
+	case state.SubMachine != nil && state.SubMachineRef != "":
+		return ErrAmbiguousSubMachine
+	}
+
+	for childFinal, target := range state.OnExit {
+		if !c.stateExists(target) {
+			return fmt.Errorf("onExit %s: %w: %s", childFinal, ErrTransitionToNotFound, target)
+		}
+	}
+
+	child := state.InlineSubMachine()
+	if child == nil {
+		return nil
+	}
+
+	err := child.Validate()
+	if err != nil {
+		return fmt.Errorf("sub-machine: %w", err)
+	}
+
+	for childFinal := range state.OnExit {
+		if !slices.Contains(child.FinalStates, childFinal) {
+			return fmt.Errorf("onExit: %w: %s", ErrSubMachineFinalStateNotFound, childFinal)
+		}
+	}
+
+	return nil
+}
+
+// EffectiveTransitions returns the declared transitions followed by the
+// implicit transitions created by the OnExit mappings of composite states and
+// the timeouts of wait states. Implicit transitions carry the condition that
+// the engine evaluates for them.
+func (c *Config) EffectiveTransitions() []TransitionConfig {
+	transitions := slices.Clone(c.Transitions)
+
+	for _, state := range c.States {
+		if state.Type == StateTypeWait && state.Await != nil && state.Await.OnTimeout != "" {
+			transitions = append(transitions, TransitionConfig{
+				From:      state.Name,
+				To:        state.Await.OnTimeout,
+				Condition: fmt.Sprintf("data[%s] == true", strconv.Quote(WaitTimedOutKey(state.Name))),
+			})
+		}
+
+		if state.Type != StateTypeComposite {
+			continue
+		}
+
+		for _, childFinal := range slices.Sorted(maps.Keys(state.OnExit)) {
+			transitions = append(transitions, TransitionConfig{
+				From:      state.Name,
+				To:        state.OnExit[childFinal],
+				Condition: fmt.Sprintf("data[%s] == %s", strconv.Quote(SubMachineFinalStateKey(state.Name)), strconv.Quote(childFinal)),
+			})
+		}
+	}
+
+	return transitions
+}
+
+// ErrorTransitions returns the edges from states to their OnError states.
+// The engine follows them only when a state fails, so they are not part of
+// EffectiveTransitions, but they matter for graph analysis such as reachability.
+func (c *Config) ErrorTransitions() []TransitionConfig {
+	var transitions []TransitionConfig
+
+	for _, state := range c.States {
+		if state.OnError != "" {
+			transitions = append(transitions, TransitionConfig{
+				From: state.Name,
+				To:   state.OnError,
+			})
+		}
+	}
+
+	return transitions
+}
+
 // stateExists checks if a state with the given name exists.
 func (c *Config) stateExists(name string) bool {
 	for _, state := range c.States {
@@ -267,7 +412,7 @@ func (c *Config) findReachableStates() map[string]bool {
 		current := queue[0]
 		queue = queue[1:]
 
-		for _, transition := range c.Transitions {
+		for _, transition := range append(c.EffectiveTransitions(), c.ErrorTransitions()...) {
 			if transition.From == current && !reachable[transition.To] {
 				reachable[transition.To] = true
 
@@ -21,6 +21,9 @@ type Context struct {
 	ContextChunkID string   // Unique ID for this conversation chunk/interaction
 	ToolName       string   // Tool name (e.g., "guided_setup", "integration_doctor")
 	PathHistory    []string // Ordered list of states visited (append CurrentState on entry)
+
+	// checkpointVersion is the Version of the last checkpoint saved or loaded for this context.
+	checkpointVersion int64
 }
 
 // StateTransition records a transition in the state machine history.
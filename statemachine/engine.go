@@ -2,6 +2,7 @@ package statemachine
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
 	"time"
@@ -18,8 +19,9 @@ const stateMachineContextKey contextKey = "statemachine_context"
 
 // Metric outcome constants.
 const (
-	outcomeSuccess = "success"
-	outcomeError   = "error"
+	outcomeSuccess   = "success"
+	outcomeError     = "error"
+	outcomeSuspended = "suspended"
 )
 
 // ActionExecutionHook is called before and after action execution.
@@ -35,11 +37,23 @@ type Engine struct {
 	executionHooks     []ActionExecutionHook
 	enableCancellation bool
 	logger             Logger
+	checkpointStore    CheckpointStore
+	stateTimeouts      map[string]time.Duration
+	errorHandlers      map[string]string
+	// nested is set for sub-engines of a CompositeState, which report waits
+	// to their parent instead of checkpointing them.
+	nested bool
 }
 
 // NewEngine creates a new state machine engine from a configuration.
 // If factory is nil, a new default factory is created.
 func NewEngine(config *Config, factory *ActionFactory) (*Engine, error) {
+	return newEngine(config, factory, []string{config.Name})
+}
+
+// newEngine builds an engine for config. refs lists the config names that were
+// loaded to reach this config and is used to reject recursive sub-machines.
+func newEngine(config *Config, factory *ActionFactory, refs []string) (*Engine, error) {
 	err := config.Validate()
 	if err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
@@ -53,6 +67,8 @@ func NewEngine(config *Config, factory *ActionFactory) (*Engine, error) {
 		actionTimeout:      0, // No timeout by default
 		executionHooks:     []ActionExecutionHook{},
 		enableCancellation: true, // Enable cancellation by default
+		stateTimeouts:      make(map[string]time.Duration),
+		errorHandlers:      make(map[string]string),
 	}
 
 	// Create action factory if not provided
@@ -62,17 +78,29 @@ func NewEngine(config *Config, factory *ActionFactory) (*Engine, error) {
 
 	// Build states from config
 	for _, stateConfig := range config.States {
-		state, err := buildStateFromConfig(stateConfig, factory)
+		state, err := buildStateFromConfig(stateConfig, factory, refs)
 		if err != nil {
 			return nil, fmt.Errorf("failed to build state %s: %w", stateConfig.Name, err)
 		}
 
 		engine.RegisterState(state)
+
+		timeout, err := parseStateTimeout(stateConfig)
+		if err != nil {
+			return nil, fmt.Errorf("state %s: %w", stateConfig.Name, err)
+		}
+
+		engine.SetStateTimeout(stateConfig.Name, timeout)
+		engine.SetErrorHandler(stateConfig.Name, stateConfig.OnError)
 	}
 
-	// Build transitions from config
-	for _, transConfig := range config.Transitions {
-		transition := buildTransitionFromConfig(transConfig)
+	// Build transitions from config, including the implicit exits of composite states
+	for i, transConfig := range config.EffectiveTransitions() {
+		transition, err := buildTransitionFromConfig(transConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build transition %d (%s -> %s): %w", i, transConfig.From, transConfig.To, err)
+		}
+
 		engine.RegisterTransition(transition)
 	}
 
@@ -85,10 +113,13 @@ func (e *Engine) Execute(ctx context.Context, smCtx *Context) (err error) {
 	ctx, span := startExecutionSpan(ctx, smCtx)
 
 	defer func() {
-		if err != nil {
+		switch {
+		case errors.Is(err, ErrSuspended):
+			span.SetStatus(codes.Ok, outcomeSuspended)
+		case err != nil:
 			span.RecordError(err)
 			span.SetStatus(codes.Error, err.Error())
-		} else {
+		default:
 			span.SetStatus(codes.Ok, "completed")
 		}
 
@@ -99,11 +130,8 @@ func (e *Engine) Execute(ctx context.Context, smCtx *Context) (err error) {
 	executionStart := time.Now()
 
 	defer func() {
-		// Record execution duration on exit (success or error)
-		outcome := outcomeSuccess
-		if err != nil {
-			outcome = outcomeError
-		}
+		// Record execution duration on exit (success, error or suspension)
+		outcome := executionOutcome(err)
 
 		executionDuration.WithLabelValues(
 			sanitizeTool(smCtx.ToolName),
@@ -134,6 +162,12 @@ func (e *Engine) Execute(ctx context.Context, smCtx *Context) (err error) {
 		smCtx.CurrentState = e.initialState
 	}
 
+	// Persist the starting point so that a crash in the first state can still be resumed
+	err = e.saveCheckpoint(ctx, smCtx, false)
+	if err != nil {
+		return err
+	}
+
 	for {
 		// Check for context cancellation
 		if e.enableCancellation {
@@ -174,11 +208,12 @@ func (e *Engine) Execute(ctx context.Context, smCtx *Context) (err error) {
 		stateStartTime := time.Now()
 		result, err := e.executeStateWithHooks(stateCtx, state, smCtx)
 		stateElapsed := time.Since(stateStartTime)
+		suspended, isSuspended := AsSuspended(err)
 
 		// Update span on state exit
 		stateSpan.SetAttributes(attribute.Int64("duration_ms", stateElapsed.Milliseconds()))
 
-		if err != nil {
+		if err != nil && !isSuspended {
 			stateSpan.RecordError(err)
 			stateSpan.SetStatus(codes.Error, err.Error())
 			stateSpan.SetAttributes(attribute.String("error", err.Error()))
@@ -195,10 +230,7 @@ func (e *Engine) Execute(ctx context.Context, smCtx *Context) (err error) {
 		}
 
 		// Record state exit outcome
-		outcome := outcomeSuccess
-		if err != nil {
-			outcome = outcomeError
-		}
+		outcome := executionOutcome(err)
 
 		stateVisitsTotal.WithLabelValues(
 			sanitizeTool(smCtx.ToolName),
@@ -219,7 +251,20 @@ func (e *Engine) Execute(ctx context.Context, smCtx *Context) (err error) {
 			sanitizeChunkID(smCtx.ContextChunkID),
 		).Observe(stateElapsed.Seconds())
 
+		if isSuspended {
+			return e.suspend(ctx, smCtx, suspended)
+		}
+
 		if err != nil {
+			routed, routeErr := e.routeError(ctx, smCtx, err)
+			if routeErr != nil {
+				return routeErr
+			}
+
+			if routed {
+				continue
+			}
+
 			return WrapStateError(smCtx.CurrentState, err)
 		}
 
@@ -227,7 +272,7 @@ func (e *Engine) Execute(ctx context.Context, smCtx *Context) (err error) {
 		if result.Complete || slices.Contains(e.finalStates, result.NextState) {
 			smCtx.CurrentState = result.NextState
 
-			return nil
+			return e.saveCheckpoint(ctx, smCtx, true)
 		}
 
 		// Find and apply transition
@@ -255,7 +300,111 @@ func (e *Engine) Execute(ctx context.Context, smCtx *Context) (err error) {
 		smCtx.AddTransition(smCtx.CurrentState, nextState, result.Data)
 		smCtx.CurrentState = nextState
 		smCtx.Merge(result.Data)
+
+		err = e.saveCheckpoint(ctx, smCtx, false)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Resume loads the latest checkpoint for a session from the configured
+// CheckpointStore and continues execution from the state recorded in it.
+// It returns the rehydrated context; if the checkpointed execution had already
+// completed, the context is returned without running any states.
+//
+// For an execution suspended in a wait state, Resume returns the *Suspended
+// as its error unless the wait's deadline has passed, in which case it follows
+// the wait state's timeout transition and continues.
+//
+// Resume, Signal and ResumeExpired may race for the same execution: at most
+// one of them advances it, and the others fail with ErrCheckpointConflict
+// before running any state. The guarantee relies on the store's version check
+// (see CheckpointStore.Save).
+func (e *Engine) Resume(ctx context.Context, sessionID string) (*Context, error) {
+	if e.checkpointStore == nil {
+		return nil, ErrCheckpointStoreRequired
+	}
+
+	checkpoint, err := e.checkpointStore.Load(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	smCtx := checkpoint.Context()
+
+	if checkpoint.Completed {
+		return smCtx, nil
+	}
+
+	// A suspended execution stays suspended until signaled or until its wait times out
+	if suspended := checkpoint.Suspended; suspended != nil {
+		if !suspended.Expired(time.Now()) {
+			return smCtx, suspended
+		}
+
+		return smCtx, e.continueAfterWait(ctx, smCtx, suspended, nil, true)
+	}
+
+	if _, exists := e.states[smCtx.CurrentState]; !exists {
+		return smCtx, WrapStateError(smCtx.CurrentState, ErrStateNotFound)
 	}
+
+	return smCtx, e.Execute(ctx, smCtx)
+}
+
+// suspend records that execution paused in a wait state and returns the
+// suspension to the caller. Without a checkpoint store the execution could
+// never be signaled, so reaching a wait state is an error.
+func (e *Engine) suspend(ctx context.Context, smCtx *Context, suspended *Suspended) error {
+	if e.nested {
+		return suspended
+	}
+
+	if e.checkpointStore == nil {
+		return WrapStateError(suspended.State, fmt.Errorf("%w: wait states can only be signaled "+
+			"through a checkpoint store", ErrCheckpointStoreRequired))
+	}
+
+	checkpoint := smCtx.Checkpoint()
+	checkpoint.Suspended = suspended
+
+	err := e.storeCheckpoint(ctx, smCtx, checkpoint)
+	if err != nil {
+		return err
+	}
+
+	return suspended
+}
+
+// saveCheckpoint persists the context to the checkpoint store, if one is configured.
+func (e *Engine) saveCheckpoint(ctx context.Context, smCtx *Context, completed bool) error {
+	if e.checkpointStore == nil {
+		return nil
+	}
+
+	checkpoint := smCtx.Checkpoint()
+	checkpoint.Completed = completed
+
+	return e.storeCheckpoint(ctx, smCtx, checkpoint)
+}
+
+// storeCheckpoint writes a checkpoint taken from smCtx to the checkpoint
+// store as the next version, so that the store rejects it with
+// ErrCheckpointConflict if someone else advanced the execution meanwhile.
+func (e *Engine) storeCheckpoint(ctx context.Context, smCtx *Context, checkpoint *Checkpoint) error {
+	checkpoint.Version++
+
+	err := e.checkpointStore.Save(ctx, checkpoint)
+	if err != nil {
+		return WrapStateError(checkpoint.CurrentState, fmt.Errorf("%w: %w", ErrCheckpointFailed, err))
+	}
+
+	smCtx.mu.Lock()
+	smCtx.checkpointVersion = checkpoint.Version
+	smCtx.mu.Unlock()
+
+	return nil
 }
 
 // RegisterState registers a state with the engine.
@@ -272,22 +421,60 @@ func (e *Engine) RegisterTransition(transition Transition) {
 // A timeout of 0 means no timeout.
 func (e *Engine) SetActionTimeout(timeout time.Duration) {
 	e.actionTimeout = timeout
+
+	e.forEachSubEngine(func(sub *Engine) { sub.SetActionTimeout(timeout) })
 }
 
 // AddExecutionHook adds a hook to be called before and after each action execution.
 // Hooks are called with phase "start" before execution and "end" after execution.
 func (e *Engine) AddExecutionHook(hook ActionExecutionHook) {
 	e.executionHooks = append(e.executionHooks, hook)
+
+	e.forEachSubEngine(func(sub *Engine) { sub.AddExecutionHook(hook) })
 }
 
 // SetCancellationEnabled enables or disables context cancellation handling.
 func (e *Engine) SetCancellationEnabled(enabled bool) {
 	e.enableCancellation = enabled
+
+	e.forEachSubEngine(func(sub *Engine) { sub.SetCancellationEnabled(enabled) })
+}
+
+// SetCheckpointStore sets the store the engine writes a checkpoint to after
+// every state transition. Checkpoints make executions resumable with Resume.
+func (e *Engine) SetCheckpointStore(store CheckpointStore) {
+	e.checkpointStore = store
 }
 
 // SetLogger sets the logger for state machine execution.
 func (e *Engine) SetLogger(logger Logger) {
 	e.logger = logger
+
+	e.forEachSubEngine(func(sub *Engine) { sub.SetLogger(logger) })
+}
+
+// forEachSubEngine calls fn with the engine of every composite state, so that
+// settings applied to a parent also apply to its nested state machines.
+// Checkpointing is deliberately not propagated: sub-machines run inside a
+// single parent state, which is what gets checkpointed.
+func (e *Engine) forEachSubEngine(fn func(sub *Engine)) {
+	for _, state := range e.states {
+		if composite, ok := state.(*CompositeState); ok {
+			fn(composite.engine)
+		}
+	}
+}
+
+// executionOutcome returns the metric outcome label for an execution or state result.
+func executionOutcome(err error) string {
+	switch {
+	case errors.Is(err, ErrSuspended):
+		return outcomeSuspended
+	case err != nil:
+		return outcomeError
+	default:
+		return outcomeSuccess
+	}
 }
 
 // logExecutionSummary logs a summary of the state machine execution including the complete path.
@@ -300,14 +487,16 @@ func (e *Engine) logExecutionSummary(ctx context.Context, duration time.Duration
 }
 
 // executeStateWithHooks executes a state with timeout and hooks.
+// The state's own timeout, if set, takes precedence over the action timeout.
 func (e *Engine) executeStateWithHooks(ctx context.Context, state State, smCtx *Context) (TransitionResult, error) {
 	// Create context with timeout if configured
 	execCtx := ctx
+	timeout := e.timeoutFor(state.Name())
 
-	if e.actionTimeout > 0 {
+	if timeout > 0 {
 		var cancel context.CancelFunc
 
-		execCtx, cancel = context.WithTimeout(ctx, e.actionTimeout)
+		execCtx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 	}
 
@@ -316,8 +505,9 @@ func (e *Engine) executeStateWithHooks(ctx context.Context, state State, smCtx *
 		hook(execCtx, state.Name(), smCtx.CurrentState, "start", nil)
 	}
 
-	// Execute state
-	result, err := state.Execute(execCtx, smCtx)
+	// Execute state, recovering panics only if they can be routed to an OnError state
+	_, hasErrorHandler := e.errorHandlers[state.Name()]
+	result, err := runState(ctx, execCtx, state, smCtx, timeout, hasErrorHandler)
 
 	// Call "end" hooks
 	for _, hook := range e.executionHooks {
@@ -368,7 +558,7 @@ func (e *Engine) findTransition(ctx context.Context, smCtx *Context, preferred s
 }
 
 // buildStateFromConfig creates a State from configuration.
-func buildStateFromConfig(config StateConfig, factory *ActionFactory) (State, error) {
+func buildStateFromConfig(config StateConfig, factory *ActionFactory, refs []string) (State, error) {
 	switch config.Type {
 	case "action":
 		// Build actions
@@ -401,17 +591,34 @@ func buildStateFromConfig(config StateConfig, factory *ActionFactory) (State, er
 	case "final":
 		return NewFinalState(config.Name), nil
 
+	case StateTypeComposite:
+		return buildCompositeState(config, factory, refs)
+
+	case StateTypeWait:
+		return buildWaitState(config)
+
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrUnknownStateType, config.Type)
 	}
 }
 
 // buildTransitionFromConfig creates a Transition from configuration.
-func buildTransitionFromConfig(config TransitionConfig) Transition {
-	if config.Condition == "" || config.Condition == "always" {
-		return NewSimpleTransition(config.From, config.To)
+// Conditions are compiled here so that malformed expressions fail at engine
+// construction rather than in the middle of an execution.
+func buildTransitionFromConfig(config TransitionConfig) (Transition, error) {
+	if isUnconditional(config.Condition) {
+		return NewSimpleTransition(config.From, config.To), nil
 	}
 
-	// For now, use expression-based transitions
-	return NewExpressionTransition(config.From, config.To, config.Condition)
+	expr, err := CompileExpression(config.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCompiledExpressionTransition(config.From, config.To, expr), nil
+}
+
+// isUnconditional reports whether a transition condition always holds.
+func isUnconditional(condition string) bool {
+	return condition == "" || condition == "always"
 }
@@ -0,0 +1,175 @@
+package statemachine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	amperrors "github.com/amp-labs/amp-common/errors"
+	"github.com/amp-labs/amp-common/utils"
+)
+
+// Error kinds recorded in the history when a failure is routed to an OnError state.
+const (
+	ErrorKindError   = "error"
+	ErrorKindTimeout = "timeout"
+	ErrorKindPanic   = "panic"
+)
+
+// StateErrorKey returns the context data key under which the error message of
+// a failed state is stored when the failure is routed to its OnError state.
+func StateErrorKey(stateName string) string {
+	return stateName + "_error"
+}
+
+// ErrorKind classifies a state failure as ErrorKindTimeout, ErrorKindPanic or ErrorKindError.
+func ErrorKind(err error) string {
+	switch {
+	case errors.Is(err, ErrTimeout):
+		return ErrorKindTimeout
+	case errors.Is(err, amperrors.ErrPanicRecovery):
+		return ErrorKindPanic
+	default:
+		return ErrorKindError
+	}
+}
+
+// SetStateTimeout sets the maximum duration for executing a single state,
+// overriding the engine-wide action timeout. A timeout of 0 removes the override.
+// The timeout is cooperative: the state's context is cancelled when it
+// expires, and a state that ignores cancellation runs to completion.
+func (e *Engine) SetStateTimeout(state string, timeout time.Duration) {
+	if e.stateTimeouts == nil {
+		e.stateTimeouts = make(map[string]time.Duration)
+	}
+
+	if timeout <= 0 {
+		delete(e.stateTimeouts, state)
+
+		return
+	}
+
+	e.stateTimeouts[state] = timeout
+}
+
+// SetErrorHandler routes failures of state (errors, timeouts and panics) to
+// the target state instead of aborting the execution. An empty target removes the route.
+func (e *Engine) SetErrorHandler(state, target string) {
+	if e.errorHandlers == nil {
+		e.errorHandlers = make(map[string]string)
+	}
+
+	if target == "" {
+		delete(e.errorHandlers, state)
+
+		return
+	}
+
+	e.errorHandlers[state] = target
+}
+
+// timeoutFor returns the timeout that applies to a state, or 0 for none.
+func (e *Engine) timeoutFor(state string) time.Duration {
+	if timeout, ok := e.stateTimeouts[state]; ok {
+		return timeout
+	}
+
+	return e.actionTimeout
+}
+
+// runState executes a state synchronously under execCtx, which carries the
+// state's deadline, and reports a failure caused by that deadline as a
+// timeout. The timeout is cooperative: a state that ignores cancellation runs
+// to completion. ctx is the caller's context, used to tell the state's
+// deadline apart from the cancellation of the whole execution. Panics are
+// converted into errors only when recoverPanics is set, so that they can be routed
+// to an OnError state; otherwise they propagate as before.
+func runState(
+	ctx, execCtx context.Context,
+	state State,
+	smCtx *Context,
+	timeout time.Duration,
+	recoverPanics bool,
+) (TransitionResult, error) {
+	var (
+		result TransitionResult
+		err    error
+	)
+
+	if recoverPanics {
+		result, err = executeRecovered(execCtx, state, smCtx)
+	} else {
+		result, err = state.Execute(execCtx, smCtx)
+	}
+
+	if err != nil && timeout > 0 && ctx.Err() == nil && errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+		return result, fmt.Errorf("%w after %s: %w", ErrTimeout, timeout, err)
+	}
+
+	return result, err
+}
+
+// executeRecovered executes a state and turns a panic into an error.
+func executeRecovered(ctx context.Context, state State, smCtx *Context) (result TransitionResult, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = utils.GetPanicRecoveryError(recovered, debug.Stack())
+		}
+	}()
+
+	return state.Execute(ctx, smCtx)
+}
+
+// routeError sends a failed state to its OnError state, if it has one. The
+// failure is recorded in the history and in the context data under
+// StateErrorKey. Failures caused by cancellation of the execution itself are
+// never routed. It reports whether the error was handled.
+func (e *Engine) routeError(ctx context.Context, smCtx *Context, stateErr error) (bool, error) {
+	from := smCtx.CurrentState
+
+	target, ok := e.errorHandlers[from]
+	if !ok || ctx.Err() != nil {
+		return false, nil
+	}
+
+	kind := ErrorKind(stateErr)
+
+	smCtx.Set(StateErrorKey(from), stateErr.Error())
+	smCtx.AddTransition(from, target, map[string]any{
+		"error":     stateErr.Error(),
+		"errorKind": kind,
+	})
+
+	if e.logger != nil {
+		e.logger.TransitionExecuted(ctx, from, target)
+	}
+
+	transitionTotal.WithLabelValues(
+		sanitizeTool(smCtx.ToolName),
+		from,
+		target,
+		sanitizeProvider(smCtx.Provider),
+		sanitizeProjectID(smCtx.ProjectID),
+		sanitizeChunkID(smCtx.ContextChunkID),
+	).Inc()
+
+	smCtx.CurrentState = target
+
+	return true, e.saveCheckpoint(ctx, smCtx, false)
+}
+
+// parseStateTimeout parses the Timeout of a state config. Empty means no timeout.
+func parseStateTimeout(config StateConfig) (time.Duration, error) {
+	if config.Timeout == "" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(config.Timeout)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidTimeout, config.Timeout)
+	}
+
+	return timeout, nil
+}
@@ -0,0 +1,191 @@
+package statemachine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// behaviorAction fails, panics or blocks depending on its "behavior" parameter.
+type behaviorAction struct {
+	BaseAction
+
+	behavior string
+}
+
+func (a *behaviorAction) Execute(ctx context.Context, smCtx *Context) error {
+	switch a.behavior {
+	case "fail":
+		return ErrTestTemporary
+	case "panic":
+		panic("boom")
+	case "block":
+		<-ctx.Done()
+
+		return ctx.Err()
+	case "ignore":
+		// Ignores cancellation entirely
+		time.Sleep(time.Second)
+	}
+
+	return nil
+}
+
+func newErrorHandlingFactory() *ActionFactory {
+	factory := NewActionFactory()
+	factory.Register("behave", func(_ *ActionFactory, name string, params map[string]any) (Action, error) {
+		behavior, _ := params["behavior"].(string)
+
+		return &behaviorAction{BaseAction: BaseAction{name: name}, behavior: behavior}, nil
+	})
+
+	return factory
+}
+
+func newErrorHandlingConfig(behavior, timeout string) *Config {
+	return &Config{
+		Name:         "error_handling",
+		InitialState: "work",
+		FinalStates:  []string{"done", "recovered"},
+		States: []StateConfig{
+			{
+				Name:    "work",
+				Type:    "action",
+				Actions: []ActionConfig{{Type: "behave", Name: "work", Parameters: map[string]any{"behavior": behavior}}},
+				OnError: "recover",
+				Timeout: timeout,
+			},
+			{Name: "recover", Type: "action", Actions: []ActionConfig{{Type: "behave", Name: "recover"}}},
+			{Name: "done", Type: "final"},
+			{Name: "recovered", Type: "final"},
+		},
+		Transitions: []TransitionConfig{
+			{From: "work", To: "done"},
+			{From: "recover", To: "recovered"},
+		},
+	}
+}
+
+func TestEngineRoutesFailuresToOnError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		behavior string
+		timeout  string
+		kind     string
+	}{
+		{behavior: "fail", kind: ErrorKindError},
+		{behavior: "panic", kind: ErrorKindPanic},
+		{behavior: "panic", timeout: "1s", kind: ErrorKindPanic},
+		{behavior: "block", timeout: "20ms", kind: ErrorKindTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.behavior+"/"+tt.timeout, func(t *testing.T) {
+			t.Parallel()
+
+			engine, err := NewEngine(newErrorHandlingConfig(tt.behavior, tt.timeout), newErrorHandlingFactory())
+			require.NoError(t, err)
+
+			smCtx := NewContext("session", "project")
+			require.NoError(t, engine.Execute(context.Background(), smCtx))
+
+			assert.Equal(t, "recovered", smCtx.CurrentState)
+			assert.Equal(t, []string{"work", "recover", "recovered"}, smCtx.PathHistory)
+
+			require.Len(t, smCtx.History, 2)
+			assert.Equal(t, "work", smCtx.History[0].From)
+			assert.Equal(t, "recover", smCtx.History[0].To)
+			assert.Equal(t, tt.kind, smCtx.History[0].Data["errorKind"])
+			assert.NotEmpty(t, smCtx.History[0].Data["error"])
+
+			message, ok := smCtx.GetString(StateErrorKey("work"))
+			assert.True(t, ok)
+			assert.NotEmpty(t, message)
+		})
+	}
+}
+
+func TestEngineStateTimeoutWithoutOnError(t *testing.T) {
+	t.Parallel()
+
+	config := newErrorHandlingConfig("block", "20ms")
+	config.States[0].OnError = ""
+
+	engine, err := NewEngine(config, newErrorHandlingFactory())
+	require.NoError(t, err)
+
+	err = engine.Execute(context.Background(), NewContext("session", "project"))
+	require.ErrorIs(t, err, ErrTimeout)
+
+	var stateErr *StateError
+
+	require.ErrorAs(t, err, &stateErr)
+	assert.Equal(t, "work", stateErr.State)
+}
+
+func TestEngineStateTimeoutIsCooperative(t *testing.T) {
+	t.Parallel()
+
+	engine, err := NewEngine(newErrorHandlingConfig("ignore", "20ms"), newErrorHandlingFactory())
+	require.NoError(t, err)
+
+	smCtx := NewContext("session", "project")
+	require.NoError(t, engine.Execute(context.Background(), smCtx))
+
+	// The state ignored its deadline but succeeded, so it wasn't abandoned
+	assert.Equal(t, "done", smCtx.CurrentState)
+	assert.Equal(t, []string{"work", "done"}, smCtx.PathHistory)
+}
+
+func TestEnginePanicWithoutOnErrorPropagates(t *testing.T) {
+	t.Parallel()
+
+	config := newErrorHandlingConfig("panic", "")
+	config.States[0].OnError = ""
+
+	engine, err := NewEngine(config, newErrorHandlingFactory())
+	require.NoError(t, err)
+
+	assert.Panics(t, func() {
+		_ = engine.Execute(context.Background(), NewContext("session", "project"))
+	})
+}
+
+func TestEngineDoesNotRouteCancellation(t *testing.T) {
+	t.Parallel()
+
+	engine, err := NewEngine(newErrorHandlingConfig("block", ""), newErrorHandlingFactory())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	smCtx := NewContext("session", "project")
+
+	err = engine.Execute(ctx, smCtx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, smCtx.History)
+}
+
+func TestConfigValidateStateTimeoutAndOnError(t *testing.T) {
+	t.Parallel()
+
+	config := newErrorHandlingConfig("fail", "soon")
+	require.ErrorIs(t, config.Validate(), ErrInvalidTimeout)
+
+	config = newErrorHandlingConfig("fail", "-1s")
+	require.ErrorIs(t, config.Validate(), ErrInvalidTimeout)
+
+	config = newErrorHandlingConfig("fail", "")
+	config.States[0].OnError = "nowhere"
+	require.ErrorIs(t, config.Validate(), ErrOnErrorStateNotFound)
+
+	// A state only entered through onError is reachable
+	config = newErrorHandlingConfig("fail", "5s")
+	require.NoError(t, config.Validate())
+	assert.Equal(t, []TransitionConfig{{From: "work", To: "recover"}}, config.ErrorTransitions())
+}
@@ -115,6 +115,57 @@ var (
 	ErrInvalidExpression = errors.New("invalid expression")
 	// ErrUnsupportedExpression indicates that an expression is unsupported.
 	ErrUnsupportedExpression = errors.New("unsupported expression")
+	// ErrExpressionTypeMismatch indicates that an expression operator was applied to values of the wrong type.
+	ErrExpressionTypeMismatch = errors.New("expression type mismatch")
+
+	// ErrSubMachineRequired indicates that a composite state declares no sub-machine.
+	ErrSubMachineRequired = errors.New("composite state requires subMachine or subMachineRef")
+	// ErrAmbiguousSubMachine indicates that a composite state declares both an inline and a referenced sub-machine.
+	ErrAmbiguousSubMachine = errors.New("composite state must not set both subMachine and subMachineRef")
+	// ErrSubMachineFinalStateNotFound indicates that onExit refers to a state that is not a final state of the sub-machine.
+	ErrSubMachineFinalStateNotFound = errors.New("sub-machine final state does not exist")
+	// ErrRecursiveSubMachine indicates that a sub-machine (directly or indirectly) contains itself.
+	ErrRecursiveSubMachine = errors.New("recursive sub-machine")
+
+	// ErrSuspended indicates that execution paused in a wait state; see Suspended.
+	ErrSuspended = errors.New("execution suspended")
+	// ErrNotSuspended indicates that a signal was sent to an execution that is not waiting.
+	ErrNotSuspended = errors.New("execution is not suspended")
+	// ErrUnexpectedEvent indicates that a signal carried a different event than the one awaited.
+	ErrUnexpectedEvent = errors.New("unexpected event")
+	// ErrWaitExpired indicates that a signal arrived after the wait state's deadline.
+	ErrWaitExpired = errors.New("wait expired")
+	// ErrExecutionCompleted indicates that the execution has already reached a final state.
+	ErrExecutionCompleted = errors.New("execution already completed")
+	// ErrAwaitEventRequired indicates that a wait state does not declare the event it awaits.
+	ErrAwaitEventRequired = errors.New("wait state requires await.event")
+	// ErrOnTimeoutRequired indicates that a wait state with a timeout has no timeout transition.
+	ErrOnTimeoutRequired = errors.New("wait state with a timeout requires await.onTimeout")
+	// ErrInvalidTimeout indicates that a timeout is not a valid positive duration.
+	ErrInvalidTimeout = errors.New("invalid timeout")
+	// ErrOnErrorStateNotFound indicates that a state's onError target does not exist.
+	ErrOnErrorStateNotFound = errors.New("onError state does not exist")
+	// ErrWaitInSubMachine indicates that a nested state machine tried to suspend, which is not supported.
+	ErrWaitInSubMachine = errors.New("wait states are not supported in sub-machines")
+
+	// ErrCheckpointNotFound indicates that no checkpoint exists for a session.
+	ErrCheckpointNotFound = errors.New("checkpoint not found")
+	// ErrCheckpointStoreRequired indicates that an operation needs a checkpoint store but none is configured.
+	ErrCheckpointStoreRequired = errors.New("checkpoint store is required")
+	// ErrListingNotSupported indicates that the checkpoint store does not implement SuspendedLister.
+	ErrListingNotSupported = errors.New("checkpoint store cannot list suspended executions")
+	// ErrCheckpointFailed indicates that the engine could not persist a checkpoint.
+	ErrCheckpointFailed = errors.New("failed to save checkpoint")
+	// ErrCheckpointConflict indicates that a checkpoint was saved for the session by someone else
+	// since it was loaded, for instance by a concurrent Signal or Resume of the same execution.
+	ErrCheckpointConflict = errors.New("checkpoint was modified concurrently")
+
+	// ErrReplayedFailure is returned by a replayed state whose recorded execution failed.
+	ErrReplayedFailure = errors.New("replayed state failure")
+	// ErrEmptyTrace indicates that a trace has no steps to replay.
+	ErrEmptyTrace = errors.New("trace has no steps")
+	// ErrTraceStepOutOfRange indicates that a step index is outside of a trace.
+	ErrTraceStepOutOfRange = errors.New("trace step out of range")
 
 	// ErrTestActionFailed is used in test files to indicate that an action failed.
 	ErrTestActionFailed = errors.New("action failed")
@@ -0,0 +1,543 @@
+package statemachine
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Expression is a compiled transition condition.
+//
+// Expressions are parsed and type-checked once by CompileExpression and can then
+// be evaluated any number of times against a Context. The language supports:
+//
+//   - Literals: numbers (3, 2.5), strings ('a' or "a"), true, false, null and lists (['a', 'b'])
+//   - Boolean operators: &&, ||, !
+//   - Comparisons: ==, !=, <, <=, >, >=
+//   - Membership: x in ['a', 'b'], 'key' in data.someMap, 'sub' in data.someString
+//   - Member and index access: data.account.region, pathHistory[0], history[0].from, data['key']
+//   - Built-in functions: len(x)
+//
+// The following root identifiers are available:
+//
+//   - data:         Context.Data
+//   - metadata:     Context.Metadata
+//   - history:      Context.History (entries expose from, to, timestamp and data)
+//   - pathHistory:  Context.PathHistory
+//   - currentState, provider, toolName, sessionId, projectId: the matching Context fields
+//
+// Missing keys evaluate to null rather than failing, so "data.retries < 3" is
+// simply false when retries has not been set. Only boolean true is truthy.
+type Expression struct {
+	source string
+	eval   evalFunc
+}
+
+// evalFunc evaluates a compiled expression node. The caller must hold a read
+// lock on the Context for the duration of the call.
+type evalFunc func(smCtx *Context) (any, error)
+
+// expressionRoots maps root identifiers to accessors on the Context.
+var expressionRoots = map[string]func(smCtx *Context) any{
+	"data":         func(smCtx *Context) any { return smCtx.Data },
+	"metadata":     func(smCtx *Context) any { return smCtx.Metadata },
+	"history":      func(smCtx *Context) any { return smCtx.History },
+	"pathHistory":  func(smCtx *Context) any { return smCtx.PathHistory },
+	"currentState": func(smCtx *Context) any { return smCtx.CurrentState },
+	"provider":     func(smCtx *Context) any { return smCtx.Provider },
+	"toolName":     func(smCtx *Context) any { return smCtx.ToolName },
+	"sessionId":    func(smCtx *Context) any { return smCtx.SessionID },
+	"projectId":    func(smCtx *Context) any { return smCtx.ProjectID },
+}
+
+// expressionFunctions maps built-in function names to their arity and implementation.
+var expressionFunctions = map[string]struct {
+	arity int
+	fn    func(args []any) (any, error)
+}{
+	"len": {arity: 1, fn: builtinLen},
+}
+
+// CompileExpression parses and compiles an expression. Syntax errors are
+// reported as ErrInvalidExpression, and references to unknown identifiers or
+// functions as ErrUnsupportedExpression.
+func CompileExpression(source string) (*Expression, error) {
+	node, err := parseExpression(source)
+	if err != nil {
+		return nil, fmt.Errorf("%w (in %q)", err, source)
+	}
+
+	eval, err := compileNode(node)
+	if err != nil {
+		return nil, fmt.Errorf("%w (in %q)", err, source)
+	}
+
+	return &Expression{source: source, eval: eval}, nil
+}
+
+// String returns the source text of the expression.
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Evaluate evaluates the expression against the context and reports whether it holds.
+func (e *Expression) Evaluate(smCtx *Context) (bool, error) {
+	value, err := e.Value(smCtx)
+	if err != nil {
+		return false, err
+	}
+
+	return isTruthy(value), nil
+}
+
+// Value evaluates the expression against the context and returns the raw result.
+func (e *Expression) Value(smCtx *Context) (any, error) {
+	smCtx.mu.RLock()
+	defer smCtx.mu.RUnlock()
+
+	value, err := e.eval(smCtx)
+	if err != nil {
+		return nil, fmt.Errorf("evaluating %q: %w", e.source, err)
+	}
+
+	return value, nil
+}
+
+// compileNode turns a parsed node into an evaluation closure, resolving
+// identifiers and functions up front so that typos fail at compile time.
+func compileNode(node exprNode) (evalFunc, error) {
+	switch node := node.(type) {
+	case literalNode:
+		value := node.value
+
+		return func(*Context) (any, error) { return value, nil }, nil
+
+	case listNode:
+		return compileList(node)
+
+	case identNode:
+		root, ok := expressionRoots[node.name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown identifier %q at offset %d", ErrUnsupportedExpression, node.name, node.pos)
+		}
+
+		return func(smCtx *Context) (any, error) { return root(smCtx), nil }, nil
+
+	case memberNode:
+		target, err := compileNode(node.target)
+		if err != nil {
+			return nil, err
+		}
+
+		name := node.name
+
+		return func(smCtx *Context) (any, error) {
+			value, err := target(smCtx)
+			if err != nil {
+				return nil, err
+			}
+
+			return lookupMember(value, name), nil
+		}, nil
+
+	case indexNode:
+		return compileIndex(node)
+
+	case callNode:
+		return compileCall(node)
+
+	case unaryNode:
+		return compileUnary(node)
+
+	case binaryNode:
+		return compileBinary(node)
+
+	default:
+		return nil, fmt.Errorf("%w: unknown node %T", ErrInvalidExpression, node)
+	}
+}
+
+func compileList(node listNode) (evalFunc, error) {
+	items := make([]evalFunc, len(node.items))
+
+	for idx, item := range node.items {
+		eval, err := compileNode(item)
+		if err != nil {
+			return nil, err
+		}
+
+		items[idx] = eval
+	}
+
+	return func(smCtx *Context) (any, error) {
+		values := make([]any, len(items))
+
+		for idx, item := range items {
+			value, err := item(smCtx)
+			if err != nil {
+				return nil, err
+			}
+
+			values[idx] = value
+		}
+
+		return values, nil
+	}, nil
+}
+
+func compileIndex(node indexNode) (evalFunc, error) {
+	target, err := compileNode(node.target)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := compileNode(node.index)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(smCtx *Context) (any, error) {
+		value, err := target(smCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := index(smCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		if name, ok := key.(string); ok {
+			return lookupMember(value, name), nil
+		}
+
+		pos, ok := toNumber(key)
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot index with %T", ErrExpressionTypeMismatch, key)
+		}
+
+		return lookupIndex(value, int(pos)), nil
+	}, nil
+}
+
+func compileCall(node callNode) (evalFunc, error) {
+	builtin, ok := expressionFunctions[node.name]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown function %q at offset %d", ErrUnsupportedExpression, node.name, node.pos)
+	}
+
+	if len(node.args) != builtin.arity {
+		return nil, fmt.Errorf("%w: %s expects %d argument(s), got %d",
+			ErrInvalidExpression, node.name, builtin.arity, len(node.args))
+	}
+
+	args, err := compileList(listNode{items: node.args})
+	if err != nil {
+		return nil, err
+	}
+
+	return func(smCtx *Context) (any, error) {
+		values, err := args(smCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		return builtin.fn(values.([]any)) //nolint:forcetypeassert // compileList always returns []any
+	}, nil
+}
+
+func compileUnary(node unaryNode) (evalFunc, error) {
+	operand, err := compileNode(node.operand)
+	if err != nil {
+		return nil, err
+	}
+
+	if node.op == "!" {
+		return func(smCtx *Context) (any, error) {
+			value, err := operand(smCtx)
+			if err != nil {
+				return nil, err
+			}
+
+			return !isTruthy(value), nil
+		}, nil
+	}
+
+	return func(smCtx *Context) (any, error) {
+		value, err := operand(smCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		num, ok := toNumber(value)
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot negate %T", ErrExpressionTypeMismatch, value)
+		}
+
+		return -num, nil
+	}, nil
+}
+
+func compileBinary(node binaryNode) (evalFunc, error) {
+	left, err := compileNode(node.left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := compileNode(node.right)
+	if err != nil {
+		return nil, err
+	}
+
+	// Boolean operators short-circuit, so they can't share the generic path below.
+	switch node.op {
+	case "&&":
+		return func(smCtx *Context) (any, error) {
+			value, err := left(smCtx)
+			if err != nil || !isTruthy(value) {
+				return false, err
+			}
+
+			value, err = right(smCtx)
+
+			return isTruthy(value), err
+		}, nil
+
+	case "||":
+		return func(smCtx *Context) (any, error) {
+			value, err := left(smCtx)
+			if err != nil || isTruthy(value) {
+				return err == nil, err
+			}
+
+			value, err = right(smCtx)
+
+			return isTruthy(value), err
+		}, nil
+	}
+
+	apply := binaryOperators[node.op]
+
+	return func(smCtx *Context) (any, error) {
+		lhs, err := left(smCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		rhs, err := right(smCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		return apply(lhs, rhs)
+	}, nil
+}
+
+// binaryOperators implements the non-short-circuiting binary operators.
+var binaryOperators = map[string]func(lhs, rhs any) (any, error){
+	"==": func(lhs, rhs any) (any, error) { return valuesEqual(lhs, rhs), nil },
+	"!=": func(lhs, rhs any) (any, error) { return !valuesEqual(lhs, rhs), nil },
+	"<":  func(lhs, rhs any) (any, error) { return compareValues(lhs, rhs, func(c int) bool { return c < 0 }) },
+	"<=": func(lhs, rhs any) (any, error) { return compareValues(lhs, rhs, func(c int) bool { return c <= 0 }) },
+	">":  func(lhs, rhs any) (any, error) { return compareValues(lhs, rhs, func(c int) bool { return c > 0 }) },
+	">=": func(lhs, rhs any) (any, error) { return compareValues(lhs, rhs, func(c int) bool { return c >= 0 }) },
+	"in": func(lhs, rhs any) (any, error) { return containsValue(rhs, lhs) },
+}
+
+// isTruthy reports whether a value counts as true. Only boolean true does;
+// missing values and non-boolean values are false.
+func isTruthy(value any) bool {
+	b, ok := value.(bool)
+
+	return ok && b
+}
+
+// valuesEqual compares two values. Numbers compare numerically regardless of
+// their Go type; mismatched types fall back to comparing their string forms so
+// that "data.count == '3'" keeps working when count is an int.
+func valuesEqual(lhs, rhs any) bool {
+	if lhs == nil || rhs == nil {
+		return lhs == nil && rhs == nil
+	}
+
+	lnum, lok := toNumber(lhs)
+	rnum, rok := toNumber(rhs)
+
+	if lok && rok {
+		return lnum == rnum
+	}
+
+	switch lv := lhs.(type) {
+	case string:
+		if rv, ok := rhs.(string); ok {
+			return lv == rv
+		}
+	case bool:
+		if rv, ok := rhs.(bool); ok {
+			return lv == rv
+		}
+	}
+
+	return fmt.Sprintf("%v", lhs) == fmt.Sprintf("%v", rhs)
+}
+
+// compareValues orders two numbers or two strings. Comparisons involving a
+// missing value are false.
+func compareValues(lhs, rhs any, accept func(int) bool) (any, error) {
+	if lhs == nil || rhs == nil {
+		return false, nil
+	}
+
+	lnum, lok := toNumber(lhs)
+	rnum, rok := toNumber(rhs)
+
+	if lok && rok {
+		switch {
+		case lnum < rnum:
+			return accept(-1), nil
+		case lnum > rnum:
+			return accept(1), nil
+		default:
+			return accept(0), nil
+		}
+	}
+
+	lstr, lok := lhs.(string)
+	rstr, rok := rhs.(string)
+
+	if lok && rok {
+		return accept(strings.Compare(lstr, rstr)), nil
+	}
+
+	return nil, fmt.Errorf("%w: cannot compare %T with %T", ErrExpressionTypeMismatch, lhs, rhs)
+}
+
+// containsValue implements "needle in haystack" for lists, maps and strings.
+func containsValue(haystack, needle any) (any, error) {
+	if haystack == nil {
+		return false, nil
+	}
+
+	if str, ok := haystack.(string); ok {
+		sub, ok := needle.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot search string for %T", ErrExpressionTypeMismatch, needle)
+		}
+
+		return strings.Contains(str, sub), nil
+	}
+
+	rv := reflect.ValueOf(haystack)
+
+	switch rv.Kind() { //nolint:exhaustive // Only collections support membership
+	case reflect.Slice, reflect.Array:
+		for idx := range rv.Len() {
+			if valuesEqual(rv.Index(idx).Interface(), needle) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+
+	case reflect.Map:
+		return lookupMember(haystack, fmt.Sprintf("%v", needle)) != nil, nil
+
+	default:
+		return nil, fmt.Errorf("%w: cannot search %T", ErrExpressionTypeMismatch, haystack)
+	}
+}
+
+// lookupMember returns a named field of a map or history entry, or nil if it does not exist.
+func lookupMember(value any, name string) any {
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		return value[name]
+	case StateTransition:
+		return transitionField(value, name)
+	case *StateTransition:
+		return transitionField(*value, name)
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+
+	result := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+	if !result.IsValid() {
+		return nil
+	}
+
+	return result.Interface()
+}
+
+// lookupIndex returns the element at pos of a list, or nil if out of range.
+// Negative positions count from the end, so pathHistory[-1] is the latest state.
+func lookupIndex(value any, pos int) any {
+	if value == nil {
+		return nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil
+	}
+
+	if pos < 0 {
+		pos += rv.Len()
+	}
+
+	if pos < 0 || pos >= rv.Len() {
+		return nil
+	}
+
+	return rv.Index(pos).Interface()
+}
+
+func transitionField(transition StateTransition, name string) any {
+	switch name {
+	case "from":
+		return transition.From
+	case "to":
+		return transition.To
+	case "timestamp":
+		return transition.Timestamp
+	case "data":
+		return transition.Data
+	default:
+		return nil
+	}
+}
+
+// toNumber converts any Go numeric type to float64.
+func toNumber(value any) (float64, bool) {
+	rv := reflect.ValueOf(value)
+
+	switch rv.Kind() { //nolint:exhaustive // Only numeric kinds convert
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func builtinLen(args []any) (any, error) {
+	if args[0] == nil {
+		return 0.0, nil
+	}
+
+	rv := reflect.ValueOf(args[0])
+
+	switch rv.Kind() { //nolint:exhaustive // Only sized kinds have a length
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return float64(rv.Len()), nil
+	default:
+		return nil, fmt.Errorf("%w: len of %T", ErrExpressionTypeMismatch, args[0])
+	}
+}
@@ -0,0 +1,508 @@
+package statemachine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind classifies a lexical token in a transition expression.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+// token is a single lexical unit produced by the expression lexer.
+type token struct {
+	kind tokenKind
+	text string // raw operator/identifier text, or the unquoted string literal
+	num  float64
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return strconv.Quote(t.text)
+	case tokenNumber, tokenIdent, tokenOperator:
+		return t.text
+	default:
+		return t.text
+	}
+}
+
+// twoCharOperators lists operators that span two characters. They are matched
+// before single-character operators so that "<=" is not lexed as "<" "=".
+var twoCharOperators = []string{"&&", "||", "==", "!=", "<=", ">="}
+
+// singleCharOperators lists all operators and punctuation of a single character.
+const singleCharOperators = "!<>-()[].,"
+
+// tokenize splits an expression into tokens.
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+
+	pos := 0
+	for pos < len(src) {
+		r, width := utf8.DecodeRuneInString(src[pos:])
+
+		switch {
+		case unicode.IsSpace(r):
+			pos += width
+
+		case r == '\'' || r == '"':
+			str, end, err := lexString(src, pos)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, token{kind: tokenString, text: str, pos: pos})
+			pos = end
+
+		case r >= '0' && r <= '9':
+			end := pos
+			for end < len(src) && (isDigit(src[end]) || src[end] == '.') {
+				end++
+			}
+
+			num, err := strconv.ParseFloat(src[pos:end], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid number %q at offset %d", ErrInvalidExpression, src[pos:end], pos)
+			}
+
+			tokens = append(tokens, token{kind: tokenNumber, text: src[pos:end], num: num, pos: pos})
+			pos = end
+
+		case r == '_' || unicode.IsLetter(r):
+			end := pos
+			for end < len(src) {
+				next, w := utf8.DecodeRuneInString(src[end:])
+				if next != '_' && !unicode.IsLetter(next) && !unicode.IsDigit(next) {
+					break
+				}
+
+				end += w
+			}
+
+			tokens = append(tokens, token{kind: tokenIdent, text: src[pos:end], pos: pos})
+			pos = end
+
+		default:
+			op := matchOperator(src[pos:])
+			if op == "" {
+				return nil, fmt.Errorf("%w: unexpected character %q at offset %d", ErrInvalidExpression, r, pos)
+			}
+
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: pos})
+			pos += len(op)
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(src)})
+
+	return tokens, nil
+}
+
+// lexString reads a quoted string literal starting at pos and returns its
+// unescaped value along with the offset just past the closing quote.
+func lexString(src string, pos int) (string, int, error) {
+	quote := src[pos]
+
+	var builder strings.Builder
+
+	for idx := pos + 1; idx < len(src); idx++ {
+		char := src[idx]
+
+		switch {
+		case char == quote:
+			return builder.String(), idx + 1, nil
+
+		case char == '\\' && idx+1 < len(src):
+			idx++
+
+			switch src[idx] {
+			case 'n':
+				builder.WriteByte('\n')
+			case 't':
+				builder.WriteByte('\t')
+			default:
+				builder.WriteByte(src[idx])
+			}
+
+		default:
+			builder.WriteByte(char)
+		}
+	}
+
+	return "", 0, fmt.Errorf("%w: unterminated string starting at offset %d", ErrInvalidExpression, pos)
+}
+
+func matchOperator(rest string) string {
+	for _, op := range twoCharOperators {
+		if strings.HasPrefix(rest, op) {
+			return op
+		}
+	}
+
+	if strings.IndexByte(singleCharOperators, rest[0]) >= 0 {
+		return rest[:1]
+	}
+
+	return ""
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// exprNode is a node in the parsed expression tree.
+type exprNode interface {
+	exprNode()
+}
+
+type (
+	// literalNode is a constant value (number, string, bool or nil).
+	literalNode struct {
+		value any
+	}
+
+	// listNode is a list literal such as ['a', 'b'].
+	listNode struct {
+		items []exprNode
+	}
+
+	// identNode is a reference to one of the root identifiers (data, history, ...).
+	identNode struct {
+		name string
+		pos  int
+	}
+
+	// memberNode is a field access such as data.provider.
+	memberNode struct {
+		target exprNode
+		name   string
+	}
+
+	// indexNode is an index access such as pathHistory[0] or data['key'].
+	indexNode struct {
+		target exprNode
+		index  exprNode
+	}
+
+	// callNode is a call to a built-in function such as len(pathHistory).
+	callNode struct {
+		name string
+		args []exprNode
+		pos  int
+	}
+
+	// unaryNode is a prefix operator applied to an operand.
+	unaryNode struct {
+		op      string
+		operand exprNode
+	}
+
+	// binaryNode is an infix operator applied to two operands.
+	binaryNode struct {
+		op    string
+		left  exprNode
+		right exprNode
+	}
+)
+
+func (literalNode) exprNode() {}
+func (listNode) exprNode()    {}
+func (identNode) exprNode()   {}
+func (memberNode) exprNode()  {}
+func (indexNode) exprNode()   {}
+func (callNode) exprNode()    {}
+func (unaryNode) exprNode()   {}
+func (binaryNode) exprNode()  {}
+
+// parser is a recursive-descent parser for transition expressions.
+//
+// Grammar, from lowest to highest precedence:
+//
+//	or         = and { "||" and }
+//	and        = equality { "&&" equality }
+//	equality   = relational { ("==" | "!=") relational }
+//	relational = unary { ("<" | "<=" | ">" | ">=" | "in") unary }
+//	unary      = ("!" | "-") unary | postfix
+//	postfix    = primary { "." ident | "[" or "]" }
+//	primary    = number | string | "true" | "false" | "null" | ident [ "(" args ")" ]
+//	           | "(" or ")" | "[" [ or { "," or } ] "]"
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parseExpression parses src into an expression tree.
+func parseExpression(src string) (exprNode, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	if p.peek().kind == tokenEOF {
+		return nil, fmt.Errorf("%w: empty expression", ErrInvalidExpression)
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.unexpected(tok)
+	}
+
+	return node, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+// isOperator reports whether the next token is the given operator (or keyword).
+func (p *parser) isOperator(op string) bool {
+	tok := p.peek()
+
+	return (tok.kind == tokenOperator || tok.kind == tokenIdent) && tok.text == op
+}
+
+func (p *parser) expect(op string) error {
+	if !p.isOperator(op) {
+		return fmt.Errorf("%w: expected %q but found %s at offset %d",
+			ErrInvalidExpression, op, p.peek(), p.peek().pos)
+	}
+
+	p.next()
+
+	return nil
+}
+
+func (p *parser) unexpected(tok token) error {
+	return fmt.Errorf("%w: unexpected %s at offset %d", ErrInvalidExpression, tok, tok.pos)
+}
+
+func (p *parser) parseBinary(next func() (exprNode, error), ops ...string) (exprNode, error) {
+	left, err := next()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		matched := ""
+
+		for _, op := range ops {
+			if p.isOperator(op) {
+				matched = op
+
+				break
+			}
+		}
+
+		if matched == "" {
+			return left, nil
+		}
+
+		p.next()
+
+		right, err := next()
+		if err != nil {
+			return nil, err
+		}
+
+		left = binaryNode{op: matched, left: left, right: right}
+	}
+}
+
+func (p *parser) parseOr() (exprNode, error) {
+	return p.parseBinary(p.parseAnd, "||")
+}
+
+func (p *parser) parseAnd() (exprNode, error) {
+	return p.parseBinary(p.parseEquality, "&&")
+}
+
+func (p *parser) parseEquality() (exprNode, error) {
+	return p.parseBinary(p.parseRelational, "==", "!=")
+}
+
+func (p *parser) parseRelational() (exprNode, error) {
+	return p.parseBinary(p.parseUnary, "<=", ">=", "<", ">", "in")
+}
+
+func (p *parser) parseUnary() (exprNode, error) {
+	if p.isOperator("!") || p.isOperator("-") {
+		op := p.next().text
+
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return unaryNode{op: op, operand: operand}, nil
+	}
+
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (exprNode, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case p.isOperator("."):
+			p.next()
+
+			tok := p.next()
+			if tok.kind != tokenIdent {
+				return nil, p.unexpected(tok)
+			}
+
+			node = memberNode{target: node, name: tok.text}
+
+		case p.isOperator("["):
+			p.next()
+
+			index, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+
+			node = indexNode{target: node, index: index}
+
+		default:
+			return node, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenNumber:
+		return literalNode{value: tok.num}, nil
+
+	case tokenString:
+		return literalNode{value: tok.text}, nil
+
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "null", "nil":
+			return literalNode{value: nil}, nil
+		case "in":
+			return nil, p.unexpected(tok)
+		}
+
+		if p.isOperator("(") {
+			return p.parseCall(tok)
+		}
+
+		return identNode{name: tok.text, pos: tok.pos}, nil
+
+	case tokenOperator:
+		switch tok.text {
+		case "(":
+			node, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+
+			return node, nil
+
+		case "[":
+			items, err := p.parseList("]")
+			if err != nil {
+				return nil, err
+			}
+
+			return listNode{items: items}, nil
+		}
+
+	case tokenEOF:
+	}
+
+	return nil, p.unexpected(tok)
+}
+
+func (p *parser) parseCall(name token) (exprNode, error) {
+	p.next() // consume "("
+
+	args, err := p.parseList(")")
+	if err != nil {
+		return nil, err
+	}
+
+	return callNode{name: name.text, args: args, pos: name.pos}, nil
+}
+
+// parseList parses a comma-separated list of expressions up to and including
+// the closing delimiter.
+func (p *parser) parseList(closing string) ([]exprNode, error) {
+	var items []exprNode
+
+	if p.isOperator(closing) {
+		p.next()
+
+		return items, nil
+	}
+
+	for {
+		item, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+
+		if p.isOperator(",") {
+			p.next()
+
+			continue
+		}
+
+		if err := p.expect(closing); err != nil {
+			return nil, err
+		}
+
+		return items, nil
+	}
+}
@@ -0,0 +1,208 @@
+package statemachine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newExpressionTestContext() *Context {
+	smCtx := NewContext("session-1", "project-1")
+	smCtx.Provider = "hubspot"
+	smCtx.CurrentState = "configure"
+	smCtx.Data["provider"] = "salesforce"
+	smCtx.Data["retries"] = 2
+	smCtx.Data["ratio"] = 0.75
+	smCtx.Data["enabled"] = true
+	smCtx.Data["count"] = 3
+	smCtx.Data["tags"] = []string{"crm", "beta"}
+	smCtx.Data["account"] = map[string]any{
+		"region": "us-east",
+		"limits": map[string]int{"daily": 100},
+	}
+	smCtx.AppendToPath("start")
+	smCtx.AppendToPath("configure")
+	smCtx.AddTransition("start", "configure", map[string]any{"reason": "initial"})
+
+	return smCtx
+}
+
+func TestExpressionEvaluate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr     string
+		expected bool
+	}{
+		// Forms supported by the original string-splitting evaluator
+		{"data.provider == 'salesforce'", true},
+		{`data.provider == "hubspot"`, false},
+		{"data.provider != 'hubspot'", true},
+		{"data.missing != 'x'", true},
+		{"data.missing == 'x'", false},
+		{"data.enabled", true},
+		{"!data.enabled", false},
+		{"data.missing", false},
+		{"!data.missing", true},
+		{"data.count == '3'", true},
+
+		// Comparisons and boolean operators
+		{"data.retries < 3", true},
+		{"data.retries >= 3", false},
+		{"data.ratio > 0.5 && data.ratio <= 1", true},
+		{"data.retries < 3 && data.provider in ['salesforce', 'hubspot']", true},
+		{"data.retries > 5 || data.enabled", true},
+		{"!(data.retries < 3)", false},
+		{"data.missing < 3", false},
+		{"-data.retries < 0", true},
+		{"'b' < 'c'", true},
+
+		// Membership
+		{"data.provider in ['hubspot']", false},
+		{"'crm' in data.tags", true},
+		{"'region' in data.account", true},
+		{"'force' in data.provider", true},
+		{"'x' in data.missing", false},
+
+		// Nested access
+		{"data.account.region == 'us-east'", true},
+		{"data.account.limits.daily == 100", true},
+		{"data['account']['region'] == 'us-east'", true},
+		{"data.account.missing.deeper == null", true},
+
+		// Context fields, history and path
+		{"provider == 'hubspot'", true},
+		{"currentState == 'configure'", true},
+		{"sessionId == 'session-1' && projectId == 'project-1'", true},
+		{"pathHistory[0] == 'start'", true},
+		{"pathHistory[-1] == 'configure'", true},
+		{"pathHistory[5] == null", true},
+		{"'configure' in pathHistory", true},
+		{"len(pathHistory) == 2", true},
+		{"len(history) > 0 && history[0].from == 'start'", true},
+		{"history[-1].data.reason == 'initial'", true},
+	}
+
+	smCtx := newExpressionTestContext()
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := CompileExpression(tt.expr)
+			require.NoError(t, err)
+
+			result, err := expr.Evaluate(smCtx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestCompileExpressionErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr     string
+		expected error
+	}{
+		{"", ErrInvalidExpression},
+		{"data.retries <", ErrInvalidExpression},
+		{"data.retries < 3 &&", ErrInvalidExpression},
+		{"(data.enabled", ErrInvalidExpression},
+		{"data.provider == 'unterminated", ErrInvalidExpression},
+		{"data.provider = 'x'", ErrInvalidExpression},
+		{"data. == 1", ErrInvalidExpression},
+		{"len(data.a, data.b) > 0", ErrInvalidExpression},
+		{"dta.provider == 'x'", ErrUnsupportedExpression},
+		{"size(pathHistory) > 0", ErrUnsupportedExpression},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CompileExpression(tt.expr)
+			require.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestExpressionTypeMismatch(t *testing.T) {
+	t.Parallel()
+
+	expr, err := CompileExpression("data.provider < 3")
+	require.NoError(t, err)
+
+	_, err = expr.Evaluate(newExpressionTestContext())
+	require.ErrorIs(t, err, ErrExpressionTypeMismatch)
+}
+
+func TestExpressionTransitionCompileError(t *testing.T) {
+	t.Parallel()
+
+	transition := NewExpressionTransition("a", "b", "data.x ==")
+
+	_, err := transition.Condition(context.Background(), NewContext("s", "p"))
+	require.ErrorIs(t, err, ErrInvalidExpression)
+}
+
+func TestConfigValidateRejectsInvalidCondition(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Name:         "bad_condition",
+		InitialState: "start",
+		FinalStates:  []string{"end"},
+		States: []StateConfig{
+			{Name: "start", Type: "action", Actions: []ActionConfig{{Type: "noop", Name: "noop"}}},
+			{Name: "end", Type: "final"},
+		},
+		Transitions: []TransitionConfig{
+			{From: "start", To: "end", Condition: "data.retries <> 3"},
+		},
+	}
+
+	err := config.Validate()
+	require.ErrorIs(t, err, ErrInvalidExpression)
+
+	_, err = NewEngine(config, nil)
+	require.ErrorIs(t, err, ErrInvalidExpression)
+}
+
+func TestEngineWithExpressionTransitions(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Name:         "expression_routing",
+		InitialState: "start",
+		FinalStates:  []string{"crm", "other"},
+		States: []StateConfig{
+			{Name: "start", Type: "action", Actions: []ActionConfig{{Type: "noop", Name: "noop"}}},
+			{Name: "crm", Type: "final"},
+			{Name: "other", Type: "final"},
+		},
+		Transitions: []TransitionConfig{
+			{From: "start", To: "crm", Condition: "data.retries < 3 && data.provider in ['salesforce', 'hubspot']"},
+			{From: "start", To: "other", Condition: "always"},
+		},
+	}
+
+	engine, err := NewEngine(config, nil)
+	require.NoError(t, err)
+
+	smCtx := NewContext("session", "project")
+	smCtx.Set("provider", "hubspot")
+	smCtx.Set("retries", 1)
+
+	require.NoError(t, engine.Execute(context.Background(), smCtx))
+	assert.Equal(t, "crm", smCtx.CurrentState)
+
+	smCtx = NewContext("session", "project")
+	smCtx.Set("provider", "zendesk")
+
+	require.NoError(t, engine.Execute(context.Background(), smCtx))
+	assert.Equal(t, "other", smCtx.CurrentState)
+}
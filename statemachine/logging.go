@@ -108,18 +108,14 @@ func (l *DefaultLogger) StateExited(ctx context.Context, state string, duration
 			"chunk_id", smCtx.ContextChunkID,
 			"tool", smCtx.ToolName,
 			"path_history", smCtx.PathHistory,
-			"outcome", func() string {
-				if err != nil {
-					return "error"
-				}
-
-				return "success"
-			}(),
+			"outcome", executionOutcome(err),
 		)
 	}
 
 	// Use logger.Get(ctx) for automatic trace correlation
-	if err != nil {
+	if suspended, ok := AsSuspended(err); ok {
+		logger.Get(ctx).InfoContext(ctx, "State suspended", append(fields, "awaiting", suspended.Event)...)
+	} else if err != nil {
 		logger.Get(ctx).ErrorContext(ctx, "State exited with error", append(fields, "error", err)...)
 	} else {
 		logger.Get(ctx).InfoContext(ctx, "State exited", fields...)
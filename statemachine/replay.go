@@ -0,0 +1,539 @@
+package statemachine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Trace is a recorded execution: the states that were entered and what each
+// of them did to the context data. Traces are JSON-serializable so that
+// production executions can be stored and replayed against new configs.
+type Trace struct {
+	SessionID   string         `json:"sessionId"`
+	ProjectID   string         `json:"projectId"`
+	Provider    string         `json:"provider,omitempty"`
+	ToolName    string         `json:"toolName,omitempty"`
+	InitialData map[string]any `json:"initialData"`
+	Steps       []TraceStep    `json:"steps"`
+}
+
+// TraceStep records the execution of a single state.
+type TraceStep struct {
+	State     string         `json:"state"`
+	StartedAt time.Time      `json:"startedAt"`
+	Duration  time.Duration  `json:"duration"`
+	Error     string         `json:"error,omitempty"`
+	Suspended bool           `json:"suspended,omitempty"`
+	Output    map[string]any `json:"output,omitempty"`  // keys the step set or changed
+	Removed   []string       `json:"removed,omitempty"` // keys the step deleted
+	Data      map[string]any `json:"data"`              // snapshot of the data after the step
+}
+
+// Path returns the states entered in the trace, in order.
+func (t *Trace) Path() []string {
+	path := make([]string, len(t.Steps))
+	for i, step := range t.Steps {
+		path[i] = step.State
+	}
+
+	return path
+}
+
+// At reconstructs the context as it was right after the given step, so that
+// an execution can be inspected at any point in time. Step -1 is the context
+// before the first state ran.
+func (t *Trace) At(step int) (*Context, error) {
+	if len(t.Steps) == 0 {
+		return nil, ErrEmptyTrace
+	}
+
+	if step < -1 || step >= len(t.Steps) {
+		return nil, fmt.Errorf("%w: %d", ErrTraceStepOutOfRange, step)
+	}
+
+	smCtx := t.newContext()
+
+	for i := 0; i <= step; i++ {
+		applyStepOutput(smCtx, t.Steps[i])
+
+		smCtx.AppendToPath(t.Steps[i].State)
+
+		if i+1 < len(t.Steps) {
+			smCtx.AddTransition(t.Steps[i].State, t.Steps[i+1].State, nil)
+		}
+	}
+
+	// The state about to run, or the last state once the trace is over
+	if step+1 < len(t.Steps) {
+		smCtx.CurrentState = t.Steps[step+1].State
+	} else {
+		smCtx.CurrentState = t.Steps[step].State
+	}
+
+	return smCtx, nil
+}
+
+// newContext creates a context carrying the trace's identity and initial data.
+func (t *Trace) newContext() *Context {
+	smCtx := NewContext(t.SessionID, t.ProjectID)
+	smCtx.Provider = t.Provider
+	smCtx.ToolName = t.ToolName
+	maps.Copy(smCtx.Data, t.InitialData)
+
+	return smCtx
+}
+
+// applyStepOutput replays the data changes of a step onto a context.
+func applyStepOutput(smCtx *Context, step TraceStep) {
+	smCtx.mu.Lock()
+	defer smCtx.mu.Unlock()
+
+	maps.Copy(smCtx.Data, step.Output)
+
+	for _, key := range step.Removed {
+		delete(smCtx.Data, key)
+	}
+
+	smCtx.UpdatedAt = time.Now()
+}
+
+// Recorder records executions into a Trace. Register its Hook with
+// Engine.AddExecutionHook before calling Execute:
+//
+//	recorder := statemachine.NewRecorder()
+//	engine.AddExecutionHook(recorder.Hook)
+//	err := engine.Execute(ctx, smCtx)
+//	trace := recorder.Trace()
+//
+// Data changes made between two states, such as a Signal payload, are
+// attributed to the earlier state. States of nested state machines are part
+// of their composite state's step.
+type Recorder struct {
+	mu    sync.Mutex
+	trace Trace
+	depth int
+	start map[string]any // data when the current step started
+}
+
+// NewRecorder creates an empty recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Hook is an ActionExecutionHook that records each executed state.
+func (r *Recorder) Hook(ctx context.Context, _ string, stateName string, phase string, err error) {
+	smCtx, ok := ctx.Value(stateMachineContextKey).(*Context)
+	if !ok || smCtx == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch phase {
+	case "start":
+		r.depth++
+		if r.depth > 1 {
+			// A state of a nested state machine
+			return
+		}
+
+		r.startStep(smCtx, stateName)
+	case "end":
+		r.depth--
+		if r.depth > 0 {
+			return
+		}
+
+		r.endStep(smCtx, err)
+	}
+}
+
+// Trace returns a copy of the trace recorded so far.
+func (r *Recorder) Trace() *Trace {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	trace := r.trace
+	trace.InitialData = maps.Clone(r.trace.InitialData)
+	trace.Steps = slices.Clone(r.trace.Steps)
+
+	return &trace
+}
+
+func (r *Recorder) startStep(smCtx *Context, state string) {
+	data := snapshotData(smCtx)
+
+	if len(r.trace.Steps) == 0 {
+		r.trace.SessionID = smCtx.SessionID
+		r.trace.ProjectID = smCtx.ProjectID
+		r.trace.Provider = smCtx.Provider
+		r.trace.ToolName = smCtx.ToolName
+		r.trace.InitialData = data
+	} else {
+		// Attribute changes made outside of any state to the previous step
+		previous := &r.trace.Steps[len(r.trace.Steps)-1]
+		output, removed := diffData(previous.Data, data)
+
+		if previous.Output == nil && len(output) > 0 {
+			previous.Output = make(map[string]any, len(output))
+		}
+
+		maps.Copy(previous.Output, output)
+		previous.Removed = append(previous.Removed, removed...)
+		previous.Data = data
+	}
+
+	r.start = data
+	r.trace.Steps = append(r.trace.Steps, TraceStep{
+		State:     state,
+		StartedAt: time.Now(),
+	})
+}
+
+func (r *Recorder) endStep(smCtx *Context, err error) {
+	if len(r.trace.Steps) == 0 {
+		return
+	}
+
+	step := &r.trace.Steps[len(r.trace.Steps)-1]
+	step.Duration = time.Since(step.StartedAt)
+	step.Data = snapshotData(smCtx)
+	step.Output, step.Removed = diffData(r.start, step.Data)
+
+	switch {
+	case errors.Is(err, ErrSuspended):
+		step.Suspended = true
+	case err != nil:
+		step.Error = err.Error()
+	}
+}
+
+// snapshotData copies the context data.
+func snapshotData(smCtx *Context) map[string]any {
+	smCtx.mu.RLock()
+	defer smCtx.mu.RUnlock()
+
+	return maps.Clone(smCtx.Data)
+}
+
+// diffData returns the keys that were added or changed, and the keys that were removed, between two snapshots.
+func diffData(before, after map[string]any) (map[string]any, []string) {
+	var output map[string]any
+
+	for key, value := range after {
+		old, ok := before[key]
+		if ok && dataEqual(old, value) {
+			continue
+		}
+
+		if output == nil {
+			output = make(map[string]any)
+		}
+
+		output[key] = value
+	}
+
+	var removed []string
+
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+
+	slices.Sort(removed)
+
+	return output, removed
+}
+
+// dataEqual compares data values, ignoring differences that don't survive
+// JSON encoding (e.g. int vs. float64) so that live data compares equal to
+// data loaded from a stored trace.
+func dataEqual(a, b any) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+
+	if errA != nil || errB != nil {
+		return false
+	}
+
+	var decodedA, decodedB any
+
+	if json.Unmarshal(encodedA, &decodedA) != nil || json.Unmarshal(encodedB, &decodedB) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(decodedA, decodedB)
+}
+
+// Divergence describes where a replayed execution departed from its trace.
+type Divergence struct {
+	// Step is the index of the first step that differs.
+	Step int
+	// Expected is the state the trace entered at Step, or "" if the trace had already ended.
+	Expected string
+	// Actual is the state the replay entered at Step, or "" if the replay had already ended.
+	Actual string
+	// Reason explains the difference.
+	Reason string
+}
+
+func (d *Divergence) String() string {
+	return fmt.Sprintf("step %d: %s", d.Step, d.Reason)
+}
+
+// ReplayResult is the outcome of replaying a trace.
+type ReplayResult struct {
+	// Context is the context of the replayed execution.
+	Context *Context
+	// Divergence is the first difference from the trace, or nil if the replay matched it.
+	Divergence *Divergence
+	// Err is the error the replayed execution ended with, if any.
+	Err error
+}
+
+// Diverged reports whether the replay departed from the trace.
+func (r *ReplayResult) Diverged() bool {
+	return r.Divergence != nil
+}
+
+// Replay re-runs a recorded execution against config and reports the first
+// step at which it diverges from the trace. No actions are executed: every
+// action, composite and wait state instead applies the data changes recorded
+// for it (and fails if the recorded step failed), so only the config's states,
+// transitions, conditions and error routing are exercised. This makes it
+// possible to regression-test config changes against production traces.
+//
+// Replay returns an error only if the engine cannot be built or the trace is
+// empty; divergences and execution errors are part of the result.
+func Replay(ctx context.Context, config *Config, trace *Trace) (*ReplayResult, error) {
+	if len(trace.Steps) == 0 {
+		return nil, ErrEmptyTrace
+	}
+
+	engine, err := NewEngine(config, newReplayFactory(config))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	player := &replayPlayer{trace: trace, cancel: cancel}
+
+	for name, state := range engine.states {
+		switch state.(type) {
+		case *ActionState, *CompositeState, *WaitState:
+			engine.states[name] = &replayState{name: name, player: player}
+		}
+	}
+
+	engine.SetCancellationEnabled(true)
+	engine.AddExecutionHook(player.hook)
+
+	smCtx := trace.newContext()
+	execErr := engine.Execute(ctx, smCtx)
+
+	result := &ReplayResult{Context: smCtx, Divergence: player.divergence}
+	if result.Divergence == nil {
+		result.Err = execErr
+		result.Divergence = player.finish(smCtx)
+	}
+
+	return result, nil
+}
+
+// replayPlayer walks the trace alongside a replayed execution.
+type replayPlayer struct {
+	trace      *Trace
+	cancel     context.CancelFunc
+	step       int // index of the step being executed
+	divergence *Divergence
+}
+
+// hook compares every state the replay enters with the trace and stops the
+// execution at the first difference.
+func (p *replayPlayer) hook(ctx context.Context, _ string, stateName string, phase string, _ error) {
+	if phase != "start" || p.divergence != nil {
+		return
+	}
+
+	smCtx, _ := ctx.Value(stateMachineContextKey).(*Context)
+
+	switch {
+	case p.step >= len(p.trace.Steps):
+		p.diverge(stateName, "replay continued past the end of the trace")
+	case p.trace.Steps[p.step].State != stateName:
+		p.diverge(stateName, fmt.Sprintf("expected state %s, replay entered %s", p.trace.Steps[p.step].State, stateName))
+	case p.step > 0 && smCtx != nil:
+		if key, ok := firstDataDifference(p.trace.Steps[p.step-1].Data, snapshotData(smCtx)); ok {
+			p.diverge(stateName, fmt.Sprintf("data differs before state %s at key %q", stateName, key))
+		}
+	}
+
+	if p.divergence == nil {
+		p.step++
+	}
+}
+
+func (p *replayPlayer) diverge(actual, reason string) {
+	expected := ""
+	if p.step < len(p.trace.Steps) {
+		expected = p.trace.Steps[p.step].State
+	}
+
+	p.divergence = &Divergence{Step: p.step, Expected: expected, Actual: actual, Reason: reason}
+	p.cancel()
+}
+
+// finish checks that a replay that ran to the end covered the whole trace.
+func (p *replayPlayer) finish(smCtx *Context) *Divergence {
+	if p.step < len(p.trace.Steps) {
+		return &Divergence{
+			Step:     p.step,
+			Expected: p.trace.Steps[p.step].State,
+			Reason:   fmt.Sprintf("replay stopped before state %s", p.trace.Steps[p.step].State),
+		}
+	}
+
+	last := p.trace.Steps[len(p.trace.Steps)-1]
+	if key, ok := firstDataDifference(last.Data, snapshotData(smCtx)); ok {
+		return &Divergence{
+			Step:     len(p.trace.Steps) - 1,
+			Expected: last.State,
+			Actual:   last.State,
+			Reason:   fmt.Sprintf("data differs after state %s at key %q", last.State, key),
+		}
+	}
+
+	return nil
+}
+
+// current returns the step being replayed.
+func (p *replayPlayer) current() (TraceStep, bool) {
+	if p.step == 0 || p.step > len(p.trace.Steps) {
+		return TraceStep{}, false
+	}
+
+	return p.trace.Steps[p.step-1], true
+}
+
+// firstDataDifference returns the first key, in sorted order, whose value differs between two snapshots.
+func firstDataDifference(expected, actual map[string]any) (string, bool) {
+	keys := slices.Collect(maps.Keys(expected))
+	for key := range actual {
+		if _, ok := expected[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		expectedValue, inExpected := expected[key]
+		actualValue, inActual := actual[key]
+
+		if inExpected != inActual || !dataEqual(expectedValue, actualValue) {
+			return key, true
+		}
+	}
+
+	return "", false
+}
+
+// replayState stands in for a state during replay and applies its recorded output.
+type replayState struct {
+	name   string
+	player *replayPlayer
+}
+
+func (s *replayState) Name() string {
+	return s.name
+}
+
+func (s *replayState) Execute(ctx context.Context, smCtx *Context) (TransitionResult, error) {
+	if ctx.Err() != nil {
+		return TransitionResult{}, ctx.Err()
+	}
+
+	step, ok := s.player.current()
+	if !ok {
+		return TransitionResult{}, fmt.Errorf("%w: no recorded step for state %s", ErrReplayedFailure, s.name)
+	}
+
+	applyStepOutput(smCtx, step)
+
+	if step.Error != "" {
+		return TransitionResult{}, &replayedError{message: step.Error}
+	}
+
+	return TransitionResult{}, nil
+}
+
+// replayedError reproduces a recorded failure. It keeps the recorded message
+// so that error routing stores the same data as in the original execution.
+type replayedError struct {
+	message string
+}
+
+func (e *replayedError) Error() string {
+	return e.message
+}
+
+// Is makes errors.Is(err, ErrReplayedFailure) true for a replayed failure.
+func (e *replayedError) Is(target error) bool {
+	return target == ErrReplayedFailure
+}
+
+// newReplayFactory returns a factory that can build every action type used in
+// config, including referenced sub-machines. The actions are never executed
+// because Replay replaces the states that own them.
+func newReplayFactory(config *Config) *ActionFactory {
+	factory := NewActionFactory()
+	stub := func(_ *ActionFactory, name string, _ map[string]any) (Action, error) {
+		return &NoopAction{BaseAction: BaseAction{name: name}}, nil
+	}
+
+	// Referenced configs are loaded anew on every lookup, so track them by name
+	seenRefs := make(map[string]bool)
+
+	var register func(config *Config)
+
+	register = func(config *Config) {
+		if config == nil {
+			return
+		}
+
+		for _, state := range config.States {
+			for _, action := range state.Actions {
+				factory.Register(action.Type, stub)
+			}
+
+			register(state.SubMachine)
+
+			if state.SubMachineRef != "" && !seenRefs[state.SubMachineRef] {
+				seenRefs[state.SubMachineRef] = true
+
+				if ref, err := LoadConfig(state.SubMachineRef); err == nil {
+					register(ref)
+				}
+			}
+		}
+	}
+
+	register(config)
+
+	return factory
+}
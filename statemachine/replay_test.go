@@ -0,0 +1,150 @@
+package statemachine
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReplayTestConfig() *Config {
+	return &Config{
+		Name:         "replay",
+		InitialState: "fetch",
+		FinalStates:  []string{"large", "small", "failed"},
+		States: []StateConfig{
+			{Name: "fetch", Type: "action", Actions: []ActionConfig{{Type: "set", Name: "fetch", Parameters: map[string]any{"count": 12}}}},
+			{
+				Name:    "sync",
+				Type:    "action",
+				Actions: []ActionConfig{{Type: "behave", Name: "sync", Parameters: map[string]any{"behavior": "fail"}}},
+				OnError: "classify",
+			},
+			{Name: "classify", Type: "action", Actions: []ActionConfig{{Type: "set", Name: "classify", Parameters: map[string]any{"classified": true}}}},
+			{Name: "large", Type: "final"},
+			{Name: "small", Type: "final"},
+			{Name: "failed", Type: "final"},
+		},
+		Transitions: []TransitionConfig{
+			{From: "fetch", To: "sync"},
+			{From: "sync", To: "large"},
+			{From: "classify", To: "large", Condition: "data.count > 10"},
+			{From: "classify", To: "small", Condition: "always"},
+		},
+	}
+}
+
+func newReplayTestFactory() *ActionFactory {
+	factory := newErrorHandlingFactory()
+	factory.Register("set", func(_ *ActionFactory, name string, params map[string]any) (Action, error) {
+		return &setAction{BaseAction: BaseAction{name: name}, values: params}, nil
+	})
+
+	return factory
+}
+
+// recordReplayTestTrace runs the test config and returns its trace after a JSON round trip.
+func recordReplayTestTrace(t *testing.T) *Trace {
+	t.Helper()
+
+	engine, err := NewEngine(newReplayTestConfig(), newReplayTestFactory())
+	require.NoError(t, err)
+
+	recorder := NewRecorder()
+	engine.AddExecutionHook(recorder.Hook)
+
+	smCtx := NewContext("session", "project")
+	smCtx.Set("input", "value")
+	require.NoError(t, engine.Execute(context.Background(), smCtx))
+
+	encoded, err := json.Marshal(recorder.Trace())
+	require.NoError(t, err)
+
+	var trace Trace
+	require.NoError(t, json.Unmarshal(encoded, &trace))
+
+	return &trace
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	trace := recordReplayTestTrace(t)
+
+	assert.Equal(t, "session", trace.SessionID)
+	assert.Equal(t, map[string]any{"input": "value"}, trace.InitialData)
+	assert.Equal(t, []string{"fetch", "sync", "classify", "large"}, trace.Path())
+
+	assert.Equal(t, map[string]any{"count": 12.0}, trace.Steps[0].Output)
+	assert.Equal(t, ErrTestTemporary.Error(), trace.Steps[1].Error)
+	// The error routed to onError is attributed to the failed step
+	assert.Equal(t, ErrTestTemporary.Error(), trace.Steps[1].Output[StateErrorKey("sync")])
+	assert.Equal(t, true, trace.Steps[2].Data["classified"])
+}
+
+func TestReplayMatchesTrace(t *testing.T) {
+	t.Parallel()
+
+	trace := recordReplayTestTrace(t)
+
+	result, err := Replay(context.Background(), newReplayTestConfig(), trace)
+	require.NoError(t, err)
+	require.NoError(t, result.Err)
+	assert.False(t, result.Diverged(), "unexpected divergence: %v", result.Divergence)
+	assert.Equal(t, trace.Path(), result.Context.PathHistory)
+}
+
+func TestReplayReportsDivergence(t *testing.T) {
+	t.Parallel()
+
+	trace := recordReplayTestTrace(t)
+
+	// Raising the threshold sends the recorded execution down another branch
+	config := newReplayTestConfig()
+	config.Transitions[2].Condition = "data.count > 100"
+
+	result, err := Replay(context.Background(), config, trace)
+	require.NoError(t, err)
+	require.True(t, result.Diverged())
+	assert.Equal(t, 3, result.Divergence.Step)
+	assert.Equal(t, "large", result.Divergence.Expected)
+	assert.Equal(t, "small", result.Divergence.Actual)
+
+	// Without onError the replay stops where the recorded step failed
+	config = newReplayTestConfig()
+	config.States[1].OnError = ""
+
+	result, err = Replay(context.Background(), config, trace)
+	require.NoError(t, err)
+	require.ErrorIs(t, result.Err, ErrReplayedFailure)
+	require.True(t, result.Diverged())
+	assert.Equal(t, 2, result.Divergence.Step)
+	assert.Equal(t, "classify", result.Divergence.Expected)
+	assert.Empty(t, result.Divergence.Actual)
+
+	_, err = Replay(context.Background(), config, &Trace{})
+	require.ErrorIs(t, err, ErrEmptyTrace)
+}
+
+func TestTraceAt(t *testing.T) {
+	t.Parallel()
+
+	trace := recordReplayTestTrace(t)
+
+	before, err := trace.At(-1)
+	require.NoError(t, err)
+	assert.Equal(t, "fetch", before.CurrentState)
+	assert.Empty(t, before.PathHistory)
+
+	smCtx, err := trace.At(1)
+	require.NoError(t, err)
+	assert.Equal(t, "classify", smCtx.CurrentState)
+	assert.Equal(t, []string{"fetch", "sync"}, smCtx.PathHistory)
+	assert.Contains(t, smCtx.Data, StateErrorKey("sync"))
+	assert.NotContains(t, smCtx.Data, "classified")
+
+	_, err = trace.At(len(trace.Steps))
+	require.ErrorIs(t, err, ErrTraceStepOutOfRange)
+}
@@ -139,8 +139,8 @@ var CommonTestConfigs = struct {
 				{Name: "failure", Type: "final"},
 			},
 			Transitions: []statemachine.TransitionConfig{
-				{From: "start", To: "success", Condition: "result.success"},
-				{From: "start", To: "failure", Condition: "result.failure"},
+				{From: "start", To: "success", Condition: "data.result.success"},
+				{From: "start", To: "failure", Condition: "data.result.failure"},
 			},
 		}
 	},
@@ -156,8 +156,8 @@ var CommonTestConfigs = struct {
 			},
 			Transitions: []statemachine.TransitionConfig{
 				{From: "start", To: "retry", Condition: "always"},
-				{From: "retry", To: "retry", Condition: "attempts < 3"},
-				{From: "retry", To: "complete", Condition: "attempts >= 3"},
+				{From: "retry", To: "retry", Condition: "data.attempts < 3"},
+				{From: "retry", To: "complete", Condition: "data.attempts >= 3"},
 			},
 		}
 	},
@@ -176,12 +176,12 @@ var CommonTestConfigs = struct {
 			},
 			Transitions: []statemachine.TransitionConfig{
 				{From: "init", To: "validate", Condition: "always"},
-				{From: "validate", To: "process", Condition: "valid"},
-				{From: "validate", To: "failure", Condition: "!valid"},
-				{From: "process", To: "success", Condition: "success"},
-				{From: "process", To: "retry", Condition: "retryable"},
-				{From: "retry", To: "process", Condition: "attempts < 3"},
-				{From: "retry", To: "failure", Condition: "attempts >= 3"},
+				{From: "validate", To: "process", Condition: "data.valid"},
+				{From: "validate", To: "failure", Condition: "!data.valid"},
+				{From: "process", To: "success", Condition: "data.success"},
+				{From: "process", To: "retry", Condition: "data.retryable"},
+				{From: "retry", To: "process", Condition: "data.attempts < 3"},
+				{From: "retry", To: "failure", Condition: "data.attempts >= 3"},
 			},
 		}
 	},
@@ -2,12 +2,8 @@ package statemachine
 
 import (
 	"context"
-	"fmt"
-	"strings"
 )
 
-const expectedExpressionParts = 2
-
 // SimpleTransition always transitions from A to B.
 type SimpleTransition struct {
 	from string
@@ -65,19 +61,37 @@ func (t *ConditionalTransition) Condition(ctx context.Context, smCtx *Context) (
 	return t.condition(ctx, smCtx)
 }
 
-// ExpressionTransition evaluates expression against context.
+// ExpressionTransition evaluates a compiled expression against the context.
 type ExpressionTransition struct {
 	from       string
 	to         string
 	expression string // e.g., "data.provider == 'salesforce'"
+	compiled   *Expression
+	compileErr error
 }
 
 // NewExpressionTransition creates a new expression-based transition.
+// If the expression does not compile, Condition returns the compile error;
+// use CompileExpression with NewCompiledExpressionTransition to fail earlier.
 func NewExpressionTransition(from, to, expr string) *ExpressionTransition {
+	compiled, err := CompileExpression(expr)
+
 	return &ExpressionTransition{
 		from:       from,
 		to:         to,
 		expression: expr,
+		compiled:   compiled,
+		compileErr: err,
+	}
+}
+
+// NewCompiledExpressionTransition creates a transition from an already compiled expression.
+func NewCompiledExpressionTransition(from, to string, expr *Expression) *ExpressionTransition {
+	return &ExpressionTransition{
+		from:       from,
+		to:         to,
+		expression: expr.String(),
+		compiled:   expr,
 	}
 }
 
@@ -89,87 +103,15 @@ func (t *ExpressionTransition) To() string {
 	return t.to
 }
 
-func (t *ExpressionTransition) Condition(ctx context.Context, smCtx *Context) (bool, error) {
-	// Simple expression evaluator for common patterns
-	// In production, could use github.com/antonmedv/expr or similar
-	return evaluateExpression(t.expression, smCtx)
+// Expression returns the source text of the transition's condition.
+func (t *ExpressionTransition) Expression() string {
+	return t.expression
 }
 
-// evaluateExpression evaluates simple expressions against context
-// This is a basic implementation - production should use a proper expression library.
-func evaluateExpression(expr string, smCtx *Context) (bool, error) {
-	expr = strings.TrimSpace(expr)
-
-	// Handle simple equality checks: "data.key == 'value'"
-	if strings.Contains(expr, "==") {
-		parts := strings.Split(expr, "==")
-		if len(parts) != expectedExpressionParts {
-			return false, fmt.Errorf("%w: %s", ErrInvalidExpression, expr)
-		}
-
-		left := strings.TrimSpace(parts[0])
-		right := strings.TrimSpace(parts[1])
-
-		// Remove quotes from right side
-		right = strings.Trim(right, "'\"")
-
-		// Extract key from "data.key" format
-		key := strings.TrimPrefix(left, "data.")
-
-		// Get value from context
-		value, exists := smCtx.Get(key)
-		if !exists {
-			return false, nil
-		}
-
-		// Compare as strings
-		return fmt.Sprintf("%v", value) == right, nil
-	}
-
-	// Handle simple inequality checks: "data.key != 'value'"
-	if strings.Contains(expr, "!=") {
-		parts := strings.Split(expr, "!=")
-		if len(parts) != expectedExpressionParts {
-			return false, fmt.Errorf("%w: %s", ErrInvalidExpression, expr)
-		}
-
-		left := strings.TrimSpace(parts[0])
-		right := strings.TrimSpace(parts[1])
-		right = strings.Trim(right, "'\"")
-
-		key := strings.TrimPrefix(left, "data.")
-
-		value, exists := smCtx.Get(key)
-		if !exists {
-			return true, nil // If key doesn't exist, it's not equal
-		}
-
-		return fmt.Sprintf("%v", value) != right, nil
-	}
-
-	// Handle boolean checks: "data.key"
-	if after, ok := strings.CutPrefix(expr, "data."); ok {
-		key := after
-
-		value, exists := smCtx.GetBool(key)
-		if !exists {
-			return false, nil
-		}
-
-		return value, nil
-	}
-
-	// Handle negation: "!data.key"
-	if after, ok := strings.CutPrefix(expr, "!data."); ok {
-		key := after
-
-		value, exists := smCtx.GetBool(key)
-		if !exists {
-			return true, nil
-		}
-
-		return !value, nil
+func (t *ExpressionTransition) Condition(ctx context.Context, smCtx *Context) (bool, error) {
+	if t.compileErr != nil {
+		return false, t.compileErr
 	}
 
-	return false, fmt.Errorf("%w: %s", ErrUnsupportedExpression, expr)
+	return t.compiled.Evaluate(smCtx)
 }
@@ -142,11 +142,17 @@ func RenameState(oldName, newName string) *Fix {
 				}
 			}
 
-			// Update error handlers
+			// Update error handlers and composite state exits
 			for i, state := range config.States {
 				if state.OnError == oldName {
 					config.States[i].OnError = newName
 				}
+
+				for childFinal, target := range state.OnExit {
+					if target == oldName {
+						config.States[i].OnExit[childFinal] = newName
+					}
+				}
 			}
 
 			return nil
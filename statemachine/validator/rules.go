@@ -37,6 +37,7 @@ func DefaultRules() []Rule {
 		&duplicateTransitionRule{},
 		&namingConventionRule{},
 		&cyclicTransitionRule{},
+		&compositeStateRule{},
 	}
 }
 
@@ -66,12 +67,15 @@ func (r *unreachableStateRule) Check(config *statemachine.Config) RuleResult {
 	reachable := make(map[string]bool)
 	reachable[config.InitialState] = true
 
+	// States only entered through onError still count as reachable
+	transitions := append(config.EffectiveTransitions(), config.ErrorTransitions()...)
+
 	queue := []string{config.InitialState}
 	for len(queue) > 0 {
 		current := queue[0]
 		queue = queue[1:]
 
-		for _, transition := range config.Transitions {
+		for _, transition := range transitions {
 			if transition.From == current && !reachable[transition.To] {
 				reachable[transition.To] = true
 
@@ -120,7 +124,7 @@ func (r *missingTransitionRule) Check(config *statemachine.Config) RuleResult {
 
 	// Build map of states with outgoing transitions
 	hasOutgoing := make(map[string]bool)
-	for _, transition := range config.Transitions {
+	for _, transition := range config.EffectiveTransitions() {
 		hasOutgoing[transition.From] = true
 	}
 
@@ -225,7 +229,7 @@ func (r *cyclicTransitionRule) Check(config *statemachine.Config) RuleResult {
 
 	// Build adjacency list
 	graph := make(map[string][]string)
-	for _, transition := range config.Transitions {
+	for _, transition := range config.EffectiveTransitions() {
 		graph[transition.From] = append(graph[transition.From], transition.To)
 	}
 
@@ -282,8 +286,101 @@ func (r *cyclicTransitionRule) Check(config *statemachine.Config) RuleResult {
 	return RuleResult{Warnings: warnings}
 }
 
+// compositeStateRule checks composite states and validates their inline
+// sub-machines with the default rules.
+type compositeStateRule struct{}
+
+func (r *compositeStateRule) Name() string {
+	return "CompositeState"
+}
+
+func (r *compositeStateRule) Severity() Severity {
+	return SeverityError
+}
+
+func (r *compositeStateRule) Check(config *statemachine.Config) RuleResult {
+	var result RuleResult
+
+	for _, state := range config.States {
+		if state.Type != statemachine.StateTypeComposite {
+			continue
+		}
+
+		location := Location{State: state.Name}
+
+		switch {
+		case state.SubMachine == nil && state.SubMachineRef == "":
+			result.Errors = append(result.Errors, ValidationError{
+				Code:     "MISSING_SUB_MACHINE",
+				Message:  fmt.Sprintf("Composite state '%s' has neither subMachine nor subMachineRef", state.Name),
+				Location: location,
+			})
+
+			continue
+		case state.SubMachine != nil && state.SubMachineRef != "":
+			result.Errors = append(result.Errors, ValidationError{
+				Code:     "AMBIGUOUS_SUB_MACHINE",
+				Message:  fmt.Sprintf("Composite state '%s' sets both subMachine and subMachineRef", state.Name),
+				Location: location,
+			})
+
+			continue
+		}
+
+		sub := state.InlineSubMachine()
+		if sub == nil {
+			// Referenced sub-machines are resolved when the engine is built
+			continue
+		}
+
+		finalStates := make(map[string]bool)
+		for _, finalState := range sub.FinalStates {
+			finalStates[finalState] = true
+		}
+
+		for childFinal := range state.OnExit {
+			if !finalStates[childFinal] {
+				result.Errors = append(result.Errors, ValidationError{
+					Code:     "INVALID_ON_EXIT",
+					Message:  fmt.Sprintf("Composite state '%s' maps '%s', which is not a final state of its sub-machine", state.Name, childFinal),
+					Location: location,
+				})
+			}
+		}
+
+		// Validate the sub-machine, reporting issues against the nested state path
+		for _, rule := range DefaultRules() {
+			nested := rule.Check(sub)
+
+			for _, err := range nested.Errors {
+				err.Message = fmt.Sprintf("In sub-machine of '%s': %s", state.Name, err.Message)
+				err.Location.State = nestedStateName(state.Name, err.Location.State)
+				result.Errors = append(result.Errors, err)
+			}
+
+			for _, warning := range nested.Warnings {
+				warning.Message = fmt.Sprintf("In sub-machine of '%s': %s", state.Name, warning.Message)
+				warning.Location.State = nestedStateName(state.Name, warning.Location.State)
+				result.Warnings = append(result.Warnings, warning)
+			}
+		}
+	}
+
+	return result
+}
+
 // Helper functions
 
+// nestedStateName qualifies a sub-machine state with its parent, matching the
+// "parent/child" form used in Context.PathHistory.
+func nestedStateName(parent, child string) string {
+	if child == "" {
+		return parent
+	}
+
+	return parent + "/" + child
+}
+
 func isSnakeCase(s string) bool {
 	for _, r := range s {
 		if r >= 'A' && r <= 'Z' {
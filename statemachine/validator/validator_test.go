@@ -268,6 +268,56 @@ func TestCyclicTransitionRule(t *testing.T) {
 	assert.Empty(t, result.Errors)
 }
 
+func TestCompositeStateRule(t *testing.T) {
+	t.Parallel()
+
+	rule := &compositeStateRule{}
+
+	config := &statemachine.Config{
+		Name:         "test",
+		InitialState: "nested",
+		FinalStates:  []string{"complete"},
+		States: []statemachine.StateConfig{
+			{
+				Name: "nested",
+				Type: statemachine.StateTypeComposite,
+				SubMachine: &statemachine.Config{
+					InitialState: "a",
+					FinalStates:  []string{"b"},
+					States: []statemachine.StateConfig{
+						{Name: "a", Type: "action", Actions: []statemachine.ActionConfig{{Type: "noop", Name: "test"}}},
+						{Name: "orphan", Type: "action", Actions: []statemachine.ActionConfig{{Type: "noop", Name: "test"}}},
+						{Name: "b", Type: "final"},
+					},
+					Transitions: []statemachine.TransitionConfig{
+						{From: "a", To: "b", Condition: "always"},
+						{From: "orphan", To: "b", Condition: "always"},
+					},
+				},
+				OnExit: map[string]string{"b": "complete", "a": "complete"},
+			},
+			{Name: "missing", Type: statemachine.StateTypeComposite},
+			{Name: "complete", Type: "final"},
+		},
+	}
+
+	result := rule.Check(config)
+
+	codes := make(map[string]string)
+	for _, err := range result.Errors {
+		codes[err.Code] = err.Location.State
+	}
+
+	assert.Equal(t, "nested", codes["INVALID_ON_EXIT"])
+	assert.Equal(t, "nested/orphan", codes["UNREACHABLE_STATE"])
+	assert.Equal(t, "missing", codes["MISSING_SUB_MACHINE"])
+
+	// The exits of a composite state count as outgoing transitions
+	for _, err := range (&missingTransitionRule{}).Check(config).Errors {
+		assert.NotEqual(t, "nested", err.Location.State)
+	}
+}
+
 func TestValidationResultString(t *testing.T) {
 	t.Parallel()
 
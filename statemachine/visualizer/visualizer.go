@@ -6,7 +6,9 @@ package visualizer
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/amp-labs/amp-common/statemachine"
@@ -50,15 +52,51 @@ func GenerateMermaidWithOptions(config *statemachine.Config, opts Options) (stri
 	sb.WriteString("```mermaid\n")
 	sb.WriteString(fmt.Sprintf("stateDiagram-%s\n", opts.Direction))
 
-	// Initial state marker
-	sb.WriteString(fmt.Sprintf("    [*] --> %s\n", config.InitialState))
-
-	// Build highlight map for quick lookup
+	// Build highlight map for quick lookup. Nested states appear in path
+	// history as "parent/child" and are rendered with the ID "parent_child".
 	highlightMap := make(map[string]bool)
 	for _, state := range opts.HighlightPath {
-		highlightMap[state] = true
+		highlightMap[strings.ReplaceAll(state, "/", "_")] = true
 	}
 
+	writeMachine(&sb, config, opts, highlightMap, "", "    ", []string{config.Name})
+
+	// Add class definitions based on theme
+	sb.WriteString("\n")
+	sb.WriteString("    classDef actionState fill:#e1f5ff,stroke:#01579b,stroke-width:2px\n")
+	sb.WriteString("    classDef finalState fill:#c8e6c9,stroke:#2e7d32,stroke-width:2px\n")
+	sb.WriteString("    classDef highlighted fill:#fff9c4,stroke:#f57f17,stroke-width:3px\n")
+
+	sb.WriteString("```\n")
+
+	return sb.String(), nil
+}
+
+// writeMachine writes the states and transitions of a (sub-)machine. Composite
+// states are rendered as Mermaid composite states; the IDs of their children are
+// prefixed with the parent's ID so that they stay unique across the diagram.
+func writeMachine(
+	sb *strings.Builder,
+	config *statemachine.Config,
+	opts Options,
+	highlightMap map[string]bool,
+	prefix, indent string,
+	refs []string,
+) {
+	stateID := func(name string) string {
+		return prefix + name
+	}
+
+	// Nested states are declared with their plain name as the label
+	if prefix != "" {
+		for _, state := range config.States {
+			sb.WriteString(fmt.Sprintf("%sstate \"%s\" as %s\n", indent, state.Name, stateID(state.Name)))
+		}
+	}
+
+	// Initial state marker
+	sb.WriteString(fmt.Sprintf("%s[*] --> %s\n", indent, stateID(config.InitialState)))
+
 	// Build final states map for quick lookup
 	finalStatesMap := make(map[string]bool)
 	for _, finalState := range config.FinalStates {
@@ -73,6 +111,8 @@ func GenerateMermaidWithOptions(config *statemachine.Config, opts Options) (stri
 
 	// Process each state
 	for _, state := range config.States {
+		id := stateID(state.Name)
+
 		// State declaration with description if actions shown
 		if opts.ShowActions && len(state.Actions) > 0 {
 			actionNames := make([]string, len(state.Actions))
@@ -80,20 +120,27 @@ func GenerateMermaidWithOptions(config *statemachine.Config, opts Options) (stri
 				actionNames[i] = action.Type
 			}
 
-			sb.WriteString(fmt.Sprintf("    %s: %s\\n[%s]\n",
-				state.Name, state.Name, strings.Join(actionNames, ", ")))
+			sb.WriteString(fmt.Sprintf("%s%s: %s\\n[%s]\n",
+				indent, id, state.Name, strings.Join(actionNames, ", ")))
 		}
 
 		isFinal := finalStatesMap[state.Name]
 
 		// Apply styling based on state type and highlighting
 		switch {
-		case highlightMap[state.Name]:
-			sb.WriteString(fmt.Sprintf("    class %s highlighted\n", state.Name))
+		case highlightMap[id]:
+			sb.WriteString(fmt.Sprintf("%sclass %s highlighted\n", indent, id))
 		case isFinal:
-			sb.WriteString(fmt.Sprintf("    class %s finalState\n", state.Name))
+			sb.WriteString(fmt.Sprintf("%sclass %s finalState\n", indent, id))
 		case len(state.Actions) > 0:
-			sb.WriteString(fmt.Sprintf("    class %s actionState\n", state.Name))
+			sb.WriteString(fmt.Sprintf("%sclass %s actionState\n", indent, id))
+		}
+
+		// Render the nested machine of composite states
+		if sub, subRefs := resolveSubMachine(state, refs); sub != nil {
+			sb.WriteString(fmt.Sprintf("%sstate %s {\n", indent, id))
+			writeMachine(sb, sub, opts, highlightMap, id+"_", indent+"    ", subRefs)
+			sb.WriteString(fmt.Sprintf("%s}\n", indent))
 		}
 
 		// Add transitions from this state
@@ -104,25 +151,73 @@ func GenerateMermaidWithOptions(config *statemachine.Config, opts Options) (stri
 				transitionLabel = ": " + transition.Condition
 			}
 
-			sb.WriteString(fmt.Sprintf("    %s --> %s%s\n",
-				state.Name, transition.To, transitionLabel))
+			sb.WriteString(fmt.Sprintf("%s%s --> %s%s\n",
+				indent, id, stateID(transition.To), transitionLabel))
+		}
+
+		// Add exits of composite states, labeled with the sub-machine's final state
+		for _, childFinal := range slices.Sorted(maps.Keys(state.OnExit)) {
+			transitionLabel := ""
+			if opts.ShowConditions {
+				transitionLabel = ": " + childFinal
+			}
+
+			sb.WriteString(fmt.Sprintf("%s%s --> %s%s\n",
+				indent, id, stateID(state.OnExit[childFinal]), transitionLabel))
+		}
+
+		// Add the timeout exit of wait states
+		if state.Type == statemachine.StateTypeWait && state.Await != nil && state.Await.OnTimeout != "" {
+			transitionLabel := ""
+			if opts.ShowConditions {
+				transitionLabel = ": timeout " + state.Await.Timeout
+			}
+
+			sb.WriteString(fmt.Sprintf("%s%s --> %s%s\n",
+				indent, id, stateID(state.Await.OnTimeout), transitionLabel))
+		}
+
+		// Add the error exit
+		if state.OnError != "" {
+			transitionLabel := ""
+			if opts.ShowConditions {
+				transitionLabel = ": error"
+			}
+
+			sb.WriteString(fmt.Sprintf("%s%s --> %s%s\n",
+				indent, id, stateID(state.OnError), transitionLabel))
 		}
 
 		// Mark final states
 		if isFinal {
-			sb.WriteString(fmt.Sprintf("    %s --> [*]\n", state.Name))
+			sb.WriteString(fmt.Sprintf("%s%s --> [*]\n", indent, id))
 		}
 	}
+}
 
-	// Add class definitions based on theme
-	sb.WriteString("\n")
-	sb.WriteString("    classDef actionState fill:#e1f5ff,stroke:#01579b,stroke-width:2px\n")
-	sb.WriteString("    classDef finalState fill:#c8e6c9,stroke:#2e7d32,stroke-width:2px\n")
-	sb.WriteString("    classDef highlighted fill:#fff9c4,stroke:#f57f17,stroke-width:3px\n")
+// resolveSubMachine returns the sub-machine of a composite state and the list
+// of referenced configs leading to it. Sub-machines referenced by name are
+// loaded through statemachine.LoadConfig; if that fails, or the reference is
+// recursive, nil is returned and the state is drawn as a plain state.
+func resolveSubMachine(state statemachine.StateConfig, refs []string) (*statemachine.Config, []string) {
+	if state.Type != statemachine.StateTypeComposite {
+		return nil, nil
+	}
 
-	sb.WriteString("```\n")
+	if sub := state.InlineSubMachine(); sub != nil {
+		return sub, refs
+	}
 
-	return sb.String(), nil
+	if state.SubMachineRef == "" || slices.Contains(refs, state.SubMachineRef) {
+		return nil, nil
+	}
+
+	sub, err := statemachine.LoadConfig(state.SubMachineRef)
+	if err != nil {
+		return nil, nil
+	}
+
+	return sub, append(slices.Clone(refs), state.SubMachineRef)
 }
 
 // loadConfig loads a state machine config from a YAML file.
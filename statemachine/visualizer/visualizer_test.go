@@ -356,3 +356,79 @@ func TestMermaidStructure(t *testing.T) {
 	lines := strings.Split(result, "\n")
 	assert.GreaterOrEqual(t, len(lines), 5, "should have multiple lines")
 }
+
+func TestGenerateMermaidCompositeState(t *testing.T) {
+	t.Parallel()
+
+	config := &statemachine.Config{
+		Name:         "nested",
+		InitialState: "setup",
+		FinalStates:  []string{"done", "aborted"},
+		States: []statemachine.StateConfig{
+			{
+				Name: "setup",
+				Type: statemachine.StateTypeComposite,
+				SubMachine: &statemachine.Config{
+					InitialState: "collect",
+					FinalStates:  []string{"configured", "skipped"},
+					States: []statemachine.StateConfig{
+						{Name: "collect", Type: "action", Actions: []statemachine.ActionConfig{{Type: "ask", Name: "ask"}}},
+						{Name: "configured", Type: "final"},
+						{Name: "skipped", Type: "final"},
+					},
+					Transitions: []statemachine.TransitionConfig{
+						{From: "collect", To: "configured", Condition: "data.ok"},
+						{From: "collect", To: "skipped", Condition: "!data.ok"},
+					},
+				},
+				OnExit: map[string]string{"configured": "done", "skipped": "aborted"},
+			},
+			{Name: "done", Type: "final"},
+			{Name: "aborted", Type: "final"},
+		},
+	}
+
+	result, err := GenerateMermaidWithOptions(config, DefaultOptions().WithHighlightPath([]string{"setup", "setup/collect"}))
+	require.NoError(t, err)
+
+	for _, want := range []string{
+		"    state setup {\n",
+		"        state \"collect\" as setup_collect\n",
+		"        [*] --> setup_collect\n",
+		"        class setup_collect highlighted\n",
+		"        setup_collect --> setup_configured: data.ok\n",
+		"        setup_skipped --> [*]\n",
+		"    }\n",
+		"    setup --> done: configured\n",
+		"    setup --> aborted: skipped\n",
+	} {
+		assert.Contains(t, result, want)
+	}
+}
+
+func TestGenerateMermaidWaitState(t *testing.T) {
+	t.Parallel()
+
+	config := &statemachine.Config{
+		Name:         "wait",
+		InitialState: "ask",
+		FinalStates:  []string{"answered", "expired"},
+		States: []statemachine.StateConfig{
+			{
+				Name:  "ask",
+				Type:  statemachine.StateTypeWait,
+				Await: &statemachine.AwaitConfig{Event: "answer", Timeout: "24h", OnTimeout: "expired"},
+			},
+			{Name: "answered", Type: "final"},
+			{Name: "expired", Type: "final"},
+		},
+		Transitions: []statemachine.TransitionConfig{
+			{From: "ask", To: "answered"},
+		},
+	}
+
+	result, err := GenerateMermaid(config)
+	require.NoError(t, err)
+	assert.Contains(t, result, "ask --> answered\n")
+	assert.Contains(t, result, "ask --> expired: timeout 24h\n")
+}
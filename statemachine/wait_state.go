@@ -0,0 +1,312 @@
+package statemachine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+)
+
+// StateTypeWait represents a state that suspends execution until it receives an event.
+// Engines with wait states need a CheckpointStore; reaching a wait state without
+// one fails with ErrCheckpointStoreRequired.
+const StateTypeWait = "wait"
+
+// AwaitConfig configures what a wait state waits for.
+type AwaitConfig struct {
+	// Event is the name of the event that resumes execution (see Engine.Signal).
+	Event string `json:"event" yaml:"event"`
+	// Prompt is a human-readable description of what is being waited for.
+	Prompt string `json:"prompt,omitempty" yaml:"prompt,omitempty"`
+	// Timeout is how long to wait (e.g. "30m", "24h"). Empty means wait forever.
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// OnTimeout is the state to transition to when the timeout expires.
+	OnTimeout string `json:"onTimeout,omitempty" yaml:"onTimeout,omitempty"`
+}
+
+// WaitTimedOutKey returns the context data key a wait state sets to true when
+// it timed out and to false when it received its event.
+func WaitTimedOutKey(stateName string) string {
+	return stateName + "_timed_out"
+}
+
+// Suspended describes an execution that is paused in a wait state.
+//
+// Engine.Execute returns a *Suspended as its error when it reaches a wait state;
+// use AsSuspended to tell it apart from failures. The execution continues when
+// Engine.Signal delivers the awaited event.
+type Suspended struct {
+	SessionID string         `json:"sessionId"`
+	State     string         `json:"state"`
+	Event     string         `json:"event"`
+	Prompt    string         `json:"prompt,omitempty"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	Deadline  time.Time      `json:"deadline,omitzero"` // zero if the wait has no timeout
+}
+
+func (s *Suspended) Error() string {
+	return fmt.Sprintf("execution suspended in state %s awaiting event %q", s.State, s.Event)
+}
+
+// Is makes errors.Is(err, ErrSuspended) true for a *Suspended.
+func (s *Suspended) Is(target error) bool {
+	return target == ErrSuspended
+}
+
+// Expired reports whether the wait's deadline has passed.
+func (s *Suspended) Expired(now time.Time) bool {
+	return !s.Deadline.IsZero() && !now.Before(s.Deadline)
+}
+
+// AsSuspended returns the *Suspended in err's chain, if any.
+func AsSuspended(err error) (*Suspended, bool) {
+	var suspended *Suspended
+	if errors.As(err, &suspended) {
+		return suspended, true
+	}
+
+	return nil, false
+}
+
+// WaitState suspends execution until an event is signaled.
+type WaitState struct {
+	name      string
+	event     string
+	prompt    string
+	timeout   time.Duration
+	onTimeout string
+	metadata  map[string]any
+}
+
+// NewWaitState creates a state that waits for event. If timeout is positive,
+// the wait expires after it and execution continues at onTimeout.
+func NewWaitState(name, event, prompt string, timeout time.Duration, onTimeout string) *WaitState {
+	return &WaitState{
+		name:      name,
+		event:     event,
+		prompt:    prompt,
+		timeout:   timeout,
+		onTimeout: onTimeout,
+	}
+}
+
+// WithMetadata attaches metadata that is reported in the Suspended result,
+// e.g. a form schema for the answer being awaited.
+func (s *WaitState) WithMetadata(metadata map[string]any) *WaitState {
+	s.metadata = maps.Clone(metadata)
+
+	return s
+}
+
+func (s *WaitState) Name() string {
+	return s.name
+}
+
+// Execute always suspends. The engine records the suspension and stops.
+func (s *WaitState) Execute(ctx context.Context, smCtx *Context) (TransitionResult, error) {
+	suspended := &Suspended{
+		SessionID: smCtx.SessionID,
+		State:     s.name,
+		Event:     s.event,
+		Prompt:    s.prompt,
+		Metadata:  maps.Clone(s.metadata),
+	}
+
+	if s.timeout > 0 {
+		suspended.Deadline = time.Now().Add(s.timeout)
+	}
+
+	return TransitionResult{}, suspended
+}
+
+// buildWaitState creates a WaitState from configuration.
+func buildWaitState(config StateConfig) (State, error) {
+	timeout, err := parseAwaitTimeout(config.Await)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWaitState(config.Name, config.Await.Event, config.Await.Prompt, timeout, config.Await.OnTimeout).
+		WithMetadata(config.Metadata), nil
+}
+
+// parseAwaitTimeout parses the timeout of a wait state.
+func parseAwaitTimeout(await *AwaitConfig) (time.Duration, error) {
+	if await.Timeout == "" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(await.Timeout)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidTimeout, await.Timeout)
+	}
+
+	return timeout, nil
+}
+
+// validateWait checks the await configuration of a wait state.
+func (c *Config) validateWait(state StateConfig) error {
+	if state.Await == nil || state.Await.Event == "" {
+		return ErrAwaitEventRequired
+	}
+
+	timeout, err := parseAwaitTimeout(state.Await)
+	if err != nil {
+		return err
+	}
+
+	if timeout > 0 && state.Await.OnTimeout == "" {
+		return ErrOnTimeoutRequired
+	}
+
+	if state.Await.OnTimeout != "" && !c.stateExists(state.Await.OnTimeout) {
+		return fmt.Errorf("onTimeout: %w: %s", ErrTransitionToNotFound, state.Await.OnTimeout)
+	}
+
+	return nil
+}
+
+// Signal delivers an event to an execution suspended in a wait state. The
+// payload is merged into the context data, the next state is chosen by the
+// wait state's transitions, and execution continues from there. The execution
+// is loaded from, and saved to, the configured CheckpointStore.
+//
+// Like Execute, Signal returns a *Suspended error if execution reaches another
+// wait state. Signaling after the wait's deadline fails with ErrWaitExpired;
+// call Resume to follow the timeout transition instead. If the execution is
+// advanced concurrently, by another Signal or by Resume, at most one of them
+// proceeds and the others fail with ErrCheckpointConflict before running any
+// state.
+func (e *Engine) Signal(ctx context.Context, sessionID, event string, payload map[string]any) (*Context, error) {
+	if e.checkpointStore == nil {
+		return nil, ErrCheckpointStoreRequired
+	}
+
+	checkpoint, err := e.checkpointStore.Load(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	smCtx := checkpoint.Context()
+	suspended := checkpoint.Suspended
+
+	switch {
+	case checkpoint.Completed:
+		return smCtx, fmt.Errorf("%w: %s", ErrExecutionCompleted, sessionID)
+	case suspended == nil:
+		return smCtx, fmt.Errorf("%w: %s", ErrNotSuspended, sessionID)
+	case suspended.Event != event:
+		return smCtx, fmt.Errorf("%w: state %s awaits %q, got %q", ErrUnexpectedEvent, suspended.State, suspended.Event, event)
+	case suspended.Expired(time.Now()):
+		return smCtx, fmt.Errorf("%w: state %s", ErrWaitExpired, suspended.State)
+	}
+
+	return smCtx, e.continueAfterWait(ctx, smCtx, suspended, payload, false)
+}
+
+// ResumeExpired follows the timeout transition of every suspended execution
+// whose wait deadline has passed. Timeouts are not fired on their own; call
+// ResumeExpired periodically (or Resume for a single session) to act on them.
+// The configured CheckpointStore must implement SuspendedLister.
+//
+// It returns the IDs of the sessions it resumed. An execution that reaches
+// another wait state counts as resumed, and one advanced concurrently by
+// someone else (see Resume) is skipped; other failures are joined into the
+// returned error without stopping the sweep.
+func (e *Engine) ResumeExpired(ctx context.Context) ([]string, error) {
+	if e.checkpointStore == nil {
+		return nil, ErrCheckpointStoreRequired
+	}
+
+	lister, ok := e.checkpointStore.(SuspendedLister)
+	if !ok {
+		return nil, ErrListingNotSupported
+	}
+
+	suspensions, err := lister.ListSuspended(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		resumed []string
+		errs    []error
+	)
+
+	now := time.Now()
+
+	for _, suspended := range suspensions {
+		if !suspended.Expired(now) {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+
+			break
+		}
+
+		_, err := e.Resume(ctx, suspended.SessionID)
+		if errors.Is(err, ErrCheckpointConflict) {
+			continue
+		}
+
+		if err != nil && !errors.Is(err, ErrSuspended) {
+			errs = append(errs, fmt.Errorf("session %s: %w", suspended.SessionID, err))
+
+			continue
+		}
+
+		resumed = append(resumed, suspended.SessionID)
+	}
+
+	return resumed, errors.Join(errs...)
+}
+
+// continueAfterWait leaves a wait state, either because its event arrived or
+// because it timed out, and runs the execution onwards.
+func (e *Engine) continueAfterWait(
+	ctx context.Context,
+	smCtx *Context,
+	suspended *Suspended,
+	payload map[string]any,
+	timedOut bool,
+) error {
+	waitState, ok := e.states[suspended.State].(*WaitState)
+	if !ok {
+		return WrapStateError(suspended.State, ErrNotSuspended)
+	}
+
+	smCtx.CurrentState = waitState.name
+	smCtx.Merge(payload)
+	smCtx.Set(WaitTimedOutKey(waitState.name), timedOut)
+
+	preferred := ""
+	if timedOut {
+		preferred = waitState.onTimeout
+	}
+
+	nextState, err := e.findTransition(ctx, smCtx, preferred)
+	if err != nil {
+		return err
+	}
+
+	if e.logger != nil {
+		e.logger.TransitionExecuted(ctx, waitState.name, nextState)
+	}
+
+	transitionTotal.WithLabelValues(
+		sanitizeTool(smCtx.ToolName),
+		waitState.name,
+		nextState,
+		sanitizeProvider(smCtx.Provider),
+		sanitizeProjectID(smCtx.ProjectID),
+		sanitizeChunkID(smCtx.ContextChunkID),
+	).Inc()
+
+	smCtx.AddTransition(waitState.name, nextState, payload)
+	smCtx.CurrentState = nextState
+
+	return e.Execute(ctx, smCtx)
+}
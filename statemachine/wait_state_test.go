@@ -0,0 +1,339 @@
+package statemachine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const waitWorkflowYAML = `
+name: confirm_setup
+initialState: start
+finalStates: [configured, rejected, expired]
+states:
+  - name: start
+    type: action
+    actions:
+      - type: noop
+        name: prepare
+  - name: confirm
+    type: wait
+    await:
+      event: confirmation
+      prompt: Do you want to connect Salesforce?
+      timeout: 1h
+      onTimeout: expired
+    metadata:
+      schema: boolean
+  - name: configured
+    type: final
+  - name: rejected
+    type: final
+  - name: expired
+    type: final
+transitions:
+  - from: start
+    to: confirm
+  - from: confirm
+    to: configured
+    condition: "data.confirmed"
+  - from: confirm
+    to: rejected
+    condition: "!data.confirmed"
+`
+
+func TestEngineSuspendAndSignal(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	config, err := LoadConfigFromBytes([]byte(waitWorkflowYAML))
+	require.NoError(t, err)
+
+	engine, err := NewEngine(config, nil)
+	require.NoError(t, err)
+
+	store := NewMemoryCheckpointStore()
+	engine.SetCheckpointStore(store)
+
+	smCtx := NewContext("session-wait", "project")
+
+	err = engine.Execute(ctx, smCtx)
+	require.ErrorIs(t, err, ErrSuspended)
+
+	suspended, ok := AsSuspended(err)
+	require.True(t, ok)
+	assert.Equal(t, "session-wait", suspended.SessionID)
+	assert.Equal(t, "confirm", suspended.State)
+	assert.Equal(t, "confirmation", suspended.Event)
+	assert.Equal(t, "Do you want to connect Salesforce?", suspended.Prompt)
+	assert.Equal(t, "boolean", suspended.Metadata["schema"])
+	assert.WithinDuration(t, time.Now().Add(time.Hour), suspended.Deadline, time.Minute)
+
+	// Resuming before the deadline leaves the execution suspended
+	_, err = engine.Resume(ctx, "session-wait")
+	require.ErrorIs(t, err, ErrSuspended)
+
+	_, err = engine.Signal(ctx, "session-wait", "cancel", nil)
+	require.ErrorIs(t, err, ErrUnexpectedEvent)
+
+	resumed, err := engine.Signal(ctx, "session-wait", "confirmation", map[string]any{"confirmed": true})
+	require.NoError(t, err)
+	assert.Equal(t, "configured", resumed.CurrentState)
+	assert.Equal(t, []string{"start", "confirm", "configured"}, resumed.PathHistory)
+
+	timedOut, ok := resumed.GetBool(WaitTimedOutKey("confirm"))
+	assert.True(t, ok)
+	assert.False(t, timedOut)
+
+	_, err = engine.Signal(ctx, "session-wait", "confirmation", nil)
+	require.ErrorIs(t, err, ErrExecutionCompleted)
+}
+
+// barrierStore holds every Load until all the loads added to its WaitGroup
+// are in flight, so that concurrent callers all load the same checkpoint.
+type barrierStore struct {
+	*MemoryCheckpointStore
+
+	loads sync.WaitGroup
+}
+
+func (s *barrierStore) Load(ctx context.Context, sessionID string) (*Checkpoint, error) {
+	checkpoint, err := s.MemoryCheckpointStore.Load(ctx, sessionID)
+
+	s.loads.Done()
+	s.loads.Wait()
+
+	return checkpoint, err
+}
+
+func TestEngineConcurrentSignals(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	config, err := LoadConfigFromBytes([]byte(waitWorkflowYAML))
+	require.NoError(t, err)
+
+	engine, err := NewEngine(config, nil)
+	require.NoError(t, err)
+
+	memory := NewMemoryCheckpointStore()
+	engine.SetCheckpointStore(memory)
+
+	err = engine.Execute(ctx, NewContext("session-race", "project"))
+	require.ErrorIs(t, err, ErrSuspended)
+
+	store := &barrierStore{MemoryCheckpointStore: memory}
+	store.loads.Add(2)
+	engine.SetCheckpointStore(store)
+
+	errs := make(chan error, 2)
+
+	for _, confirmed := range []bool{true, false} {
+		go func() {
+			_, err := engine.Signal(ctx, "session-race", "confirmation", map[string]any{"confirmed": confirmed})
+			errs <- err
+		}()
+	}
+
+	var succeeded, conflicted int
+
+	for range 2 {
+		err := <-errs
+		if err == nil {
+			succeeded++
+		} else {
+			require.ErrorIs(t, err, ErrCheckpointConflict)
+
+			conflicted++
+		}
+	}
+
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, 1, conflicted)
+
+	checkpoint, err := memory.Load(ctx, "session-race")
+	require.NoError(t, err)
+	assert.True(t, checkpoint.Completed)
+	assert.Len(t, checkpoint.PathHistory, 3, "the wait state was left only once")
+}
+
+func TestEngineWaitTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	engine := newTimeoutEngine(time.Nanosecond)
+	engine.SetCheckpointStore(NewMemoryCheckpointStore())
+
+	err := engine.Execute(ctx, NewContext("session-timeout", "project"))
+	require.ErrorIs(t, err, ErrSuspended)
+
+	time.Sleep(time.Millisecond)
+
+	_, err = engine.Signal(ctx, "session-timeout", "answer", nil)
+	require.ErrorIs(t, err, ErrWaitExpired)
+
+	resumed, err := engine.Resume(ctx, "session-timeout")
+	require.NoError(t, err)
+	assert.Equal(t, "expired", resumed.CurrentState)
+	assert.True(t, resumed.Data[WaitTimedOutKey("wait")].(bool)) //nolint:forcetypeassert // Set by the engine
+}
+
+func newTimeoutEngine(timeout time.Duration) *Engine {
+	engine := &Engine{
+		states:       make(map[string]State),
+		transitions:  []Transition{},
+		initialState: "wait",
+		finalStates:  []string{"answered", "expired"},
+	}
+
+	engine.RegisterState(NewWaitState("wait", "answer", "", timeout, "expired"))
+	engine.RegisterState(NewFinalState("answered"))
+	engine.RegisterState(NewFinalState("expired"))
+	engine.RegisterTransition(NewSimpleTransition("wait", "answered"))
+	engine.RegisterTransition(NewSimpleTransition("wait", "expired"))
+
+	return engine
+}
+
+func TestEngineResumeExpired(t *testing.T) {
+	t.Parallel()
+
+	fileStore, err := NewFileCheckpointStore(t.TempDir())
+	require.NoError(t, err)
+
+	stores := map[string]CheckpointStore{
+		"memory": NewMemoryCheckpointStore(),
+		"file":   fileStore,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			expiring := newTimeoutEngine(time.Nanosecond)
+			expiring.SetCheckpointStore(store)
+
+			waiting := newTimeoutEngine(time.Hour)
+			waiting.SetCheckpointStore(store)
+
+			require.ErrorIs(t, expiring.Execute(ctx, NewContext("session-expired", "project")), ErrSuspended)
+			require.ErrorIs(t, waiting.Execute(ctx, NewContext("session-waiting", "project")), ErrSuspended)
+
+			time.Sleep(time.Millisecond)
+
+			resumed, err := expiring.ResumeExpired(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"session-expired"}, resumed)
+
+			checkpoint, err := store.Load(ctx, "session-expired")
+			require.NoError(t, err)
+			assert.True(t, checkpoint.Completed)
+			assert.Equal(t, "expired", checkpoint.CurrentState)
+
+			checkpoint, err = store.Load(ctx, "session-waiting")
+			require.NoError(t, err)
+			assert.NotNil(t, checkpoint.Suspended)
+
+			// Completed executions are not picked up again
+			resumed, err = expiring.ResumeExpired(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, resumed)
+		})
+	}
+}
+
+type unlistableStore struct {
+	CheckpointStore
+}
+
+func TestEngineResumeExpiredErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	engine := newTimeoutEngine(time.Hour)
+
+	_, err := engine.ResumeExpired(ctx)
+	require.ErrorIs(t, err, ErrCheckpointStoreRequired)
+
+	engine.SetCheckpointStore(unlistableStore{NewMemoryCheckpointStore()})
+
+	_, err = engine.ResumeExpired(ctx)
+	require.ErrorIs(t, err, ErrListingNotSupported)
+}
+
+func TestEngineWaitWithoutCheckpointStore(t *testing.T) {
+	t.Parallel()
+
+	engine := newTimeoutEngine(time.Hour)
+
+	err := engine.Execute(context.Background(), NewContext("session", "project"))
+	require.ErrorIs(t, err, ErrCheckpointStoreRequired)
+
+	_, ok := AsSuspended(err)
+	assert.False(t, ok)
+}
+
+func TestSignalErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	engine := &Engine{states: make(map[string]State), initialState: "start"}
+
+	_, err := engine.Signal(ctx, "session", "event", nil)
+	require.ErrorIs(t, err, ErrCheckpointStoreRequired)
+
+	store := NewMemoryCheckpointStore()
+	engine.SetCheckpointStore(store)
+
+	_, err = engine.Signal(ctx, "session", "event", nil)
+	require.ErrorIs(t, err, ErrCheckpointNotFound)
+
+	require.NoError(t, store.Save(ctx, NewContext("session", "project").Checkpoint()))
+
+	_, err = engine.Signal(ctx, "session", "event", nil)
+	require.ErrorIs(t, err, ErrNotSuspended)
+}
+
+func TestWaitStateValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		await    *AwaitConfig
+		expected error
+	}{
+		{name: "missing await", await: nil, expected: ErrAwaitEventRequired},
+		{name: "missing event", await: &AwaitConfig{Prompt: "?"}, expected: ErrAwaitEventRequired},
+		{name: "invalid timeout", await: &AwaitConfig{Event: "e", Timeout: "soon", OnTimeout: "end"}, expected: ErrInvalidTimeout},
+		{name: "timeout without target", await: &AwaitConfig{Event: "e", Timeout: "1h"}, expected: ErrOnTimeoutRequired},
+		{name: "unknown timeout target", await: &AwaitConfig{Event: "e", Timeout: "1h", OnTimeout: "nowhere"}, expected: ErrTransitionToNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := &Config{
+				Name:         "wait",
+				InitialState: "wait",
+				FinalStates:  []string{"end"},
+				States: []StateConfig{
+					{Name: "wait", Type: StateTypeWait, Await: tt.await},
+					{Name: "end", Type: "final"},
+				},
+			}
+
+			require.ErrorIs(t, config.Validate(), tt.expected)
+		})
+	}
+}